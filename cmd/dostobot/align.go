@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/abdulachik/dostobot/internal/config"
+	"github.com/abdulachik/dostobot/internal/extractor"
+	"github.com/abdulachik/dostobot/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var (
+	alignAll    bool
+	alignBook   string
+	alignBudget float64
+)
+
+var alignCmd = &cobra.Command{
+	Use:   "align",
+	Short: "Link quotes of originals to their translations",
+	Long: `Link the quotes of an original, e.g. the Russian text of a novel, to the
+passages of its translations that render them (EXTRACT_MODEL).
+
+Translations are catalog books added with --original. For each quote the
+LLM searches the same chapter of the translation, around the same point in
+it, and the passage it finds is checked against the translation's text like
+an extracted quote. The passage is saved as a quote in the translation's
+language and linked to the original, so the two can be posted together
+(POST_BILINGUAL). Quotes already linked are skipped on later runs.
+
+Examples:
+  dostobot align --book "Преступление и наказание"
+  dostobot align --all --budget 2`,
+	RunE: runAlign,
+}
+
+func init() {
+	alignCmd.Flags().BoolVar(&alignAll, "all", false, "Align every book that has a translation")
+	alignCmd.Flags().StringVar(&alignBook, "book", "", "Title of the original to align")
+	alignCmd.Flags().Float64Var(&alignBudget, "budget", 0, "Maximum API spend in USD (default: EXTRACT_BUDGET_USD)")
+	rootCmd.AddCommand(alignCmd)
+}
+
+func runAlign(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if !alignAll && alignBook == "" {
+		return fmt.Errorf("must specify --all or --book")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if err := cfg.ValidateForExtraction(); err != nil {
+		return fmt.Errorf("validate config: %w", err)
+	}
+	if cmd.Flags().Changed("budget") {
+		cfg.ExtractBudgetUSD = alignBudget
+	}
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	budget := llm.NewBudget(cfg.ExtractBudgetUSD)
+	backend := llm.Backend{
+		Provider: cfg.LLMProvider,
+		BaseURL:  cfg.LLMBaseURL,
+		APIKey:   cfg.LLMAPIKey,
+		Limiter:  llm.NewRateLimiter(cfg.ExtractRequestsPerMinute),
+		Budget:   budget,
+	}
+	alignLLM, err := backend.New(cfg.ExtractModel)
+	if err != nil {
+		return fmt.Errorf("create extraction model: %w", err)
+	}
+
+	ext := extractor.New(extractor.Config{
+		Store:    store,
+		BooksDir: "books",
+		LLM:      alignLLM,
+		Budget:   budget,
+	})
+
+	var linked int
+	if alignAll {
+		linked, err = ext.AlignAll(ctx)
+	} else {
+		linked, err = ext.AlignTranslations(ctx, alignBook)
+	}
+
+	slog.Info("alignment spend", "usd", fmt.Sprintf("%.2f", ext.Spent()))
+	fmt.Printf("Linked %d quotes to their translations.\n", linked)
+	return err
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+
+	"github.com/abdulachik/dostobot/internal/config"
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bookAuthor      string
+	bookTranslator  string
+	bookGutenbergID int64
+	bookFile        string
+	bookLanguage    string
+	bookOriginal    string
+)
+
+var bookCmd = &cobra.Command{
+	Use:   "book",
+	Short: "Manage the book catalog",
+	Long: `Manage the catalog of books quotes are extracted from.
+
+Each book has a title (shown in post attributions), an author, an optional
+translator and Project Gutenberg ID, the name of its file in books/ and a
+language. "dostobot download" fetches books by their Gutenberg ID and
+"dostobot extract --all" extracts every book whose file is present.`,
+}
+
+var bookAddCmd = &cobra.Command{
+	Use:   "add <title>",
+	Short: "Add a book to the catalog",
+	Long: `Add a book to the catalog. The file name defaults to the title in
+lowercase with dashes, e.g. "White Nights" is read from books/white-nights.txt.
+Files placed by hand may be plain text, HTML (.html, .htm, .xhtml) or EPUB
+(.epub); the chapters of an EPUB are taken from its table of contents.
+
+With --original the book is recorded as a translation of another catalog
+book, and "dostobot align" links the quotes of the original to their
+passages in it.
+
+Examples:
+  dostobot book add "White Nights" --gutenberg-id 36034
+  dostobot book add "The House of the Dead" --gutenberg-id 37536 --translator "Constance Garnett"
+  dostobot book add "A Writer's Diary" --file writers-diary.txt   # File placed by hand
+  dostobot book add "Demons" --translator "Pevear and Volokhonsky" --file demons-pv.epub
+  dostobot book add "Бедные люди" --language ru --author "Фёдор Достоевский" --file bednye-lyudi.txt
+  dostobot book add "Poor Folk (Hogarth)" --file poor-folk-hogarth.txt --original "Бедные люди"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBookAdd,
+}
+
+var bookListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the books in the catalog",
+	RunE:  runBookList,
+}
+
+var bookLinkCmd = &cobra.Command{
+	Use:   "link <translation> <original>",
+	Short: "Record a catalog book as a translation of another",
+	Long: `Record a catalog book as a translation of another, so "dostobot align"
+links the quotes of the original to their passages in it.
+
+Examples:
+  dostobot book link "Crime and Punishment" "Преступление и наказание"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runBookLink,
+}
+
+var bookRemoveCmd = &cobra.Command{
+	Use:   "remove <title>",
+	Short: "Remove a book from the catalog",
+	Long: `Remove a book from the catalog so it is no longer downloaded or
+extracted. Quotes already extracted from it are kept.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBookRemove,
+}
+
+func init() {
+	bookAddCmd.Flags().StringVar(&bookAuthor, "author", "Fyodor Dostoyevsky", "Author of the book")
+	bookAddCmd.Flags().StringVar(&bookTranslator, "translator", "", "Translator of the edition")
+	bookAddCmd.Flags().Int64Var(&bookGutenbergID, "gutenberg-id", 0, "Project Gutenberg ebook number, for downloading")
+	bookAddCmd.Flags().StringVar(&bookFile, "file", "", "File name in the books directory (default: derived from the title)")
+	bookAddCmd.Flags().StringVar(&bookLanguage, "language", "en", "Language of the edition")
+	bookAddCmd.Flags().StringVar(&bookOriginal, "original", "", "Title of the catalog book this edition translates")
+
+	bookCmd.AddCommand(bookAddCmd, bookListCmd, bookLinkCmd, bookRemoveCmd)
+	rootCmd.AddCommand(bookCmd)
+}
+
+func runBookAdd(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	title := strings.TrimSpace(args[0])
+
+	var original *db.Book
+	if bookOriginal != "" {
+		original, err = store.GetBookByTitle(ctx, bookOriginal)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("unknown original: %s", bookOriginal)
+		}
+		if err != nil {
+			return fmt.Errorf("find original: %w", err)
+		}
+	}
+
+	file := bookFile
+	if file == "" {
+		file = bookFileName(title)
+	}
+
+	book, err := store.CreateBook(ctx, db.CreateBookParams{
+		Title:       title,
+		Author:      bookAuthor,
+		Translator:  sql.NullString{String: bookTranslator, Valid: bookTranslator != ""},
+		GutenbergID: sql.NullInt64{Int64: bookGutenbergID, Valid: bookGutenbergID > 0},
+		FilePath:    file,
+		Language:    bookLanguage,
+	})
+	if err != nil {
+		return fmt.Errorf("add book: %w", err)
+	}
+
+	if original != nil {
+		err = store.SetBookOriginal(ctx, db.SetBookOriginalParams{
+			OriginalID: sql.NullInt64{Int64: original.ID, Valid: true},
+			ID:         book.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("link original: %w", err)
+		}
+	}
+
+	fmt.Printf("Added %q by %s (books/%s)\n", book.Title, book.Author, book.FilePath)
+	if original != nil {
+		fmt.Printf("It translates %q; run 'dostobot align --book %q' once both are extracted.\n", original.Title, original.Title)
+	}
+	if book.GutenbergID.Valid {
+		fmt.Printf("Run 'dostobot download --book %q' to fetch it.\n", book.Title)
+	}
+	return nil
+}
+
+func runBookList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	books, err := store.ListBooks(ctx)
+	if err != nil {
+		return fmt.Errorf("list books: %w", err)
+	}
+
+	if len(books) == 0 {
+		fmt.Println("The catalog is empty. Add books with 'dostobot book add'.")
+		return nil
+	}
+
+	titles := make(map[int64]string, len(books))
+	for _, book := range books {
+		titles[book.ID] = book.Title
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TITLE\tAUTHOR\tTRANSLATOR\tGUTENBERG\tLANG\tORIGINAL\tFILE")
+	for _, book := range books {
+		gutenberg := "-"
+		if book.GutenbergID.Valid {
+			gutenberg = fmt.Sprintf("%d", book.GutenbergID.Int64)
+		}
+		translator := "-"
+		if book.Translator.Valid {
+			translator = book.Translator.String
+		}
+		original := "-"
+		if book.OriginalID.Valid {
+			original = titles[book.OriginalID.Int64]
+		}
+		file := book.FilePath
+		if _, err := os.Stat(filepath.Join("books", book.FilePath)); err != nil {
+			file += " (missing)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			book.Title,
+			book.Author,
+			translator,
+			gutenberg,
+			book.Language,
+			original,
+			file,
+		)
+	}
+	return w.Flush()
+}
+
+func runBookLink(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	translation, err := store.GetBookByTitle(ctx, args[0])
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("unknown book: %s", args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("find book: %w", err)
+	}
+	original, err := store.GetBookByTitle(ctx, args[1])
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("unknown original: %s", args[1])
+	}
+	if err != nil {
+		return fmt.Errorf("find original: %w", err)
+	}
+	if original.ID == translation.ID {
+		return fmt.Errorf("a book cannot translate itself")
+	}
+
+	err = store.SetBookOriginal(ctx, db.SetBookOriginalParams{
+		OriginalID: sql.NullInt64{Int64: original.ID, Valid: true},
+		ID:         translation.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("link original: %w", err)
+	}
+
+	fmt.Printf("%q now translates %q; run 'dostobot align --book %q' once both are extracted.\n",
+		translation.Title, original.Title, original.Title)
+	return nil
+}
+
+func runBookRemove(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	book, err := store.GetBookByTitle(ctx, args[0])
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("unknown book: %s", args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("find book: %w", err)
+	}
+
+	if err := store.DeleteBook(ctx, book.ID); err != nil {
+		return fmt.Errorf("remove book: %w", err)
+	}
+
+	fmt.Printf("Removed %q from the catalog; its quotes are kept.\n", book.Title)
+	return nil
+}
+
+// openStore loads the configuration and opens the migrated database.
+func openStore(ctx context.Context) (*db.Store, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+
+	store, err := db.NewStore(ctx, cfg.DatabasePath)
+	if err != nil {
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+
+	if err := store.Migrate(ctx); err != nil {
+		store.Close()
+		return nil, fmt.Errorf("run migrations: %w", err)
+	}
+	return store, nil
+}
+
+// bookFileName derives a book's file name from its title:
+// "The House of the Dead" becomes "the-house-of-the-dead.txt".
+func bookFileName(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "-") + ".txt"
+}
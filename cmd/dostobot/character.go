@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/abdulachik/dostobot/internal/characters"
+	"github.com/spf13/cobra"
+)
+
+var characterCmd = &cobra.Command{
+	Use:   "character",
+	Short: "Manage each book's character registry",
+	Long: `Quotes are credited to characters by their canonical name in the book's
+registry. Names the LLM returns are mapped onto them through aliases
+("Rodion Romanovitch" and "Rodya" both become "Raskolnikov"); names that
+match neither a character nor an alias are added as new characters, to be
+reviewed with 'character list' and folded into others with 'character alias'.`,
+}
+
+var characterListCmd = &cobra.Command{
+	Use:   "list <book>",
+	Short: "List a book's characters with their aliases and quote counts",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runCharacterList,
+}
+
+var characterAddCmd = &cobra.Command{
+	Use:   "add <book> <name>",
+	Short: "Add a character to a book",
+	Long: `Add a character to a book's registry, optionally with aliases.
+
+Examples:
+  dostobot character add "Crime and Punishment" Lebeziatnikov --alias "Andrey Semyonovitch"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCharacterAdd,
+}
+
+var characterAliasCmd = &cobra.Command{
+	Use:   "alias <book> <alias> <name>",
+	Short: "Map an alias onto a character",
+	Long: `Map an alias onto a character of a book. If the alias is a character
+itself, it is merged: its quotes are credited to the character and its
+aliases move over.
+
+Examples:
+  dostobot character alias "Crime and Punishment" "Rodion" Raskolnikov`,
+	Args: cobra.ExactArgs(3),
+	RunE: runCharacterAlias,
+}
+
+var characterNormalizeCmd = &cobra.Command{
+	Use:   "normalize",
+	Short: "Credit stored quotes to canonical character names",
+	Long: `Replace the character of every stored quote with its canonical name.
+Run it once after upgrading, then 'dostobot reindex' so searches by
+character see the new names.
+
+Examples:
+  dostobot character normalize --dry-run  # Count the quotes that would change`,
+	Args: cobra.NoArgs,
+	RunE: runCharacterNormalize,
+}
+
+var (
+	characterAliases []string
+	characterDryRun  bool
+)
+
+func init() {
+	characterAddCmd.Flags().StringSliceVar(&characterAliases, "alias", nil, "Alias of the character (repeatable)")
+	characterNormalizeCmd.Flags().BoolVar(&characterDryRun, "dry-run", false, "Count the quotes that would change without saving")
+	characterCmd.AddCommand(characterListCmd, characterAddCmd, characterAliasCmd, characterNormalizeCmd)
+	rootCmd.AddCommand(characterCmd)
+}
+
+func runCharacterList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	book := args[0]
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	chars, err := store.ListBookCharacters(ctx, book)
+	if err != nil {
+		return fmt.Errorf("list characters: %w", err)
+	}
+	if len(chars) == 0 {
+		fmt.Printf("No characters registered for %s.\n", book)
+		return nil
+	}
+	aliases, err := store.ListBookCharacterAliases(ctx, book)
+	if err != nil {
+		return fmt.Errorf("list character aliases: %w", err)
+	}
+	counts, err := store.CountQuotesByCharacter(ctx, book)
+	if err != nil {
+		return fmt.Errorf("count quotes by character: %w", err)
+	}
+
+	byCharacter := make(map[int64][]string)
+	for _, a := range aliases {
+		byCharacter[a.CharacterID] = append(byCharacter[a.CharacterID], a.Alias)
+	}
+	quotes := make(map[string]int64, len(counts))
+	posted := make(map[string]int64, len(counts))
+	for _, c := range counts {
+		quotes[c.Character.String] = c.Quotes
+		posted[c.Character.String] = c.Posted
+	}
+
+	for _, c := range chars {
+		line := fmt.Sprintf("%-24s %5d quotes, %d posted", c.Name, quotes[c.Name], posted[c.Name])
+		if names := byCharacter[c.ID]; len(names) > 0 {
+			sort.Strings(names)
+			line += "  (" + strings.Join(names, ", ") + ")"
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runCharacterAdd(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	book := args[0]
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	if _, err := store.GetBookByTitle(ctx, book); err != nil {
+		return fmt.Errorf("unknown book: %s", book)
+	}
+
+	cast, err := characters.Load(ctx, store.Queries, book)
+	if err != nil {
+		return err
+	}
+	if c, known := cast.Lookup(args[1]); known {
+		return fmt.Errorf("%q is already the character %q or one of their aliases", args[1], c.Name)
+	}
+	name, err := cast.Resolve(ctx, store.Queries, args[1])
+	if err != nil {
+		return err
+	}
+	if name == "" || name == characters.Narrator {
+		return fmt.Errorf("invalid character name: %q", args[1])
+	}
+	fmt.Printf("Added %q to %s.\n", name, book)
+
+	for _, alias := range characterAliases {
+		if _, err := characters.AddAlias(ctx, store, book, alias, name); err != nil {
+			return fmt.Errorf("alias %q: %w", alias, err)
+		}
+		fmt.Printf("Added alias %q.\n", characters.Key(alias))
+	}
+	return nil
+}
+
+func runCharacterAlias(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	book, alias, name := args[0], args[1], args[2]
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	moved, err := characters.AddAlias(ctx, store, book, alias, name)
+	if err != nil {
+		return err
+	}
+	if moved > 0 {
+		fmt.Printf("Merged %q into %q, crediting %d quotes.\n", alias, name, moved)
+		fmt.Println("Run 'dostobot reindex' so searches by character see the change.")
+		return nil
+	}
+	fmt.Printf("%q is now an alias of %q.\n", characters.Key(alias), name)
+	return nil
+}
+
+func runCharacterNormalize(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	changed, err := characters.NormalizeAll(ctx, store, characterDryRun)
+	if err != nil {
+		return fmt.Errorf("normalize characters: %w", err)
+	}
+
+	if characterDryRun {
+		fmt.Printf("%d quotes would be credited to another name.\n", changed)
+		return nil
+	}
+	fmt.Printf("Credited %d quotes to canonical names.\n", changed)
+	if changed > 0 {
+		fmt.Println("Run 'dostobot reindex' so searches by character see the new names.")
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/abdulachik/dostobot/internal/characters"
+	"github.com/abdulachik/dostobot/internal/curate"
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/themes"
+	"github.com/spf13/cobra"
+)
+
+var (
+	curateBook        string
+	curateCharacter   string
+	curateTheme       string
+	curateMinQuality  int
+	curateNeverPosted bool
+	curateStatus      string
+	curateFavorites   bool
+)
+
+var curateCmd = &cobra.Command{
+	Use:   "curate",
+	Short: "Review quotes one at a time",
+	Long: `Page through quotes and review them: approve or reject each one, fix its
+text, retag its themes or mark it as a favorite. Decisions are saved as
+they are made, so a session can be left at any point and resumed later.
+
+Quotes an editor rejects are never matched. With CURATION_MODE=true only
+approved quotes are matched, so nothing is posted that an editor has not
+seen. Run 'dostobot embed' after editing text to re-embed the quotes; edited
+text is no longer marked as verbatim from the book.
+
+Commands at the prompt:
+  a      approve and go to the next quote
+  r      reject and go to the next quote
+  e      replace the text
+  t      replace the themes (comma-separated)
+  f      toggle favorite
+  s      skip (or just press enter)
+  q      quit
+
+Examples:
+  dostobot curate                                      # Quotes not yet reviewed
+  dostobot curate --book "The Idiot" --min-quality 7
+  dostobot curate --character Raskolnikov --never-posted
+  dostobot curate --status approved --theme suffering  # Revisit approved quotes`,
+	RunE: runCurate,
+}
+
+func init() {
+	curateCmd.Flags().StringVar(&curateBook, "book", "", "Only quotes from this book")
+	curateCmd.Flags().StringVar(&curateCharacter, "character", "", "Only quotes spoken by this character (aliases are resolved with --book)")
+	curateCmd.Flags().StringVar(&curateTheme, "theme", "", "Only quotes with this theme or one of its aliases")
+	curateCmd.Flags().IntVar(&curateMinQuality, "min-quality", 0, "Only quotes with at least this quality score (1-10)")
+	curateCmd.Flags().BoolVar(&curateNeverPosted, "never-posted", false, "Only quotes never posted")
+	curateCmd.Flags().StringVar(&curateStatus, "status", curate.StatusPending, "Only quotes with this status: pending, approved, rejected or all")
+	curateCmd.Flags().BoolVar(&curateFavorites, "favorites", false, "Only favorite quotes")
+	rootCmd.AddCommand(curateCmd)
+}
+
+func runCurate(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	status := curateStatus
+	switch status {
+	case curate.StatusPending, db.CurationApproved, db.CurationRejected:
+	case "all":
+		status = ""
+	default:
+		return fmt.Errorf("invalid status %q (want pending, approved, rejected or all)", curateStatus)
+	}
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	theme := curateTheme
+	if theme != "" {
+		taxonomy, err := themes.Load(ctx, store.Queries)
+		if err != nil {
+			return err
+		}
+		theme, _ = taxonomy.Canonical(theme)
+	}
+
+	character := curateCharacter
+	if character != "" && curateBook != "" {
+		cast, err := characters.Load(ctx, store.Queries, curateBook)
+		if err != nil {
+			return err
+		}
+		character = cast.Canonical(character)
+	}
+
+	session := &curate.Session{
+		Store: store,
+		Filter: curate.Filter{
+			Book:        curateBook,
+			Character:   character,
+			Theme:       theme,
+			MinQuality:  curateMinQuality,
+			NeverPosted: curateNeverPosted,
+			Status:      status,
+			Favorites:   curateFavorites,
+		},
+		In:  os.Stdin,
+		Out: os.Stdout,
+	}
+
+	sum, err := session.Run(ctx)
+
+	fmt.Printf("\nReviewed %d quotes: %d approved, %d rejected, %d edited, %d retagged, %d marked favorite.\n",
+		sum.Reviewed, sum.Approved, sum.Rejected, sum.Edited, sum.Retagged, sum.Favorited)
+	if sum.Edited > 0 {
+		fmt.Println("Run 'dostobot embed' to re-embed the edited quotes.")
+	}
+	return err
+}
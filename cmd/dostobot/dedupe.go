@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/abdulachik/dostobot/internal/config"
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/dedupe"
+	"github.com/abdulachik/dostobot/internal/embedder"
+	"github.com/abdulachik/dostobot/internal/vectorstore"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dedupeDryRun     bool
+	dedupeThreshold  float64
+	dedupeSimilarity float64
+)
+
+var dedupeCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "Merge near-duplicate quotes",
+	Long: `Find quotes that are the same passage extracted more than once, usually
+from the overlap between two chunks, and merge each group into one quote.
+
+Quotes are duplicates when most of the shorter one's text appears in the
+longer one (--threshold), or when they share at least half their text and
+their embeddings are nearly identical (--similarity). Embeddings are read
+from VecLite when it is available.
+
+Each group keeps the quote an editor approved, favorited or edited, then the
+one verified against the source text, then the one not rejected by
+validation, then the most posted, then the oldest; quotes an editor rejected
+come last. Posts, queued matches and themes of the duplicates are moved to
+the kept quote and their post counts added to it, so posting history and
+cooldowns are preserved. The kept quote also takes a duplicate's curation
+status if it has none, and stays a favorite if any of them was. Run 'dostobot embed'
+afterwards to drop the merged quotes from VecLite.
+
+New duplicates are skipped at extraction time; this command cleans up
+quotes extracted before that.
+
+Examples:
+  dostobot dedupe --dry-run          # List duplicate groups without merging
+  dostobot dedupe                    # Merge them
+  dostobot dedupe --threshold 0.9    # Only merge near-identical text`,
+	RunE: runDedupe,
+}
+
+func init() {
+	dedupeCmd.Flags().BoolVar(&dedupeDryRun, "dry-run", false, "List duplicates without merging them")
+	dedupeCmd.Flags().Float64Var(&dedupeThreshold, "threshold", dedupe.DefaultThreshold, "Text containment (0-1) at which quotes are duplicates")
+	dedupeCmd.Flags().Float64Var(&dedupeSimilarity, "similarity", dedupe.DefaultSimilarity, "Embedding similarity (0-1) at which overlapping quotes are duplicates")
+	rootCmd.AddCommand(dedupeCmd)
+}
+
+func runDedupe(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	store, err := db.NewStore(ctx, cfg.DatabasePath)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer store.Close()
+
+	if err := store.Migrate(ctx); err != nil {
+		return fmt.Errorf("run migrations: %w", err)
+	}
+
+	quotes, err := store.ListQuotes(ctx, db.ListQuotesParams{Limit: 100000, Offset: 0})
+	if err != nil {
+		return fmt.Errorf("list quotes: %w", err)
+	}
+
+	// Quotes from different books are never the same passage.
+	byBook := make(map[string][]*db.Quote)
+	for _, q := range quotes {
+		byBook[q.SourceBook] = append(byBook[q.SourceBook], q)
+	}
+
+	embeddings := loadQuoteEmbeddings(cfg, quotes)
+	opts := dedupe.Options{Threshold: dedupeThreshold, Similarity: float32(dedupeSimilarity)}
+
+	var groups []dedupe.Group
+	for _, bookQuotes := range byBook {
+		groups = append(groups, dedupe.FindGroups(bookQuotes, embeddings, opts)...)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Keeper.ID < groups[j].Keeper.ID })
+
+	if len(groups) == 0 {
+		fmt.Println("No duplicate quotes found.")
+		return nil
+	}
+
+	merged := 0
+	for _, g := range groups {
+		fmt.Printf("Keep #%d (%s): %s\n", g.Keeper.ID, g.Keeper.SourceBook, truncate(g.Keeper.Text, 80))
+		for _, dup := range g.Duplicates {
+			fmt.Printf("  merge #%d: %s\n", dup.ID, truncate(dup.Text, 80))
+		}
+
+		if dedupeDryRun {
+			continue
+		}
+		if err := dedupe.Merge(ctx, store, g); err != nil {
+			return fmt.Errorf("merge into quote %d: %w", g.Keeper.ID, err)
+		}
+		merged += len(g.Duplicates)
+	}
+
+	fmt.Println()
+	if dedupeDryRun {
+		fmt.Printf("Found %d duplicate groups (dry run, nothing merged).\n", len(groups))
+		return nil
+	}
+	fmt.Printf("Merged %d duplicate quotes into %d.\n", merged, len(groups))
+	fmt.Println("Run 'dostobot embed' to remove them from VecLite.")
+
+	return nil
+}
+
+// loadQuoteEmbeddings returns the quote vectors from VecLite, or from the
+// legacy SQLite column when VecLite is unavailable. Deduplication works on
+// text alone without them.
+func loadQuoteEmbeddings(cfg *config.Config, quotes []*db.Quote) map[int64][]float32 {
+	if cfg.VecLitePath != "" {
+		provider, err := embedder.LoadProvider(embedder.ProviderConfig{
+			Provider: cfg.EmbedProvider,
+			CacheDir: cfg.EmbedCacheDir,
+		})
+		if err == nil {
+			var quoteStore *vectorstore.QuoteStore
+			quoteStore, err = vectorstore.NewReadOnly(vectorstore.Config{
+				Path:     cfg.VecLitePath,
+				Provider: provider,
+			})
+			if err == nil {
+				defer quoteStore.Close()
+				return quoteStore.Vectors()
+			}
+		}
+		slog.Warn("failed to open VecLite, using stored embeddings", "error", err)
+	}
+
+	embeddings := make(map[int64][]float32)
+	for _, q := range quotes {
+		if vec, err := embedder.BytesToEmbedding(q.Embedding); err == nil && len(vec) > 0 {
+			embeddings[q.ID] = vec
+		}
+	}
+	return embeddings
+}
+
+// truncate shortens s to maxLen runes, adding an ellipsis if truncated.
+func truncate(s string, maxLen int) string {
+	r := []rune(s)
+	if len(r) <= maxLen {
+		return s
+	}
+	return string(r[:maxLen-3]) + "..."
+}
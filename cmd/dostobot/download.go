@@ -2,125 +2,183 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
-	"io"
 	"log/slog"
-	"net/http"
 	"os"
 	"path/filepath"
-	"time"
+	"strings"
+	"text/tabwriter"
 
+	"github.com/abdulachik/dostobot/internal/config"
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/gutenberg"
 	"github.com/spf13/cobra"
 )
 
-// Book represents a Dostoyevsky book to download.
-type Book struct {
-	Filename string
-	Title    string
-	URL      string
-}
-
-// Books to download from Project Gutenberg.
-var books = []Book{
-	{
-		Filename: "crime-and-punishment.txt",
-		Title:    "Crime and Punishment",
-		URL:      "https://www.gutenberg.org/cache/epub/2554/pg2554.txt",
-	},
-	{
-		Filename: "brothers-karamazov.txt",
-		Title:    "The Brothers Karamazov",
-		URL:      "https://www.gutenberg.org/cache/epub/28054/pg28054.txt",
-	},
-	{
-		Filename: "notes-from-underground.txt",
-		Title:    "Notes from Underground",
-		URL:      "https://www.gutenberg.org/cache/epub/600/pg600.txt",
-	},
-	{
-		Filename: "the-idiot.txt",
-		Title:    "The Idiot",
-		URL:      "https://www.gutenberg.org/cache/epub/2638/pg2638.txt",
-	},
-	{
-		Filename: "the-possessed.txt",
-		Title:    "The Possessed (Demons)",
-		URL:      "https://www.gutenberg.org/cache/epub/8117/pg8117.txt",
-	},
-	{
-		Filename: "the-gambler.txt",
-		Title:    "The Gambler",
-		URL:      "https://www.gutenberg.org/cache/epub/2197/pg2197.txt",
-	},
-	{
-		Filename: "poor-folk.txt",
-		Title:    "Poor Folk",
-		URL:      "https://www.gutenberg.org/cache/epub/2302/pg2302.txt",
-	},
-}
-
 var (
-	downloadForce bool
-	booksDir      string
+	downloadForce   bool
+	downloadBook    string
+	downloadAuthor  string
+	downloadIDs     []int64
+	downloadCatalog string
+	booksDir        string
 )
 
 var downloadCmd = &cobra.Command{
 	Use:   "download",
-	Short: "Download Dostoyevsky books from Project Gutenberg",
-	Long: `Download all Dostoyevsky books from Project Gutenberg to the books/ directory.
-
-Books downloaded:
-  - Crime and Punishment
-  - The Brothers Karamazov
-  - Notes from Underground
-  - The Idiot
-  - The Possessed (Demons)
-  - The Gambler
-  - Poor Folk`,
+	Short: "Download catalog books from Project Gutenberg",
+	Long: `Download the books in the catalog from Project Gutenberg to the books/
+directory, by their Gutenberg IDs. Books without a Gutenberg ID must be
+placed in books/ by hand.
+
+Works are found in a local copy of Gutenberg's offline catalog
+(GUTENBERG_CATALOG, from https://www.gutenberg.org/cache/epub/feeds/pg_catalog.csv):
+--author lists an author's works and translations with their IDs, and --id
+adds the chosen ones to the book catalog with their author, translator and
+language before downloading them.
+
+Downloads are retried on network and server errors. The SHA-256 of each
+text and its Gutenberg edition (release and update dates, from the text's
+header) are recorded; a later download whose text differs from the
+recorded edition is refused, because the source lines of quotes already
+extracted refer to it. Use --force to accept the new edition.
+
+Examples:
+  dostobot download                           # Download all missing books
+  dostobot download --book "White Nights"     # Download one catalog book
+  dostobot download --author Dostoyevsky      # List works in the Gutenberg catalog
+  dostobot download --id 36034 --id 37536     # Add works by ID and download them
+  dostobot download --book "The Idiot" --force  # Accept a new upstream edition`,
 	RunE: runDownload,
 }
 
 func init() {
-	downloadCmd.Flags().BoolVarP(&downloadForce, "force", "f", false, "Re-download even if file exists")
+	downloadCmd.Flags().BoolVarP(&downloadForce, "force", "f", false, "Re-download even if the file exists, accepting a changed edition")
+	downloadCmd.Flags().StringVar(&downloadBook, "book", "", "Download only this book")
+	downloadCmd.Flags().StringVar(&downloadAuthor, "author", "", "List the Gutenberg catalog's works by this author instead of downloading")
+	downloadCmd.Flags().Int64SliceVar(&downloadIDs, "id", nil, "Add these Gutenberg ebook numbers to the book catalog and download them")
+	downloadCmd.Flags().StringVar(&downloadCatalog, "catalog", "", "Gutenberg catalog CSV (default: GUTENBERG_CATALOG)")
 	downloadCmd.Flags().StringVar(&booksDir, "dir", "books", "Directory to save books")
 	rootCmd.AddCommand(downloadCmd)
 }
 
 func runDownload(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if downloadCatalog == "" {
+		downloadCatalog = cfg.GutenbergCatalog
+	}
+
+	store, err := db.NewStore(ctx, cfg.DatabasePath)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer store.Close()
+
+	if err := store.Migrate(ctx); err != nil {
+		return fmt.Errorf("run migrations: %w", err)
+	}
+
+	if downloadAuthor != "" {
+		return listGutenbergWorks(ctx, store, downloadAuthor)
+	}
+
+	var books []*db.Book
+	switch {
+	case len(downloadIDs) > 0:
+		books, err = importGutenbergWorks(ctx, store, downloadIDs)
+		if err != nil {
+			return err
+		}
+	case downloadBook != "":
+		book, err := store.GetBookByTitle(ctx, downloadBook)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("unknown book: %s (add it with 'dostobot book add')", downloadBook)
+		}
+		if err != nil {
+			return fmt.Errorf("find book: %w", err)
+		}
+		books = []*db.Book{book}
+	default:
+		books, err = store.ListBooks(ctx)
+		if err != nil {
+			return fmt.Errorf("list books: %w", err)
+		}
+	}
+
 	// Create books directory if it doesn't exist
 	if err := os.MkdirAll(booksDir, 0755); err != nil {
 		return fmt.Errorf("create books directory: %w", err)
 	}
 
-	client := &http.Client{
-		Timeout: 60 * time.Second,
-	}
+	client := gutenberg.NewClient(gutenberg.ClientConfig{BaseURL: cfg.GutenbergMirror})
 
-	fmt.Println("Downloading Dostoyevsky books from Project Gutenberg...")
+	fmt.Println("Downloading books from Project Gutenberg...")
 	fmt.Println()
 
 	downloaded := 0
 	skipped := 0
 
 	for _, book := range books {
-		path := filepath.Join(booksDir, book.Filename)
+		path := filepath.Join(booksDir, book.FilePath)
+		want := book.Checksum.String
 
 		// Check if already exists
-		if !downloadForce {
-			if _, err := os.Stat(path); err == nil {
+		if _, err := os.Stat(path); err == nil && !downloadForce {
+			sum, err := gutenberg.FileChecksum(path)
+			if err != nil {
+				return fmt.Errorf("checksum %s: %w", path, err)
+			}
+			switch {
+			case want == "":
+				// Downloaded before editions were recorded: adopt the file
+				if err := recordDownload(ctx, store, book, path, sum); err != nil {
+					return err
+				}
+				fmt.Printf("  ✓ %s (already downloaded, edition recorded)\n", book.Title)
+				skipped++
+				continue
+			case sum == want:
 				fmt.Printf("  ✓ %s (already downloaded)\n", book.Title)
 				skipped++
 				continue
+			default:
+				fmt.Printf("  ! %s was modified since it was downloaded\n", book.Title)
 			}
 		}
 
+		if !book.GutenbergID.Valid {
+			fmt.Printf("  - %s (no Gutenberg ID; place %s by hand)\n", book.Title, path)
+			skipped++
+			continue
+		}
+
+		if downloadForce {
+			want = "" // accept whatever edition Gutenberg has now
+		}
+
 		fmt.Printf("  ↓ Downloading %s...", book.Title)
 
-		if err := downloadFile(cmd.Context(), client, book.URL, path); err != nil {
+		sum, err := client.Download(ctx, book.GutenbergID.Int64, path, want)
+		if errors.Is(err, gutenberg.ErrChecksumMismatch) {
+			fmt.Printf(" ERROR: the Gutenberg text changed since it was recorded (use --force to accept the new edition)\n")
+			slog.Error("edition changed upstream", "title", book.Title, "error", err)
+			continue
+		}
+		if err != nil {
 			fmt.Printf(" ERROR: %v\n", err)
 			slog.Error("failed to download book", "title", book.Title, "error", err)
 			continue
 		}
+		if err := recordDownload(ctx, store, book, path, sum); err != nil {
+			return err
+		}
 
 		fmt.Println(" done")
 		downloaded++
@@ -133,28 +191,113 @@ func runDownload(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func downloadFile(ctx context.Context, client *http.Client, url, path string) error {
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// recordDownload stores the checksum of a book's file and the edition and
+// translator named in its Gutenberg header. A translator already in the
+// catalog is kept.
+func recordDownload(ctx context.Context, store *db.Store, book *db.Book, path, checksum string) error {
+	header, err := gutenberg.ReadHeader(path)
 	if err != nil {
-		return err
+		return fmt.Errorf("read %s: %w", path, err)
 	}
 
-	resp, err := client.Do(req)
+	edition := header.Edition()
+	if err := store.UpdateBookDownload(ctx, db.UpdateBookDownloadParams{
+		Checksum:   sql.NullString{String: checksum, Valid: true},
+		Edition:    sql.NullString{String: edition, Valid: edition != ""},
+		Translator: sql.NullString{String: header.Translator, Valid: header.Translator != ""},
+		ID:         book.ID,
+	}); err != nil {
+		return fmt.Errorf("record download of %s: %w", book.Title, err)
+	}
+	return nil
+}
+
+// listGutenbergWorks prints the works by author in the Gutenberg catalog,
+// marking the ones already in the book catalog.
+func listGutenbergWorks(ctx context.Context, store *db.Store, author string) error {
+	entries, err := gutenberg.LoadCatalog(downloadCatalog)
 	if err != nil {
-		return err
+		return fmt.Errorf("%w (download pg_catalog.csv from Project Gutenberg and set GUTENBERG_CATALOG)", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	works := gutenberg.Search(entries, author)
+	if len(works) == 0 {
+		fmt.Printf("No works by %q in %s.\n", author, downloadCatalog)
+		return nil
 	}
 
-	file, err := os.Create(path)
-	if err != nil {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTITLE\tLANG\tTRANSLATOR\tISSUED\tCATALOG")
+	for _, e := range works {
+		inCatalog := ""
+		if _, err := store.GetBookByGutenbergID(ctx, sql.NullInt64{Int64: e.ID, Valid: true}); err == nil {
+			inCatalog = "✓"
+		}
+		translator := e.Translator()
+		if translator == "" {
+			translator = "-"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", e.ID, e.Title, e.Language, translator, e.Issued, inCatalog)
+	}
+	if err := w.Flush(); err != nil {
 		return err
 	}
-	defer file.Close()
 
-	_, err = io.Copy(file, resp.Body)
-	return err
+	fmt.Println()
+	fmt.Println("Add works with 'dostobot download --id <ID>'.")
+	return nil
+}
+
+// importGutenbergWorks returns the catalog books for the given ebook
+// numbers, adding the ones not yet in the catalog from the Gutenberg
+// catalog's metadata.
+func importGutenbergWorks(ctx context.Context, store *db.Store, ids []int64) ([]*db.Book, error) {
+	var entries []gutenberg.Entry
+	var books []*db.Book
+
+	for _, id := range ids {
+		book, err := store.GetBookByGutenbergID(ctx, sql.NullInt64{Int64: id, Valid: true})
+		if err == nil {
+			books = append(books, book)
+			continue
+		}
+		if err != sql.ErrNoRows {
+			return nil, fmt.Errorf("find ebook %d: %w", id, err)
+		}
+
+		if entries == nil {
+			entries, err = gutenberg.LoadCatalog(downloadCatalog)
+			if err != nil {
+				return nil, fmt.Errorf("%w (download pg_catalog.csv from Project Gutenberg and set GUTENBERG_CATALOG)", err)
+			}
+		}
+		e, ok := gutenberg.Find(entries, id)
+		if !ok {
+			return nil, fmt.Errorf("ebook %d is not in %s", id, downloadCatalog)
+		}
+
+		if _, err := store.GetBookByTitle(ctx, e.Title); err == nil {
+			return nil, fmt.Errorf("a book titled %q is already in the catalog; add ebook %d with 'dostobot book add' under another title", e.Title, id)
+		}
+
+		language, _, _ := strings.Cut(e.Language, ";")
+		if language = strings.TrimSpace(language); language == "" {
+			language = "en"
+		}
+		book, err = store.CreateBook(ctx, db.CreateBookParams{
+			Title:       e.Title,
+			Author:      e.Author(),
+			Translator:  sql.NullString{String: e.Translator(), Valid: e.Translator() != ""},
+			GutenbergID: sql.NullInt64{Int64: id, Valid: true},
+			FilePath:    bookFileName(e.Title),
+			Language:    language,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("add ebook %d: %w", id, err)
+		}
+		fmt.Printf("Added %q by %s to the catalog\n", book.Title, book.Author)
+		books = append(books, book)
+	}
+
+	return books, nil
 }
@@ -8,22 +8,33 @@ import (
 
 	"github.com/abdulachik/dostobot/internal/config"
 	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/embedder"
 	"github.com/abdulachik/dostobot/internal/vectorstore"
 	"github.com/spf13/cobra"
 )
 
+var embedDryRun bool
+
 var embedCmd = &cobra.Command{
 	Use:   "embed",
 	Short: "Generate embeddings for quotes",
 	Long: `Generate vector embeddings for quotes and store them in VecLite.
 
+Only quotes that are missing from VecLite or whose text changed are embedded;
+records for deleted quotes are removed. Running it twice is a no-op.
+
 Uses the embedding provider configured in veclite.yaml:
   - openai: OpenAI API (requires OPENAI_API_KEY env var)
-  - ollama: Local Ollama server`,
+  - ollama: Local Ollama server
+
+Examples:
+  dostobot embed            # Sync VecLite with SQLite
+  dostobot embed --dry-run  # Show the diff without embedding anything`,
 	RunE: runEmbed,
 }
 
 func init() {
+	embedCmd.Flags().BoolVar(&embedDryRun, "dry-run", false, "Show what would change without embedding")
 	rootCmd.AddCommand(embedCmd)
 }
 
@@ -49,9 +60,18 @@ func runEmbed(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("run migrations: %w", err)
 	}
 
-	// Create VecLite store (uses veclite.yaml for embedder config)
+	// Create embedder (uses veclite.yaml, optionally overridden by EMBED_PROVIDER)
+	provider, err := embedder.LoadProvider(embedder.ProviderConfig{
+		Provider: cfg.EmbedProvider,
+		CacheDir: cfg.EmbedCacheDir,
+	})
+	if err != nil {
+		return fmt.Errorf("create embedder: %w", err)
+	}
+
 	quoteStore, err := vectorstore.New(vectorstore.Config{
-		Path: cfg.VecLitePath,
+		Path:     cfg.VecLitePath,
+		Provider: provider,
 	})
 	if err != nil {
 		return fmt.Errorf("create quote store: %w", err)
@@ -67,69 +87,55 @@ func runEmbed(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("list quotes: %w", err)
 	}
 
-	existingCount := quoteStore.Count()
-	needEmbed := len(quotes) - existingCount
-
-	if needEmbed <= 0 {
-		slog.Info("all quotes already embedded",
-			"total", len(quotes),
-			"in_veclite", existingCount,
-		)
+	plan := quoteStore.PlanSync(quotes)
+
+	fmt.Println("=== VecLite Sync Plan ===")
+	fmt.Printf("  SQLite quotes:   %d\n", len(quotes))
+	fmt.Printf("  VecLite records: %d\n", quoteStore.Count())
+	fmt.Printf("  Model:           %s/%s (%d dims)\n", provider.Name(), provider.Model(), provider.Dimension())
+	fmt.Println()
+	fmt.Printf("  + add:       %d\n", len(plan.Add))
+	fmt.Printf("  ~ re-embed:  %d\n", len(plan.Reembed))
+	fmt.Printf("  * refresh:   %d (metadata only)\n", len(plan.Refresh))
+	fmt.Printf("  - delete:    %d\n", len(plan.Delete))
+	fmt.Printf("  = unchanged: %d\n", plan.Unchanged)
+	fmt.Println()
+
+	if plan.Empty() {
+		fmt.Println("VecLite is already in sync.")
 		return nil
 	}
 
-	slog.Info("embedding quotes",
-		"total", len(quotes),
-		"already_embedded", existingCount,
-		"need_embedding", needEmbed,
-	)
-
-	// Embed quotes that aren't in VecLite yet
-	// For simplicity, we'll re-embed all since we can't easily check which are missing
-	// VecLite handles duplicates gracefully
-	start := time.Now()
-	embedded := 0
-	errors := 0
-
-	for i, q := range quotes {
-		_, err := quoteStore.InsertQuote(ctx, q)
-		if err != nil {
-			slog.Warn("failed to embed quote", "id", q.ID, "error", err)
-			errors++
-			continue
-		}
-
-		embedded++
-		if embedded%100 == 0 {
-			elapsed := time.Since(start)
-			rate := float64(embedded) / elapsed.Seconds()
-			slog.Info("progress",
-				"embedded", embedded,
-				"total", len(quotes),
-				"rate", fmt.Sprintf("%.1f/sec", rate),
-			)
-		}
-
-		// Sync periodically
-		if (i+1)%500 == 0 {
-			if err := quoteStore.Sync(); err != nil {
-				slog.Warn("failed to sync", "error", err)
-			}
-		}
+	if embedDryRun {
+		fmt.Printf("=== DRY RUN - %d quotes would be embedded ===\n", plan.EmbedCount())
+		return nil
 	}
 
-	// Final sync
-	if err := quoteStore.Sync(); err != nil {
-		return fmt.Errorf("sync: %w", err)
+	start := time.Now()
+	result, err := quoteStore.ApplySync(ctx, plan, func(done, total int) {
+		elapsed := time.Since(start)
+		slog.Info("progress",
+			"embedded", done,
+			"total", total,
+			"rate", fmt.Sprintf("%.1f/sec", float64(done)/elapsed.Seconds()),
+		)
+	})
+	if err != nil {
+		return fmt.Errorf("apply sync: %w", err)
 	}
 
-	elapsed := time.Since(start)
 	slog.Info("embedding complete",
-		"embedded", embedded,
-		"errors", errors,
-		"duration", elapsed.Round(time.Second),
-		"rate", fmt.Sprintf("%.1f/sec", float64(embedded)/elapsed.Seconds()),
+		"added", result.Added,
+		"reembedded", result.Reembedded,
+		"refreshed", result.Refreshed,
+		"deleted", result.Deleted,
+		"failed", result.Failed,
+		"duration", time.Since(start).Round(time.Second),
 	)
 
+	if result.Failed > 0 {
+		return fmt.Errorf("%d quotes failed to sync (rerun to retry)", result.Failed)
+	}
+
 	return nil
 }
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abdulachik/dostobot/internal/config"
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/evergreen"
+	"github.com/abdulachik/dostobot/internal/matcher"
+	"github.com/spf13/cobra"
+)
+
+var evergreenCmd = &cobra.Command{
+	Use:   "evergreen",
+	Short: "Manage the occasions evergreen posts are tied to",
+	Long: `With EVERGREEN_FALLBACK on, a post slot with no queued match posts an
+evergreen quote instead, up to EVERGREEN_MAX_PER_DAY a day. The quote is the
+best one posted longest ago among those of the day's occasions, tried most
+specific first: a date, a month, a range of months, then a day of the week.
+Days without an occasion quote use the day's theme from EVERGREEN_THEMES,
+and then any quote. Days are taken in POST_TIMEZONE.`,
+}
+
+var evergreenListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the occasions",
+	Args:  cobra.NoArgs,
+	RunE:  runEvergreenList,
+}
+
+var evergreenAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add an occasion",
+	Long: `Add an occasion. It falls on the days matching all of its --month,
+--day and --weekday, and narrows the quotes posted to its --author, --book
+and --theme. The name is recorded as the post's trend title.
+
+Examples:
+  dostobot evergreen add "Tolstoy's birthday" --month 9 --day 9 --author "Leo Tolstoy"
+  dostobot evergreen add "Winter nights" --month 12 --end-month 2 --theme loneliness
+  dostobot evergreen add "Friday" --weekday 5 --book "White Nights"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEvergreenAdd,
+}
+
+var evergreenRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove an occasion",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runEvergreenRemove,
+}
+
+var evergreenPickCmd = &cobra.Command{
+	Use:   "pick",
+	Short: "Show the evergreen quote a post slot would post",
+	Args:  cobra.NoArgs,
+	RunE:  runEvergreenPick,
+}
+
+var (
+	evergreenMonth    int
+	evergreenEndMonth int
+	evergreenDay      int
+	evergreenWeekday  int
+	evergreenAuthor   string
+	evergreenBook     string
+	evergreenTheme    string
+	evergreenDate     string
+)
+
+func init() {
+	evergreenAddCmd.Flags().IntVar(&evergreenMonth, "month", 0, "Month, 1-12")
+	evergreenAddCmd.Flags().IntVar(&evergreenEndMonth, "end-month", 0, "Last month of a range starting at --month, 1-12")
+	evergreenAddCmd.Flags().IntVar(&evergreenDay, "day", 0, "Day of the month, 1-31")
+	evergreenAddCmd.Flags().IntVar(&evergreenWeekday, "weekday", -1, "Day of the week, 0 (Sunday) to 6")
+	evergreenAddCmd.Flags().StringVar(&evergreenAuthor, "author", "", "Only post quotes from books by this author")
+	evergreenAddCmd.Flags().StringVar(&evergreenBook, "book", "", "Only post quotes from this book")
+	evergreenAddCmd.Flags().StringVar(&evergreenTheme, "theme", "", "Only post quotes with this theme")
+	evergreenPickCmd.Flags().StringVar(&evergreenDate, "date", "", "Day to pick for, as YYYY-MM-DD (default today)")
+	evergreenCmd.AddCommand(evergreenListCmd, evergreenAddCmd, evergreenRemoveCmd, evergreenPickCmd)
+	rootCmd.AddCommand(evergreenCmd)
+}
+
+func runEvergreenList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	occasions, err := store.ListEvergreenOccasions(ctx)
+	if err != nil {
+		return fmt.Errorf("list occasions: %w", err)
+	}
+	if len(occasions) == 0 {
+		fmt.Println("No occasions.")
+		return nil
+	}
+	for _, o := range occasions {
+		fmt.Printf("%-55s %s\n", o.Name, describeOccasion(o))
+	}
+	return nil
+}
+
+// describeOccasion returns when occasion o falls and what it posts.
+func describeOccasion(o *db.EvergreenOccasion) string {
+	var parts []string
+	if o.Month.Valid {
+		when := time.Month(o.Month.Int64).String()
+		if o.Day.Valid {
+			when += fmt.Sprintf(" %d", o.Day.Int64)
+		}
+		if o.EndMonth.Valid && o.EndMonth.Int64 != o.Month.Int64 {
+			when += "-" + time.Month(o.EndMonth.Int64).String()
+		}
+		parts = append(parts, when)
+	} else if o.Day.Valid {
+		parts = append(parts, fmt.Sprintf("day %d", o.Day.Int64))
+	}
+	if o.Weekday.Valid {
+		parts = append(parts, time.Weekday(o.Weekday.Int64).String()+"s")
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "every day")
+	}
+	if o.Author.Valid {
+		parts = append(parts, "author: "+o.Author.String)
+	}
+	if o.SourceBook.Valid {
+		parts = append(parts, "book: "+o.SourceBook.String)
+	}
+	if o.Theme.Valid {
+		parts = append(parts, "theme: "+o.Theme.String)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func runEvergreenAdd(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if evergreenMonth < 0 || evergreenMonth > 12 {
+		return fmt.Errorf("invalid month %d", evergreenMonth)
+	}
+	if evergreenEndMonth < 0 || evergreenEndMonth > 12 || (evergreenEndMonth > 0 && evergreenMonth == 0) {
+		return fmt.Errorf("invalid end month %d: it needs --month", evergreenEndMonth)
+	}
+	if evergreenDay < 0 || evergreenDay > 31 {
+		return fmt.Errorf("invalid day %d", evergreenDay)
+	}
+	if evergreenWeekday < -1 || evergreenWeekday > 6 {
+		return fmt.Errorf("invalid weekday %d", evergreenWeekday)
+	}
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	optInt := func(n, none int) sql.NullInt64 { return sql.NullInt64{Int64: int64(n), Valid: n != none} }
+	optString := func(s string) sql.NullString { return sql.NullString{String: s, Valid: s != ""} }
+	if err := store.CreateEvergreenOccasion(ctx, db.CreateEvergreenOccasionParams{
+		Name:       args[0],
+		Month:      optInt(evergreenMonth, 0),
+		EndMonth:   optInt(evergreenEndMonth, 0),
+		Day:        optInt(evergreenDay, 0),
+		Weekday:    optInt(evergreenWeekday, -1),
+		Author:     optString(evergreenAuthor),
+		SourceBook: optString(evergreenBook),
+		Theme:      optString(evergreenTheme),
+	}); err != nil {
+		return fmt.Errorf("add occasion: %w", err)
+	}
+	fmt.Printf("Added occasion %q.\n", args[0])
+	return nil
+}
+
+func runEvergreenRemove(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	n, err := store.DeleteEvergreenOccasion(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("remove occasion: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("no occasion named %q", args[0])
+	}
+	fmt.Printf("Removed occasion %q.\n", args[0])
+	return nil
+}
+
+func runEvergreenPick(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	loc, err := time.LoadLocation(cfg.PostTimezone)
+	if err != nil {
+		return fmt.Errorf("invalid POST_TIMEZONE: %w", err)
+	}
+	day := time.Now().In(loc)
+	if evergreenDate != "" {
+		day, err = time.ParseInLocation("2006-01-02", evergreenDate, loc)
+		if err != nil {
+			return fmt.Errorf("invalid date: %w", err)
+		}
+	}
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	m := matcher.New(matcher.Config{
+		Store:        store,
+		Language:     cfg.PostLanguage,
+		ApprovedOnly: cfg.CurationMode,
+		Themes:       cfg.PostThemes,
+	})
+	choice, err := evergreen.Pick(ctx, store.Queries, day, cfg.EvergreenThemes, m.Eligible)
+	if err != nil {
+		return err
+	}
+	if choice == nil {
+		fmt.Println("No evergreen quote to post.")
+		return nil
+	}
+
+	title := choice.Title
+	if title == "" {
+		title = "(none)"
+	}
+	fmt.Printf("Day:      %s\n", day.Format("Mon 2006-01-02"))
+	fmt.Printf("Occasion: %s\n", title)
+	fmt.Printf("Quote:    #%d from %s\n", choice.Quote.ID, choice.Quote.SourceBook)
+	fmt.Printf("\n%s\n", choice.Quote.Text)
+	return nil
+}
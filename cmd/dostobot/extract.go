@@ -7,29 +7,66 @@ import (
 
 	"github.com/abdulachik/dostobot/internal/config"
 	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/embedder"
 	"github.com/abdulachik/dostobot/internal/extractor"
+	"github.com/abdulachik/dostobot/internal/llm"
 	"github.com/spf13/cobra"
 )
 
 var (
-	extractAll  bool
-	extractBook string
+	extractAll   bool
+	extractBook  string
+	extractForce bool
+
+	extractConcurrency int
+	extractBudget      float64
+	extractValidate    bool
 )
 
 var extractCmd = &cobra.Command{
 	Use:   "extract",
 	Short: "Extract quotes from books",
-	Long: `Extract memorable quotes from Dostoyevsky books using Claude AI.
+	Long: `Extract memorable quotes from Dostoyevsky books using the LLM (EXTRACT_MODEL).
+
+Interrupted runs resume from the last processed chunk of the same book file.
+Books that were already fully extracted are skipped unless --force is given.
+Use "dostobot jobs" to see job history.
+
+Every extracted quote is aligned against the book text: small slips are
+corrected to the verbatim original and its source lines are recorded;
+quotes that cannot be found (paraphrased or invented) are dropped.
+
+Chunks are sent to the LLM in parallel (EXTRACT_CONCURRENCY, default 4) and
+paced to the API's rate limits. With a budget (EXTRACT_BUDGET_USD or
+--budget) extraction stops before any request that could exceed it; the
+interrupted job resumes on the next run.
+
+With --validate (or EXTRACT_VALIDATE=true) every new quote is reviewed by
+VALIDATE_MODEL before it is saved; rejected quotes are kept but never
+matched. Quotes saved without a review can be checked later with
+"dostobot validate".
+
+Quotes already stored for the book are not saved again: neither the same
+passage trimmed differently, nor one sharing at least half its text whose
+embedding is nearly identical, as with "dostobot dedupe". Without an
+embedding provider only the text is compared.
 
 Examples:
   dostobot extract --all                    # Extract from all books
-  dostobot extract --book "Crime and Punishment"  # Extract from specific book`,
+  dostobot extract --book "Crime and Punishment"  # Extract from specific book
+  dostobot extract --book "The Idiot" --force     # Re-extract a finished book
+  dostobot extract --all --concurrency 8 --budget 5  # Faster, capped at $5
+  dostobot extract --book "Poor Folk" --validate  # Review quotes as they are found`,
 	RunE: runExtract,
 }
 
 func init() {
 	extractCmd.Flags().BoolVar(&extractAll, "all", false, "Extract from all books")
 	extractCmd.Flags().StringVar(&extractBook, "book", "", "Extract from specific book")
+	extractCmd.Flags().BoolVar(&extractForce, "force", false, "Re-extract books that were already completed")
+	extractCmd.Flags().IntVar(&extractConcurrency, "concurrency", 0, "Chunks to process in parallel (default: EXTRACT_CONCURRENCY)")
+	extractCmd.Flags().Float64Var(&extractBudget, "budget", 0, "Maximum API spend in USD (default: EXTRACT_BUDGET_USD)")
+	extractCmd.Flags().BoolVar(&extractValidate, "validate", false, "Review each new quote before saving it (default: EXTRACT_VALIDATE)")
 	rootCmd.AddCommand(extractCmd)
 }
 
@@ -59,19 +96,75 @@ func runExtract(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("must specify --all or --book")
 	}
 
+	if cmd.Flags().Changed("concurrency") {
+		cfg.ExtractConcurrency = extractConcurrency
+	}
+	if cmd.Flags().Changed("budget") {
+		cfg.ExtractBudgetUSD = extractBudget
+	}
+	if cmd.Flags().Changed("validate") {
+		cfg.ExtractValidate = extractValidate
+	}
+
 	slog.Info("starting quote extraction",
 		"all", extractAll,
 		"book", extractBook,
+		"force", extractForce,
+		"concurrency", cfg.ExtractConcurrency,
+		"budget_usd", cfg.ExtractBudgetUSD,
+		"model", cfg.ExtractModel,
+		"validate", cfg.ExtractValidate,
 	)
 
+	budget := llm.NewBudget(cfg.ExtractBudgetUSD)
+	backend := llm.Backend{
+		Provider: cfg.LLMProvider,
+		BaseURL:  cfg.LLMBaseURL,
+		APIKey:   cfg.LLMAPIKey,
+		Limiter:  llm.NewRateLimiter(cfg.ExtractRequestsPerMinute),
+		Budget:   budget,
+	}
+	extractLLM, err := backend.New(cfg.ExtractModel)
+	if err != nil {
+		return fmt.Errorf("create extraction model: %w", err)
+	}
+
+	// The review shares the budget and rate limit with extraction
+	var validateLLM llm.Completer
+	if cfg.ExtractValidate {
+		validateLLM, err = backend.New(cfg.ValidateModel)
+		if err != nil {
+			return fmt.Errorf("create validation model: %w", err)
+		}
+	}
+
+	// Embeddings confirm near-duplicates that share only part of their text
+	provider, err := embedder.LoadProvider(embedder.ProviderConfig{
+		Provider: cfg.EmbedProvider,
+		CacheDir: cfg.EmbedCacheDir,
+	})
+	if err != nil {
+		slog.Warn("no embedding provider, checking near-duplicates by text only", "error", err)
+		provider = nil
+	}
+
 	ext := extractor.New(extractor.Config{
-		Store:    store,
-		APIKey:   cfg.AnthropicAPIKey,
-		BooksDir: "books",
+		Store:       store,
+		BooksDir:    "books",
+		LLM:         extractLLM,
+		Validator:   validateLLM,
+		Budget:      budget,
+		Force:       extractForce,
+		Concurrency: cfg.ExtractConcurrency,
+		Embedder:    provider,
 	})
 
 	if extractAll {
-		return ext.ExtractAll(ctx)
+		err = ext.ExtractAll(ctx)
+	} else {
+		err = ext.ExtractBook(ctx, extractBook)
 	}
-	return ext.ExtractBook(ctx, extractBook)
+
+	slog.Info("extraction spend", "usd", fmt.Sprintf("%.2f", ext.Spent()))
+	return err
 }
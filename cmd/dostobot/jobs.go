@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/abdulachik/dostobot/internal/config"
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var jobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "Show extraction job history",
+	Long: `List quote extraction jobs, newest first, with their progress.
+
+Jobs that are still "running" or "failed" are resumed from their last
+processed chunk the next time the same book file is extracted.`,
+	RunE: runJobs,
+}
+
+func init() {
+	rootCmd.AddCommand(jobsCmd)
+}
+
+func runJobs(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("validate config: %w", err)
+	}
+
+	store, err := db.NewStore(ctx, cfg.DatabasePath)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer store.Close()
+
+	if err := store.Migrate(ctx); err != nil {
+		return fmt.Errorf("run migrations: %w", err)
+	}
+
+	jobs, err := store.ListExtractionJobs(ctx)
+	if err != nil {
+		return fmt.Errorf("list extraction jobs: %w", err)
+	}
+
+	if len(jobs) == 0 {
+		fmt.Println("No extraction jobs yet.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tBOOK\tSTATUS\tCHUNKS\tQUOTES\tSTARTED\tFINISHED")
+	for _, job := range jobs {
+		chunks := fmt.Sprintf("%d", job.ProcessedChunks.Int64)
+		if job.TotalChunks.Valid {
+			chunks = fmt.Sprintf("%d/%d", job.ProcessedChunks.Int64, job.TotalChunks.Int64)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			job.ID,
+			job.BookTitle,
+			job.Status.String,
+			chunks,
+			job.QuotesExtracted.Int64,
+			formatJobTime(job.StartedAt),
+			formatJobTime(job.CompletedAt),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		if job.ErrorMessage.Valid && job.ErrorMessage.String != "" {
+			fmt.Printf("\nJob %d error: %s\n", job.ID, job.ErrorMessage.String)
+		}
+	}
+
+	return nil
+}
+
+// formatJobTime renders an optional timestamp for the jobs table.
+func formatJobTime(t sql.NullTime) string {
+	if !t.Valid {
+		return "-"
+	}
+	return t.Time.Local().Format("2006-01-02 15:04")
+}
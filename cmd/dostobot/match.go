@@ -8,7 +8,9 @@ import (
 	"github.com/abdulachik/dostobot/internal/config"
 	"github.com/abdulachik/dostobot/internal/db"
 	"github.com/abdulachik/dostobot/internal/embedder"
+	"github.com/abdulachik/dostobot/internal/llm"
 	"github.com/abdulachik/dostobot/internal/matcher"
+	"github.com/abdulachik/dostobot/internal/themes"
 	"github.com/abdulachik/dostobot/internal/vectorstore"
 	"github.com/spf13/cobra"
 )
@@ -18,13 +20,20 @@ var matchCmd = &cobra.Command{
 	Short: "Test matching with a trend",
 	Long: `Test the quote matching system with a given trend string.
 
-Example:
-  dostobot match "Political scandal shakes the nation"`,
+Examples:
+  dostobot match "Political scandal shakes the nation"
+  dostobot match --theme justice --theme power "Political scandal shakes the nation"
+
+--theme only considers quotes with one of the given themes, overriding
+POST_THEMES. Aliases are resolved to their themes.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMatch,
 }
 
+var matchThemes []string
+
 func init() {
+	matchCmd.Flags().StringSliceVar(&matchThemes, "theme", nil, "Only match quotes with one of these themes (overrides POST_THEMES)")
 	rootCmd.AddCommand(matchCmd)
 }
 
@@ -37,7 +46,7 @@ func runMatch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("load config: %w", err)
 	}
 
-	if err := cfg.ValidateForExtraction(); err != nil {
+	if err := cfg.ValidateForSelection(); err != nil {
 		return fmt.Errorf("validate config: %w", err)
 	}
 
@@ -55,19 +64,35 @@ func runMatch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("run migrations: %w", err)
 	}
 
-	slog.Info("matching trend", "trend", trendText)
+	onlyThemes := cfg.PostThemes
+	if len(matchThemes) > 0 {
+		onlyThemes = matchThemes
+	}
+	if len(onlyThemes) > 0 {
+		taxonomy, err := themes.Load(ctx, store.Queries)
+		if err != nil {
+			return err
+		}
+		onlyThemes = taxonomy.Normalize(onlyThemes)
+	}
+
+	slog.Info("matching trend", "trend", trendText, "themes", onlyThemes)
 
-	// Create embedder
-	emb := embedder.New(embedder.Config{
-		Host:  cfg.OllamaHost,
-		Model: cfg.OllamaModel,
+	// Create embedder (shared by VecLite and the in-memory fallback)
+	provider, err := embedder.LoadProvider(embedder.ProviderConfig{
+		Provider: cfg.EmbedProvider,
+		CacheDir: cfg.EmbedCacheDir,
 	})
+	if err != nil {
+		return fmt.Errorf("create embedder: %w", err)
+	}
 
 	// Check if VecLite is available
 	var quoteStore *vectorstore.QuoteStore
 	if cfg.VecLitePath != "" {
 		quoteStore, err = vectorstore.NewReadOnly(vectorstore.Config{
-			Path: cfg.VecLitePath,
+			Path:     cfg.VecLitePath,
+			Provider: provider,
 		})
 		if err != nil {
 			slog.Warn("failed to open VecLite, falling back to in-memory", "error", err)
@@ -77,12 +102,27 @@ func runMatch(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Create the selection (and optional trend screening) models
+	backend := llm.Backend{Provider: cfg.LLMProvider, BaseURL: cfg.LLMBaseURL, APIKey: cfg.LLMAPIKey}
+	selectLLM, err := backend.New(cfg.SelectModel)
+	if err != nil {
+		return fmt.Errorf("create selection model: %w", err)
+	}
+	filterLLM, err := backend.NewOptional(cfg.FilterModel)
+	if err != nil {
+		return fmt.Errorf("create filter model: %w", err)
+	}
+
 	// Create matcher
 	m := matcher.New(matcher.Config{
-		Store:      store,
-		Embedder:   emb,
-		QuoteStore: quoteStore,
-		APIKey:     cfg.AnthropicAPIKey,
+		Store:        store,
+		Embedder:     provider,
+		QuoteStore:   quoteStore,
+		LLM:          selectLLM,
+		FilterLLM:    filterLLM,
+		Language:     cfg.PostLanguage,
+		ApprovedOnly: cfg.CurationMode,
+		Themes:       onlyThemes,
 	})
 
 	// Match the text
@@ -3,15 +3,18 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 
 	"github.com/abdulachik/dostobot/internal/config"
 	"github.com/abdulachik/dostobot/internal/db"
 	"github.com/abdulachik/dostobot/internal/embedder"
+	"github.com/abdulachik/dostobot/internal/llm"
 	"github.com/abdulachik/dostobot/internal/matcher"
 	"github.com/abdulachik/dostobot/internal/monitor"
 	"github.com/abdulachik/dostobot/internal/poster"
+	"github.com/abdulachik/dostobot/internal/scheduler"
 	"github.com/abdulachik/dostobot/internal/vectorstore"
 	"github.com/spf13/cobra"
 )
@@ -52,6 +55,10 @@ func runPost(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("validate config: %w", err)
 	}
 
+	if err := cfg.ValidateForSelection(); err != nil {
+		return fmt.Errorf("validate config: %w", err)
+	}
+
 	store, err := db.NewStore(ctx, cfg.DatabasePath)
 	if err != nil {
 		return fmt.Errorf("connect to database: %w", err)
@@ -64,16 +71,21 @@ func runPost(cmd *cobra.Command, args []string) error {
 
 	slog.Info("starting post workflow", "dry_run", postDryRun)
 
-	// Create embedder (for fallback)
-	emb := embedder.New(embedder.Config{
-		Host: cfg.OllamaHost,
+	// Create embedder (shared by VecLite and the in-memory fallback)
+	provider, err := embedder.LoadProvider(embedder.ProviderConfig{
+		Provider: cfg.EmbedProvider,
+		CacheDir: cfg.EmbedCacheDir,
 	})
+	if err != nil {
+		return fmt.Errorf("create embedder: %w", err)
+	}
 
 	// Create VecLite store if configured
 	var quoteStore *vectorstore.QuoteStore
 	if cfg.VecLitePath != "" {
 		quoteStore, err = vectorstore.New(vectorstore.Config{
-			Path: cfg.VecLitePath,
+			Path:     cfg.VecLitePath,
+			Provider: provider,
 		})
 		if err != nil {
 			slog.Warn("failed to open VecLite, falling back to in-memory", "error", err)
@@ -83,12 +95,27 @@ func runPost(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Create the selection (and optional trend screening) models
+	backend := llm.Backend{Provider: cfg.LLMProvider, BaseURL: cfg.LLMBaseURL, APIKey: cfg.LLMAPIKey}
+	selectLLM, err := backend.New(cfg.SelectModel)
+	if err != nil {
+		return fmt.Errorf("create selection model: %w", err)
+	}
+	filterLLM, err := backend.NewOptional(cfg.FilterModel)
+	if err != nil {
+		return fmt.Errorf("create filter model: %w", err)
+	}
+
 	// Create matcher
 	m := matcher.New(matcher.Config{
-		Store:      store,
-		Embedder:   emb,
-		QuoteStore: quoteStore,
-		APIKey:     cfg.AnthropicAPIKey,
+		Store:        store,
+		Embedder:     provider,
+		QuoteStore:   quoteStore,
+		LLM:          selectLLM,
+		FilterLLM:    filterLLM,
+		Language:     cfg.PostLanguage,
+		ApprovedOnly: cfg.CurationMode,
+		Themes:       cfg.PostThemes,
 	})
 
 	// Monitor for trends
@@ -139,6 +166,9 @@ func runPost(cmd *cobra.Command, args []string) error {
 
 		result, err := m.Match(ctx, trend)
 		if err != nil {
+			if errors.Is(err, llm.ErrAuth) || llm.IsRetryable(err) {
+				return fmt.Errorf("match trend %q: %w", trend.Title, err)
+			}
 			slog.Warn("match failed", "trend", trend.Title, "error", err)
 			continue
 		}
@@ -155,17 +185,13 @@ func runPost(cmd *cobra.Command, args []string) error {
 	}
 
 	// Format the post
-	character := ""
-	if bestMatch.Quote.Character.Valid {
-		character = bestMatch.Quote.Character.String
-	}
-	formatted := poster.FormatQuote(bestMatch.Quote.Text, bestMatch.Quote.SourceBook, character)
+	content := scheduler.FormatPost(ctx, store, bestMatch.Quote, bestMatch.Trend.Title, cfg.PostBilingual)
 
 	// Display what we're posting
 	fmt.Println()
 	fmt.Println("=== Post Content ===")
 	fmt.Println()
-	fmt.Println(formatted)
+	fmt.Println(content.Text)
 	fmt.Println()
 	fmt.Printf("Trend: %s\n", bestMatch.Trend.Title)
 	fmt.Printf("Similarity: %.2f\n", bestMatch.VectorSimilarity)
@@ -183,12 +209,7 @@ func runPost(cmd *cobra.Command, args []string) error {
 		AppPassword: cfg.BlueskyAppPassword,
 	})
 
-	result, err := bsPoster.Post(ctx, poster.PostContent{
-		Text:       formatted,
-		QuoteText:  bestMatch.Quote.Text,
-		SourceBook: bestMatch.Quote.SourceBook,
-		TrendTitle: bestMatch.Trend.Title,
-	})
+	result, err := bsPoster.Post(ctx, content)
 	if err != nil {
 		return fmt.Errorf("post to Bluesky: %w", err)
 	}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/abdulachik/dostobot/internal/characters"
+	"github.com/abdulachik/dostobot/internal/config"
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/embedder"
+	"github.com/abdulachik/dostobot/internal/vectorstore"
+	"github.com/spf13/cobra"
+)
+
+var quoteCmd = &cobra.Command{
+	Use:   "quote",
+	Short: "Inspect stored quotes",
+}
+
+var quoteShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a quote with its translations and variants",
+	Long: `Show a quote with its source, quality review and posting history, the
+original it translates and its translations (from 'dostobot align'), and
+the other editions' renderings of the same passage (from 'dostobot variants').
+
+Examples:
+  dostobot quote show 42`,
+	Args: cobra.ExactArgs(1),
+	RunE: runQuoteShow,
+}
+
+var quoteSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search the vector index for quotes",
+	Long: `Search the vector index for the quotes closest to a query, optionally
+only those of a book or of one of its characters. The character may be any
+of its aliases in the book's registry ('dostobot character list').
+
+Examples:
+  dostobot quote search "freedom and suffering"
+  dostobot quote search "poverty" --book "Crime and Punishment" --character Rodya`,
+	Args: cobra.ExactArgs(1),
+	RunE: runQuoteSearch,
+}
+
+var (
+	quoteSearchBook      string
+	quoteSearchCharacter string
+	quoteSearchLimit     int
+)
+
+func init() {
+	quoteSearchCmd.Flags().StringVar(&quoteSearchBook, "book", "", "Only search quotes from this book")
+	quoteSearchCmd.Flags().StringVar(&quoteSearchCharacter, "character", "", "Only search quotes of this character (needs --book)")
+	quoteSearchCmd.Flags().IntVarP(&quoteSearchLimit, "limit", "k", 10, "Number of quotes to show")
+	quoteCmd.AddCommand(quoteShowCmd, quoteSearchCmd)
+	rootCmd.AddCommand(quoteCmd)
+}
+
+func runQuoteShow(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid quote id: %s", args[0])
+	}
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	q, err := store.GetQuote(ctx, id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("unknown quote: %d", id)
+	}
+	if err != nil {
+		return fmt.Errorf("get quote: %w", err)
+	}
+
+	fmt.Printf("#%d  %s\n\n", q.ID, q.Text)
+	fmt.Printf("Book: %s (%s)\n", q.SourceBook, q.Language)
+	if q.Chapter.Valid {
+		fmt.Printf("Chapter: %s\n", q.Chapter.String)
+	}
+	if q.Character.Valid {
+		fmt.Printf("Character: %s\n", q.Character.String)
+	}
+	if q.SourceStartLine.Valid {
+		fmt.Printf("Lines: %d-%d\n", q.SourceStartLine.Int64, q.SourceEndLine.Int64)
+	}
+	fmt.Printf("Themes: %s\n", q.Themes)
+	if q.QualityVerdict.Valid {
+		fmt.Printf("Quality: %s (%d)\n", q.QualityVerdict.String, q.QualityScore.Int64)
+	}
+	if q.CurationStatus.Valid {
+		fmt.Printf("Curation: %s\n", q.CurationStatus.String)
+	}
+	if q.Favorite {
+		fmt.Println("Favorite: yes")
+	}
+	fmt.Printf("Posted: %d times\n", q.TimesPosted.Int64)
+
+	original, err := store.GetQuoteOriginal(ctx, q.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("get original: %w", err)
+	}
+	if err == nil {
+		fmt.Println()
+		fmt.Println("Translates:")
+		printRelatedQuote(original)
+	}
+
+	translations, err := store.ListQuoteTranslations(ctx, q.ID)
+	if err != nil {
+		return fmt.Errorf("list translations: %w", err)
+	}
+	if len(translations) > 0 {
+		fmt.Println()
+		fmt.Println("Translations:")
+		for _, t := range translations {
+			printRelatedQuote(t)
+		}
+	}
+
+	if q.GroupID.Valid {
+		group, err := store.ListGroupQuotes(ctx, q.GroupID)
+		if err != nil {
+			return fmt.Errorf("list variants: %w", err)
+		}
+		if len(group) > 1 {
+			fmt.Println()
+			fmt.Println("Variants:")
+			for _, v := range group {
+				if v.ID != q.ID {
+					printRelatedQuote(v)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func runQuoteSearch(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	query := args[0]
+
+	if quoteSearchCharacter != "" && quoteSearchBook == "" {
+		return fmt.Errorf("--character needs --book")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if err := cfg.ValidateForVecLite(); err != nil {
+		return fmt.Errorf("validate config: %w", err)
+	}
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	provider, err := embedder.LoadProvider(embedder.ProviderConfig{
+		Provider: cfg.EmbedProvider,
+		CacheDir: cfg.EmbedCacheDir,
+	})
+	if err != nil {
+		return fmt.Errorf("create embedder: %w", err)
+	}
+	quoteStore, err := vectorstore.NewReadOnly(vectorstore.Config{
+		Path:     cfg.VecLitePath,
+		Provider: provider,
+	})
+	if err != nil {
+		return fmt.Errorf("open vector index: %w", err)
+	}
+	defer quoteStore.Close()
+
+	var results []vectorstore.SearchResult
+	switch {
+	case quoteSearchCharacter != "":
+		// The index holds canonical names, so resolve aliases first
+		var cast *characters.Registry
+		if cast, err = characters.Load(ctx, store.Queries, quoteSearchBook); err != nil {
+			return err
+		}
+		character := cast.Canonical(quoteSearchCharacter)
+		results, err = quoteStore.SearchByCharacter(ctx, query, quoteSearchBook, character, quoteSearchLimit)
+	case quoteSearchBook != "":
+		results, err = quoteStore.SearchByBook(ctx, query, quoteSearchBook, quoteSearchLimit)
+	default:
+		results, err = quoteStore.Search(ctx, query, quoteSearchLimit)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No quotes found.")
+		return nil
+	}
+	for _, r := range results {
+		speaker := r.Book
+		if r.Character != "" {
+			speaker = r.Character + ", " + r.Book
+		}
+		fmt.Printf("#%d (%.3f, %s): %s\n", r.SQLiteID, r.Similarity, speaker, r.Text)
+	}
+	return nil
+}
+
+// printRelatedQuote prints a quote related to the one shown, on one
+// indented entry.
+func printRelatedQuote(q *db.Quote) {
+	fmt.Printf("  #%d (%s, %s): %s\n", q.ID, q.SourceBook, q.Language, q.Text)
+}
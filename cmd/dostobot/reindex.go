@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/abdulachik/dostobot/internal/config"
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/embedder"
+	"github.com/abdulachik/dostobot/internal/vectorstore"
+	"github.com/spf13/cobra"
+)
+
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild the vector index from scratch",
+	Long: `Rebuild the VecLite index with the currently configured embedding provider.
+
+Use this after changing the embedder in veclite.yaml (or EMBED_PROVIDER):
+vectors from different models cannot be mixed, so every quote is embedded
+again. The new index is built in a temporary file next to the old one,
+checked against the quote count in SQLite, and then atomically swapped in.
+If anything fails the existing index is left untouched.
+
+A running daemon keeps using the old index until it receives SIGHUP
+(systemctl reload dostobot).
+
+Examples:
+  dostobot reindex
+  EMBED_PROVIDER=ollama dostobot reindex`,
+	RunE: runReindex,
+}
+
+func init() {
+	rootCmd.AddCommand(reindexCmd)
+}
+
+func runReindex(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	if err := cfg.ValidateForVecLite(); err != nil {
+		return fmt.Errorf("validate config: %w", err)
+	}
+
+	store, err := db.NewStore(ctx, cfg.DatabasePath)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer store.Close()
+
+	if err := store.Migrate(ctx); err != nil {
+		return fmt.Errorf("run migrations: %w", err)
+	}
+
+	provider, err := embedder.LoadProvider(embedder.ProviderConfig{
+		Provider: cfg.EmbedProvider,
+		CacheDir: cfg.EmbedCacheDir,
+	})
+	if err != nil {
+		return fmt.Errorf("create embedder: %w", err)
+	}
+
+	quotes, err := store.ListQuotes(ctx, db.ListQuotesParams{
+		Limit:  100000, // Get all quotes
+		Offset: 0,
+	})
+	if err != nil {
+		return fmt.Errorf("list quotes: %w", err)
+	}
+
+	slog.Info("rebuilding vector index",
+		"path", cfg.VecLitePath,
+		"quotes", len(quotes),
+		"provider", provider.Name(),
+		"model", provider.Model(),
+	)
+
+	start := time.Now()
+	result, err := vectorstore.Reindex(ctx, vectorstore.Config{
+		Path:     cfg.VecLitePath,
+		Provider: provider,
+	}, quotes, func(done, total int) {
+		slog.Info("progress", "embedded", done, "total", total)
+	})
+	if err != nil {
+		return fmt.Errorf("reindex: %w", err)
+	}
+
+	fmt.Println()
+	fmt.Println("=== Reindex Complete ===")
+	fmt.Printf("  Path:     %s\n", cfg.VecLitePath)
+	fmt.Printf("  Quotes:   %d\n", result.Indexed)
+	fmt.Printf("  Model:    %s/%s (%d dims)\n", provider.Name(), result.Model, result.Dim)
+	fmt.Printf("  Duration: %s\n", time.Since(start).Round(time.Second))
+	fmt.Println()
+	fmt.Println("Send SIGHUP to a running daemon to pick up the new index.")
+
+	return nil
+}
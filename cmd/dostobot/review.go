@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/abdulachik/dostobot/internal/config"
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/review"
+	"github.com/spf13/cobra"
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Review matches waiting to be posted",
+	Long: `The daemon queues the matches it finds, and each post slot posts the
+approved match with the highest relevance. With POST_APPROVAL on, matches
+wait for approval unless they score at least AUTO_POST_SCORE, and expire
+after APPROVAL_TTL; otherwise they are queued approved and expire after
+MATCH_TTL.`,
+}
+
+var reviewListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List matches waiting for review or to be posted",
+	Args:  cobra.NoArgs,
+	RunE:  runReviewList,
+}
+
+var reviewApproveCmd = &cobra.Command{
+	Use:   "approve <id>...",
+	Short: "Approve matches for posting",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runReviewAction(args, review.Approve, "Approved")
+	},
+}
+
+var reviewRejectCmd = &cobra.Command{
+	Use:   "reject <id>...",
+	Short: "Reject matches",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runReviewAction(args, review.Reject, "Rejected")
+	},
+}
+
+var reviewEditCmd = &cobra.Command{
+	Use:   "edit <id> <text>",
+	Short: "Replace the text of a match's post",
+	Long: `Replace the text of a match's post. The match keeps its status, so an
+approved match is posted with the new text.
+
+Examples:
+  dostobot review edit 12 "$(cat post.txt)"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runReviewEdit,
+}
+
+var reviewWebCmd = &cobra.Command{
+	Use:   "web",
+	Short: "Serve the review page",
+	Long: `Serve a page listing the open matches, to approve, reject or edit them
+in a browser. The page has no authentication; keep it on a local address.`,
+	Args: cobra.NoArgs,
+	RunE: runReviewWeb,
+}
+
+var reviewAddr string
+
+func init() {
+	reviewWebCmd.Flags().StringVar(&reviewAddr, "addr", "", "Address to listen on (default REVIEW_ADDR)")
+	reviewCmd.AddCommand(reviewListCmd, reviewApproveCmd, reviewRejectCmd, reviewEditCmd, reviewWebCmd)
+	rootCmd.AddCommand(reviewCmd)
+}
+
+func runReviewList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	posts, err := review.Open(ctx, store)
+	if err != nil {
+		return fmt.Errorf("list pending posts: %w", err)
+	}
+	if len(posts) == 0 {
+		fmt.Println("Nothing to review.")
+		return nil
+	}
+
+	for _, p := range posts {
+		fmt.Printf("#%d  %s  score %.2f  expires %s\n", p.ID, p.Status, p.RelevanceScore, p.ExpiresAt.Local().Format("2006-01-02 15:04"))
+		fmt.Printf("    Trend: [%s] %s\n", p.TrendSource, truncate(p.TrendTitle, 70))
+		if p.RelevanceReasoning.Valid {
+			fmt.Printf("    Why: %s\n", truncate(p.RelevanceReasoning.String, 70))
+		}
+		for _, line := range strings.Split(p.Text, "\n") {
+			fmt.Printf("    | %s\n", line)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func runReviewAction(args []string, action func(context.Context, *db.Store, int64) error, done string) error {
+	ctx := context.Background()
+
+	ids := make([]int64, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid id: %s", arg)
+		}
+		ids = append(ids, id)
+	}
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	for _, id := range ids {
+		if err := action(ctx, store, id); err != nil {
+			return err
+		}
+		fmt.Printf("%s #%d.\n", done, id)
+	}
+	return nil
+}
+
+func runReviewEdit(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid id: %s", args[0])
+	}
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	if err := review.Edit(ctx, store, id, args[1]); err != nil {
+		return err
+	}
+	fmt.Printf("Edited #%d.\n", id)
+	return nil
+}
+
+func runReviewWeb(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	addr := reviewAddr
+	if addr == "" {
+		addr = cfg.ReviewAddr
+	}
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           review.Handler(store),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+	slog.Info("serving review page", "url", "http://"+addr)
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("serve review page: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abdulachik/dostobot/internal/calendar"
+	"github.com/abdulachik/dostobot/internal/config"
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Show the upcoming post slots",
+	Long: `Show when the daemon will next post, from the last post and the posting
+calendar: POST_TIMEZONE, POST_WINDOWS, POST_SCHEDULE (or POST_INTERVAL),
+MIN_POST_SPACING and POST_JITTER. Each slot assumes the one before it was
+used; jitter is not applied.
+
+Examples:
+  dostobot schedule
+  POST_WINDOWS="mon-fri 08:00-22:00" dostobot schedule --count 10`,
+	Args: cobra.NoArgs,
+	RunE: runSchedule,
+}
+
+var scheduleCount int
+
+func init() {
+	scheduleCmd.Flags().IntVarP(&scheduleCount, "count", "n", 5, "Number of slots to show")
+	rootCmd.AddCommand(scheduleCmd)
+}
+
+func runSchedule(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	cal, err := calendar.New(calendar.Config{
+		Timezone:   cfg.PostTimezone,
+		Windows:    cfg.PostWindows,
+		Schedule:   cfg.PostSchedule,
+		Interval:   cfg.PostInterval,
+		MinSpacing: cfg.MinPostSpacing,
+		Jitter:     cfg.PostJitter,
+	})
+	if err != nil {
+		return fmt.Errorf("posting calendar: %w", err)
+	}
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	var last time.Time
+	posts, err := store.ListPostsByPlatform(ctx, db.ListPostsByPlatformParams{Platform: "bluesky", Limit: 1})
+	if err != nil {
+		return fmt.Errorf("get last post: %w", err)
+	}
+	if len(posts) > 0 && posts[0].PostedAt.Valid {
+		last = posts[0].PostedAt.Time
+		fmt.Printf("Last post: %s\n", last.In(cal.Location()).Format("Mon 2006-01-02 15:04 MST"))
+	} else {
+		fmt.Println("Last post: none")
+	}
+
+	fmt.Println("Next slots:")
+	after := time.Now()
+	for range scheduleCount {
+		next := cal.Next(last, after)
+		if next.IsZero() {
+			break
+		}
+		fmt.Printf("  %s\n", next.Format("Mon 2006-01-02 15:04 MST"))
+		last, after = next, next
+	}
+	return nil
+}
@@ -18,7 +18,9 @@ var serveCmd = &cobra.Command{
 	Use:   "serve",
 	Short: "Run the bot daemon",
 	Long: `Run the DostoBot daemon that monitors trends, matches quotes,
-and posts to social media on a schedule.`,
+and posts to social media on a schedule.
+
+Send SIGHUP to reload the vector index after running "dostobot reindex".`,
 	RunE: runServe,
 }
 
@@ -53,6 +55,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 	slog.Info("starting DostoBot daemon",
 		"monitor_interval", cfg.MonitorInterval,
 		"post_interval", cfg.PostInterval,
+		"post_schedule", cfg.PostSchedule,
 		"max_posts_per_day", cfg.MaxPostsPerDay,
 	)
 
@@ -69,16 +72,26 @@ func runServe(cmd *cobra.Command, args []string) error {
 		errCh <- sched.Run(ctx)
 	}()
 
-	// Wait for shutdown signal or error
+	// Wait for shutdown signal or error; SIGHUP reloads the vector index
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-
-	select {
-	case sig := <-sigCh:
-		slog.Info("received shutdown signal", "signal", sig)
-	case err := <-errCh:
-		if err != nil && err != context.Canceled {
-			return fmt.Errorf("scheduler error: %w", err)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+
+wait:
+	for {
+		select {
+		case sig := <-sigCh:
+			if sig == syscall.SIGHUP {
+				slog.Info("received SIGHUP, reloading vector index")
+				sched.ReloadIndex()
+				continue
+			}
+			slog.Info("received shutdown signal", "signal", sig)
+			break wait
+		case err := <-errCh:
+			if err != nil && err != context.Canceled {
+				return fmt.Errorf("scheduler error: %w", err)
+			}
+			break wait
 		}
 	}
 
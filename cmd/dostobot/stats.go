@@ -7,6 +7,7 @@ import (
 
 	"github.com/abdulachik/dostobot/internal/config"
 	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/themes"
 	"github.com/abdulachik/dostobot/internal/vectorstore"
 	"github.com/spf13/cobra"
 )
@@ -14,11 +15,17 @@ import (
 var statsCmd = &cobra.Command{
 	Use:   "stats",
 	Short: "Show database statistics",
-	Long:  `Display statistics about quotes, posts, and trends in the database.`,
-	RunE:  runStats,
+	Long: `Display statistics about quotes, posts, and trends in the database.
+
+With --theme, also show how the quotes with that theme (or one of its
+aliases) are spread over the books.`,
+	RunE: runStats,
 }
 
+var statsTheme string
+
 func init() {
+	statsCmd.Flags().StringVar(&statsTheme, "theme", "", "Break down the quotes with this theme by book")
 	rootCmd.AddCommand(statsCmd)
 }
 
@@ -61,6 +68,41 @@ func runStats(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("count quotes by book: %w", err)
 	}
 
+	quotesByVerdict, err := store.CountQuotesByVerdict(ctx)
+	if err != nil {
+		return fmt.Errorf("count quotes by verdict: %w", err)
+	}
+
+	quotesByCuration, err := store.CountQuotesByCuration(ctx)
+	if err != nil {
+		return fmt.Errorf("count quotes by curation: %w", err)
+	}
+
+	quotesByLanguage, err := store.CountQuotesByLanguage(ctx)
+	if err != nil {
+		return fmt.Errorf("count quotes by language: %w", err)
+	}
+
+	quotesByTheme, err := store.CountQuotesByTheme(ctx)
+	if err != nil {
+		return fmt.Errorf("count quotes by theme: %w", err)
+	}
+
+	untaggedQuotes, err := store.CountUntaggedQuotes(ctx)
+	if err != nil {
+		return fmt.Errorf("count untagged quotes: %w", err)
+	}
+
+	translationLinks, err := store.CountQuoteTranslations(ctx)
+	if err != nil {
+		return fmt.Errorf("count quote translations: %w", err)
+	}
+
+	variantGroups, err := store.CountQuoteGroups(ctx)
+	if err != nil {
+		return fmt.Errorf("count quote groups: %w", err)
+	}
+
 	// Get post count
 	var totalPosts int64
 	err = store.QueryRowContext(ctx, "SELECT COUNT(*) FROM posts").Scan(&totalPosts)
@@ -75,6 +117,25 @@ func runStats(cmd *cobra.Command, args []string) error {
 		slog.Warn("failed to count trends", "error", err)
 	}
 
+	// Get match queue counts
+	pendingByStatus, err := store.CountPendingPostsByStatus(ctx)
+	if err != nil {
+		slog.Warn("failed to count pending posts", "error", err)
+	}
+
+	// Get post counts by trend source, evergreen posts included
+	postsBySource, err := store.CountPostsBySource(ctx)
+	if err != nil {
+		slog.Warn("failed to count posts by source", "error", err)
+	}
+
+	// Get selection cache stats
+	cacheStats, err := store.GetSelectionCacheStats(ctx)
+	if err != nil {
+		slog.Warn("failed to read selection cache stats", "error", err)
+		cacheStats = &db.GetSelectionCacheStatsRow{}
+	}
+
 	// Print stats
 	fmt.Println("=== DostoBot Statistics ===")
 	fmt.Println()
@@ -86,6 +147,72 @@ func runStats(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Without embeddings: %d\n", totalQuotes-quotesWithEmbeddings)
 	fmt.Println()
 
+	fmt.Println("  By quality verdict:")
+	for _, row := range quotesByVerdict {
+		verdict := row.Verdict
+		if verdict == "" {
+			verdict = "not reviewed"
+		}
+		fmt.Printf("    %s: %d\n", verdict, row.Count)
+	}
+	fmt.Println()
+
+	fmt.Println("  By curation:")
+	for _, row := range quotesByCuration {
+		status := row.Status
+		if status == "" {
+			status = "pending"
+		}
+		fmt.Printf("    %s: %d\n", status, row.Count)
+	}
+	if cfg.CurationMode {
+		fmt.Println("  Curation mode: only approved quotes are matched")
+	}
+	fmt.Println()
+
+	if len(quotesByLanguage) > 1 || translationLinks > 0 {
+		fmt.Println("  By language:")
+		for _, row := range quotesByLanguage {
+			fmt.Printf("    %s: %d\n", row.Language, row.Count)
+		}
+		fmt.Printf("  Linked to a translation: %d\n", translationLinks)
+		fmt.Println()
+	}
+
+	if variantGroups > 0 {
+		fmt.Printf("  Passages with translation variants: %d\n", variantGroups)
+		fmt.Println()
+	}
+
+	fmt.Println("  By theme (quotes, posted):")
+	for _, row := range quotesByTheme {
+		if row.Quotes > 0 {
+			fmt.Printf("    %s: %d, %d\n", row.Name, row.Quotes, row.Posted)
+		}
+	}
+	fmt.Printf("  Without a theme: %d\n", untaggedQuotes)
+	fmt.Println()
+
+	if statsTheme != "" {
+		taxonomy, err := themes.Load(ctx, store.Queries)
+		if err != nil {
+			return err
+		}
+		theme, _ := taxonomy.Canonical(statsTheme)
+		themeByBook, err := store.CountThemeQuotesByBook(ctx, theme)
+		if err != nil {
+			return fmt.Errorf("count %s quotes by book: %w", theme, err)
+		}
+		fmt.Printf("  Theme %q by book:\n", theme)
+		for _, row := range themeByBook {
+			fmt.Printf("    %s: %d\n", row.SourceBook, row.Count)
+		}
+		if len(themeByBook) == 0 {
+			fmt.Println("    no quotes")
+		}
+		fmt.Println()
+	}
+
 	if len(quotesByBook) > 0 {
 		fmt.Println("  By book:")
 		for _, row := range quotesByBook {
@@ -99,6 +226,34 @@ func runStats(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Total trends tracked: %d\n", totalTrends)
 	fmt.Println()
 
+	if len(postsBySource) > 0 {
+		fmt.Println("Posts by source:")
+		for _, row := range postsBySource {
+			fmt.Printf("  %s: %d\n", row.TrendSource, row.Count)
+		}
+		fmt.Println()
+	}
+
+	if len(pendingByStatus) > 0 {
+		fmt.Println("Match queue:")
+		for _, row := range pendingByStatus {
+			fmt.Printf("  %s: %d\n", row.Status, row.Count)
+		}
+		if cfg.AutoPostScore > 0 {
+			fmt.Printf("  Auto-post score: %.2f\n", cfg.AutoPostScore)
+		}
+		fmt.Println()
+	}
+
+	// Every cached entry cost one LLM call; every hit saved one.
+	fmt.Println("Selection cache:")
+	fmt.Printf("  Entries: %d\n", cacheStats.Entries)
+	fmt.Printf("  Hits: %d\n", cacheStats.Hits)
+	if lookups := cacheStats.Entries + cacheStats.Hits; lookups > 0 {
+		fmt.Printf("  Hit rate: %.1f%%\n", 100*float64(cacheStats.Hits)/float64(lookups))
+	}
+	fmt.Println()
+
 	// Check VecLite stats if configured
 	if cfg.VecLitePath != "" {
 		quoteStore, err := vectorstore.New(vectorstore.Config{
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/themes"
+	"github.com/spf13/cobra"
+)
+
+var themeCmd = &cobra.Command{
+	Use:   "theme",
+	Short: "Manage the theme taxonomy",
+	Long: `Quotes are tagged with canonical themes. Themes the LLM returns are
+mapped onto them through aliases ("pain" and "human-suffering" both become
+"suffering"); themes that match neither a theme nor an alias are added as
+new themes, to be reviewed with 'theme list' and folded into others with
+'theme alias'.`,
+}
+
+var themeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List themes with their aliases and quote counts",
+	Args:  cobra.NoArgs,
+	RunE:  runThemeList,
+}
+
+var themeAddCmd = &cobra.Command{
+	Use:   "add <theme>",
+	Short: "Add a canonical theme",
+	Long: `Add a canonical theme, optionally with aliases.
+
+Examples:
+  dostobot theme add rebellion --alias revolt --alias defiance`,
+	Args: cobra.ExactArgs(1),
+	RunE: runThemeAdd,
+}
+
+var themeAliasCmd = &cobra.Command{
+	Use:   "alias <alias> <theme>",
+	Short: "Map an alias onto a theme",
+	Long: `Map an alias onto a theme. If the alias is a theme itself, it is merged:
+its quotes are retagged with the theme and its aliases move over.
+
+Examples:
+  dostobot theme alias anguish suffering
+  dostobot theme alias "human suffering" suffering`,
+	Args: cobra.ExactArgs(2),
+	RunE: runThemeAlias,
+}
+
+var themeNormalizeCmd = &cobra.Command{
+	Use:   "normalize",
+	Short: "Retag stored quotes with canonical themes",
+	Long: `Map the themes of every stored quote onto the taxonomy. Run it once after
+upgrading, and again after adding aliases by hand.
+
+Examples:
+  dostobot theme normalize --dry-run  # Count the quotes that would change`,
+	Args: cobra.NoArgs,
+	RunE: runThemeNormalize,
+}
+
+var (
+	themeAliases []string
+	themeDryRun  bool
+)
+
+func init() {
+	themeAddCmd.Flags().StringSliceVar(&themeAliases, "alias", nil, "Alias of the theme (repeatable)")
+	themeNormalizeCmd.Flags().BoolVar(&themeDryRun, "dry-run", false, "Count the quotes that would change without saving")
+	themeCmd.AddCommand(themeListCmd, themeAddCmd, themeAliasCmd, themeNormalizeCmd)
+	rootCmd.AddCommand(themeCmd)
+}
+
+func runThemeList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	all, err := store.ListThemes(ctx)
+	if err != nil {
+		return fmt.Errorf("list themes: %w", err)
+	}
+	aliases, err := store.ListThemeAliases(ctx)
+	if err != nil {
+		return fmt.Errorf("list theme aliases: %w", err)
+	}
+	counts, err := store.CountQuotesByTheme(ctx)
+	if err != nil {
+		return fmt.Errorf("count quotes by theme: %w", err)
+	}
+
+	byTheme := make(map[int64][]string)
+	for _, a := range aliases {
+		byTheme[a.ThemeID] = append(byTheme[a.ThemeID], a.Alias)
+	}
+	quotes := make(map[string]*db.CountQuotesByThemeRow, len(counts))
+	for _, c := range counts {
+		quotes[c.Name] = c
+	}
+
+	for _, t := range all {
+		line := fmt.Sprintf("%-20s %5d quotes, %d posted", t.Name, quotes[t.Name].Quotes, quotes[t.Name].Posted)
+		if names := byTheme[t.ID]; len(names) > 0 {
+			sort.Strings(names)
+			line += "  (" + strings.Join(names, ", ") + ")"
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runThemeAdd(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	name := themes.Key(args[0])
+	if name == "" {
+		return fmt.Errorf("theme name is empty")
+	}
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	taxonomy, err := themes.Load(ctx, store.Queries)
+	if err != nil {
+		return err
+	}
+	if existing, known := taxonomy.Canonical(name); known {
+		return fmt.Errorf("%q is already the theme %q or one of its aliases", args[0], existing)
+	}
+
+	if _, err := store.CreateTheme(ctx, name); err != nil {
+		return fmt.Errorf("create theme: %w", err)
+	}
+	fmt.Printf("Added theme %q.\n", name)
+
+	for _, alias := range themeAliases {
+		retagged, err := themes.AddAlias(ctx, store, alias, name)
+		if err != nil {
+			return fmt.Errorf("alias %q: %w", alias, err)
+		}
+		fmt.Printf("Added alias %q", themes.Key(alias))
+		if retagged > 0 {
+			fmt.Printf(", retagging %d quotes", retagged)
+		}
+		fmt.Println(".")
+	}
+	return nil
+}
+
+func runThemeAlias(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	taxonomy, err := themes.Load(ctx, store.Queries)
+	if err != nil {
+		return err
+	}
+	theme, known := taxonomy.Canonical(args[1])
+	if !known {
+		return fmt.Errorf("unknown theme: %s (add it with 'dostobot theme add')", args[1])
+	}
+
+	retagged, err := themes.AddAlias(ctx, store, args[0], theme)
+	if err != nil {
+		return err
+	}
+	if retagged > 0 {
+		fmt.Printf("Merged %q into %q, retagging %d quotes.\n", themes.Key(args[0]), theme, retagged)
+	} else {
+		fmt.Printf("%q is now an alias of %q.\n", themes.Key(args[0]), theme)
+	}
+	fmt.Println("Run 'dostobot theme normalize' to retag quotes still using the alias.")
+	return nil
+}
+
+func runThemeNormalize(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	changed, err := themes.NormalizeAll(ctx, store, themeDryRun)
+	if err != nil {
+		return fmt.Errorf("normalize themes: %w", err)
+	}
+
+	if themeDryRun {
+		fmt.Printf("%d quotes would be retagged.\n", changed)
+		return nil
+	}
+	fmt.Printf("Retagged %d quotes.\n", changed)
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/abdulachik/dostobot/internal/config"
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/extractor"
+	"github.com/abdulachik/dostobot/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+var (
+	validateLimit  int
+	validateBudget float64
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Review stored quotes for quality",
+	Long: `Run every quote that has not been reviewed yet through the quality rubric
+(VALIDATE_MODEL): does it stand alone without the plot, is it a postable
+length, does it carry universal wisdom?
+
+Each quote gets a 1-10 quality score, a list of issues and a verdict:
+approve, edit (keep, but the text needs trimming) or reject. Rejected
+quotes stay in the database so they are not extracted again, but the
+matcher never selects them.
+
+Examples:
+  dostobot validate                  # Review all unreviewed quotes
+  dostobot validate --limit 50       # Review a sample
+  dostobot validate --budget 1       # Stop before spending more than $1`,
+	RunE: runValidate,
+}
+
+func init() {
+	validateCmd.Flags().IntVar(&validateLimit, "limit", 0, "Maximum number of quotes to review (default: all)")
+	validateCmd.Flags().Float64Var(&validateBudget, "budget", 0, "Maximum API spend in USD (default: unlimited)")
+	rootCmd.AddCommand(validateCmd)
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	if err := cfg.ValidateForExtraction(); err != nil {
+		return fmt.Errorf("validate config: %w", err)
+	}
+
+	store, err := db.NewStore(ctx, cfg.DatabasePath)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer store.Close()
+
+	if err := store.Migrate(ctx); err != nil {
+		return fmt.Errorf("run migrations: %w", err)
+	}
+
+	budget := llm.NewBudget(validateBudget)
+	backend := llm.Backend{
+		Provider: cfg.LLMProvider,
+		BaseURL:  cfg.LLMBaseURL,
+		APIKey:   cfg.LLMAPIKey,
+		Limiter:  llm.NewRateLimiter(cfg.ExtractRequestsPerMinute),
+		Budget:   budget,
+	}
+	validateLLM, err := backend.New(cfg.ValidateModel)
+	if err != nil {
+		return fmt.Errorf("create validation model: %w", err)
+	}
+
+	limit := int64(validateLimit)
+	if limit <= 0 {
+		limit = 100000 // Get all quotes
+	}
+	quotes, err := store.ListUnvalidatedQuotes(ctx, limit)
+	if err != nil {
+		return fmt.Errorf("list unvalidated quotes: %w", err)
+	}
+
+	if len(quotes) == 0 {
+		fmt.Println("All quotes have been reviewed.")
+		return nil
+	}
+
+	slog.Info("reviewing quotes", "count", len(quotes), "model", validateLLM.Model())
+
+	counts := make(map[string]int)
+	failed := 0
+	for i, q := range quotes {
+		v, err := extractor.ValidateQuote(ctx, validateLLM, q.Text, q.SourceBook, q.Character.String)
+		if err != nil {
+			if errors.Is(err, llm.ErrBudgetExceeded) || errors.Is(err, llm.ErrAuth) || llm.IsRetryable(err) {
+				slog.Warn("validation stopped", "reviewed", i, "error", err)
+				break
+			}
+			slog.Warn("failed to validate quote", "id", q.ID, "error", err)
+			failed++
+			continue
+		}
+
+		if err := extractor.SaveValidation(ctx, store, q.ID, v); err != nil {
+			return fmt.Errorf("save validation for quote %d: %w", q.ID, err)
+		}
+		counts[v.Recommendation]++
+
+		slog.Debug("reviewed quote",
+			"id", q.ID,
+			"quality", v.OverallQuality,
+			"verdict", v.Recommendation,
+			"issues", v.Issues,
+		)
+		if (i+1)%25 == 0 {
+			slog.Info("progress", "reviewed", i+1, "total", len(quotes))
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("=== Validation Complete ===")
+	fmt.Printf("  Approved: %d\n", counts[db.VerdictApprove])
+	fmt.Printf("  Edit:     %d\n", counts[db.VerdictEdit])
+	fmt.Printf("  Rejected: %d\n", counts[db.VerdictReject])
+	if failed > 0 {
+		fmt.Printf("  Failed:   %d\n", failed)
+	}
+	fmt.Printf("  Spent:    $%.2f\n", budget.Spent())
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/abdulachik/dostobot/internal/config"
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/variants"
+	"github.com/spf13/cobra"
+)
+
+var (
+	variantsBook       string
+	variantsDryRun     bool
+	variantsSimilarity float64
+)
+
+var variantsCmd = &cobra.Command{
+	Use:   "variants",
+	Short: "Group the translations of each passage",
+	Long: `Find quotes of different editions of a work that render the same passage,
+e.g. Garnett's and Pevear and Volokhonsky's Crime and Punishment, and store
+them as a group of variants.
+
+The editions of a work are its original and the catalog books added with
+--original. Quotes of two editions in the same language are variants when
+each is the other's closest quote by embedding (--similarity) and they are
+in the same chapter; quotes linked by 'dostobot align' are variants too.
+Embeddings are read from VecLite when it is available.
+
+When the matcher picks a quote with variants, the LLM chooses the best-
+phrased translation in the posted language for the post. Use
+'dostobot quote show <id>' to see every variant of a quote.
+
+Examples:
+  dostobot variants --dry-run                              # List groups without saving
+  dostobot variants                                        # Group every work
+  dostobot variants --book "Преступление и наказание"      # One work, by its original`,
+	RunE: runVariants,
+}
+
+func init() {
+	variantsCmd.Flags().StringVar(&variantsBook, "book", "", "Title of the original whose editions to group (default: every work with translations)")
+	variantsCmd.Flags().BoolVar(&variantsDryRun, "dry-run", false, "List groups without saving them")
+	variantsCmd.Flags().Float64Var(&variantsSimilarity, "similarity", variants.DefaultSimilarity, "Embedding similarity (0-1) at which passages of two editions are variants")
+	rootCmd.AddCommand(variantsCmd)
+}
+
+func runVariants(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	var originals []*db.Book
+	if variantsBook != "" {
+		book, err := store.GetBookByTitle(ctx, variantsBook)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("unknown book: %s", variantsBook)
+		}
+		if err != nil {
+			return fmt.Errorf("find book: %w", err)
+		}
+		originals = append(originals, book)
+	} else {
+		books, err := store.ListBooks(ctx)
+		if err != nil {
+			return fmt.Errorf("list books: %w", err)
+		}
+		byID := make(map[int64]*db.Book, len(books))
+		for _, book := range books {
+			byID[book.ID] = book
+		}
+		seen := make(map[int64]bool)
+		for _, book := range books {
+			if book.OriginalID.Valid && !seen[book.OriginalID.Int64] && byID[book.OriginalID.Int64] != nil {
+				seen[book.OriginalID.Int64] = true
+				originals = append(originals, byID[book.OriginalID.Int64])
+			}
+		}
+	}
+
+	grouped, saved := 0, 0
+	for _, original := range originals {
+		quotes, links, err := workQuotes(ctx, store, original)
+		if err != nil {
+			return err
+		}
+
+		groups := variants.Find(quotes, loadQuoteEmbeddings(cfg, quotes), links, float32(variantsSimilarity))
+		if len(groups) == 0 {
+			continue
+		}
+
+		fmt.Printf("%s:\n", original.Title)
+		for _, group := range groups {
+			for i, q := range group {
+				prefix := "  "
+				if i == 0 {
+					prefix = "- "
+				}
+				fmt.Printf("%s#%d (%s): %s\n", prefix, q.ID, q.SourceBook, truncate(q.Text, 70))
+			}
+
+			grouped++
+			if variantsDryRun {
+				continue
+			}
+			if _, err := variants.Save(ctx, store, group); err != nil {
+				return fmt.Errorf("save variants of quote %d: %w", group[0].ID, err)
+			}
+			saved++
+		}
+		fmt.Println()
+	}
+
+	if grouped == 0 {
+		if len(originals) == 0 {
+			fmt.Println("No book has a translation; add one with 'dostobot book add --original'.")
+		} else {
+			fmt.Println("No passages found in more than one edition.")
+		}
+		return nil
+	}
+	if variantsDryRun {
+		fmt.Printf("Found %d passages with variants (dry run, nothing saved).\n", grouped)
+		return nil
+	}
+	fmt.Printf("Saved %d passages with variants.\n", saved)
+	return nil
+}
+
+// workQuotes returns the quotes of every edition of the work original is
+// the original of, and the translation links between them.
+func workQuotes(ctx context.Context, store *db.Store, original *db.Book) ([]*db.Quote, []variants.Link, error) {
+	editions, err := store.ListBookTranslations(ctx, sql.NullInt64{Int64: original.ID, Valid: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("list translations: %w", err)
+	}
+	editions = append([]*db.Book{original}, editions...)
+
+	var quotes []*db.Quote
+	for _, edition := range editions {
+		qs, err := store.ListQuotesByBook(ctx, edition.Title)
+		if err != nil {
+			return nil, nil, fmt.Errorf("list quotes of %s: %w", edition.Title, err)
+		}
+		quotes = append(quotes, qs...)
+	}
+
+	var links []variants.Link
+	for _, q := range quotes {
+		if q.SourceBook != original.Title {
+			continue
+		}
+		translated, err := store.ListQuoteTranslations(ctx, q.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("list translations of quote %d: %w", q.ID, err)
+		}
+		for _, t := range translated {
+			links = append(links, variants.Link{OriginalID: q.ID, TranslationID: t.ID})
+		}
+	}
+
+	return quotes, links, nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/abdulachik/dostobot/internal/config"
 	"github.com/abdulachik/dostobot/internal/db"
 	"github.com/abdulachik/dostobot/internal/embedder"
+	"github.com/abdulachik/dostobot/internal/llm"
 	"github.com/abdulachik/dostobot/internal/matcher"
 	"github.com/abdulachik/dostobot/internal/monitor"
 	"github.com/abdulachik/dostobot/internal/poster"
@@ -13,11 +14,11 @@ import (
 
 // App is the main application container holding all dependencies.
 type App struct {
-	Config  *config.Config
-	Store   *db.Store
-	Embedder *embedder.Embedder
-	Matcher *matcher.Matcher
-	Poster  poster.Poster
+	Config   *config.Config
+	Store    *db.Store
+	Embedder embedder.Provider
+	Matcher  *matcher.Matcher
+	Poster   poster.Poster
 	Monitors []monitor.Monitor
 }
 
@@ -36,15 +37,37 @@ func New(ctx context.Context, cfg *config.Config) (*App, error) {
 	}
 
 	// Create embedder
-	emb := embedder.New(embedder.Config{
-		Host: cfg.OllamaHost,
+	emb, err := embedder.LoadProvider(embedder.ProviderConfig{
+		Provider: cfg.EmbedProvider,
+		CacheDir: cfg.EmbedCacheDir,
 	})
+	if err != nil {
+		store.Close()
+		return nil, err
+	}
+
+	// Create the selection (and optional trend screening) models
+	backend := llm.Backend{Provider: cfg.LLMProvider, BaseURL: cfg.LLMBaseURL, APIKey: cfg.LLMAPIKey}
+	selectLLM, err := backend.New(cfg.SelectModel)
+	if err != nil {
+		store.Close()
+		return nil, err
+	}
+	filterLLM, err := backend.NewOptional(cfg.FilterModel)
+	if err != nil {
+		store.Close()
+		return nil, err
+	}
 
 	// Create matcher
 	m := matcher.New(matcher.Config{
-		Store:    store,
-		Embedder: emb,
-		APIKey:   cfg.AnthropicAPIKey,
+		Store:        store,
+		Embedder:     emb,
+		LLM:          selectLLM,
+		FilterLLM:    filterLLM,
+		Language:     cfg.PostLanguage,
+		ApprovedOnly: cfg.CurationMode,
+		Themes:       cfg.PostThemes,
 	})
 
 	// Create monitors
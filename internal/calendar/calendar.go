@@ -0,0 +1,281 @@
+// Package calendar decides when the bot may post: inside weekly posting
+// windows in the audience's timezone, at least a minimum spacing after the
+// last post, and either at the times of cron expressions or a post
+// interval after the last post.
+package calendar
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // POST_TIMEZONE must resolve on hosts without zoneinfo
+)
+
+// Config holds the posting calendar's settings.
+type Config struct {
+	// Timezone windows and cron expressions are read in, such as
+	// "America/New_York". Empty means UTC.
+	Timezone string
+
+	// Windows lists when posting is allowed, as entries separated by ';'
+	// of days and times: "mon-fri 08:00-12:00 17:00-22:00; sat,sun
+	// 10:00-23:00". Days are mon..sun, ranges of them or '*'. Empty allows
+	// any time.
+	Windows string
+
+	// Schedule lists cron expressions separated by ';' giving the times to
+	// post at, such as "0 9,13,18 * * *". Empty posts Interval after the
+	// last post.
+	Schedule string
+
+	// Interval is the time between posts without a schedule.
+	Interval time.Duration
+
+	// MinSpacing is the least time between two posts.
+	MinSpacing time.Duration
+
+	// Jitter is the most a slot is moved later by, at random, so posts do
+	// not land on the same minute every day.
+	Jitter time.Duration
+}
+
+// window is a span of a day in minutes since midnight.
+type window struct {
+	start, end int
+}
+
+// Calendar computes posting slots.
+type Calendar struct {
+	loc     *time.Location
+	windows [7][]window // by time.Weekday; all empty allows any time
+	crons   []*cron
+	spacing time.Duration
+	jitter  time.Duration
+}
+
+// New parses a posting calendar.
+func New(cfg Config) (*Calendar, error) {
+	loc := time.UTC
+	if cfg.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(cfg.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone: %w", err)
+		}
+	}
+
+	c := &Calendar{loc: loc, spacing: cfg.MinSpacing, jitter: cfg.Jitter}
+	if err := c.parseWindows(cfg.Windows); err != nil {
+		return nil, fmt.Errorf("invalid windows: %w", err)
+	}
+	for _, expr := range strings.Split(cfg.Schedule, ";") {
+		if strings.TrimSpace(expr) == "" {
+			continue
+		}
+		cr, err := parseCron(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule: %w", err)
+		}
+		c.crons = append(c.crons, cr)
+	}
+	if len(c.crons) == 0 {
+		if cfg.Interval <= 0 {
+			return nil, fmt.Errorf("interval must be positive without a schedule")
+		}
+		c.spacing = max(c.spacing, cfg.Interval)
+	}
+
+	if c.Next(time.Time{}, time.Now()).IsZero() {
+		return nil, fmt.Errorf("schedule never falls inside the posting windows")
+	}
+	return c, nil
+}
+
+// Location returns the timezone of the calendar.
+func (c *Calendar) Location() *time.Location {
+	return c.loc
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseWindows parses Config.Windows.
+func (c *Calendar) parseWindows(spec string) error {
+	for _, entry := range strings.Split(spec, ";") {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return fmt.Errorf("%q: want days and at least one time range", strings.TrimSpace(entry))
+		}
+		days, err := parseDays(fields[0])
+		if err != nil {
+			return fmt.Errorf("%q: %w", strings.TrimSpace(entry), err)
+		}
+		for _, span := range fields[1:] {
+			w, err := parseWindow(span)
+			if err != nil {
+				return fmt.Errorf("%q: %w", strings.TrimSpace(entry), err)
+			}
+			for _, d := range days {
+				c.windows[d] = append(c.windows[d], w)
+			}
+		}
+	}
+	for d := range c.windows {
+		sort.Slice(c.windows[d], func(i, j int) bool { return c.windows[d][i].start < c.windows[d][j].start })
+	}
+	return nil
+}
+
+// parseDays parses "mon-fri", "sat,sun", "fri-mon" or "*".
+func parseDays(spec string) ([]time.Weekday, error) {
+	if spec == "*" {
+		return []time.Weekday{0, 1, 2, 3, 4, 5, 6}, nil
+	}
+	var days []time.Weekday
+	for _, part := range strings.Split(strings.ToLower(spec), ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		if !isRange {
+			to = from
+		}
+		first, ok := weekdays[from]
+		last, ok2 := weekdays[to]
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("invalid days %q", part)
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			days = append(days, d)
+			if d == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// parseWindow parses "08:00-22:00"; the end may be 24:00.
+func parseWindow(span string) (window, error) {
+	from, to, ok := strings.Cut(span, "-")
+	if !ok {
+		return window{}, fmt.Errorf("invalid time range %q", span)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return window{}, err
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return window{}, err
+	}
+	if end <= start {
+		return window{}, fmt.Errorf("time range %q must end after it starts", span)
+	}
+	return window{start: start, end: end}, nil
+}
+
+// parseClock parses "HH:MM" into minutes since midnight.
+func parseClock(s string) (int, error) {
+	h, m, ok := strings.Cut(s, ":")
+	hour, err := strconv.Atoi(h)
+	if !ok || err != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	minute, err := strconv.Atoi(m)
+	if err != nil || len(m) != 2 || minute > 59 || hour < 0 || hour*60+minute > 24*60 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return hour*60 + minute, nil
+}
+
+// Next returns the first slot at or after after: a time inside the posting
+// windows, at least the spacing after last and, with a schedule, when one
+// of its expressions fires. last is the zero time if nothing was posted
+// yet. Next returns the zero time if there is no such slot.
+func (c *Calendar) Next(last, after time.Time) time.Time {
+	t := after
+	if !last.IsZero() && last.Add(c.spacing).After(t) {
+		t = last.Add(c.spacing)
+	}
+	t = t.In(c.loc)
+
+	// Alternate between the next firing and the next open window until
+	// they agree; a firing outside every window moves on to the next.
+	for range 1000 {
+		if len(c.crons) > 0 {
+			t = c.nextFiring(t)
+			if t.IsZero() {
+				return t
+			}
+		}
+		open := c.nextOpen(t)
+		if open.IsZero() || open.Equal(t) {
+			return open
+		}
+		t = open
+	}
+	return time.Time{}
+}
+
+// Jitter moves slot later by a random amount up to the configured jitter,
+// keeping it inside the posting windows.
+func (c *Calendar) Jitter(slot time.Time) time.Time {
+	if c.jitter <= 0 {
+		return slot
+	}
+	t := slot.Add(rand.N(c.jitter))
+	if !c.Allowed(t) {
+		return slot
+	}
+	return t
+}
+
+// Allowed reports whether t is inside the posting windows.
+func (c *Calendar) Allowed(t time.Time) bool {
+	t = t.In(c.loc)
+	return c.nextOpen(t).Equal(t)
+}
+
+// nextFiring returns the earliest firing of the schedule at or after t.
+func (c *Calendar) nextFiring(t time.Time) time.Time {
+	var first time.Time
+	for _, cr := range c.crons {
+		if f := cr.next(t); !f.IsZero() && (first.IsZero() || f.Before(first)) {
+			first = f
+		}
+	}
+	return first
+}
+
+// nextOpen returns t if it is inside a posting window, the start of the
+// next window otherwise, or the zero time if no day has a window.
+func (c *Calendar) nextOpen(t time.Time) time.Time {
+	open := false
+	for _, ws := range c.windows {
+		open = open || len(ws) > 0
+	}
+	if !open {
+		return t
+	}
+
+	y, m, d := t.Date()
+	for i := range 8 {
+		day := time.Date(y, m, d+i, 0, 0, 0, 0, c.loc)
+		for _, w := range c.windows[day.Weekday()] {
+			start := time.Date(y, m, d+i, 0, w.start, 0, 0, c.loc)
+			end := time.Date(y, m, d+i, 0, w.end, 0, 0, c.loc)
+			if t.Before(end) {
+				if t.Before(start) {
+					return start
+				}
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,156 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustNew(t *testing.T, cfg Config) *Calendar {
+	t.Helper()
+	c, err := New(cfg)
+	require.NoError(t, err)
+	return c
+}
+
+func TestParseCron(t *testing.T) {
+	valid := []string{"* * * * *", "0 9,13,18 * * 1-5", "*/15 8-22/2 1 1-12 7", "30 6/6 * * *"}
+	for _, expr := range valid {
+		_, err := parseCron(expr)
+		assert.NoError(t, err, expr)
+	}
+
+	invalid := []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "a * * * *"}
+	for _, expr := range invalid {
+		_, err := parseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	loc := time.UTC
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.March, day, hour, minute, 0, 0, loc)
+	}
+
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"0 9,13,18 * * *", at(2, 10, 0), at(2, 13, 0)},
+		{"0 9,13,18 * * *", at(2, 13, 0), at(2, 13, 0)},
+		{"0 9,13,18 * * *", at(2, 18, 0).Add(time.Second), at(3, 9, 0)},
+		{"30 8 * * 1-5", at(6, 9, 0), at(9, 8, 30)}, // Friday to Monday
+		{"0 12 1 * *", at(2, 0, 0), time.Date(2026, time.April, 1, 12, 0, 0, 0, loc)},
+		{"0 12 15 * 0", at(2, 0, 0), at(8, 12, 0)}, // day of month or Sunday
+		{"*/20 * * * *", at(2, 10, 41), at(2, 11, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			c, err := parseCron(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, c.next(tt.from))
+		})
+	}
+
+	never, err := parseCron("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, never.next(at(1, 0, 0)).IsZero())
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{Interval: time.Hour})
+	assert.NoError(t, err)
+
+	invalid := []Config{
+		{},
+		{Interval: time.Hour, Timezone: "Mars/Olympus"},
+		{Interval: time.Hour, Windows: "mon"},
+		{Interval: time.Hour, Windows: "funday 08:00-10:00"},
+		{Interval: time.Hour, Windows: "mon 10:00-08:00"},
+		{Interval: time.Hour, Windows: "mon 08:00-25:00"},
+		{Schedule: "0 9 * *"},
+		{Schedule: "0 3 * * *", Windows: "* 08:00-22:00"},
+	}
+	for _, cfg := range invalid {
+		_, err := New(cfg)
+		assert.Error(t, err, "%+v", cfg)
+	}
+}
+
+func TestNext_Interval(t *testing.T) {
+	c := mustNew(t, Config{
+		Timezone:   "America/New_York",
+		Windows:    "mon-fri 08:00-22:00; sat,sun 10:00-20:00",
+		Interval:   4 * time.Hour,
+		MinSpacing: time.Hour,
+	})
+	ny := c.Location()
+	at := func(day, hour, minute int) time.Time { return time.Date(2026, time.March, day, hour, minute, 0, 0, ny) }
+
+	// Nothing posted yet: now, if inside a window
+	assert.Equal(t, at(2, 12, 0), c.Next(time.Time{}, at(2, 12, 0)))
+
+	// Spacing after the last post, measured from the post, not from startup
+	assert.Equal(t, at(2, 16, 0), c.Next(at(2, 12, 0), at(2, 13, 30)))
+	assert.Equal(t, at(2, 17, 0), c.Next(at(2, 12, 0), at(2, 17, 0)))
+
+	// A slot at 3am moves to the morning's window
+	assert.Equal(t, at(3, 8, 0), c.Next(at(2, 23, 0), at(2, 23, 0)))
+
+	// Saturday opens later
+	assert.Equal(t, at(7, 10, 0), c.Next(at(6, 21, 0), at(6, 21, 0)))
+
+	assert.True(t, c.Allowed(at(2, 21, 59)))
+	assert.False(t, c.Allowed(at(2, 22, 0)))
+	assert.False(t, c.Allowed(at(7, 9, 0)))
+}
+
+func TestNext_Schedule(t *testing.T) {
+	c := mustNew(t, Config{
+		Timezone:   "Europe/Berlin",
+		Windows:    "mon-fri 08:00-20:00",
+		Schedule:   "0 9,13,18,22 * * *; 30 10 * * 6",
+		MinSpacing: 2 * time.Hour,
+	})
+	berlin := c.Location()
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.March, day, hour, minute, 0, 0, berlin)
+	}
+
+	assert.Equal(t, at(2, 9, 0), c.Next(time.Time{}, at(2, 7, 0)))
+	assert.Equal(t, at(2, 13, 0), c.Next(at(2, 9, 0), at(2, 9, 1)))
+
+	// A manual post at 12:00 pushes the 13:00 slot to 18:00
+	assert.Equal(t, at(2, 18, 0), c.Next(at(2, 12, 0), at(2, 12, 0)))
+
+	// 22:00 is outside the window, and Saturday has no window at all
+	assert.Equal(t, at(3, 9, 0), c.Next(at(2, 18, 0), at(2, 18, 1)))
+	assert.Equal(t, at(9, 9, 0), c.Next(at(6, 18, 0), at(6, 18, 1)))
+
+	// Slots are computed in the calendar's timezone
+	utc := c.Next(time.Time{}, time.Date(2026, time.March, 2, 6, 0, 0, 0, time.UTC))
+	assert.Equal(t, at(2, 9, 0), utc)
+}
+
+func TestJitter(t *testing.T) {
+	c := mustNew(t, Config{Windows: "* 08:00-09:00", Interval: time.Hour, Jitter: 10 * time.Minute})
+	slot := time.Date(2026, time.March, 2, 8, 0, 0, 0, time.UTC)
+	for range 100 {
+		got := c.Jitter(slot)
+		assert.False(t, got.Before(slot))
+		assert.Less(t, got.Sub(slot), 10*time.Minute)
+	}
+
+	// Jitter never moves a slot out of its window
+	late := time.Date(2026, time.March, 2, 8, 59, 0, 0, time.UTC)
+	for range 100 {
+		assert.True(t, c.Allowed(c.Jitter(late)))
+	}
+
+	none := mustNew(t, Config{Interval: time.Hour})
+	assert.Equal(t, slot, none.Jitter(slot))
+}
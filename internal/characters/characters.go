@@ -0,0 +1,237 @@
+// Package characters maps the names the LLM gives speakers onto a per-book
+// registry of characters and their aliases, so that "Rodion Romanovitch"
+// and "Rodya" are both stored, searched and credited as "Raskolnikov".
+package characters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/abdulachik/dostobot/internal/db"
+)
+
+// Narrator is the name the LLM gives passages not spoken by a character.
+// It is never registered, and posts do not credit it.
+const Narrator = "Narrator"
+
+// Key returns the form names and aliases are compared in: lowercase, with
+// runs of spaces collapsed.
+func Key(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// Registry maps the names of a book's characters to their canonical names.
+// Names that are neither a character nor an alias become new characters
+// when a quote is saved with them, so they can later be reviewed and
+// folded into others with AddAlias.
+type Registry struct {
+	book  string
+	chars map[string]*db.Character // key of a name or alias to character
+}
+
+// Load reads the registry of a book from the database.
+func Load(ctx context.Context, q *db.Queries, book string) (*Registry, error) {
+	chars, err := q.ListBookCharacters(ctx, book)
+	if err != nil {
+		return nil, fmt.Errorf("list characters: %w", err)
+	}
+	aliases, err := q.ListBookCharacterAliases(ctx, book)
+	if err != nil {
+		return nil, fmt.Errorf("list character aliases: %w", err)
+	}
+
+	r := &Registry{book: book, chars: make(map[string]*db.Character, len(chars)+len(aliases))}
+	byID := make(map[int64]*db.Character, len(chars))
+	for _, c := range chars {
+		r.chars[Key(c.Name)] = c
+		byID[c.ID] = c
+	}
+	for _, a := range aliases {
+		if c, ok := byID[a.CharacterID]; ok {
+			r.chars[Key(a.Alias)] = c
+		}
+	}
+	return r, nil
+}
+
+// Lookup returns the character a name or alias refers to.
+func (r *Registry) Lookup(name string) (*db.Character, bool) {
+	c, ok := r.chars[Key(name)]
+	return c, ok
+}
+
+// Canonical returns the canonical name for name, or name itself, trimmed,
+// if the registry does not know it.
+func (r *Registry) Canonical(name string) string {
+	if c, ok := r.Lookup(name); ok {
+		return c.Name
+	}
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// Resolve returns the canonical name for name, registering it as a new
+// character if the registry does not know it. Empty names and the narrator
+// are returned as they are.
+func (r *Registry) Resolve(ctx context.Context, q *db.Queries, name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return "", nil
+	}
+	if Key(name) == Key(Narrator) {
+		return Narrator, nil
+	}
+	if c, ok := r.Lookup(name); ok {
+		return c.Name, nil
+	}
+
+	c, err := q.CreateCharacter(ctx, db.CreateCharacterParams{SourceBook: r.book, Name: name})
+	if err != nil {
+		return "", fmt.Errorf("create character %q: %w", name, err)
+	}
+	r.chars[Key(name)] = c
+	return c.Name, nil
+}
+
+// Display returns the name posts credit a quote's speaker with: the
+// canonical name of its character, or the name stored if its book's
+// registry does not know it.
+func Display(ctx context.Context, q *db.Queries, quote *db.Quote) string {
+	if !quote.Character.Valid || quote.Character.String == "" {
+		return ""
+	}
+	r, err := Load(ctx, q, quote.SourceBook)
+	if err != nil {
+		return quote.Character.String
+	}
+	return r.Canonical(quote.Character.String)
+}
+
+// NormalizeAll replaces the character of every stored quote with its
+// canonical name and returns how many quotes changed. With dryRun nothing
+// is saved. The vector index keeps the old names until it is rebuilt.
+func NormalizeAll(ctx context.Context, store *db.Store, dryRun bool) (int, error) {
+	tx, err := store.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := store.Queries.WithTx(tx)
+	quotes, err := qtx.ListQuotes(ctx, db.ListQuotesParams{Limit: 100000, Offset: 0})
+	if err != nil {
+		return 0, fmt.Errorf("list quotes: %w", err)
+	}
+
+	registries := make(map[string]*Registry)
+	changed := 0
+	for _, q := range quotes {
+		if !q.Character.Valid {
+			continue
+		}
+		r, ok := registries[q.SourceBook]
+		if !ok {
+			if r, err = Load(ctx, qtx, q.SourceBook); err != nil {
+				return 0, err
+			}
+			registries[q.SourceBook] = r
+		}
+
+		name, err := r.Resolve(ctx, qtx, q.Character.String)
+		if err != nil {
+			return 0, err
+		}
+		if name == q.Character.String {
+			continue
+		}
+		if err := qtx.SetQuoteCharacter(ctx, db.SetQuoteCharacterParams{
+			Character: sql.NullString{String: name, Valid: name != ""},
+			ID:        q.ID,
+		}); err != nil {
+			return 0, fmt.Errorf("set character of quote %d: %w", q.ID, err)
+		}
+		changed++
+	}
+
+	if dryRun {
+		return changed, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return changed, nil
+}
+
+// AddAlias records alias as another name of the character name in book. If
+// alias is itself one of the book's characters, it is merged into name: its
+// quotes and aliases move to name and it becomes an alias. It returns the
+// number of quotes moved.
+func AddAlias(ctx context.Context, store *db.Store, book, alias, name string) (int, error) {
+	key := Key(alias)
+	if key == "" {
+		return 0, fmt.Errorf("alias is empty")
+	}
+
+	tx, err := store.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := store.Queries.WithTx(tx)
+	r, err := Load(ctx, qtx, book)
+	if err != nil {
+		return 0, err
+	}
+	target, ok := r.Lookup(name)
+	if !ok {
+		return 0, fmt.Errorf("unknown character in %s: %s", book, name)
+	}
+
+	moved := 0
+	if merged, ok := r.Lookup(alias); ok && Key(merged.Name) == key {
+		if merged.ID == target.ID {
+			return 0, fmt.Errorf("%q is the character itself", alias)
+		}
+		counts, err := qtx.CountQuotesByCharacter(ctx, book)
+		if err != nil {
+			return 0, fmt.Errorf("count quotes of %s: %w", merged.Name, err)
+		}
+		for _, c := range counts {
+			if c.Character.String == merged.Name {
+				moved = int(c.Quotes)
+			}
+		}
+
+		if err := qtx.RenameQuoteCharacter(ctx, db.RenameQuoteCharacterParams{
+			Character:    sql.NullString{String: target.Name, Valid: true},
+			SourceBook:   book,
+			OldCharacter: sql.NullString{String: merged.Name, Valid: true},
+		}); err != nil {
+			return 0, fmt.Errorf("move quotes of %s: %w", merged.Name, err)
+		}
+		if err := qtx.ReassignCharacterAliases(ctx, db.ReassignCharacterAliasesParams{
+			KeeperID:    target.ID,
+			DuplicateID: merged.ID,
+		}); err != nil {
+			return 0, fmt.Errorf("move aliases of %s: %w", merged.Name, err)
+		}
+		if err := qtx.DeleteCharacter(ctx, merged.ID); err != nil {
+			return 0, fmt.Errorf("delete character %s: %w", merged.Name, err)
+		}
+	}
+
+	if err := qtx.SetCharacterAlias(ctx, db.SetCharacterAliasParams{
+		SourceBook:  book,
+		Alias:       key,
+		CharacterID: target.ID,
+	}); err != nil {
+		return 0, fmt.Errorf("add alias: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
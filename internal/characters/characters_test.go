@@ -0,0 +1,144 @@
+package characters
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStore(t *testing.T) *db.Store {
+	t.Helper()
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	require.NoError(t, store.Migrate(ctx))
+	return store
+}
+
+func createQuote(t *testing.T, store *db.Store, book, character, text string) *db.Quote {
+	t.Helper()
+	q, err := store.CreateQuote(context.Background(), db.CreateQuoteParams{
+		Text:       text,
+		TextHash:   text,
+		SourceBook: book,
+		Character:  sql.NullString{String: character, Valid: character != ""},
+		Themes:     "[]",
+		CharCount:  int64(len(text)),
+		Language:   "en",
+	})
+	require.NoError(t, err)
+	return q
+}
+
+func character(t *testing.T, store *db.Store, q *db.Quote) string {
+	t.Helper()
+	got, err := store.GetQuote(context.Background(), q.ID)
+	require.NoError(t, err)
+	return got.Character.String
+}
+
+func TestResolve(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	cast, err := Load(ctx, store.Queries, "Crime and Punishment")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Raskolnikov", "Raskolnikov"},
+		{"Rodion  Romanovitch", "Raskolnikov"},
+		{"rodya", "Raskolnikov"},
+		{"Sonya", "Sonia"},
+		{"narrator", Narrator},
+		{"", ""},
+		{"Lebeziatnikov", "Lebeziatnikov"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cast.Resolve(ctx, store.Queries, tt.name)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	registered, err := store.ListBookCharacters(ctx, "Crime and Punishment")
+	require.NoError(t, err)
+	var names []string
+	for _, c := range registered {
+		names = append(names, c.Name)
+	}
+	assert.Contains(t, names, "Lebeziatnikov", "unknown characters are registered")
+	assert.NotContains(t, names, Narrator)
+
+	other, err := Load(ctx, store.Queries, "The Idiot")
+	require.NoError(t, err)
+	assert.Equal(t, "Rodya", other.Canonical("Rodya"), "registries are per book")
+}
+
+func TestDisplay(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+
+	assert.Equal(t, "Prince Myshkin", Display(ctx, store.Queries, createQuote(t, store, "The Idiot", "Myshkin", "a")))
+	assert.Equal(t, "Ganya", Display(ctx, store.Queries, createQuote(t, store, "The Idiot", "Ganya", "b")))
+	assert.Empty(t, Display(ctx, store.Queries, createQuote(t, store, "The Idiot", "", "c")))
+}
+
+func TestNormalizeAll(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	a := createQuote(t, store, "The Brothers Karamazov", "Mitya", "a")
+	b := createQuote(t, store, "The Brothers Karamazov", "Alyosha", "b")
+	c := createQuote(t, store, "The Brothers Karamazov", "", "c")
+
+	changed, err := NormalizeAll(ctx, store, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, "Mitya", character(t, store, a), "a dry run saves nothing")
+
+	changed, err = NormalizeAll(ctx, store, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, "Dmitri", character(t, store, a))
+	assert.Equal(t, "Alyosha", character(t, store, b))
+	assert.Empty(t, character(t, store, c))
+}
+
+func TestAddAlias(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	book := "The Possessed"
+	cast, err := Load(ctx, store.Queries, book)
+	require.NoError(t, err)
+
+	q := createQuote(t, store, book, "Nikolay", "a")
+	_, err = cast.Resolve(ctx, store.Queries, "Nikolay")
+	require.NoError(t, err)
+
+	moved, err := AddAlias(ctx, store, book, "Nikolay", "Stavrogin")
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved)
+	assert.Equal(t, "Stavrogin", character(t, store, q))
+
+	moved, err = AddAlias(ctx, store, book, "The Prince", "stavrogin")
+	require.NoError(t, err)
+	assert.Zero(t, moved)
+
+	reloaded, err := Load(ctx, store.Queries, book)
+	require.NoError(t, err)
+	assert.Equal(t, "Stavrogin", reloaded.Canonical("nikolay"))
+	assert.Equal(t, "Stavrogin", reloaded.Canonical("the prince"))
+
+	_, err = AddAlias(ctx, store, book, "Stavrogin", "Stavrogin")
+	assert.Error(t, err)
+	_, err = AddAlias(ctx, store, book, "x", "Raskolnikov")
+	assert.Error(t, err, "characters of other books are unknown")
+}
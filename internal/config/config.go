@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -16,11 +17,31 @@ type Config struct {
 
 	// VecLite
 	VecLitePath   string // Path to VecLite database (default: data/quotes.veclite)
-	EmbedProvider string // Embedding provider: "ollama" or "openai" (default: ollama)
+	EmbedProvider string // Overrides the veclite.yaml embedding provider: "ollama", "openai" or "onnx" (default: use veclite.yaml)
+	EmbedCacheDir string // On-disk embedding cache; empty disables it (default: data/embedcache)
 
 	// Anthropic API
 	AnthropicAPIKey string
 
+	// LLM backend and per-task models
+	LLMProvider   string // "anthropic" or "openai" for any OpenAI-compatible server (default: anthropic)
+	LLMBaseURL    string // Backend URL (default: provider's; http://localhost:11434/v1 for openai)
+	LLMAPIKey     string // Backend API key (default: ANTHROPIC_API_KEY for anthropic)
+	ExtractModel  string // Model for quote extraction (default: claude-sonnet-4-20250514; required for openai)
+	SelectModel   string // Model for quote selection (default: claude-sonnet-4-20250514; required for openai)
+	FilterModel   string // Cheap model to screen trends before selection; empty disables screening
+	ValidateModel string // Model for the quote quality review (default: claude-sonnet-4-20250514; EXTRACT_MODEL for openai)
+
+	// Extraction
+	ExtractConcurrency       int     // Chunks sent to Claude in parallel (default: 4)
+	ExtractRequestsPerMinute int     // Client-side request rate cap; 0 relies on API headers (default: 50)
+	ExtractBudgetUSD         float64 // Hard spend limit per extraction run; 0 is unlimited (default: 0)
+	ExtractValidate          bool    // Review each new quote with VALIDATE_MODEL before saving it (default: false)
+
+	// Project Gutenberg
+	GutenbergCatalog string // Local copy of pg_catalog.csv for searching works (default: data/pg_catalog.csv)
+	GutenbergMirror  string // Base URL books are downloaded from (default: https://www.gutenberg.org)
+
 	// OpenAI API (for embeddings)
 	OpenAIAPIKey string
 
@@ -45,10 +66,47 @@ type Config struct {
 	PostInterval    time.Duration
 	MaxPostsPerDay  int
 
+	// Posting calendar
+	PostTimezone   string        // Timezone posting windows and schedules are read in (default: UTC)
+	PostWindows    string        // When posting is allowed, e.g. "mon-fri 08:00-22:00; sat,sun 10:00-23:00"; empty allows any time (default: empty)
+	PostSchedule   string        // Cron expressions separated by ';' to post at instead of every POST_INTERVAL (default: empty)
+	PostJitter     time.Duration // Most a post slot is delayed by at random (default: 0)
+	MinPostSpacing time.Duration // Least time between two posts (default: 1h)
+
+	// Languages
+	PostLanguage  string // Language of the quotes matched and posted; empty matches any (default: en)
+	PostBilingual bool   // Post the original above a translated quote when they fit together (default: false)
+
+	// Curation
+	CurationMode bool // Only match quotes an editor approved with 'dostobot curate' (default: false)
+
+	// Themes
+	PostThemes []string // Only match quotes with one of these themes; empty matches any (default: empty)
+
+	// Match queue
+	MatchTTL          time.Duration // How long a match stays ready to post (default: 12h)
+	MatchQueueSize    int           // Matching pauses while this many matches are queued (default: 10)
+	EvergreenFallback bool          // Post an evergreen quote when no match is queued (default: false)
+
+	// Evergreen posts
+	EvergreenMaxPerDay int      // Maximum evergreen posts per day, within MAX_POSTS_PER_DAY (default: 1)
+	EvergreenThemes    []string // Themes of evergreen posts on days without an occasion, one a day in turn (default: empty)
+
+	// Post approval
+	PostApproval  bool          // Queue matches for review with 'dostobot review' instead of posting them (default: false)
+	ApprovalTTL   time.Duration // How long a queued match waits for review before it expires (default: 24h)
+	AutoPostScore float64       // With approval on, post matches scoring at least this right away; 0 never does (default: 0)
+	ReviewAddr    string        // Address of the review page served by 'dostobot review web' (default: 127.0.0.1:8089)
+
 	// Notification settings
 	NotifyHandle string
 }
 
+// defaultLLMModel is the default model for extraction and selection with
+// the anthropic backend. OpenAI-compatible servers have no common model, so
+// theirs must be configured.
+const defaultLLMModel = "claude-sonnet-4-20250514"
+
 // Load reads configuration from environment variables.
 // It automatically loads .env file if present.
 func Load() (*Config, error) {
@@ -58,8 +116,14 @@ func Load() (*Config, error) {
 	cfg := &Config{
 		DatabasePath:       getEnv("DATABASE_PATH", "data/dostobot.db"),
 		VecLitePath:        getEnv("VECLITE_PATH", "data/quotes.veclite"),
-		EmbedProvider:      getEnv("EMBED_PROVIDER", "ollama"),
+		EmbedProvider:      getEnv("EMBED_PROVIDER", ""),
+		EmbedCacheDir:      getEnv("EMBED_CACHE_DIR", "data/embedcache"),
 		AnthropicAPIKey:    getEnv("ANTHROPIC_API_KEY", ""),
+		LLMProvider:        getEnv("LLM_PROVIDER", "anthropic"),
+		LLMBaseURL:         getEnv("LLM_BASE_URL", ""),
+		FilterModel:        getEnv("FILTER_MODEL", ""),
+		GutenbergCatalog:   getEnv("GUTENBERG_CATALOG", "data/pg_catalog.csv"),
+		GutenbergMirror:    getEnv("GUTENBERG_MIRROR", ""),
 		OpenAIAPIKey:       getEnv("OPENAI_API_KEY", ""),
 		BlueskyHandle:      getEnv("BLUESKY_HANDLE", ""),
 		BlueskyAppPassword: getEnv("BLUESKY_APP_PASSWORD", ""),
@@ -70,6 +134,27 @@ func Load() (*Config, error) {
 		OllamaModel:        getEnv("OLLAMA_MODEL", "nomic-embed-text"),
 		LogLevel:           getEnv("LOG_LEVEL", "info"),
 		NotifyHandle:       getEnv("NOTIFY_HANDLE", ""),
+		PostLanguage:       getEnv("POST_LANGUAGE", "en"),
+		PostTimezone:       getEnv("POST_TIMEZONE", "UTC"),
+		PostWindows:        getEnv("POST_WINDOWS", ""),
+		PostSchedule:       getEnv("POST_SCHEDULE", ""),
+		ReviewAddr:         getEnv("REVIEW_ADDR", "127.0.0.1:8089"),
+	}
+
+	cfg.LLMAPIKey = getEnv("LLM_API_KEY", "")
+	if cfg.LLMAPIKey == "" && cfg.LLMProvider == "anthropic" {
+		cfg.LLMAPIKey = cfg.AnthropicAPIKey
+	}
+
+	defaultModel := ""
+	if cfg.LLMProvider == "anthropic" {
+		defaultModel = defaultLLMModel
+	}
+	cfg.ExtractModel = getEnv("EXTRACT_MODEL", defaultModel)
+	cfg.SelectModel = getEnv("SELECT_MODEL", defaultModel)
+	cfg.ValidateModel = getEnv("VALIDATE_MODEL", defaultModel)
+	if cfg.ValidateModel == "" {
+		cfg.ValidateModel = cfg.ExtractModel
 	}
 
 	// Parse durations
@@ -84,6 +169,26 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid POST_INTERVAL: %w", err)
 	}
 
+	cfg.PostJitter, err = time.ParseDuration(getEnv("POST_JITTER", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid POST_JITTER: %w", err)
+	}
+
+	cfg.MinPostSpacing, err = time.ParseDuration(getEnv("MIN_POST_SPACING", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MIN_POST_SPACING: %w", err)
+	}
+
+	cfg.MatchTTL, err = time.ParseDuration(getEnv("MATCH_TTL", "12h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MATCH_TTL: %w", err)
+	}
+
+	cfg.ApprovalTTL, err = time.ParseDuration(getEnv("APPROVAL_TTL", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid APPROVAL_TTL: %w", err)
+	}
+
 	// Parse integers
 	maxPosts, err := strconv.Atoi(getEnv("MAX_POSTS_PER_DAY", "6"))
 	if err != nil {
@@ -91,6 +196,66 @@ func Load() (*Config, error) {
 	}
 	cfg.MaxPostsPerDay = maxPosts
 
+	cfg.ExtractConcurrency, err = strconv.Atoi(getEnv("EXTRACT_CONCURRENCY", "4"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EXTRACT_CONCURRENCY: %w", err)
+	}
+
+	cfg.ExtractRequestsPerMinute, err = strconv.Atoi(getEnv("EXTRACT_REQUESTS_PER_MINUTE", "50"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EXTRACT_REQUESTS_PER_MINUTE: %w", err)
+	}
+
+	cfg.MatchQueueSize, err = strconv.Atoi(getEnv("MATCH_QUEUE_SIZE", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MATCH_QUEUE_SIZE: %w", err)
+	}
+
+	cfg.EvergreenMaxPerDay, err = strconv.Atoi(getEnv("EVERGREEN_MAX_PER_DAY", "1"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EVERGREEN_MAX_PER_DAY: %w", err)
+	}
+
+	// Parse floats
+	cfg.ExtractBudgetUSD, err = strconv.ParseFloat(getEnv("EXTRACT_BUDGET_USD", "0"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid EXTRACT_BUDGET_USD: %w", err)
+	}
+
+	cfg.AutoPostScore, err = strconv.ParseFloat(getEnv("AUTO_POST_SCORE", "0"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid AUTO_POST_SCORE: %w", err)
+	}
+
+	// Parse booleans
+	cfg.ExtractValidate, err = strconv.ParseBool(getEnv("EXTRACT_VALIDATE", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EXTRACT_VALIDATE: %w", err)
+	}
+
+	cfg.PostBilingual, err = strconv.ParseBool(getEnv("POST_BILINGUAL", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid POST_BILINGUAL: %w", err)
+	}
+
+	cfg.CurationMode, err = strconv.ParseBool(getEnv("CURATION_MODE", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CURATION_MODE: %w", err)
+	}
+
+	cfg.EvergreenFallback, err = strconv.ParseBool(getEnv("EVERGREEN_FALLBACK", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EVERGREEN_FALLBACK: %w", err)
+	}
+
+	cfg.PostApproval, err = strconv.ParseBool(getEnv("POST_APPROVAL", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid POST_APPROVAL: %w", err)
+	}
+
+	cfg.PostThemes = splitList(getEnv("POST_THEMES", ""))
+	cfg.EvergreenThemes = splitList(getEnv("EVERGREEN_THEMES", ""))
+
 	return cfg, nil
 }
 
@@ -107,8 +272,41 @@ func (c *Config) ValidateForExtraction() error {
 	if err := c.Validate(); err != nil {
 		return err
 	}
-	if c.AnthropicAPIKey == "" {
-		return fmt.Errorf("ANTHROPIC_API_KEY is required for extraction")
+	if err := c.ValidateForLLM(); err != nil {
+		return err
+	}
+	return c.requireModel("EXTRACT_MODEL", c.ExtractModel)
+}
+
+// ValidateForSelection checks configuration needed to match quotes to
+// trends.
+func (c *Config) ValidateForSelection() error {
+	if err := c.ValidateForLLM(); err != nil {
+		return err
+	}
+	return c.requireModel("SELECT_MODEL", c.SelectModel)
+}
+
+// ValidateForLLM checks configuration needed to reach the LLM backend.
+func (c *Config) ValidateForLLM() error {
+	switch c.LLMProvider {
+	case "anthropic", "":
+		if c.LLMAPIKey == "" && c.AnthropicAPIKey == "" {
+			return fmt.Errorf("ANTHROPIC_API_KEY is required when LLM_PROVIDER is anthropic")
+		}
+	case "openai":
+		// Local OpenAI-compatible servers usually need no key.
+	default:
+		return fmt.Errorf("invalid LLM_PROVIDER: %s (must be 'anthropic' or 'openai')", c.LLMProvider)
+	}
+	return nil
+}
+
+// requireModel checks that the model set in env var name is configured
+// with the openai backend, which unlike anthropic has no default model.
+func (c *Config) requireModel(name, model string) error {
+	if model == "" && c.LLMProvider == "openai" {
+		return fmt.Errorf("%s is required when LLM_PROVIDER is openai", name)
 	}
 	return nil
 }
@@ -127,8 +325,10 @@ func (c *Config) ValidateForEmbedding() error {
 		if c.OllamaHost == "" {
 			return fmt.Errorf("OLLAMA_HOST is required for embedding")
 		}
+	case "onnx":
+		// Runs in-process; configured entirely in veclite.yaml.
 	default:
-		return fmt.Errorf("invalid EMBED_PROVIDER: %s (must be 'ollama' or 'openai')", c.EmbedProvider)
+		return fmt.Errorf("invalid EMBED_PROVIDER: %s (must be 'ollama', 'openai' or 'onnx')", c.EmbedProvider)
 	}
 	return nil
 }
@@ -172,6 +372,9 @@ func (c *Config) ValidateForServe() error {
 	if err := c.ValidateForExtraction(); err != nil {
 		return err
 	}
+	if err := c.ValidateForSelection(); err != nil {
+		return err
+	}
 	if err := c.ValidateForEmbedding(); err != nil {
 		return err
 	}
@@ -188,6 +391,17 @@ func getEnv(key, defaultVal string) string {
 	return defaultVal
 }
 
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // normalizeOllamaHost ensures the Ollama host has a proper URL scheme.
 // This handles cases where OLLAMA_HOST is set to a bind address like "0.0.0.0"
 // (used by Ollama server) instead of a client URL like "http://localhost:11434".
@@ -35,6 +35,35 @@ func TestLoad(t *testing.T) {
 		assert.Equal(t, 30*time.Minute, cfg.MonitorInterval)
 		assert.Equal(t, 4*time.Hour, cfg.PostInterval)
 		assert.Equal(t, 6, cfg.MaxPostsPerDay)
+		assert.Equal(t, "UTC", cfg.PostTimezone)
+		assert.Empty(t, cfg.PostWindows)
+		assert.Empty(t, cfg.PostSchedule)
+		assert.Zero(t, cfg.PostJitter)
+		assert.Equal(t, time.Hour, cfg.MinPostSpacing)
+		assert.Equal(t, 4, cfg.ExtractConcurrency)
+		assert.Equal(t, 50, cfg.ExtractRequestsPerMinute)
+		assert.Zero(t, cfg.ExtractBudgetUSD)
+		assert.Equal(t, "anthropic", cfg.LLMProvider)
+		assert.Equal(t, "claude-sonnet-4-20250514", cfg.ExtractModel)
+		assert.Equal(t, "claude-sonnet-4-20250514", cfg.SelectModel)
+		assert.Empty(t, cfg.FilterModel)
+		assert.Equal(t, "claude-sonnet-4-20250514", cfg.ValidateModel)
+		assert.False(t, cfg.ExtractValidate)
+		assert.Equal(t, "data/pg_catalog.csv", cfg.GutenbergCatalog)
+		assert.Empty(t, cfg.GutenbergMirror)
+		assert.Equal(t, "en", cfg.PostLanguage)
+		assert.False(t, cfg.PostBilingual)
+		assert.False(t, cfg.CurationMode)
+		assert.Empty(t, cfg.PostThemes)
+		assert.Equal(t, 12*time.Hour, cfg.MatchTTL)
+		assert.Equal(t, 10, cfg.MatchQueueSize)
+		assert.False(t, cfg.EvergreenFallback)
+		assert.Equal(t, 1, cfg.EvergreenMaxPerDay)
+		assert.Empty(t, cfg.EvergreenThemes)
+		assert.False(t, cfg.PostApproval)
+		assert.Equal(t, 24*time.Hour, cfg.ApprovalTTL)
+		assert.Zero(t, cfg.AutoPostScore)
+		assert.Equal(t, "127.0.0.1:8089", cfg.ReviewAddr)
 	})
 
 	t.Run("custom values", func(t *testing.T) {
@@ -44,15 +73,65 @@ func TestLoad(t *testing.T) {
 		os.Setenv("BLUESKY_HANDLE", "test.bsky.social")
 		os.Setenv("MONITOR_INTERVAL", "1h")
 		os.Setenv("MAX_POSTS_PER_DAY", "10")
+		os.Setenv("EXTRACT_BUDGET_USD", "12.5")
+		os.Setenv("EXTRACT_VALIDATE", "true")
+		os.Setenv("POST_BILINGUAL", "true")
+		os.Setenv("CURATION_MODE", "true")
+		os.Setenv("POST_THEMES", "faith, suffering,,")
+		os.Setenv("MATCH_TTL", "3h")
+		os.Setenv("MATCH_QUEUE_SIZE", "4")
+		os.Setenv("EVERGREEN_FALLBACK", "true")
+		os.Setenv("EVERGREEN_MAX_PER_DAY", "2")
+		os.Setenv("EVERGREEN_THEMES", "hope,faith")
+		os.Setenv("POST_APPROVAL", "true")
+		os.Setenv("APPROVAL_TTL", "6h")
+		os.Setenv("AUTO_POST_SCORE", "0.9")
+		os.Setenv("POST_TIMEZONE", "America/New_York")
+		os.Setenv("POST_WINDOWS", "mon-fri 08:00-22:00")
+		os.Setenv("POST_SCHEDULE", "0 9,13,18 * * *")
+		os.Setenv("POST_JITTER", "15m")
+		os.Setenv("MIN_POST_SPACING", "2h")
 
 		cfg, err := Load()
 		require.NoError(t, err)
 
 		assert.Equal(t, "/custom/path.db", cfg.DatabasePath)
 		assert.Equal(t, "sk-test", cfg.AnthropicAPIKey)
+		assert.Equal(t, "sk-test", cfg.LLMAPIKey, "anthropic backend falls back to ANTHROPIC_API_KEY")
 		assert.Equal(t, "test.bsky.social", cfg.BlueskyHandle)
 		assert.Equal(t, time.Hour, cfg.MonitorInterval)
 		assert.Equal(t, 10, cfg.MaxPostsPerDay)
+		assert.Equal(t, 12.5, cfg.ExtractBudgetUSD)
+		assert.True(t, cfg.ExtractValidate)
+		assert.True(t, cfg.PostBilingual)
+		assert.True(t, cfg.CurationMode)
+		assert.Equal(t, []string{"faith", "suffering"}, cfg.PostThemes)
+		assert.Equal(t, 3*time.Hour, cfg.MatchTTL)
+		assert.Equal(t, 4, cfg.MatchQueueSize)
+		assert.True(t, cfg.EvergreenFallback)
+		assert.Equal(t, 2, cfg.EvergreenMaxPerDay)
+		assert.Equal(t, []string{"hope", "faith"}, cfg.EvergreenThemes)
+		assert.True(t, cfg.PostApproval)
+		assert.Equal(t, 6*time.Hour, cfg.ApprovalTTL)
+		assert.Equal(t, 0.9, cfg.AutoPostScore)
+		assert.Equal(t, "America/New_York", cfg.PostTimezone)
+		assert.Equal(t, "mon-fri 08:00-22:00", cfg.PostWindows)
+		assert.Equal(t, "0 9,13,18 * * *", cfg.PostSchedule)
+		assert.Equal(t, 15*time.Minute, cfg.PostJitter)
+		assert.Equal(t, 2*time.Hour, cfg.MinPostSpacing)
+	})
+
+	t.Run("openai backend has no default models", func(t *testing.T) {
+		os.Clearenv()
+		os.Setenv("LLM_PROVIDER", "openai")
+		os.Setenv("EXTRACT_MODEL", "qwen2.5:14b")
+
+		cfg, err := Load()
+		require.NoError(t, err)
+
+		assert.Equal(t, "qwen2.5:14b", cfg.ExtractModel)
+		assert.Empty(t, cfg.SelectModel)
+		assert.Equal(t, "qwen2.5:14b", cfg.ValidateModel, "the review falls back to EXTRACT_MODEL")
 	})
 
 	t.Run("invalid duration", func(t *testing.T) {
@@ -72,6 +151,15 @@ func TestLoad(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "MAX_POSTS_PER_DAY")
 	})
+
+	t.Run("invalid boolean", func(t *testing.T) {
+		os.Clearenv()
+		os.Setenv("EXTRACT_VALIDATE", "sometimes")
+
+		_, err := Load()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "EXTRACT_VALIDATE")
+	})
 }
 
 func TestConfig_Validate(t *testing.T) {
@@ -103,6 +191,31 @@ func TestConfig_ValidateForExtraction(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "ANTHROPIC_API_KEY")
 	})
+
+	t.Run("openai-compatible backend needs no key", func(t *testing.T) {
+		cfg := &Config{DatabasePath: "test.db", LLMProvider: "openai", ExtractModel: "qwen2.5:14b"}
+		assert.NoError(t, cfg.ValidateForExtraction())
+	})
+
+	t.Run("openai-compatible backend needs a model", func(t *testing.T) {
+		cfg := &Config{DatabasePath: "test.db", LLMProvider: "openai", SelectModel: "qwen2.5:14b"}
+		err := cfg.ValidateForExtraction()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "EXTRACT_MODEL")
+		assert.NoError(t, cfg.ValidateForSelection())
+
+		cfg = &Config{DatabasePath: "test.db", LLMProvider: "openai", ExtractModel: "qwen2.5:14b"}
+		err = cfg.ValidateForSelection()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "SELECT_MODEL")
+	})
+
+	t.Run("invalid provider", func(t *testing.T) {
+		cfg := &Config{DatabasePath: "test.db", LLMProvider: "gemini", LLMAPIKey: "k"}
+		err := cfg.ValidateForExtraction()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "LLM_PROVIDER")
+	})
 }
 
 func TestConfig_ValidateForEmbedding(t *testing.T) {
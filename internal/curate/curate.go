@@ -0,0 +1,172 @@
+// Package curate lets an editor review extracted quotes: approve or reject
+// them, fix their text, retag their themes and mark favorites. With
+// curation mode on, the matcher only considers approved quotes.
+package curate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/themes"
+)
+
+// StatusPending selects quotes no editor has reviewed yet.
+const StatusPending = "pending"
+
+// Filter selects the quotes to review. Zero fields select every quote.
+type Filter struct {
+	Book        string // Exact source book title
+	Character   string // Exact character name
+	Theme       string // One of the quote's themes, or an alias of it
+	MinQuality  int    // Minimum quality score from validation (1-10)
+	NeverPosted bool   // Only quotes never posted
+	Status      string // StatusPending, db.CurationApproved or db.CurationRejected
+	Favorites   bool   // Only favorites
+}
+
+// params returns the query parameters selecting a page of up to limit
+// quotes with IDs above afterID. The theme must already be canonical.
+func (f Filter) params(afterID int64, limit int) db.ListCurationQuotesParams {
+	p := db.ListCurationQuotesParams{
+		AfterID:        afterID,
+		Book:           exactOrAny(f.Book),
+		Character:      exactOrAny(f.Character),
+		Theme:          f.Theme,
+		MinQuality:     int64(f.MinQuality),
+		MaxTimesPosted: math.MaxInt64,
+		Status:         "%",
+		MinFavorite:    f.Favorites,
+		Limit:          int64(limit),
+	}
+	if f.NeverPosted {
+		p.MaxTimesPosted = 0
+	}
+	switch f.Status {
+	case "":
+	case StatusPending:
+		p.Status = ""
+	default:
+		p.Status = escapeLike(f.Status)
+	}
+	return p
+}
+
+// exactOrAny returns a LIKE pattern matching s exactly, or anything if s
+// is empty.
+func exactOrAny(s string) string {
+	if s == "" {
+		return "%"
+	}
+	return escapeLike(s)
+}
+
+// likeEscaper escapes the LIKE wildcards, with the query's escape
+// character, so filters match them literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// Page returns up to limit quotes matching f with IDs above afterID, in ID
+// order. Paging by ID keeps its place while reviewed quotes leave the
+// filter.
+func Page(ctx context.Context, store *db.Store, f Filter, afterID int64, limit int) ([]*db.Quote, error) {
+	if f.Theme != "" {
+		taxonomy, err := themes.Load(ctx, store.Queries)
+		if err != nil {
+			return nil, err
+		}
+		f.Theme, _ = taxonomy.Canonical(f.Theme)
+	}
+	return store.ListCurationQuotes(ctx, f.params(afterID, limit))
+}
+
+// Approve marks a quote approved for posting.
+func Approve(ctx context.Context, store *db.Store, id int64) error {
+	return setStatus(ctx, store, id, db.CurationApproved)
+}
+
+// Reject marks a quote rejected; it is never matched again.
+func Reject(ctx context.Context, store *db.Store, id int64) error {
+	return setStatus(ctx, store, id, db.CurationRejected)
+}
+
+func setStatus(ctx context.Context, store *db.Store, id int64, status string) error {
+	return store.SetQuoteCuration(ctx, db.SetQuoteCurationParams{
+		CurationStatus: sql.NullString{String: status, Valid: true},
+		ID:             id,
+	})
+}
+
+// SetFavorite marks or unmarks a quote as a favorite.
+func SetFavorite(ctx context.Context, store *db.Store, id int64, favorite bool) error {
+	return store.SetQuoteFavorite(ctx, db.SetQuoteFavoriteParams{Favorite: favorite, ID: id})
+}
+
+// EditText replaces a quote's text. Its stored embedding is cleared; run
+// 'dostobot embed' to embed the new text. The new text is no longer the
+// verbatim passage its source lines point to, so they are cleared too.
+// Saving the same text again changes nothing.
+func EditText(ctx context.Context, store *db.Store, id int64, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("text is empty")
+	}
+	q, err := store.GetQuote(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get quote %d: %w", id, err)
+	}
+	if q.Text == text {
+		return nil
+	}
+	hash := sha256.Sum256([]byte(text))
+	return store.UpdateQuoteText(ctx, db.UpdateQuoteTextParams{
+		Text:      text,
+		TextHash:  hex.EncodeToString(hash[:]),
+		CharCount: int64(utf8.RuneCountInString(text)),
+		ID:        id,
+	})
+}
+
+// Retag replaces a quote's themes with the canonical form of tags, adding
+// themes the taxonomy does not know yet.
+func Retag(ctx context.Context, store *db.Store, id int64, tags []string) error {
+	tx, err := store.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := store.Queries.WithTx(tx)
+	taxonomy, err := themes.Load(ctx, qtx)
+	if err != nil {
+		return err
+	}
+	names, err := taxonomy.Tag(ctx, qtx, id, tags)
+	if err != nil {
+		return err
+	}
+
+	themesJSON, err := json.Marshal(names)
+	if err != nil {
+		return fmt.Errorf("marshal themes: %w", err)
+	}
+	if err := qtx.UpdateQuoteThemes(ctx, db.UpdateQuoteThemesParams{Themes: string(themesJSON), ID: id}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Themes returns the themes of a quote, or nil if they are not a JSON list.
+func Themes(q *db.Quote) []string {
+	return themes.Parse(q)
+}
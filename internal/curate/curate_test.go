@@ -0,0 +1,193 @@
+package curate
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/themes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStore(t *testing.T) *db.Store {
+	t.Helper()
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	require.NoError(t, store.Migrate(ctx))
+	return store
+}
+
+func createQuote(t *testing.T, store *db.Store, book, character, themesJSON, text string) *db.Quote {
+	t.Helper()
+	q, err := store.CreateQuote(context.Background(), db.CreateQuoteParams{
+		Text:       text,
+		TextHash:   text,
+		SourceBook: book,
+		Character:  sql.NullString{String: character, Valid: character != ""},
+		Themes:     themesJSON,
+		CharCount:  int64(len(text)),
+		Language:   "en",
+	})
+	require.NoError(t, err)
+	if tags := themes.Parse(q); len(tags) > 0 {
+		taxonomy, err := themes.Load(context.Background(), store.Queries)
+		require.NoError(t, err)
+		_, err = taxonomy.Tag(context.Background(), store.Queries, q.ID, tags)
+		require.NoError(t, err)
+	}
+	return q
+}
+
+func ids(quotes []*db.Quote) []int64 {
+	out := make([]int64, len(quotes))
+	for i, q := range quotes {
+		out[i] = q.ID
+	}
+	return out
+}
+
+func TestPage(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+
+	a := createQuote(t, store, "Crime and Punishment", "Raskolnikov", `["guilt","poverty"]`, "a")
+	b := createQuote(t, store, "Crime and Punishment", "Sonya", `["faith"]`, "b")
+	c := createQuote(t, store, "The Idiot", "Myshkin", `["beauty"]`, "c")
+	d := createQuote(t, store, "100% Dostoyevsky", "", `["guilt_free"]`, "d")
+
+	require.NoError(t, Approve(ctx, store, b.ID))
+	require.NoError(t, SetFavorite(ctx, store, c.ID, true))
+	require.NoError(t, store.UpdateQuotePosted(ctx, a.ID))
+	require.NoError(t, store.UpdateQuoteValidation(ctx, db.UpdateQuoteValidationParams{
+		QualityScore:   sql.NullInt64{Int64: 8, Valid: true},
+		QualityVerdict: sql.NullString{String: db.VerdictApprove, Valid: true},
+		ID:             c.ID,
+	}))
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []int64
+	}{
+		{"everything", Filter{}, []int64{a.ID, b.ID, c.ID, d.ID}},
+		{"book", Filter{Book: "Crime and Punishment"}, []int64{a.ID, b.ID}},
+		{"wildcards match literally", Filter{Book: "100%"}, nil},
+		{"character", Filter{Character: "Sonya"}, []int64{b.ID}},
+		{"theme", Filter{Theme: "guilt"}, []int64{a.ID}},
+		{"theme by its canonical name", Filter{Theme: " Guilt "}, []int64{a.ID}},
+		{"theme matches whole names", Filter{Theme: "free"}, nil},
+		{"quality", Filter{MinQuality: 7}, []int64{c.ID}},
+		{"never posted", Filter{NeverPosted: true}, []int64{b.ID, c.ID, d.ID}},
+		{"pending", Filter{Status: StatusPending}, []int64{a.ID, c.ID, d.ID}},
+		{"approved", Filter{Status: db.CurationApproved}, []int64{b.ID}},
+		{"favorites", Filter{Favorites: true}, []int64{c.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quotes, err := Page(ctx, store, tt.filter, 0, 10)
+			require.NoError(t, err)
+			if tt.want == nil {
+				assert.Empty(t, quotes)
+				return
+			}
+			assert.Equal(t, tt.want, ids(quotes))
+		})
+	}
+
+	quotes, err := Page(ctx, store, Filter{}, a.ID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{b.ID, c.ID}, ids(quotes), "pages continue after the last ID seen")
+}
+
+func TestEditAndRetag(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	q := createQuote(t, store, "Demons", "Kirillov", `["freedom"]`, "old text")
+
+	require.NoError(t, EditText(ctx, store, q.ID, "  Man is unhappy because he doesn't know he's happy.  "))
+	require.NoError(t, Retag(ctx, store, q.ID, []string{" Happiness", "freedom", "", "happiness"}))
+	assert.Error(t, EditText(ctx, store, q.ID, " "))
+
+	got, err := store.GetQuote(ctx, q.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Man is unhappy because he doesn't know he's happy.", got.Text)
+	assert.Equal(t, int64(50), got.CharCount)
+	assert.NotEqual(t, q.TextHash, got.TextHash)
+	assert.Equal(t, []string{"happiness", "freedom"}, Themes(got))
+	assert.True(t, got.CuratedAt.Valid)
+}
+
+func TestEditText_ClearsVerbatimSpan(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	q, err := store.CreateQuote(ctx, db.CreateQuoteParams{
+		Text:            "Pain and suffering are always inevitable for a large intelligence.",
+		TextHash:        "h",
+		SourceBook:      "Crime and Punishment",
+		Themes:          `["suffering"]`,
+		CharCount:       67,
+		SourceStartLine: sql.NullInt64{Int64: 120, Valid: true},
+		SourceEndLine:   sql.NullInt64{Int64: 121, Valid: true},
+		VerbatimEdits:   sql.NullInt64{Int64: 0, Valid: true},
+		Language:        "en",
+	})
+	require.NoError(t, err)
+
+	// Saving the verified text unchanged keeps it verified.
+	require.NoError(t, EditText(ctx, store, q.ID, q.Text))
+	got, err := store.GetQuote(ctx, q.ID)
+	require.NoError(t, err)
+	assert.True(t, got.VerbatimEdits.Valid)
+
+	require.NoError(t, EditText(ctx, store, q.ID, "Pain and suffering are inevitable for a large intelligence."))
+	got, err = store.GetQuote(ctx, q.ID)
+	require.NoError(t, err)
+	assert.False(t, got.SourceStartLine.Valid)
+	assert.False(t, got.SourceEndLine.Valid)
+	assert.False(t, got.VerbatimEdits.Valid, "edited text is no longer verbatim")
+}
+
+func TestSession(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	a := createQuote(t, store, "Crime and Punishment", "Raskolnikov", `["guilt"]`, "first")
+	b := createQuote(t, store, "Crime and Punishment", "Sonya", `["faith"]`, "second")
+	c := createQuote(t, store, "Crime and Punishment", "Razumikhin", `["friendship"]`, "third")
+	d := createQuote(t, store, "Crime and Punishment", "Porfiry", `["justice"]`, "fourth")
+
+	input := strings.Join([]string{
+		"f", "a", // favorite and approve the first
+		"x", "e", "second, fixed", "t", "faith, hope", "r", // edit, retag and reject the second
+		"", // skip the third
+		"q",
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	session := &Session{Store: store, Filter: Filter{Status: StatusPending}, In: strings.NewReader(input), Out: &out}
+	sum, err := session.Run(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, Summary{Reviewed: 4, Approved: 1, Rejected: 1, Edited: 1, Retagged: 1, Favorited: 1}, sum)
+	assert.Contains(t, out.String(), `Unknown command "x"`)
+	assert.Contains(t, out.String(), "Themes: faith, hope")
+
+	get := func(q *db.Quote) *db.Quote {
+		got, err := store.GetQuote(ctx, q.ID)
+		require.NoError(t, err)
+		return got
+	}
+	first, second, third, fourth := get(a), get(b), get(c), get(d)
+	assert.True(t, first.Approved())
+	assert.True(t, first.Favorite)
+	assert.True(t, second.Discarded())
+	assert.Equal(t, "second, fixed", second.Text)
+	assert.False(t, third.CurationStatus.Valid)
+	assert.False(t, fourth.CurationStatus.Valid, "quitting leaves the quote as it was")
+}
@@ -0,0 +1,187 @@
+package curate
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/abdulachik/dostobot/internal/db"
+)
+
+// pageSize is the number of quotes loaded at a time.
+const pageSize = 50
+
+// Summary counts what an editor did in a session.
+type Summary struct {
+	Reviewed  int
+	Approved  int
+	Rejected  int
+	Edited    int
+	Retagged  int
+	Favorited int
+}
+
+// Session pages through the quotes matching a filter, one at a time, and
+// applies the editor's commands to them:
+//
+//	a        approve and go to the next quote
+//	r        reject and go to the next quote
+//	e        replace the text (read from the next line)
+//	t        replace the themes (comma-separated, read from the next line)
+//	f        toggle favorite
+//	enter, s skip to the next quote
+//	q        quit
+type Session struct {
+	Store  *db.Store
+	Filter Filter
+	In     io.Reader
+	Out    io.Writer
+}
+
+// Run reviews quotes until they run out, the editor quits or the input
+// ends.
+func (s *Session) Run(ctx context.Context) (Summary, error) {
+	var sum Summary
+	in := bufio.NewScanner(s.In)
+
+	var after int64
+	for {
+		quotes, err := Page(ctx, s.Store, s.Filter, after, pageSize)
+		if err != nil {
+			return sum, fmt.Errorf("list quotes: %w", err)
+		}
+		if len(quotes) == 0 {
+			fmt.Fprintln(s.Out, "No more quotes to review.")
+			return sum, nil
+		}
+
+		for _, q := range quotes {
+			after = q.ID
+			quit, err := s.review(ctx, in, q, &sum)
+			if err != nil {
+				return sum, err
+			}
+			if quit {
+				return sum, nil
+			}
+		}
+	}
+}
+
+// review shows one quote and applies commands to it until the editor moves
+// on. It reports whether the editor quit.
+func (s *Session) review(ctx context.Context, in *bufio.Scanner, q *db.Quote, sum *Summary) (bool, error) {
+	sum.Reviewed++
+	s.show(q)
+
+	for {
+		fmt.Fprint(s.Out, "[a]pprove [r]eject [e]dit [t]hemes [f]avorite [s]kip [q]uit > ")
+		if !in.Scan() {
+			fmt.Fprintln(s.Out)
+			return true, in.Err()
+		}
+
+		switch cmd := strings.ToLower(strings.TrimSpace(in.Text())); cmd {
+		case "a":
+			if err := Approve(ctx, s.Store, q.ID); err != nil {
+				return false, fmt.Errorf("approve quote %d: %w", q.ID, err)
+			}
+			sum.Approved++
+			return false, nil
+
+		case "r":
+			if err := Reject(ctx, s.Store, q.ID); err != nil {
+				return false, fmt.Errorf("reject quote %d: %w", q.ID, err)
+			}
+			sum.Rejected++
+			return false, nil
+
+		case "e":
+			fmt.Fprint(s.Out, "New text (empty keeps it): ")
+			if !in.Scan() {
+				return true, in.Err()
+			}
+			text := strings.TrimSpace(in.Text())
+			if text == "" {
+				continue
+			}
+			if err := EditText(ctx, s.Store, q.ID, text); err != nil {
+				fmt.Fprintf(s.Out, "Could not save the text: %v\n", err)
+				continue
+			}
+			q.Text = text
+			sum.Edited++
+			s.show(q)
+
+		case "t":
+			fmt.Fprintf(s.Out, "Themes, comma-separated (now: %s): ", strings.Join(Themes(q), ", "))
+			if !in.Scan() {
+				return true, in.Err()
+			}
+			if strings.TrimSpace(in.Text()) == "" {
+				continue
+			}
+			if err := Retag(ctx, s.Store, q.ID, strings.Split(in.Text(), ",")); err != nil {
+				return false, fmt.Errorf("retag quote %d: %w", q.ID, err)
+			}
+			updated, err := s.Store.GetQuote(ctx, q.ID)
+			if err != nil {
+				return false, fmt.Errorf("reload quote %d: %w", q.ID, err)
+			}
+			q.Themes = updated.Themes
+			sum.Retagged++
+			fmt.Fprintf(s.Out, "Themes: %s\n", strings.Join(Themes(q), ", "))
+
+		case "f":
+			if err := SetFavorite(ctx, s.Store, q.ID, !q.Favorite); err != nil {
+				return false, fmt.Errorf("favorite quote %d: %w", q.ID, err)
+			}
+			q.Favorite = !q.Favorite
+			if q.Favorite {
+				sum.Favorited++
+				fmt.Fprintln(s.Out, "Marked as favorite.")
+			} else {
+				fmt.Fprintln(s.Out, "No longer a favorite.")
+			}
+
+		case "", "s":
+			return false, nil
+
+		case "q":
+			return true, nil
+
+		default:
+			fmt.Fprintf(s.Out, "Unknown command %q.\n", cmd)
+		}
+	}
+}
+
+// show prints a quote with what an editor needs to judge it.
+func (s *Session) show(q *db.Quote) {
+	fmt.Fprintln(s.Out)
+	source := q.SourceBook
+	if q.Chapter.Valid && q.Chapter.String != "" {
+		source += ", " + q.Chapter.String
+	}
+	if q.Character.Valid && q.Character.String != "" {
+		source += " (" + q.Character.String + ")"
+	}
+	fmt.Fprintf(s.Out, "#%d  %s\n", q.ID, source)
+	fmt.Fprintf(s.Out, "  \"%s\"\n", q.Text)
+
+	status := StatusPending
+	if q.CurationStatus.Valid {
+		status = q.CurationStatus.String
+	}
+	if q.Favorite {
+		status += ", favorite"
+	}
+	quality := "not reviewed"
+	if q.QualityVerdict.Valid {
+		quality = fmt.Sprintf("%s (%d/10)", q.QualityVerdict.String, q.QualityScore.Int64)
+	}
+	fmt.Fprintf(s.Out, "  Themes: %s\n", strings.Join(Themes(q), ", "))
+	fmt.Fprintf(s.Out, "  Quality: %s | Posted: %d | Status: %s\n", quality, q.TimesPosted.Int64, status)
+}
@@ -136,6 +136,26 @@ func TestStore_Migrate(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, "6", val)
 	})
+
+	t.Run("seeds book catalog", func(t *testing.T) {
+		ctx := context.Background()
+		store, err := NewStore(ctx, filepath.Join(t.TempDir(), "test.db"))
+		require.NoError(t, err)
+		defer store.Close()
+
+		require.NoError(t, store.Migrate(ctx))
+
+		books, err := store.ListBooks(ctx)
+		require.NoError(t, err)
+		assert.Len(t, books, 7)
+
+		book, err := store.GetBookByTitle(ctx, "Crime and Punishment")
+		require.NoError(t, err)
+		assert.Equal(t, "Fyodor Dostoyevsky", book.Author)
+		assert.Equal(t, int64(2554), book.GutenbergID.Int64)
+		assert.Equal(t, "crime-and-punishment.txt", book.FilePath)
+		assert.Equal(t, "en", book.Language)
+	})
 }
 
 func TestExtractUpMigration(t *testing.T) {
@@ -0,0 +1,20 @@
+package db
+
+// Curation states stored in quotes.curation_status by 'dostobot curate'.
+// Quotes not yet reviewed by an editor have none.
+const (
+	CurationApproved = "approved"
+	CurationRejected = "rejected"
+)
+
+// Approved reports whether an editor approved the quote. With curation mode
+// on, only approved quotes are matched.
+func (q *Quote) Approved() bool {
+	return q.CurationStatus.Valid && q.CurationStatus.String == CurationApproved
+}
+
+// Discarded reports whether an editor rejected the quote. Like quotes the
+// quality rubric rejected, discarded quotes are never matched.
+func (q *Quote) Discarded() bool {
+	return q.CurationStatus.Valid && q.CurationStatus.String == CurationRejected
+}
@@ -0,0 +1,13 @@
+-- +migrate Up
+-- Record which model produced each stored embedding so vectors from
+-- different models are never compared.
+ALTER TABLE quotes ADD COLUMN embedding_model TEXT;
+ALTER TABLE quotes ADD COLUMN embedding_dim INTEGER;
+ALTER TABLE trends ADD COLUMN embedding_model TEXT;
+ALTER TABLE trends ADD COLUMN embedding_dim INTEGER;
+
+-- +migrate Down
+ALTER TABLE trends DROP COLUMN embedding_dim;
+ALTER TABLE trends DROP COLUMN embedding_model;
+ALTER TABLE quotes DROP COLUMN embedding_dim;
+ALTER TABLE quotes DROP COLUMN embedding_model;
@@ -0,0 +1,9 @@
+-- +migrate Up
+-- Identify the exact book file a job processed so an interrupted run can
+-- resume from processed_chunks instead of starting over.
+ALTER TABLE extraction_jobs ADD COLUMN file_hash TEXT;
+CREATE INDEX idx_extraction_jobs_book_hash ON extraction_jobs(book_title, file_hash);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_extraction_jobs_book_hash;
+ALTER TABLE extraction_jobs DROP COLUMN file_hash;
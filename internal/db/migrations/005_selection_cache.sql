@@ -0,0 +1,18 @@
+-- +migrate Up
+-- Remember the selector's verdict for a trend and candidate set so a trend
+-- re-evaluated in a later post cycle does not cost another LLM call.
+CREATE TABLE IF NOT EXISTS selection_cache (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    trend_hash TEXT NOT NULL,
+    quote_ids TEXT NOT NULL,          -- candidate IDs in prompt order, comma-separated
+    prompt_version TEXT NOT NULL,
+    model TEXT NOT NULL,
+    result TEXT NOT NULL,             -- JSON batch evaluation
+    hits INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_hit_at DATETIME,
+    UNIQUE (trend_hash, quote_ids, prompt_version, model)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS selection_cache;
@@ -0,0 +1,16 @@
+-- +migrate Up
+-- Verdict of the quality rubric (standalone, length, universal wisdom) on
+-- each quote. Rejected quotes are kept, so they are not extracted again,
+-- but never matched.
+ALTER TABLE quotes ADD COLUMN quality_score INTEGER;      -- 1-10
+ALTER TABLE quotes ADD COLUMN quality_issues TEXT;        -- JSON array
+ALTER TABLE quotes ADD COLUMN quality_verdict TEXT;       -- approve, reject or edit; NULL until validated
+ALTER TABLE quotes ADD COLUMN validated_at TIMESTAMP;
+CREATE INDEX idx_quotes_quality_verdict ON quotes(quality_verdict);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_quotes_quality_verdict;
+ALTER TABLE quotes DROP COLUMN validated_at;
+ALTER TABLE quotes DROP COLUMN quality_verdict;
+ALTER TABLE quotes DROP COLUMN quality_issues;
+ALTER TABLE quotes DROP COLUMN quality_score;
@@ -0,0 +1,12 @@
+-- +migrate Up
+-- Where each quote was found in its book file. Extracted quotes are
+-- aligned against the source text and stored verbatim; verbatim_edits is
+-- how many words the extracted text differed by (0 = exact).
+ALTER TABLE quotes ADD COLUMN source_start_line INTEGER;
+ALTER TABLE quotes ADD COLUMN source_end_line INTEGER;
+ALTER TABLE quotes ADD COLUMN verbatim_edits INTEGER;
+
+-- +migrate Down
+ALTER TABLE quotes DROP COLUMN verbatim_edits;
+ALTER TABLE quotes DROP COLUMN source_end_line;
+ALTER TABLE quotes DROP COLUMN source_start_line;
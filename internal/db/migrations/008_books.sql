@@ -0,0 +1,26 @@
+-- +migrate Up
+-- The catalog of books quotes are extracted from. file_path is relative to
+-- the books directory; books with a Gutenberg ID can be downloaded.
+CREATE TABLE IF NOT EXISTS books (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title TEXT NOT NULL UNIQUE,       -- stored as quotes.source_book
+    author TEXT NOT NULL,
+    translator TEXT,
+    gutenberg_id INTEGER,
+    file_path TEXT NOT NULL UNIQUE,
+    language TEXT NOT NULL DEFAULT 'en',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The books that were built in before the catalog existed
+INSERT OR IGNORE INTO books (title, author, translator, gutenberg_id, file_path) VALUES
+    ('Crime and Punishment', 'Fyodor Dostoyevsky', 'Constance Garnett', 2554, 'crime-and-punishment.txt'),
+    ('The Brothers Karamazov', 'Fyodor Dostoyevsky', 'Constance Garnett', 28054, 'brothers-karamazov.txt'),
+    ('Notes from Underground', 'Fyodor Dostoyevsky', NULL, 600, 'notes-from-underground.txt'),
+    ('The Idiot', 'Fyodor Dostoyevsky', 'Eva Martin', 2638, 'the-idiot.txt'),
+    ('The Possessed', 'Fyodor Dostoyevsky', 'Constance Garnett', 8117, 'the-possessed.txt'),
+    ('The Gambler', 'Fyodor Dostoyevsky', 'C. J. Hogarth', 2197, 'the-gambler.txt'),
+    ('Poor Folk', 'Fyodor Dostoyevsky', 'C. J. Hogarth', 2302, 'poor-folk.txt');
+
+-- +migrate Down
+DROP TABLE IF EXISTS books;
@@ -0,0 +1,13 @@
+-- +migrate Up
+-- Which edition of a book was downloaded. edition is the Gutenberg release
+-- (e.g. "eBook #2554, updated 2021-06-26") and checksum the SHA-256 of the
+-- file, so a changed upstream text is noticed before quotes' source lines
+-- stop matching it.
+ALTER TABLE books ADD COLUMN edition TEXT;
+ALTER TABLE books ADD COLUMN checksum TEXT;
+ALTER TABLE books ADD COLUMN downloaded_at DATETIME;
+
+-- +migrate Down
+ALTER TABLE books DROP COLUMN downloaded_at;
+ALTER TABLE books DROP COLUMN checksum;
+ALTER TABLE books DROP COLUMN edition;
@@ -0,0 +1,32 @@
+-- +migrate Up
+-- The language each quote is written in, taken from its book.
+ALTER TABLE quotes ADD COLUMN language TEXT NOT NULL DEFAULT 'en';
+
+UPDATE quotes
+SET language = (SELECT books.language FROM books WHERE books.title = quotes.source_book)
+WHERE source_book IN (SELECT title FROM books);
+
+CREATE INDEX idx_quotes_language ON quotes(language);
+
+-- The original a book translates, e.g. the Russian text of an English
+-- edition. Quotes of the original are aligned against its translations.
+ALTER TABLE books ADD COLUMN original_id INTEGER REFERENCES books(id) ON DELETE SET NULL;
+
+-- Links a quote of an original to the passage translating it in a
+-- translation. edits is the word edit distance between the passage the
+-- model found and the verbatim text stored.
+CREATE TABLE IF NOT EXISTS quote_translations (
+    original_id INTEGER NOT NULL REFERENCES quotes(id) ON DELETE CASCADE,
+    translation_id INTEGER NOT NULL REFERENCES quotes(id) ON DELETE CASCADE,
+    edits INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (original_id, translation_id)
+);
+
+CREATE INDEX idx_quote_translations_translation ON quote_translations(translation_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS quote_translations;
+ALTER TABLE books DROP COLUMN original_id;
+DROP INDEX IF EXISTS idx_quotes_language;
+ALTER TABLE quotes DROP COLUMN language;
@@ -0,0 +1,17 @@
+-- +migrate Up
+-- A group of quotes that render the same passage of a work in different
+-- translations, e.g. Garnett's and Pevear and Volokhonsky's. A quote is in
+-- at most one group.
+CREATE TABLE IF NOT EXISTS quote_groups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE quotes ADD COLUMN group_id INTEGER REFERENCES quote_groups(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_quotes_group ON quotes(group_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_quotes_group;
+ALTER TABLE quotes DROP COLUMN group_id;
+DROP TABLE IF EXISTS quote_groups;
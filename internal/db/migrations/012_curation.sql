@@ -0,0 +1,15 @@
+-- +migrate Up
+-- Editorial review with 'dostobot curate'. curation_status is NULL until an
+-- editor approves or rejects the quote; with CURATION_MODE only approved
+-- quotes are matched. Editors can also mark favorites.
+ALTER TABLE quotes ADD COLUMN curation_status TEXT;
+ALTER TABLE quotes ADD COLUMN favorite BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE quotes ADD COLUMN curated_at DATETIME;
+
+CREATE INDEX idx_quotes_curation ON quotes(curation_status);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_quotes_curation;
+ALTER TABLE quotes DROP COLUMN curated_at;
+ALTER TABLE quotes DROP COLUMN favorite;
+ALTER TABLE quotes DROP COLUMN curation_status;
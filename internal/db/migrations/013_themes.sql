@@ -0,0 +1,95 @@
+-- +migrate Up
+-- A taxonomy of canonical themes. The LLM tags quotes freely ("Suffering",
+-- "human-suffering", "pain"); tags are looked up by their lowercase form
+-- among theme names and aliases and stored under the canonical name.
+CREATE TABLE IF NOT EXISTS themes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS theme_aliases (
+    alias TEXT PRIMARY KEY,
+    theme_id INTEGER NOT NULL REFERENCES themes(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_theme_aliases_theme ON theme_aliases(theme_id);
+
+-- The canonical themes of each quote. quotes.themes keeps the same names
+-- as a JSON list for display and prompts.
+CREATE TABLE IF NOT EXISTS quote_themes (
+    quote_id INTEGER NOT NULL REFERENCES quotes(id) ON DELETE CASCADE,
+    theme_id INTEGER NOT NULL REFERENCES themes(id) ON DELETE CASCADE,
+    PRIMARY KEY (quote_id, theme_id)
+);
+
+CREATE INDEX idx_quote_themes_theme ON quote_themes(theme_id);
+
+INSERT OR IGNORE INTO themes (name) VALUES
+    ('suffering'),
+    ('faith'),
+    ('doubt'),
+    ('freedom'),
+    ('guilt'),
+    ('redemption'),
+    ('love'),
+    ('compassion'),
+    ('poverty'),
+    ('pride'),
+    ('humility'),
+    ('morality'),
+    ('crime'),
+    ('justice'),
+    ('death'),
+    ('beauty'),
+    ('happiness'),
+    ('despair'),
+    ('hope'),
+    ('loneliness'),
+    ('nihilism'),
+    ('reason'),
+    ('madness'),
+    ('family'),
+    ('society'),
+    ('power'),
+    ('human nature'),
+    ('jealousy'),
+    ('suicide');
+
+INSERT OR IGNORE INTO theme_aliases (alias, theme_id)
+SELECT seed.column1, themes.id
+FROM (VALUES
+    ('pain', 'suffering'), ('human suffering', 'suffering'), ('anguish', 'suffering'), ('torment', 'suffering'), ('misery', 'suffering'),
+    ('religion', 'faith'), ('belief', 'faith'), ('god', 'faith'), ('christianity', 'faith'), ('spirituality', 'faith'),
+    ('skepticism', 'doubt'), ('unbelief', 'doubt'), ('atheism', 'doubt'),
+    ('free will', 'freedom'), ('liberty', 'freedom'), ('autonomy', 'freedom'),
+    ('remorse', 'guilt'), ('conscience', 'guilt'), ('shame', 'guilt'),
+    ('salvation', 'redemption'), ('forgiveness', 'redemption'), ('repentance', 'redemption'),
+    ('romance', 'love'), ('romantic love', 'love'),
+    ('pity', 'compassion'), ('mercy', 'compassion'), ('empathy', 'compassion'), ('kindness', 'compassion'),
+    ('destitution', 'poverty'), ('debt', 'poverty'), ('money', 'poverty'),
+    ('vanity', 'pride'), ('arrogance', 'pride'), ('ego', 'pride'),
+    ('meekness', 'humility'),
+    ('ethics', 'morality'), ('good and evil', 'morality'), ('virtue', 'morality'),
+    ('murder', 'crime'), ('violence', 'crime'),
+    ('punishment', 'justice'), ('judgment', 'justice'),
+    ('mortality', 'death'),
+    ('aesthetics', 'beauty'),
+    ('joy', 'happiness'),
+    ('hopelessness', 'despair'),
+    ('isolation', 'loneliness'), ('alienation', 'loneliness'), ('solitude', 'loneliness'),
+    ('meaninglessness', 'nihilism'),
+    ('rationalism', 'reason'), ('intellect', 'reason'), ('logic', 'reason'),
+    ('insanity', 'madness'), ('mental illness', 'madness'),
+    ('fatherhood', 'family'), ('parenthood', 'family'), ('children', 'family'),
+    ('social class', 'society'), ('class', 'society'),
+    ('ambition', 'power'), ('domination', 'power'),
+    ('humanity', 'human nature'), ('the human condition', 'human nature'), ('human condition', 'human nature'),
+    ('envy', 'jealousy')
+) AS seed
+JOIN themes ON themes.name = seed.column2;
+
+-- +migrate Down
+DROP TABLE IF EXISTS quote_themes;
+DROP TABLE IF EXISTS theme_aliases;
+DROP TABLE IF EXISTS themes;
@@ -0,0 +1,132 @@
+-- +migrate Up
+-- A registry of each book's characters. The LLM names speakers freely
+-- ("Raskolnikov", "Rodion Romanovitch", "Rodya"); names are looked up by
+-- their lowercase form among the book's character names and aliases, and
+-- quotes.character stores the canonical name, which is also what posts
+-- credit.
+CREATE TABLE IF NOT EXISTS characters (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    source_book TEXT NOT NULL,       -- quotes.source_book
+    name TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (source_book, name)
+);
+
+CREATE TABLE IF NOT EXISTS character_aliases (
+    source_book TEXT NOT NULL,
+    alias TEXT NOT NULL,             -- lowercase
+    character_id INTEGER NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+    PRIMARY KEY (source_book, alias)
+);
+
+CREATE INDEX idx_character_aliases_character ON character_aliases(character_id);
+
+INSERT OR IGNORE INTO characters (source_book, name) VALUES
+    ('Crime and Punishment', 'Raskolnikov'),
+    ('Crime and Punishment', 'Sonia'),
+    ('Crime and Punishment', 'Marmeladov'),
+    ('Crime and Punishment', 'Katerina Ivanovna'),
+    ('Crime and Punishment', 'Porfiry Petrovitch'),
+    ('Crime and Punishment', 'Razumihin'),
+    ('Crime and Punishment', 'Svidrigaïlov'),
+    ('Crime and Punishment', 'Dounia'),
+    ('Crime and Punishment', 'Luzhin'),
+    ('The Brothers Karamazov', 'Alyosha'),
+    ('The Brothers Karamazov', 'Ivan'),
+    ('The Brothers Karamazov', 'Dmitri'),
+    ('The Brothers Karamazov', 'Fyodor Pavlovitch'),
+    ('The Brothers Karamazov', 'Father Zossima'),
+    ('The Brothers Karamazov', 'Smerdyakov'),
+    ('The Brothers Karamazov', 'Grushenka'),
+    ('The Brothers Karamazov', 'The Grand Inquisitor'),
+    ('Notes from Underground', 'The Underground Man'),
+    ('The Idiot', 'Prince Myshkin'),
+    ('The Idiot', 'Nastasia Philipovna'),
+    ('The Idiot', 'Rogojin'),
+    ('The Idiot', 'Aglaya'),
+    ('The Idiot', 'Hippolyte'),
+    ('The Possessed', 'Stavrogin'),
+    ('The Possessed', 'Kirillov'),
+    ('The Possessed', 'Shatov'),
+    ('The Possessed', 'Pyotr Stepanovitch'),
+    ('The Possessed', 'Stepan Trofimovitch'),
+    ('The Gambler', 'Alexis Ivanovitch'),
+    ('The Gambler', 'Polina'),
+    ('Poor Folk', 'Makar Dievushkin'),
+    ('Poor Folk', 'Barbara');
+
+INSERT OR IGNORE INTO character_aliases (source_book, alias, character_id)
+SELECT characters.source_book, seed.column3, characters.id
+FROM (VALUES
+    ('Crime and Punishment', 'Raskolnikov', 'rodion raskolnikov'),
+    ('Crime and Punishment', 'Raskolnikov', 'rodion romanovitch'),
+    ('Crime and Punishment', 'Raskolnikov', 'rodion romanovich'),
+    ('Crime and Punishment', 'Raskolnikov', 'rodion romanovitch raskolnikov'),
+    ('Crime and Punishment', 'Raskolnikov', 'rodya'),
+    ('Crime and Punishment', 'Sonia', 'sonya'),
+    ('Crime and Punishment', 'Sonia', 'sonia marmeladov'),
+    ('Crime and Punishment', 'Sonia', 'sofya semyonovna'),
+    ('Crime and Punishment', 'Sonia', 'sonechka'),
+    ('Crime and Punishment', 'Marmeladov', 'semyon zaharovitch'),
+    ('Crime and Punishment', 'Marmeladov', 'semyon marmeladov'),
+    ('Crime and Punishment', 'Katerina Ivanovna', 'katerina ivanovna marmeladov'),
+    ('Crime and Punishment', 'Porfiry Petrovitch', 'porfiry'),
+    ('Crime and Punishment', 'Porfiry Petrovitch', 'porfiry petrovich'),
+    ('Crime and Punishment', 'Razumihin', 'razumikhin'),
+    ('Crime and Punishment', 'Razumihin', 'dmitri prokofitch'),
+    ('Crime and Punishment', 'Svidrigaïlov', 'svidrigailov'),
+    ('Crime and Punishment', 'Svidrigaïlov', 'arkady ivanovitch'),
+    ('Crime and Punishment', 'Dounia', 'dunya'),
+    ('Crime and Punishment', 'Dounia', 'avdotya romanovna'),
+    ('Crime and Punishment', 'Luzhin', 'pyotr petrovitch'),
+    ('The Brothers Karamazov', 'Alyosha', 'alexey'),
+    ('The Brothers Karamazov', 'Alyosha', 'alexey fyodorovitch'),
+    ('The Brothers Karamazov', 'Alyosha', 'alyosha karamazov'),
+    ('The Brothers Karamazov', 'Alyosha', 'alexei karamazov'),
+    ('The Brothers Karamazov', 'Ivan', 'ivan fyodorovitch'),
+    ('The Brothers Karamazov', 'Ivan', 'ivan karamazov'),
+    ('The Brothers Karamazov', 'Dmitri', 'mitya'),
+    ('The Brothers Karamazov', 'Dmitri', 'dmitri fyodorovitch'),
+    ('The Brothers Karamazov', 'Dmitri', 'dmitri karamazov'),
+    ('The Brothers Karamazov', 'Fyodor Pavlovitch', 'fyodor karamazov'),
+    ('The Brothers Karamazov', 'Fyodor Pavlovitch', 'fyodor pavlovich'),
+    ('The Brothers Karamazov', 'Father Zossima', 'zossima'),
+    ('The Brothers Karamazov', 'Father Zossima', 'zosima'),
+    ('The Brothers Karamazov', 'Father Zossima', 'elder zossima'),
+    ('The Brothers Karamazov', 'Grushenka', 'agrafena alexandrovna'),
+    ('The Brothers Karamazov', 'The Grand Inquisitor', 'grand inquisitor'),
+    ('Notes from Underground', 'The Underground Man', 'underground man'),
+    ('The Idiot', 'Prince Myshkin', 'myshkin'),
+    ('The Idiot', 'Prince Myshkin', 'the prince'),
+    ('The Idiot', 'Prince Myshkin', 'lev nikolaievitch'),
+    ('The Idiot', 'Prince Myshkin', 'prince lev nikolaievitch myshkin'),
+    ('The Idiot', 'Nastasia Philipovna', 'nastasya filippovna'),
+    ('The Idiot', 'Nastasia Philipovna', 'nastasia'),
+    ('The Idiot', 'Rogojin', 'rogozhin'),
+    ('The Idiot', 'Rogojin', 'parfen rogojin'),
+    ('The Idiot', 'Aglaya', 'aglaya ivanovna'),
+    ('The Idiot', 'Aglaya', 'aglaya epanchin'),
+    ('The Idiot', 'Hippolyte', 'ippolit'),
+    ('The Possessed', 'Stavrogin', 'nikolay stavrogin'),
+    ('The Possessed', 'Stavrogin', 'nikolay vsyevolodovitch'),
+    ('The Possessed', 'Kirillov', 'alexey nilitch'),
+    ('The Possessed', 'Shatov', 'ivan shatov'),
+    ('The Possessed', 'Pyotr Stepanovitch', 'pyotr verhovensky'),
+    ('The Possessed', 'Pyotr Stepanovitch', 'pyotr verkhovensky'),
+    ('The Possessed', 'Stepan Trofimovitch', 'stepan verhovensky'),
+    ('The Possessed', 'Stepan Trofimovitch', 'stepan trofimovich'),
+    ('The Gambler', 'Alexis Ivanovitch', 'alexis'),
+    ('The Gambler', 'Alexis Ivanovitch', 'alexei ivanovich'),
+    ('The Gambler', 'Polina', 'polina alexandrovna'),
+    ('Poor Folk', 'Makar Dievushkin', 'makar'),
+    ('Poor Folk', 'Makar Dievushkin', 'makar alexievitch'),
+    ('Poor Folk', 'Makar Dievushkin', 'makar devushkin'),
+    ('Poor Folk', 'Barbara', 'varvara'),
+    ('Poor Folk', 'Barbara', 'barbara dobroselova'),
+    ('Poor Folk', 'Barbara', 'varinka')
+) AS seed
+JOIN characters ON characters.source_book = seed.column1 AND characters.name = seed.column2;
+
+-- +migrate Down
+DROP TABLE IF EXISTS character_aliases;
+DROP TABLE IF EXISTS characters;
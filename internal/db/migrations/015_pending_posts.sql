@@ -0,0 +1,27 @@
+-- +migrate Up
+-- Matches waiting for a reviewer when POST_APPROVAL is on. A reviewer
+-- approves, rejects or edits each one; the scheduler posts approved ones in
+-- its next slot. Matches not posted by expires_at expire.
+CREATE TABLE IF NOT EXISTS pending_posts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    quote_id INTEGER NOT NULL REFERENCES quotes(id) ON DELETE CASCADE,
+    trend_id INTEGER REFERENCES trends(id),
+    trend_title TEXT NOT NULL,
+    trend_source TEXT NOT NULL,
+    trend_hash TEXT NOT NULL,
+    text TEXT NOT NULL,                  -- the post, as a reviewer last edited it
+    langs TEXT NOT NULL DEFAULT '[]',    -- JSON list of the post's languages
+    relevance_score REAL NOT NULL,
+    relevance_reasoning TEXT,
+    vector_similarity REAL NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending', -- pending, approved, rejected, expired, posted
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    expires_at DATETIME NOT NULL,
+    reviewed_at DATETIME,
+    post_id INTEGER REFERENCES posts(id)
+);
+
+CREATE INDEX idx_pending_posts_status ON pending_posts(status, id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS pending_posts;
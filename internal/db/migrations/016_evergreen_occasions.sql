@@ -0,0 +1,35 @@
+-- +migrate Up
+-- Calendar occasions evergreen posts are tied to: an author's birthday, the
+-- month a book began appearing, a season or a day of the week. An occasion
+-- applies on the days matching all of its months, day and weekday, and
+-- narrows the quotes posted to its author, book and theme. More specific
+-- occasions (a day, then months, then a weekday) are tried first.
+CREATE TABLE IF NOT EXISTS evergreen_occasions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,       -- recorded as the post's trend title
+    month INTEGER,                   -- 1-12; NULL for every month
+    end_month INTEGER,               -- last month of a range such as 12 to 2; NULL for month alone
+    day INTEGER,                     -- 1-31; NULL for the whole month
+    weekday INTEGER,                 -- 0 (Sunday) to 6; NULL for any day
+    author TEXT,                     -- books.author
+    source_book TEXT,                -- quotes.source_book
+    theme TEXT,                      -- themes.name
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT OR IGNORE INTO evergreen_occasions (name, month, end_month, day, weekday, author, source_book, theme) VALUES
+    ('Dostoyevsky''s birthday', 11, NULL, 11, NULL, 'Fyodor Dostoyevsky', NULL, NULL),
+    ('Anniversary of Dostoyevsky''s death', 2, NULL, 9, NULL, 'Fyodor Dostoyevsky', NULL, 'death'),
+    ('Poor Folk first appeared in January 1846', 1, NULL, NULL, NULL, NULL, 'Poor Folk', NULL),
+    ('Crime and Punishment began appearing in January 1866', 1, NULL, NULL, NULL, NULL, 'Crime and Punishment', NULL),
+    ('The Idiot began appearing in January 1868', 1, NULL, NULL, NULL, NULL, 'The Idiot', NULL),
+    ('The Brothers Karamazov began appearing in January 1879', 1, NULL, NULL, NULL, NULL, 'The Brothers Karamazov', NULL),
+    ('Winter', 12, 2, NULL, NULL, NULL, NULL, 'compassion'),
+    ('Spring', 3, 5, NULL, NULL, NULL, NULL, 'hope'),
+    ('Summer', 6, 8, NULL, NULL, NULL, NULL, 'beauty'),
+    ('Autumn', 9, 11, NULL, NULL, NULL, NULL, 'loneliness'),
+    ('Sunday', NULL, NULL, NULL, 0, NULL, NULL, 'faith'),
+    ('Monday', NULL, NULL, NULL, 1, NULL, NULL, 'suffering');
+
+-- +migrate Down
+DROP TABLE IF EXISTS evergreen_occasions;
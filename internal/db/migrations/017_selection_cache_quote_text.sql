@@ -0,0 +1,33 @@
+-- +migrate Up
+-- Key cached selections on the candidates' text as well as their IDs, so an
+-- edited quote is judged again. Entries made without it cannot be checked
+-- against the quotes they judged, so they are dropped.
+DROP TABLE IF EXISTS selection_cache;
+CREATE TABLE selection_cache (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    trend_hash TEXT NOT NULL,
+    quote_ids TEXT NOT NULL,          -- candidate IDs in prompt order, comma-separated
+    quotes_hash TEXT NOT NULL,        -- hash of the candidates' text in prompt order
+    prompt_version TEXT NOT NULL,
+    model TEXT NOT NULL,
+    result TEXT NOT NULL,             -- JSON batch evaluation
+    hits INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_hit_at DATETIME,
+    UNIQUE (trend_hash, quote_ids, quotes_hash, prompt_version, model)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS selection_cache;
+CREATE TABLE selection_cache (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    trend_hash TEXT NOT NULL,
+    quote_ids TEXT NOT NULL,
+    prompt_version TEXT NOT NULL,
+    model TEXT NOT NULL,
+    result TEXT NOT NULL,
+    hits INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_hit_at DATETIME,
+    UNIQUE (trend_hash, quote_ids, prompt_version, model)
+);
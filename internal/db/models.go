@@ -6,14 +6,56 @@ package db
 
 import (
 	"database/sql"
+	"time"
 )
 
+type Book struct {
+	ID           int64          `json:"id"`
+	Title        string         `json:"title"`
+	Author       string         `json:"author"`
+	Translator   sql.NullString `json:"translator"`
+	GutenbergID  sql.NullInt64  `json:"gutenberg_id"`
+	FilePath     string         `json:"file_path"`
+	Language     string         `json:"language"`
+	CreatedAt    sql.NullTime   `json:"created_at"`
+	Edition      sql.NullString `json:"edition"`
+	Checksum     sql.NullString `json:"checksum"`
+	DownloadedAt sql.NullTime   `json:"downloaded_at"`
+	OriginalID   sql.NullInt64  `json:"original_id"`
+}
+
+type Character struct {
+	ID         int64        `json:"id"`
+	SourceBook string       `json:"source_book"`
+	Name       string       `json:"name"`
+	CreatedAt  sql.NullTime `json:"created_at"`
+}
+
+type CharacterAlias struct {
+	SourceBook  string `json:"source_book"`
+	Alias       string `json:"alias"`
+	CharacterID int64  `json:"character_id"`
+}
+
 type Config struct {
 	Key       string       `json:"key"`
 	Value     string       `json:"value"`
 	UpdatedAt sql.NullTime `json:"updated_at"`
 }
 
+type EvergreenOccasion struct {
+	ID         int64          `json:"id"`
+	Name       string         `json:"name"`
+	Month      sql.NullInt64  `json:"month"`
+	EndMonth   sql.NullInt64  `json:"end_month"`
+	Day        sql.NullInt64  `json:"day"`
+	Weekday    sql.NullInt64  `json:"weekday"`
+	Author     sql.NullString `json:"author"`
+	SourceBook sql.NullString `json:"source_book"`
+	Theme      sql.NullString `json:"theme"`
+	CreatedAt  sql.NullTime   `json:"created_at"`
+}
+
 type ExtractionJob struct {
 	ID              int64          `json:"id"`
 	BookTitle       string         `json:"book_title"`
@@ -26,6 +68,26 @@ type ExtractionJob struct {
 	StartedAt       sql.NullTime   `json:"started_at"`
 	CompletedAt     sql.NullTime   `json:"completed_at"`
 	CreatedAt       sql.NullTime   `json:"created_at"`
+	FileHash        sql.NullString `json:"file_hash"`
+}
+
+type PendingPost struct {
+	ID                 int64          `json:"id"`
+	QuoteID            int64          `json:"quote_id"`
+	TrendID            sql.NullInt64  `json:"trend_id"`
+	TrendTitle         string         `json:"trend_title"`
+	TrendSource        string         `json:"trend_source"`
+	TrendHash          string         `json:"trend_hash"`
+	Text               string         `json:"text"`
+	Langs              string         `json:"langs"`
+	RelevanceScore     float64        `json:"relevance_score"`
+	RelevanceReasoning sql.NullString `json:"relevance_reasoning"`
+	VectorSimilarity   float64        `json:"vector_similarity"`
+	Status             string         `json:"status"`
+	CreatedAt          sql.NullTime   `json:"created_at"`
+	ExpiresAt          time.Time      `json:"expires_at"`
+	ReviewedAt         sql.NullTime   `json:"reviewed_at"`
+	PostID             sql.NullInt64  `json:"post_id"`
 }
 
 type Post struct {
@@ -61,19 +123,76 @@ type Quote struct {
 	TimesPosted     sql.NullInt64  `json:"times_posted"`
 	LastPostedAt    sql.NullTime   `json:"last_posted_at"`
 	CreatedAt       sql.NullTime   `json:"created_at"`
+	EmbeddingModel  sql.NullString `json:"embedding_model"`
+	EmbeddingDim    sql.NullInt64  `json:"embedding_dim"`
+	QualityScore    sql.NullInt64  `json:"quality_score"`
+	QualityIssues   sql.NullString `json:"quality_issues"`
+	QualityVerdict  sql.NullString `json:"quality_verdict"`
+	ValidatedAt     sql.NullTime   `json:"validated_at"`
+	SourceStartLine sql.NullInt64  `json:"source_start_line"`
+	SourceEndLine   sql.NullInt64  `json:"source_end_line"`
+	VerbatimEdits   sql.NullInt64  `json:"verbatim_edits"`
+	Language        string         `json:"language"`
+	GroupID         sql.NullInt64  `json:"group_id"`
+	CurationStatus  sql.NullString `json:"curation_status"`
+	Favorite        bool           `json:"favorite"`
+	CuratedAt       sql.NullTime   `json:"curated_at"`
+}
+
+type QuoteGroup struct {
+	ID        int64        `json:"id"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type QuoteTheme struct {
+	QuoteID int64 `json:"quote_id"`
+	ThemeID int64 `json:"theme_id"`
+}
+
+type QuoteTranslation struct {
+	OriginalID    int64         `json:"original_id"`
+	TranslationID int64         `json:"translation_id"`
+	Edits         sql.NullInt64 `json:"edits"`
+	CreatedAt     sql.NullTime  `json:"created_at"`
+}
+
+type SelectionCache struct {
+	ID            int64        `json:"id"`
+	TrendHash     string       `json:"trend_hash"`
+	QuoteIds      string       `json:"quote_ids"`
+	QuotesHash    string       `json:"quotes_hash"`
+	PromptVersion string       `json:"prompt_version"`
+	Model         string       `json:"model"`
+	Result        string       `json:"result"`
+	Hits          int64        `json:"hits"`
+	CreatedAt     sql.NullTime `json:"created_at"`
+	LastHitAt     sql.NullTime `json:"last_hit_at"`
+}
+
+type Theme struct {
+	ID        int64        `json:"id"`
+	Name      string       `json:"name"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type ThemeAlias struct {
+	Alias   string `json:"alias"`
+	ThemeID int64  `json:"theme_id"`
 }
 
 type Trend struct {
-	ID          int64          `json:"id"`
-	Source      string         `json:"source"`
-	ExternalID  sql.NullString `json:"external_id"`
-	Title       string         `json:"title"`
-	Url         sql.NullString `json:"url"`
-	Description sql.NullString `json:"description"`
-	Score       sql.NullInt64  `json:"score"`
-	Embedding   []byte         `json:"embedding"`
-	Matched     sql.NullBool   `json:"matched"`
-	Skipped     sql.NullBool   `json:"skipped"`
-	SkipReason  sql.NullString `json:"skip_reason"`
-	DetectedAt  sql.NullTime   `json:"detected_at"`
+	ID             int64          `json:"id"`
+	Source         string         `json:"source"`
+	ExternalID     sql.NullString `json:"external_id"`
+	Title          string         `json:"title"`
+	Url            sql.NullString `json:"url"`
+	Description    sql.NullString `json:"description"`
+	Score          sql.NullInt64  `json:"score"`
+	Embedding      []byte         `json:"embedding"`
+	Matched        sql.NullBool   `json:"matched"`
+	Skipped        sql.NullBool   `json:"skipped"`
+	SkipReason     sql.NullString `json:"skip_reason"`
+	DetectedAt     sql.NullTime   `json:"detected_at"`
+	EmbeddingModel sql.NullString `json:"embedding_model"`
+	EmbeddingDim   sql.NullInt64  `json:"embedding_dim"`
 }
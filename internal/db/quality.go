@@ -0,0 +1,14 @@
+package db
+
+// Quality verdicts stored in quotes.quality_verdict.
+const (
+	VerdictApprove = "approve"
+	VerdictReject  = "reject"
+	VerdictEdit    = "edit" // usable, but the text should be trimmed or fixed
+)
+
+// Rejected reports whether the quality rubric rejected the quote. Rejected
+// quotes are kept so they are not extracted again, but are never matched.
+func (q *Quote) Rejected() bool {
+	return q.QualityVerdict.Valid && q.QualityVerdict.String == VerdictReject
+}
@@ -11,7 +11,9 @@ SELECT * FROM quotes ORDER BY created_at DESC LIMIT ? OFFSET ?;
 SELECT * FROM quotes WHERE source_book = ? ORDER BY created_at DESC;
 
 -- name: ListQuotesWithEmbeddings :many
-SELECT * FROM quotes WHERE embedding IS NOT NULL ORDER BY id;
+SELECT * FROM quotes
+WHERE embedding IS NOT NULL AND (quality_verdict IS NULL OR quality_verdict != 'reject')
+ORDER BY id;
 
 -- name: ListQuotesWithoutEmbeddings :many
 SELECT * FROM quotes WHERE embedding IS NULL ORDER BY id LIMIT ?;
@@ -25,21 +27,150 @@ SELECT COUNT(*) FROM quotes WHERE embedding IS NOT NULL;
 -- name: CountQuotesByBook :many
 SELECT source_book, COUNT(*) as count FROM quotes GROUP BY source_book;
 
+-- name: CountQuotesByVerdict :many
+SELECT COALESCE(quality_verdict, '') AS verdict, COUNT(*) AS count
+FROM quotes GROUP BY quality_verdict ORDER BY verdict;
+
+-- name: CountQuotesByLanguage :many
+SELECT language, COUNT(*) AS count FROM quotes GROUP BY language ORDER BY language;
+
+-- name: CountQuotesByCuration :many
+SELECT COALESCE(curation_status, '') AS status, COUNT(*) AS count
+FROM quotes GROUP BY curation_status ORDER BY status;
+
+-- name: ListUnvalidatedQuotes :many
+SELECT * FROM quotes WHERE validated_at IS NULL ORDER BY id LIMIT ?;
+
 -- name: CreateQuote :one
 INSERT INTO quotes (
     text, text_hash, source_book, chapter, character,
-    themes, modern_relevance, char_count
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+    themes, modern_relevance, char_count,
+    source_start_line, source_end_line, verbatim_edits, language
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: UpdateQuoteEmbedding :exec
-UPDATE quotes SET embedding = ? WHERE id = ?;
+UPDATE quotes SET embedding = ?, embedding_model = ?, embedding_dim = ? WHERE id = ?;
 
 -- name: UpdateQuotePosted :exec
 UPDATE quotes
 SET times_posted = times_posted + 1, last_posted_at = CURRENT_TIMESTAMP
 WHERE id = ?;
 
+-- name: UpdateQuoteValidation :exec
+UPDATE quotes
+SET quality_score = ?, quality_issues = ?, quality_verdict = ?, validated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: SetQuotePostHistory :exec
+UPDATE quotes SET times_posted = ?, last_posted_at = ? WHERE id = ?;
+
+-- name: DeleteQuote :exec
+DELETE FROM quotes WHERE id = ?;
+
+-- name: ListUntranslatedQuotes :many
+SELECT * FROM quotes
+WHERE source_book = sqlc.arg(original_book)
+  AND (quality_verdict IS NULL OR quality_verdict != 'reject')
+  AND NOT EXISTS (
+      SELECT 1 FROM quote_translations qt
+      JOIN quotes t ON t.id = qt.translation_id
+      WHERE qt.original_id = quotes.id AND t.source_book = sqlc.arg(translation_book)
+  )
+ORDER BY id;
+
+-- name: CreateQuoteTranslation :exec
+INSERT OR IGNORE INTO quote_translations (original_id, translation_id, edits)
+VALUES (?, ?, ?);
+
+-- name: GetQuoteOriginal :one
+SELECT quotes.* FROM quotes
+JOIN quote_translations qt ON qt.original_id = quotes.id
+WHERE qt.translation_id = ?
+ORDER BY quotes.id LIMIT 1;
+
+-- name: ListQuoteTranslations :many
+SELECT quotes.* FROM quotes
+JOIN quote_translations qt ON qt.translation_id = quotes.id
+WHERE qt.original_id = ?
+ORDER BY quotes.id;
+
+-- name: CountQuoteTranslations :one
+SELECT COUNT(*) FROM quote_translations;
+
+-- name: ReassignTranslationOriginals :exec
+UPDATE OR IGNORE quote_translations SET original_id = sqlc.arg(keeper_id) WHERE original_id = sqlc.arg(duplicate_id);
+
+-- name: ReassignTranslationTargets :exec
+UPDATE OR IGNORE quote_translations SET translation_id = sqlc.arg(keeper_id) WHERE translation_id = sqlc.arg(duplicate_id);
+
+-- name: CreateQuoteGroup :one
+INSERT INTO quote_groups DEFAULT VALUES
+RETURNING *;
+
+-- name: SetQuoteGroup :exec
+UPDATE quotes SET group_id = ? WHERE id = ?;
+
+-- name: MoveQuoteGroup :exec
+UPDATE quotes SET group_id = sqlc.arg(keeper_id) WHERE group_id = sqlc.arg(group_id);
+
+-- name: DeleteEmptyQuoteGroups :exec
+DELETE FROM quote_groups
+WHERE id NOT IN (SELECT group_id FROM quotes WHERE group_id IS NOT NULL);
+
+-- name: ListGroupQuotes :many
+SELECT * FROM quotes WHERE group_id = ? ORDER BY id;
+
+-- name: ListCurationQuotes :many
+SELECT * FROM quotes
+WHERE id > sqlc.arg(after_id)
+  AND source_book LIKE sqlc.arg(book) ESCAPE '\'
+  AND COALESCE(character, '') LIKE sqlc.arg(character) ESCAPE '\'
+  AND (sqlc.arg(theme) = '' OR id IN (
+    SELECT qt.quote_id FROM quote_themes qt
+    JOIN themes t ON t.id = qt.theme_id
+    WHERE t.name = sqlc.arg(theme)
+  ))
+  AND COALESCE(quality_score, 0) >= sqlc.arg(min_quality)
+  AND COALESCE(times_posted, 0) <= sqlc.arg(max_times_posted)
+  AND COALESCE(curation_status, '') LIKE sqlc.arg(status) ESCAPE '\'
+  AND favorite >= sqlc.arg(min_favorite)
+ORDER BY id
+LIMIT sqlc.arg(limit);
+
+-- name: ListEvergreenQuotes :many
+SELECT * FROM quotes
+WHERE COALESCE(quality_verdict, '') != 'reject'
+  AND COALESCE(curation_status, '') != 'rejected'
+  AND source_book LIKE sqlc.arg(book) ESCAPE '\'
+  AND COALESCE((SELECT author FROM books WHERE books.title = quotes.source_book LIMIT 1), '') LIKE sqlc.arg(author) ESCAPE '\'
+  AND (sqlc.arg(theme) = '' OR id IN (
+    SELECT qt.quote_id FROM quote_themes qt
+    JOIN themes t ON t.id = qt.theme_id
+    WHERE t.name = sqlc.arg(theme)
+  ))
+ORDER BY last_posted_at IS NOT NULL, last_posted_at, favorite DESC, COALESCE(quality_score, 0) DESC, id
+LIMIT sqlc.arg(limit);
+
+-- name: SetQuoteCuration :exec
+UPDATE quotes SET curation_status = ?, curated_at = CURRENT_TIMESTAMP WHERE id = ?;
+
+-- name: SetQuoteFavorite :exec
+UPDATE quotes SET favorite = ?, curated_at = CURRENT_TIMESTAMP WHERE id = ?;
+
+-- name: UpdateQuoteText :exec
+UPDATE quotes
+SET text = ?, text_hash = ?, char_count = ?, embedding = NULL,
+    source_start_line = NULL, source_end_line = NULL, verbatim_edits = NULL,
+    curated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: UpdateQuoteThemes :exec
+UPDATE quotes SET themes = ?, curated_at = CURRENT_TIMESTAMP WHERE id = ?;
+
+-- name: CountQuoteGroups :one
+SELECT COUNT(*) FROM quote_groups;
+
 -- name: GetPost :one
 SELECT * FROM posts WHERE id = ? LIMIT 1;
 
@@ -51,7 +182,15 @@ SELECT * FROM posts WHERE platform = ? ORDER BY posted_at DESC LIMIT ?;
 
 -- name: CountPostsToday :one
 SELECT COUNT(*) FROM posts
-WHERE platform = ? AND posted_at >= date('now');
+WHERE platform = ? AND posted_at >= datetime(sqlc.arg(since));
+
+-- name: CountSourcePostsToday :one
+SELECT COUNT(*) FROM posts
+WHERE platform = ? AND trend_source = ? AND posted_at >= datetime(sqlc.arg(since));
+
+-- name: CountPostsBySource :many
+SELECT trend_source, COUNT(*) AS count FROM posts
+GROUP BY trend_source ORDER BY count DESC, trend_source;
 
 -- name: GetPostByTrendHash :one
 SELECT * FROM posts WHERE trend_hash = ? AND platform = ? LIMIT 1;
@@ -64,6 +203,9 @@ INSERT INTO posts (
 ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
+-- name: ReassignQuotePosts :exec
+UPDATE posts SET quote_id = sqlc.arg(keeper_id) WHERE quote_id = sqlc.arg(duplicate_id);
+
 -- name: UpdatePostEngagement :exec
 UPDATE posts SET likes = ?, reposts = ?, replies = ? WHERE id = ?;
 
@@ -90,7 +232,7 @@ UPDATE trends SET matched = TRUE WHERE id = ?;
 UPDATE trends SET skipped = TRUE, skip_reason = ? WHERE id = ?;
 
 -- name: UpdateTrendEmbedding :exec
-UPDATE trends SET embedding = ? WHERE id = ?;
+UPDATE trends SET embedding = ?, embedding_model = ?, embedding_dim = ? WHERE id = ?;
 
 -- name: GetExtractionJob :one
 SELECT * FROM extraction_jobs WHERE id = ? LIMIT 1;
@@ -98,17 +240,23 @@ SELECT * FROM extraction_jobs WHERE id = ? LIMIT 1;
 -- name: GetExtractionJobByBook :one
 SELECT * FROM extraction_jobs WHERE book_title = ? ORDER BY created_at DESC LIMIT 1;
 
+-- name: GetLatestExtractionJobForFile :one
+SELECT * FROM extraction_jobs
+WHERE book_title = ? AND file_hash = ?
+ORDER BY id DESC LIMIT 1;
+
 -- name: ListExtractionJobs :many
 SELECT * FROM extraction_jobs ORDER BY created_at DESC;
 
 -- name: CreateExtractionJob :one
-INSERT INTO extraction_jobs (book_title, file_path, status)
-VALUES (?, ?, 'pending')
+INSERT INTO extraction_jobs (book_title, file_path, file_hash, status)
+VALUES (?, ?, ?, 'pending')
 RETURNING *;
 
 -- name: UpdateExtractionJobStarted :exec
 UPDATE extraction_jobs
-SET status = 'running', total_chunks = ?, started_at = CURRENT_TIMESTAMP
+SET status = 'running', total_chunks = ?, error_message = NULL, completed_at = NULL,
+    started_at = COALESCE(started_at, CURRENT_TIMESTAMP)
 WHERE id = ?;
 
 -- name: UpdateExtractionJobProgress :exec
@@ -135,3 +283,208 @@ ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = CURRENT_TIME
 
 -- name: ListConfig :many
 SELECT * FROM config ORDER BY key;
+
+-- name: GetSelectionCache :one
+SELECT * FROM selection_cache
+WHERE trend_hash = ? AND quote_ids = ? AND quotes_hash = ? AND prompt_version = ? AND model = ?
+LIMIT 1;
+
+-- name: CreateSelectionCache :exec
+INSERT INTO selection_cache (trend_hash, quote_ids, quotes_hash, prompt_version, model, result)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT(trend_hash, quote_ids, quotes_hash, prompt_version, model) DO UPDATE SET result = excluded.result;
+
+-- name: RecordSelectionCacheHit :exec
+UPDATE selection_cache
+SET hits = hits + 1, last_hit_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: GetSelectionCacheStats :one
+SELECT COUNT(*) AS entries, CAST(COALESCE(SUM(hits), 0) AS INTEGER) AS hits
+FROM selection_cache;
+
+-- name: GetBook :one
+SELECT * FROM books WHERE id = ? LIMIT 1;
+
+-- name: GetBookByTitle :one
+SELECT * FROM books WHERE title = ? LIMIT 1;
+
+-- name: GetBookByGutenbergID :one
+SELECT * FROM books WHERE gutenberg_id = ? LIMIT 1;
+
+-- name: ListBooks :many
+SELECT * FROM books ORDER BY title;
+
+-- name: CreateBook :one
+INSERT INTO books (title, author, translator, gutenberg_id, file_path, language)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: UpdateBookDownload :exec
+UPDATE books
+SET checksum = ?, edition = COALESCE(?, edition), translator = COALESCE(translator, ?),
+    downloaded_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: SetBookOriginal :exec
+UPDATE books SET original_id = ? WHERE id = ?;
+
+-- name: ListBookTranslations :many
+SELECT * FROM books WHERE original_id = ? ORDER BY title;
+
+-- name: DeleteBook :exec
+DELETE FROM books WHERE id = ?;
+
+-- name: ListThemes :many
+SELECT * FROM themes ORDER BY name;
+
+-- name: ListThemeAliases :many
+SELECT * FROM theme_aliases ORDER BY alias;
+
+-- name: GetThemeByName :one
+SELECT * FROM themes WHERE name = ? LIMIT 1;
+
+-- name: CreateTheme :one
+INSERT INTO themes (name) VALUES (?)
+RETURNING *;
+
+-- name: DeleteTheme :exec
+DELETE FROM themes WHERE id = ?;
+
+-- name: SetThemeAlias :exec
+INSERT INTO theme_aliases (alias, theme_id) VALUES (?, ?)
+ON CONFLICT(alias) DO UPDATE SET theme_id = excluded.theme_id;
+
+-- name: ReassignThemeAliases :exec
+UPDATE theme_aliases SET theme_id = sqlc.arg(keeper_id) WHERE theme_id = sqlc.arg(duplicate_id);
+
+-- name: AddQuoteTheme :exec
+INSERT OR IGNORE INTO quote_themes (quote_id, theme_id) VALUES (?, ?);
+
+-- name: ReassignQuoteThemes :exec
+UPDATE OR IGNORE quote_themes SET quote_id = sqlc.arg(keeper_id) WHERE quote_id = sqlc.arg(duplicate_id);
+
+-- name: DeleteQuoteThemes :exec
+DELETE FROM quote_themes WHERE quote_id = ?;
+
+-- name: SetQuoteThemes :exec
+UPDATE quotes SET themes = ? WHERE id = ?;
+
+-- name: ListThemeQuotes :many
+SELECT quotes.* FROM quotes
+JOIN quote_themes qt ON qt.quote_id = quotes.id
+WHERE qt.theme_id = ?
+ORDER BY quotes.id;
+
+-- name: CountQuotesByTheme :many
+SELECT t.name, COUNT(q.id) AS quotes,
+       CAST(COALESCE(SUM(CASE WHEN q.times_posted > 0 THEN 1 ELSE 0 END), 0) AS INTEGER) AS posted
+FROM themes t
+LEFT JOIN quote_themes qt ON qt.theme_id = t.id
+LEFT JOIN quotes q ON q.id = qt.quote_id
+GROUP BY t.id
+ORDER BY quotes DESC, t.name;
+
+-- name: CountThemeQuotesByBook :many
+SELECT q.source_book, COUNT(*) AS count
+FROM quotes q
+JOIN quote_themes qt ON qt.quote_id = q.id
+JOIN themes t ON t.id = qt.theme_id
+WHERE t.name = ?
+GROUP BY q.source_book
+ORDER BY count DESC, q.source_book;
+
+-- name: CountUntaggedQuotes :one
+SELECT COUNT(*) FROM quotes
+WHERE NOT EXISTS (SELECT 1 FROM quote_themes qt WHERE qt.quote_id = quotes.id);
+
+-- Characters
+
+-- name: ListBookCharacters :many
+SELECT * FROM characters WHERE source_book = ? ORDER BY name;
+
+-- name: ListBookCharacterAliases :many
+SELECT * FROM character_aliases WHERE source_book = ? ORDER BY alias;
+
+-- name: CreateCharacter :one
+INSERT INTO characters (source_book, name) VALUES (?, ?)
+RETURNING *;
+
+-- name: DeleteCharacter :exec
+DELETE FROM characters WHERE id = ?;
+
+-- name: SetCharacterAlias :exec
+INSERT INTO character_aliases (source_book, alias, character_id) VALUES (?, ?, ?)
+ON CONFLICT (source_book, alias) DO UPDATE SET character_id = excluded.character_id;
+
+-- name: ReassignCharacterAliases :exec
+UPDATE character_aliases SET character_id = sqlc.arg(keeper_id) WHERE character_id = sqlc.arg(duplicate_id);
+
+-- name: SetQuoteCharacter :exec
+UPDATE quotes SET character = ? WHERE id = ?;
+
+-- name: RenameQuoteCharacter :exec
+UPDATE quotes SET character = sqlc.arg(character)
+WHERE source_book = sqlc.arg(source_book) AND character = sqlc.arg(old_character);
+
+-- name: CountQuotesByCharacter :many
+SELECT character, COUNT(*) AS quotes,
+       CAST(COALESCE(SUM(CASE WHEN times_posted > 0 THEN 1 ELSE 0 END), 0) AS INTEGER) AS posted
+FROM quotes
+WHERE source_book = ? AND character IS NOT NULL
+GROUP BY character
+ORDER BY quotes DESC, character;
+
+-- Pending posts
+
+-- name: CreatePendingPost :one
+INSERT INTO pending_posts (
+    quote_id, trend_id, trend_title, trend_source, trend_hash, text, langs,
+    relevance_score, relevance_reasoning, vector_similarity, status, expires_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now', sqlc.arg(expires_in)))
+RETURNING *;
+
+-- name: GetPendingPost :one
+SELECT * FROM pending_posts WHERE id = ? LIMIT 1;
+
+-- name: ListOpenPendingPosts :many
+SELECT * FROM pending_posts WHERE status IN ('pending', 'approved') ORDER BY id;
+
+-- name: NextApprovedPendingPost :one
+SELECT * FROM pending_posts
+WHERE status = 'approved' AND expires_at > CURRENT_TIMESTAMP
+ORDER BY relevance_score DESC, id
+LIMIT 1;
+
+-- name: SetPendingPostStatus :exec
+UPDATE pending_posts SET status = ?, reviewed_at = CURRENT_TIMESTAMP WHERE id = ?;
+
+-- name: UpdatePendingPostText :exec
+UPDATE pending_posts SET text = ? WHERE id = ?;
+
+-- name: ExpirePendingPosts :exec
+UPDATE pending_posts SET status = 'expired'
+WHERE status IN ('pending', 'approved') AND expires_at <= CURRENT_TIMESTAMP;
+
+-- name: DiscardQuotePendingPosts :exec
+UPDATE pending_posts SET status = 'expired'
+WHERE quote_id = ? AND status IN ('pending', 'approved');
+
+-- name: ReassignQuotePendingPosts :exec
+UPDATE pending_posts SET quote_id = sqlc.arg(keeper_id) WHERE quote_id = sqlc.arg(duplicate_id);
+
+-- name: MarkPendingPostPosted :exec
+UPDATE pending_posts SET status = 'posted', post_id = ? WHERE id = ?;
+
+-- name: CountPendingPostsByStatus :many
+SELECT status, COUNT(*) AS count FROM pending_posts GROUP BY status ORDER BY status;
+
+-- name: ListEvergreenOccasions :many
+SELECT * FROM evergreen_occasions ORDER BY name;
+
+-- name: CreateEvergreenOccasion :exec
+INSERT INTO evergreen_occasions (name, month, end_month, day, weekday, author, source_book, theme)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: DeleteEvergreenOccasion :execrows
+DELETE FROM evergreen_occasions WHERE name = ?;
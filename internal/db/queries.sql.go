@@ -10,13 +10,119 @@ import (
 	"database/sql"
 )
 
+const addQuoteTheme = `-- name: AddQuoteTheme :exec
+INSERT OR IGNORE INTO quote_themes (quote_id, theme_id) VALUES (?, ?)
+`
+
+type AddQuoteThemeParams struct {
+	QuoteID int64 `json:"quote_id"`
+	ThemeID int64 `json:"theme_id"`
+}
+
+func (q *Queries) AddQuoteTheme(ctx context.Context, arg AddQuoteThemeParams) error {
+	_, err := q.db.ExecContext(ctx, addQuoteTheme, arg.QuoteID, arg.ThemeID)
+	return err
+}
+
+const countPendingPostsByStatus = `-- name: CountPendingPostsByStatus :many
+SELECT status, COUNT(*) AS count FROM pending_posts GROUP BY status ORDER BY status
+`
+
+type CountPendingPostsByStatusRow struct {
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+}
+
+func (q *Queries) CountPendingPostsByStatus(ctx context.Context) ([]*CountPendingPostsByStatusRow, error) {
+	rows, err := q.db.QueryContext(ctx, countPendingPostsByStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*CountPendingPostsByStatusRow{}
+	for rows.Next() {
+		var i CountPendingPostsByStatusRow
+		if err := rows.Scan(&i.Status, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countPostsBySource = `-- name: CountPostsBySource :many
+SELECT trend_source, COUNT(*) AS count FROM posts
+GROUP BY trend_source ORDER BY count DESC, trend_source
+`
+
+type CountPostsBySourceRow struct {
+	TrendSource string `json:"trend_source"`
+	Count       int64  `json:"count"`
+}
+
+func (q *Queries) CountPostsBySource(ctx context.Context) ([]*CountPostsBySourceRow, error) {
+	rows, err := q.db.QueryContext(ctx, countPostsBySource)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*CountPostsBySourceRow{}
+	for rows.Next() {
+		var i CountPostsBySourceRow
+		if err := rows.Scan(&i.TrendSource, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const countPostsToday = `-- name: CountPostsToday :one
 SELECT COUNT(*) FROM posts
-WHERE platform = ? AND posted_at >= date('now')
+WHERE platform = ? AND posted_at >= datetime(?)
+`
+
+type CountPostsTodayParams struct {
+	Platform string `json:"platform"`
+	Since    string `json:"since"`
+}
+
+func (q *Queries) CountPostsToday(ctx context.Context, arg CountPostsTodayParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPostsToday, arg.Platform, arg.Since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countQuoteGroups = `-- name: CountQuoteGroups :one
+SELECT COUNT(*) FROM quote_groups
+`
+
+func (q *Queries) CountQuoteGroups(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countQuoteGroups)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countQuoteTranslations = `-- name: CountQuoteTranslations :one
+SELECT COUNT(*) FROM quote_translations
 `
 
-func (q *Queries) CountPostsToday(ctx context.Context, platform string) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countPostsToday, platform)
+func (q *Queries) CountQuoteTranslations(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countQuoteTranslations)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -65,6 +171,181 @@ func (q *Queries) CountQuotesByBook(ctx context.Context) ([]*CountQuotesByBookRo
 	return items, nil
 }
 
+const countQuotesByCharacter = `-- name: CountQuotesByCharacter :many
+SELECT character, COUNT(*) AS quotes,
+       CAST(COALESCE(SUM(CASE WHEN times_posted > 0 THEN 1 ELSE 0 END), 0) AS INTEGER) AS posted
+FROM quotes
+WHERE source_book = ? AND character IS NOT NULL
+GROUP BY character
+ORDER BY quotes DESC, character
+`
+
+type CountQuotesByCharacterRow struct {
+	Character sql.NullString `json:"character"`
+	Quotes    int64          `json:"quotes"`
+	Posted    int64          `json:"posted"`
+}
+
+func (q *Queries) CountQuotesByCharacter(ctx context.Context, sourceBook string) ([]*CountQuotesByCharacterRow, error) {
+	rows, err := q.db.QueryContext(ctx, countQuotesByCharacter, sourceBook)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*CountQuotesByCharacterRow{}
+	for rows.Next() {
+		var i CountQuotesByCharacterRow
+		if err := rows.Scan(&i.Character, &i.Quotes, &i.Posted); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countQuotesByCuration = `-- name: CountQuotesByCuration :many
+SELECT COALESCE(curation_status, '') AS status, COUNT(*) AS count
+FROM quotes GROUP BY curation_status ORDER BY status
+`
+
+type CountQuotesByCurationRow struct {
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+}
+
+func (q *Queries) CountQuotesByCuration(ctx context.Context) ([]*CountQuotesByCurationRow, error) {
+	rows, err := q.db.QueryContext(ctx, countQuotesByCuration)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*CountQuotesByCurationRow{}
+	for rows.Next() {
+		var i CountQuotesByCurationRow
+		if err := rows.Scan(&i.Status, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countQuotesByLanguage = `-- name: CountQuotesByLanguage :many
+SELECT language, COUNT(*) AS count FROM quotes GROUP BY language ORDER BY language
+`
+
+type CountQuotesByLanguageRow struct {
+	Language string `json:"language"`
+	Count    int64  `json:"count"`
+}
+
+func (q *Queries) CountQuotesByLanguage(ctx context.Context) ([]*CountQuotesByLanguageRow, error) {
+	rows, err := q.db.QueryContext(ctx, countQuotesByLanguage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*CountQuotesByLanguageRow{}
+	for rows.Next() {
+		var i CountQuotesByLanguageRow
+		if err := rows.Scan(&i.Language, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countQuotesByTheme = `-- name: CountQuotesByTheme :many
+SELECT t.name, COUNT(q.id) AS quotes,
+       CAST(COALESCE(SUM(CASE WHEN q.times_posted > 0 THEN 1 ELSE 0 END), 0) AS INTEGER) AS posted
+FROM themes t
+LEFT JOIN quote_themes qt ON qt.theme_id = t.id
+LEFT JOIN quotes q ON q.id = qt.quote_id
+GROUP BY t.id
+ORDER BY quotes DESC, t.name
+`
+
+type CountQuotesByThemeRow struct {
+	Name   string `json:"name"`
+	Quotes int64  `json:"quotes"`
+	Posted int64  `json:"posted"`
+}
+
+func (q *Queries) CountQuotesByTheme(ctx context.Context) ([]*CountQuotesByThemeRow, error) {
+	rows, err := q.db.QueryContext(ctx, countQuotesByTheme)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*CountQuotesByThemeRow{}
+	for rows.Next() {
+		var i CountQuotesByThemeRow
+		if err := rows.Scan(&i.Name, &i.Quotes, &i.Posted); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countQuotesByVerdict = `-- name: CountQuotesByVerdict :many
+SELECT COALESCE(quality_verdict, '') AS verdict, COUNT(*) AS count
+FROM quotes GROUP BY quality_verdict ORDER BY verdict
+`
+
+type CountQuotesByVerdictRow struct {
+	Verdict string `json:"verdict"`
+	Count   int64  `json:"count"`
+}
+
+func (q *Queries) CountQuotesByVerdict(ctx context.Context) ([]*CountQuotesByVerdictRow, error) {
+	rows, err := q.db.QueryContext(ctx, countQuotesByVerdict)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*CountQuotesByVerdictRow{}
+	for rows.Next() {
+		var i CountQuotesByVerdictRow
+		if err := rows.Scan(&i.Verdict, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const countQuotesWithEmbeddings = `-- name: CountQuotesWithEmbeddings :one
 SELECT COUNT(*) FROM quotes WHERE embedding IS NOT NULL
 `
@@ -76,19 +357,177 @@ func (q *Queries) CountQuotesWithEmbeddings(ctx context.Context) (int64, error)
 	return count, err
 }
 
+const countSourcePostsToday = `-- name: CountSourcePostsToday :one
+SELECT COUNT(*) FROM posts
+WHERE platform = ? AND trend_source = ? AND posted_at >= datetime(?)
+`
+
+type CountSourcePostsTodayParams struct {
+	Platform    string `json:"platform"`
+	TrendSource string `json:"trend_source"`
+	Since       string `json:"since"`
+}
+
+func (q *Queries) CountSourcePostsToday(ctx context.Context, arg CountSourcePostsTodayParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSourcePostsToday, arg.Platform, arg.TrendSource, arg.Since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countThemeQuotesByBook = `-- name: CountThemeQuotesByBook :many
+SELECT q.source_book, COUNT(*) AS count
+FROM quotes q
+JOIN quote_themes qt ON qt.quote_id = q.id
+JOIN themes t ON t.id = qt.theme_id
+WHERE t.name = ?
+GROUP BY q.source_book
+ORDER BY count DESC, q.source_book
+`
+
+type CountThemeQuotesByBookRow struct {
+	SourceBook string `json:"source_book"`
+	Count      int64  `json:"count"`
+}
+
+func (q *Queries) CountThemeQuotesByBook(ctx context.Context, name string) ([]*CountThemeQuotesByBookRow, error) {
+	rows, err := q.db.QueryContext(ctx, countThemeQuotesByBook, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*CountThemeQuotesByBookRow{}
+	for rows.Next() {
+		var i CountThemeQuotesByBookRow
+		if err := rows.Scan(&i.SourceBook, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countUntaggedQuotes = `-- name: CountUntaggedQuotes :one
+SELECT COUNT(*) FROM quotes
+WHERE NOT EXISTS (SELECT 1 FROM quote_themes qt WHERE qt.quote_id = quotes.id)
+`
+
+func (q *Queries) CountUntaggedQuotes(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUntaggedQuotes)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createBook = `-- name: CreateBook :one
+INSERT INTO books (title, author, translator, gutenberg_id, file_path, language)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING id, title, author, translator, gutenberg_id, file_path, language, created_at, edition, checksum, downloaded_at, original_id
+`
+
+type CreateBookParams struct {
+	Title       string         `json:"title"`
+	Author      string         `json:"author"`
+	Translator  sql.NullString `json:"translator"`
+	GutenbergID sql.NullInt64  `json:"gutenberg_id"`
+	FilePath    string         `json:"file_path"`
+	Language    string         `json:"language"`
+}
+
+func (q *Queries) CreateBook(ctx context.Context, arg CreateBookParams) (*Book, error) {
+	row := q.db.QueryRowContext(ctx, createBook,
+		arg.Title,
+		arg.Author,
+		arg.Translator,
+		arg.GutenbergID,
+		arg.FilePath,
+		arg.Language,
+	)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Author,
+		&i.Translator,
+		&i.GutenbergID,
+		&i.FilePath,
+		&i.Language,
+		&i.CreatedAt,
+		&i.Edition,
+		&i.Checksum,
+		&i.DownloadedAt,
+		&i.OriginalID,
+	)
+	return &i, err
+}
+
+const createCharacter = `-- name: CreateCharacter :one
+INSERT INTO characters (source_book, name) VALUES (?, ?)
+RETURNING id, source_book, name, created_at
+`
+
+type CreateCharacterParams struct {
+	SourceBook string `json:"source_book"`
+	Name       string `json:"name"`
+}
+
+func (q *Queries) CreateCharacter(ctx context.Context, arg CreateCharacterParams) (*Character, error) {
+	row := q.db.QueryRowContext(ctx, createCharacter, arg.SourceBook, arg.Name)
+	var i Character
+	err := row.Scan(&i.ID, &i.SourceBook, &i.Name, &i.CreatedAt)
+	return &i, err
+}
+
+const createEvergreenOccasion = `-- name: CreateEvergreenOccasion :exec
+INSERT INTO evergreen_occasions (name, month, end_month, day, weekday, author, source_book, theme)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateEvergreenOccasionParams struct {
+	Name       string         `json:"name"`
+	Month      sql.NullInt64  `json:"month"`
+	EndMonth   sql.NullInt64  `json:"end_month"`
+	Day        sql.NullInt64  `json:"day"`
+	Weekday    sql.NullInt64  `json:"weekday"`
+	Author     sql.NullString `json:"author"`
+	SourceBook sql.NullString `json:"source_book"`
+	Theme      sql.NullString `json:"theme"`
+}
+
+func (q *Queries) CreateEvergreenOccasion(ctx context.Context, arg CreateEvergreenOccasionParams) error {
+	_, err := q.db.ExecContext(ctx, createEvergreenOccasion,
+		arg.Name,
+		arg.Month,
+		arg.EndMonth,
+		arg.Day,
+		arg.Weekday,
+		arg.Author,
+		arg.SourceBook,
+		arg.Theme,
+	)
+	return err
+}
+
 const createExtractionJob = `-- name: CreateExtractionJob :one
-INSERT INTO extraction_jobs (book_title, file_path, status)
-VALUES (?, ?, 'pending')
-RETURNING id, book_title, file_path, total_chunks, processed_chunks, quotes_extracted, status, error_message, started_at, completed_at, created_at
+INSERT INTO extraction_jobs (book_title, file_path, file_hash, status)
+VALUES (?, ?, ?, 'pending')
+RETURNING id, book_title, file_path, total_chunks, processed_chunks, quotes_extracted, status, error_message, started_at, completed_at, created_at, file_hash
 `
 
 type CreateExtractionJobParams struct {
-	BookTitle string `json:"book_title"`
-	FilePath  string `json:"file_path"`
+	BookTitle string         `json:"book_title"`
+	FilePath  string         `json:"file_path"`
+	FileHash  sql.NullString `json:"file_hash"`
 }
 
 func (q *Queries) CreateExtractionJob(ctx context.Context, arg CreateExtractionJobParams) (*ExtractionJob, error) {
-	row := q.db.QueryRowContext(ctx, createExtractionJob, arg.BookTitle, arg.FilePath)
+	row := q.db.QueryRowContext(ctx, createExtractionJob, arg.BookTitle, arg.FilePath, arg.FileHash)
 	var i ExtractionJob
 	err := row.Scan(
 		&i.ID,
@@ -102,11 +541,72 @@ func (q *Queries) CreateExtractionJob(ctx context.Context, arg CreateExtractionJ
 		&i.StartedAt,
 		&i.CompletedAt,
 		&i.CreatedAt,
+		&i.FileHash,
 	)
 	return &i, err
 }
 
-const createPost = `-- name: CreatePost :one
+const createPendingPost = `-- name: CreatePendingPost :one
+INSERT INTO pending_posts (
+    quote_id, trend_id, trend_title, trend_source, trend_hash, text, langs,
+    relevance_score, relevance_reasoning, vector_similarity, status, expires_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now', ?))
+RETURNING id, quote_id, trend_id, trend_title, trend_source, trend_hash, text, langs, relevance_score, relevance_reasoning, vector_similarity, status, created_at, expires_at, reviewed_at, post_id
+`
+
+type CreatePendingPostParams struct {
+	QuoteID            int64          `json:"quote_id"`
+	TrendID            sql.NullInt64  `json:"trend_id"`
+	TrendTitle         string         `json:"trend_title"`
+	TrendSource        string         `json:"trend_source"`
+	TrendHash          string         `json:"trend_hash"`
+	Text               string         `json:"text"`
+	Langs              string         `json:"langs"`
+	RelevanceScore     float64        `json:"relevance_score"`
+	RelevanceReasoning sql.NullString `json:"relevance_reasoning"`
+	VectorSimilarity   float64        `json:"vector_similarity"`
+	Status             string         `json:"status"`
+	ExpiresIn          string         `json:"expires_in"`
+}
+
+func (q *Queries) CreatePendingPost(ctx context.Context, arg CreatePendingPostParams) (*PendingPost, error) {
+	row := q.db.QueryRowContext(ctx, createPendingPost,
+		arg.QuoteID,
+		arg.TrendID,
+		arg.TrendTitle,
+		arg.TrendSource,
+		arg.TrendHash,
+		arg.Text,
+		arg.Langs,
+		arg.RelevanceScore,
+		arg.RelevanceReasoning,
+		arg.VectorSimilarity,
+		arg.Status,
+		arg.ExpiresIn,
+	)
+	var i PendingPost
+	err := row.Scan(
+		&i.ID,
+		&i.QuoteID,
+		&i.TrendID,
+		&i.TrendTitle,
+		&i.TrendSource,
+		&i.TrendHash,
+		&i.Text,
+		&i.Langs,
+		&i.RelevanceScore,
+		&i.RelevanceReasoning,
+		&i.VectorSimilarity,
+		&i.Status,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.ReviewedAt,
+		&i.PostID,
+	)
+	return &i, err
+}
+
+const createPost = `-- name: CreatePost :one
 INSERT INTO posts (
     quote_id, platform, platform_post_id, post_url,
     trend_id, trend_title, trend_source, trend_hash,
@@ -168,9 +668,10 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (*Post,
 const createQuote = `-- name: CreateQuote :one
 INSERT INTO quotes (
     text, text_hash, source_book, chapter, character,
-    themes, modern_relevance, char_count
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, text, text_hash, source_book, chapter, character, themes, modern_relevance, embedding, char_count, times_posted, last_posted_at, created_at
+    themes, modern_relevance, char_count,
+    source_start_line, source_end_line, verbatim_edits, language
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, text, text_hash, source_book, chapter, character, themes, modern_relevance, embedding, char_count, times_posted, last_posted_at, created_at, embedding_model, embedding_dim, quality_score, quality_issues, quality_verdict, validated_at, source_start_line, source_end_line, verbatim_edits, language, group_id, curation_status, favorite, curated_at
 `
 
 type CreateQuoteParams struct {
@@ -182,6 +683,10 @@ type CreateQuoteParams struct {
 	Themes          string         `json:"themes"`
 	ModernRelevance sql.NullString `json:"modern_relevance"`
 	CharCount       int64          `json:"char_count"`
+	SourceStartLine sql.NullInt64  `json:"source_start_line"`
+	SourceEndLine   sql.NullInt64  `json:"source_end_line"`
+	VerbatimEdits   sql.NullInt64  `json:"verbatim_edits"`
+	Language        string         `json:"language"`
 }
 
 func (q *Queries) CreateQuote(ctx context.Context, arg CreateQuoteParams) (*Quote, error) {
@@ -194,6 +699,10 @@ func (q *Queries) CreateQuote(ctx context.Context, arg CreateQuoteParams) (*Quot
 		arg.Themes,
 		arg.ModernRelevance,
 		arg.CharCount,
+		arg.SourceStartLine,
+		arg.SourceEndLine,
+		arg.VerbatimEdits,
+		arg.Language,
 	)
 	var i Quote
 	err := row.Scan(
@@ -210,14 +719,95 @@ func (q *Queries) CreateQuote(ctx context.Context, arg CreateQuoteParams) (*Quot
 		&i.TimesPosted,
 		&i.LastPostedAt,
 		&i.CreatedAt,
+		&i.EmbeddingModel,
+		&i.EmbeddingDim,
+		&i.QualityScore,
+		&i.QualityIssues,
+		&i.QualityVerdict,
+		&i.ValidatedAt,
+		&i.SourceStartLine,
+		&i.SourceEndLine,
+		&i.VerbatimEdits,
+		&i.Language,
+		&i.GroupID,
+		&i.CurationStatus,
+		&i.Favorite,
+		&i.CuratedAt,
 	)
 	return &i, err
 }
 
+const createQuoteGroup = `-- name: CreateQuoteGroup :one
+INSERT INTO quote_groups DEFAULT VALUES
+RETURNING id, created_at
+`
+
+func (q *Queries) CreateQuoteGroup(ctx context.Context) (*QuoteGroup, error) {
+	row := q.db.QueryRowContext(ctx, createQuoteGroup)
+	var i QuoteGroup
+	err := row.Scan(&i.ID, &i.CreatedAt)
+	return &i, err
+}
+
+const createQuoteTranslation = `-- name: CreateQuoteTranslation :exec
+INSERT OR IGNORE INTO quote_translations (original_id, translation_id, edits)
+VALUES (?, ?, ?)
+`
+
+type CreateQuoteTranslationParams struct {
+	OriginalID    int64         `json:"original_id"`
+	TranslationID int64         `json:"translation_id"`
+	Edits         sql.NullInt64 `json:"edits"`
+}
+
+func (q *Queries) CreateQuoteTranslation(ctx context.Context, arg CreateQuoteTranslationParams) error {
+	_, err := q.db.ExecContext(ctx, createQuoteTranslation, arg.OriginalID, arg.TranslationID, arg.Edits)
+	return err
+}
+
+const createSelectionCache = `-- name: CreateSelectionCache :exec
+INSERT INTO selection_cache (trend_hash, quote_ids, quotes_hash, prompt_version, model, result)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT(trend_hash, quote_ids, quotes_hash, prompt_version, model) DO UPDATE SET result = excluded.result
+`
+
+type CreateSelectionCacheParams struct {
+	TrendHash     string `json:"trend_hash"`
+	QuoteIds      string `json:"quote_ids"`
+	QuotesHash    string `json:"quotes_hash"`
+	PromptVersion string `json:"prompt_version"`
+	Model         string `json:"model"`
+	Result        string `json:"result"`
+}
+
+func (q *Queries) CreateSelectionCache(ctx context.Context, arg CreateSelectionCacheParams) error {
+	_, err := q.db.ExecContext(ctx, createSelectionCache,
+		arg.TrendHash,
+		arg.QuoteIds,
+		arg.QuotesHash,
+		arg.PromptVersion,
+		arg.Model,
+		arg.Result,
+	)
+	return err
+}
+
+const createTheme = `-- name: CreateTheme :one
+INSERT INTO themes (name) VALUES (?)
+RETURNING id, name, created_at
+`
+
+func (q *Queries) CreateTheme(ctx context.Context, name string) (*Theme, error) {
+	row := q.db.QueryRowContext(ctx, createTheme, name)
+	var i Theme
+	err := row.Scan(&i.ID, &i.Name, &i.CreatedAt)
+	return &i, err
+}
+
 const createTrend = `-- name: CreateTrend :one
 INSERT INTO trends (source, external_id, title, url, description, score)
 VALUES (?, ?, ?, ?, ?, ?)
-RETURNING id, source, external_id, title, url, description, score, embedding, matched, skipped, skip_reason, detected_at
+RETURNING id, source, external_id, title, url, description, score, embedding, matched, skipped, skip_reason, detected_at, embedding_model, embedding_dim
 `
 
 type CreateTrendParams struct {
@@ -252,6 +842,167 @@ func (q *Queries) CreateTrend(ctx context.Context, arg CreateTrendParams) (*Tren
 		&i.Skipped,
 		&i.SkipReason,
 		&i.DetectedAt,
+		&i.EmbeddingModel,
+		&i.EmbeddingDim,
+	)
+	return &i, err
+}
+
+const deleteBook = `-- name: DeleteBook :exec
+DELETE FROM books WHERE id = ?
+`
+
+func (q *Queries) DeleteBook(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteBook, id)
+	return err
+}
+
+const deleteCharacter = `-- name: DeleteCharacter :exec
+DELETE FROM characters WHERE id = ?
+`
+
+func (q *Queries) DeleteCharacter(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteCharacter, id)
+	return err
+}
+
+const deleteEmptyQuoteGroups = `-- name: DeleteEmptyQuoteGroups :exec
+DELETE FROM quote_groups
+WHERE id NOT IN (SELECT group_id FROM quotes WHERE group_id IS NOT NULL)
+`
+
+func (q *Queries) DeleteEmptyQuoteGroups(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteEmptyQuoteGroups)
+	return err
+}
+
+const deleteEvergreenOccasion = `-- name: DeleteEvergreenOccasion :execrows
+DELETE FROM evergreen_occasions WHERE name = ?
+`
+
+func (q *Queries) DeleteEvergreenOccasion(ctx context.Context, name string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteEvergreenOccasion, name)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteQuote = `-- name: DeleteQuote :exec
+DELETE FROM quotes WHERE id = ?
+`
+
+func (q *Queries) DeleteQuote(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteQuote, id)
+	return err
+}
+
+const deleteQuoteThemes = `-- name: DeleteQuoteThemes :exec
+DELETE FROM quote_themes WHERE quote_id = ?
+`
+
+func (q *Queries) DeleteQuoteThemes(ctx context.Context, quoteID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteQuoteThemes, quoteID)
+	return err
+}
+
+const deleteTheme = `-- name: DeleteTheme :exec
+DELETE FROM themes WHERE id = ?
+`
+
+func (q *Queries) DeleteTheme(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteTheme, id)
+	return err
+}
+
+const discardQuotePendingPosts = `-- name: DiscardQuotePendingPosts :exec
+UPDATE pending_posts SET status = 'expired'
+WHERE quote_id = ? AND status IN ('pending', 'approved')
+`
+
+func (q *Queries) DiscardQuotePendingPosts(ctx context.Context, quoteID int64) error {
+	_, err := q.db.ExecContext(ctx, discardQuotePendingPosts, quoteID)
+	return err
+}
+
+const expirePendingPosts = `-- name: ExpirePendingPosts :exec
+UPDATE pending_posts SET status = 'expired'
+WHERE status IN ('pending', 'approved') AND expires_at <= CURRENT_TIMESTAMP
+`
+
+func (q *Queries) ExpirePendingPosts(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, expirePendingPosts)
+	return err
+}
+
+const getBook = `-- name: GetBook :one
+SELECT id, title, author, translator, gutenberg_id, file_path, language, created_at, edition, checksum, downloaded_at, original_id FROM books WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetBook(ctx context.Context, id int64) (*Book, error) {
+	row := q.db.QueryRowContext(ctx, getBook, id)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Author,
+		&i.Translator,
+		&i.GutenbergID,
+		&i.FilePath,
+		&i.Language,
+		&i.CreatedAt,
+		&i.Edition,
+		&i.Checksum,
+		&i.DownloadedAt,
+		&i.OriginalID,
+	)
+	return &i, err
+}
+
+const getBookByGutenbergID = `-- name: GetBookByGutenbergID :one
+SELECT id, title, author, translator, gutenberg_id, file_path, language, created_at, edition, checksum, downloaded_at, original_id FROM books WHERE gutenberg_id = ? LIMIT 1
+`
+
+func (q *Queries) GetBookByGutenbergID(ctx context.Context, gutenbergID sql.NullInt64) (*Book, error) {
+	row := q.db.QueryRowContext(ctx, getBookByGutenbergID, gutenbergID)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Author,
+		&i.Translator,
+		&i.GutenbergID,
+		&i.FilePath,
+		&i.Language,
+		&i.CreatedAt,
+		&i.Edition,
+		&i.Checksum,
+		&i.DownloadedAt,
+		&i.OriginalID,
+	)
+	return &i, err
+}
+
+const getBookByTitle = `-- name: GetBookByTitle :one
+SELECT id, title, author, translator, gutenberg_id, file_path, language, created_at, edition, checksum, downloaded_at, original_id FROM books WHERE title = ? LIMIT 1
+`
+
+func (q *Queries) GetBookByTitle(ctx context.Context, title string) (*Book, error) {
+	row := q.db.QueryRowContext(ctx, getBookByTitle, title)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Author,
+		&i.Translator,
+		&i.GutenbergID,
+		&i.FilePath,
+		&i.Language,
+		&i.CreatedAt,
+		&i.Edition,
+		&i.Checksum,
+		&i.DownloadedAt,
+		&i.OriginalID,
 	)
 	return &i, err
 }
@@ -268,7 +1019,7 @@ func (q *Queries) GetConfig(ctx context.Context, key string) (string, error) {
 }
 
 const getExtractionJob = `-- name: GetExtractionJob :one
-SELECT id, book_title, file_path, total_chunks, processed_chunks, quotes_extracted, status, error_message, started_at, completed_at, created_at FROM extraction_jobs WHERE id = ? LIMIT 1
+SELECT id, book_title, file_path, total_chunks, processed_chunks, quotes_extracted, status, error_message, started_at, completed_at, created_at, file_hash FROM extraction_jobs WHERE id = ? LIMIT 1
 `
 
 func (q *Queries) GetExtractionJob(ctx context.Context, id int64) (*ExtractionJob, error) {
@@ -286,12 +1037,13 @@ func (q *Queries) GetExtractionJob(ctx context.Context, id int64) (*ExtractionJo
 		&i.StartedAt,
 		&i.CompletedAt,
 		&i.CreatedAt,
+		&i.FileHash,
 	)
 	return &i, err
 }
 
 const getExtractionJobByBook = `-- name: GetExtractionJobByBook :one
-SELECT id, book_title, file_path, total_chunks, processed_chunks, quotes_extracted, status, error_message, started_at, completed_at, created_at FROM extraction_jobs WHERE book_title = ? ORDER BY created_at DESC LIMIT 1
+SELECT id, book_title, file_path, total_chunks, processed_chunks, quotes_extracted, status, error_message, started_at, completed_at, created_at, file_hash FROM extraction_jobs WHERE book_title = ? ORDER BY created_at DESC LIMIT 1
 `
 
 func (q *Queries) GetExtractionJobByBook(ctx context.Context, bookTitle string) (*ExtractionJob, error) {
@@ -309,6 +1061,66 @@ func (q *Queries) GetExtractionJobByBook(ctx context.Context, bookTitle string)
 		&i.StartedAt,
 		&i.CompletedAt,
 		&i.CreatedAt,
+		&i.FileHash,
+	)
+	return &i, err
+}
+
+const getLatestExtractionJobForFile = `-- name: GetLatestExtractionJobForFile :one
+SELECT id, book_title, file_path, total_chunks, processed_chunks, quotes_extracted, status, error_message, started_at, completed_at, created_at, file_hash FROM extraction_jobs
+WHERE book_title = ? AND file_hash = ?
+ORDER BY id DESC LIMIT 1
+`
+
+type GetLatestExtractionJobForFileParams struct {
+	BookTitle string         `json:"book_title"`
+	FileHash  sql.NullString `json:"file_hash"`
+}
+
+func (q *Queries) GetLatestExtractionJobForFile(ctx context.Context, arg GetLatestExtractionJobForFileParams) (*ExtractionJob, error) {
+	row := q.db.QueryRowContext(ctx, getLatestExtractionJobForFile, arg.BookTitle, arg.FileHash)
+	var i ExtractionJob
+	err := row.Scan(
+		&i.ID,
+		&i.BookTitle,
+		&i.FilePath,
+		&i.TotalChunks,
+		&i.ProcessedChunks,
+		&i.QuotesExtracted,
+		&i.Status,
+		&i.ErrorMessage,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.FileHash,
+	)
+	return &i, err
+}
+
+const getPendingPost = `-- name: GetPendingPost :one
+SELECT id, quote_id, trend_id, trend_title, trend_source, trend_hash, text, langs, relevance_score, relevance_reasoning, vector_similarity, status, created_at, expires_at, reviewed_at, post_id FROM pending_posts WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetPendingPost(ctx context.Context, id int64) (*PendingPost, error) {
+	row := q.db.QueryRowContext(ctx, getPendingPost, id)
+	var i PendingPost
+	err := row.Scan(
+		&i.ID,
+		&i.QuoteID,
+		&i.TrendID,
+		&i.TrendTitle,
+		&i.TrendSource,
+		&i.TrendHash,
+		&i.Text,
+		&i.Langs,
+		&i.RelevanceScore,
+		&i.RelevanceReasoning,
+		&i.VectorSimilarity,
+		&i.Status,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.ReviewedAt,
+		&i.PostID,
 	)
 	return &i, err
 }
@@ -375,7 +1187,7 @@ func (q *Queries) GetPostByTrendHash(ctx context.Context, arg GetPostByTrendHash
 }
 
 const getQuote = `-- name: GetQuote :one
-SELECT id, text, text_hash, source_book, chapter, character, themes, modern_relevance, embedding, char_count, times_posted, last_posted_at, created_at FROM quotes WHERE id = ? LIMIT 1
+SELECT id, text, text_hash, source_book, chapter, character, themes, modern_relevance, embedding, char_count, times_posted, last_posted_at, created_at, embedding_model, embedding_dim, quality_score, quality_issues, quality_verdict, validated_at, source_start_line, source_end_line, verbatim_edits, language, group_id, curation_status, favorite, curated_at FROM quotes WHERE id = ? LIMIT 1
 `
 
 func (q *Queries) GetQuote(ctx context.Context, id int64) (*Quote, error) {
@@ -395,12 +1207,26 @@ func (q *Queries) GetQuote(ctx context.Context, id int64) (*Quote, error) {
 		&i.TimesPosted,
 		&i.LastPostedAt,
 		&i.CreatedAt,
+		&i.EmbeddingModel,
+		&i.EmbeddingDim,
+		&i.QualityScore,
+		&i.QualityIssues,
+		&i.QualityVerdict,
+		&i.ValidatedAt,
+		&i.SourceStartLine,
+		&i.SourceEndLine,
+		&i.VerbatimEdits,
+		&i.Language,
+		&i.GroupID,
+		&i.CurationStatus,
+		&i.Favorite,
+		&i.CuratedAt,
 	)
 	return &i, err
 }
 
 const getQuoteByHash = `-- name: GetQuoteByHash :one
-SELECT id, text, text_hash, source_book, chapter, character, themes, modern_relevance, embedding, char_count, times_posted, last_posted_at, created_at FROM quotes WHERE text_hash = ? LIMIT 1
+SELECT id, text, text_hash, source_book, chapter, character, themes, modern_relevance, embedding, char_count, times_posted, last_posted_at, created_at, embedding_model, embedding_dim, quality_score, quality_issues, quality_verdict, validated_at, source_start_line, source_end_line, verbatim_edits, language, group_id, curation_status, favorite, curated_at FROM quotes WHERE text_hash = ? LIMIT 1
 `
 
 func (q *Queries) GetQuoteByHash(ctx context.Context, textHash string) (*Quote, error) {
@@ -420,12 +1246,134 @@ func (q *Queries) GetQuoteByHash(ctx context.Context, textHash string) (*Quote,
 		&i.TimesPosted,
 		&i.LastPostedAt,
 		&i.CreatedAt,
+		&i.EmbeddingModel,
+		&i.EmbeddingDim,
+		&i.QualityScore,
+		&i.QualityIssues,
+		&i.QualityVerdict,
+		&i.ValidatedAt,
+		&i.SourceStartLine,
+		&i.SourceEndLine,
+		&i.VerbatimEdits,
+		&i.Language,
+		&i.GroupID,
+		&i.CurationStatus,
+		&i.Favorite,
+		&i.CuratedAt,
+	)
+	return &i, err
+}
+
+const getQuoteOriginal = `-- name: GetQuoteOriginal :one
+SELECT quotes.id, quotes.text, quotes.text_hash, quotes.source_book, quotes.chapter, quotes.character, quotes.themes, quotes.modern_relevance, quotes.embedding, quotes.char_count, quotes.times_posted, quotes.last_posted_at, quotes.created_at, quotes.embedding_model, quotes.embedding_dim, quotes.quality_score, quotes.quality_issues, quotes.quality_verdict, quotes.validated_at, quotes.source_start_line, quotes.source_end_line, quotes.verbatim_edits, quotes.language, quotes.group_id, quotes.curation_status, quotes.favorite, quotes.curated_at FROM quotes
+JOIN quote_translations qt ON qt.original_id = quotes.id
+WHERE qt.translation_id = ?
+ORDER BY quotes.id LIMIT 1
+`
+
+func (q *Queries) GetQuoteOriginal(ctx context.Context, translationID int64) (*Quote, error) {
+	row := q.db.QueryRowContext(ctx, getQuoteOriginal, translationID)
+	var i Quote
+	err := row.Scan(
+		&i.ID,
+		&i.Text,
+		&i.TextHash,
+		&i.SourceBook,
+		&i.Chapter,
+		&i.Character,
+		&i.Themes,
+		&i.ModernRelevance,
+		&i.Embedding,
+		&i.CharCount,
+		&i.TimesPosted,
+		&i.LastPostedAt,
+		&i.CreatedAt,
+		&i.EmbeddingModel,
+		&i.EmbeddingDim,
+		&i.QualityScore,
+		&i.QualityIssues,
+		&i.QualityVerdict,
+		&i.ValidatedAt,
+		&i.SourceStartLine,
+		&i.SourceEndLine,
+		&i.VerbatimEdits,
+		&i.Language,
+		&i.GroupID,
+		&i.CurationStatus,
+		&i.Favorite,
+		&i.CuratedAt,
+	)
+	return &i, err
+}
+
+const getSelectionCache = `-- name: GetSelectionCache :one
+SELECT id, trend_hash, quote_ids, quotes_hash, prompt_version, model, result, hits, created_at, last_hit_at FROM selection_cache
+WHERE trend_hash = ? AND quote_ids = ? AND quotes_hash = ? AND prompt_version = ? AND model = ?
+LIMIT 1
+`
+
+type GetSelectionCacheParams struct {
+	TrendHash     string `json:"trend_hash"`
+	QuoteIds      string `json:"quote_ids"`
+	QuotesHash    string `json:"quotes_hash"`
+	PromptVersion string `json:"prompt_version"`
+	Model         string `json:"model"`
+}
+
+func (q *Queries) GetSelectionCache(ctx context.Context, arg GetSelectionCacheParams) (*SelectionCache, error) {
+	row := q.db.QueryRowContext(ctx, getSelectionCache,
+		arg.TrendHash,
+		arg.QuoteIds,
+		arg.QuotesHash,
+		arg.PromptVersion,
+		arg.Model,
+	)
+	var i SelectionCache
+	err := row.Scan(
+		&i.ID,
+		&i.TrendHash,
+		&i.QuoteIds,
+		&i.QuotesHash,
+		&i.PromptVersion,
+		&i.Model,
+		&i.Result,
+		&i.Hits,
+		&i.CreatedAt,
+		&i.LastHitAt,
 	)
 	return &i, err
 }
 
+const getSelectionCacheStats = `-- name: GetSelectionCacheStats :one
+SELECT COUNT(*) AS entries, CAST(COALESCE(SUM(hits), 0) AS INTEGER) AS hits
+FROM selection_cache
+`
+
+type GetSelectionCacheStatsRow struct {
+	Entries int64 `json:"entries"`
+	Hits    int64 `json:"hits"`
+}
+
+func (q *Queries) GetSelectionCacheStats(ctx context.Context) (*GetSelectionCacheStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getSelectionCacheStats)
+	var i GetSelectionCacheStatsRow
+	err := row.Scan(&i.Entries, &i.Hits)
+	return &i, err
+}
+
+const getThemeByName = `-- name: GetThemeByName :one
+SELECT id, name, created_at FROM themes WHERE name = ? LIMIT 1
+`
+
+func (q *Queries) GetThemeByName(ctx context.Context, name string) (*Theme, error) {
+	row := q.db.QueryRowContext(ctx, getThemeByName, name)
+	var i Theme
+	err := row.Scan(&i.ID, &i.Name, &i.CreatedAt)
+	return &i, err
+}
+
 const getTrend = `-- name: GetTrend :one
-SELECT id, source, external_id, title, url, description, score, embedding, matched, skipped, skip_reason, detected_at FROM trends WHERE id = ? LIMIT 1
+SELECT id, source, external_id, title, url, description, score, embedding, matched, skipped, skip_reason, detected_at, embedding_model, embedding_dim FROM trends WHERE id = ? LIMIT 1
 `
 
 func (q *Queries) GetTrend(ctx context.Context, id int64) (*Trend, error) {
@@ -444,12 +1392,14 @@ func (q *Queries) GetTrend(ctx context.Context, id int64) (*Trend, error) {
 		&i.Skipped,
 		&i.SkipReason,
 		&i.DetectedAt,
+		&i.EmbeddingModel,
+		&i.EmbeddingDim,
 	)
 	return &i, err
 }
 
 const getTrendBySourceAndExternalID = `-- name: GetTrendBySourceAndExternalID :one
-SELECT id, source, external_id, title, url, description, score, embedding, matched, skipped, skip_reason, detected_at FROM trends WHERE source = ? AND external_id = ? LIMIT 1
+SELECT id, source, external_id, title, url, description, score, embedding, matched, skipped, skip_reason, detected_at, embedding_model, embedding_dim FROM trends WHERE source = ? AND external_id = ? LIMIT 1
 `
 
 type GetTrendBySourceAndExternalIDParams struct {
@@ -473,24 +1423,464 @@ func (q *Queries) GetTrendBySourceAndExternalID(ctx context.Context, arg GetTren
 		&i.Skipped,
 		&i.SkipReason,
 		&i.DetectedAt,
+		&i.EmbeddingModel,
+		&i.EmbeddingDim,
 	)
 	return &i, err
 }
 
-const listConfig = `-- name: ListConfig :many
-SELECT "key", value, updated_at FROM config ORDER BY key
+const listBookCharacterAliases = `-- name: ListBookCharacterAliases :many
+SELECT source_book, alias, character_id FROM character_aliases WHERE source_book = ? ORDER BY alias
+`
+
+func (q *Queries) ListBookCharacterAliases(ctx context.Context, sourceBook string) ([]*CharacterAlias, error) {
+	rows, err := q.db.QueryContext(ctx, listBookCharacterAliases, sourceBook)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*CharacterAlias{}
+	for rows.Next() {
+		var i CharacterAlias
+		if err := rows.Scan(&i.SourceBook, &i.Alias, &i.CharacterID); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBookCharacters = `-- name: ListBookCharacters :many
+SELECT id, source_book, name, created_at FROM characters WHERE source_book = ? ORDER BY name
+`
+
+func (q *Queries) ListBookCharacters(ctx context.Context, sourceBook string) ([]*Character, error) {
+	rows, err := q.db.QueryContext(ctx, listBookCharacters, sourceBook)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Character{}
+	for rows.Next() {
+		var i Character
+		if err := rows.Scan(&i.ID, &i.SourceBook, &i.Name, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBookTranslations = `-- name: ListBookTranslations :many
+SELECT id, title, author, translator, gutenberg_id, file_path, language, created_at, edition, checksum, downloaded_at, original_id FROM books WHERE original_id = ? ORDER BY title
+`
+
+func (q *Queries) ListBookTranslations(ctx context.Context, originalID sql.NullInt64) ([]*Book, error) {
+	rows, err := q.db.QueryContext(ctx, listBookTranslations, originalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Book{}
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Author,
+			&i.Translator,
+			&i.GutenbergID,
+			&i.FilePath,
+			&i.Language,
+			&i.CreatedAt,
+			&i.Edition,
+			&i.Checksum,
+			&i.DownloadedAt,
+			&i.OriginalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBooks = `-- name: ListBooks :many
+SELECT id, title, author, translator, gutenberg_id, file_path, language, created_at, edition, checksum, downloaded_at, original_id FROM books ORDER BY title
+`
+
+func (q *Queries) ListBooks(ctx context.Context) ([]*Book, error) {
+	rows, err := q.db.QueryContext(ctx, listBooks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Book{}
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Author,
+			&i.Translator,
+			&i.GutenbergID,
+			&i.FilePath,
+			&i.Language,
+			&i.CreatedAt,
+			&i.Edition,
+			&i.Checksum,
+			&i.DownloadedAt,
+			&i.OriginalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listConfig = `-- name: ListConfig :many
+SELECT "key", value, updated_at FROM config ORDER BY key
+`
+
+func (q *Queries) ListConfig(ctx context.Context) ([]*Config, error) {
+	rows, err := q.db.QueryContext(ctx, listConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Config{}
+	for rows.Next() {
+		var i Config
+		if err := rows.Scan(&i.Key, &i.Value, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCurationQuotes = `-- name: ListCurationQuotes :many
+SELECT id, text, text_hash, source_book, chapter, character, themes, modern_relevance, embedding, char_count, times_posted, last_posted_at, created_at, embedding_model, embedding_dim, quality_score, quality_issues, quality_verdict, validated_at, source_start_line, source_end_line, verbatim_edits, language, group_id, curation_status, favorite, curated_at FROM quotes
+WHERE id > ?
+  AND source_book LIKE ? ESCAPE '\'
+  AND COALESCE(character, '') LIKE ? ESCAPE '\'
+  AND (? = '' OR id IN (
+    SELECT qt.quote_id FROM quote_themes qt
+    JOIN themes t ON t.id = qt.theme_id
+    WHERE t.name = ?
+  ))
+  AND COALESCE(quality_score, 0) >= ?
+  AND COALESCE(times_posted, 0) <= ?
+  AND COALESCE(curation_status, '') LIKE ? ESCAPE '\'
+  AND favorite >= ?
+ORDER BY id
+LIMIT ?
+`
+
+type ListCurationQuotesParams struct {
+	AfterID        int64  `json:"after_id"`
+	Book           string `json:"book"`
+	Character      string `json:"character"`
+	Theme          string `json:"theme"`
+	MinQuality     int64  `json:"min_quality"`
+	MaxTimesPosted int64  `json:"max_times_posted"`
+	Status         string `json:"status"`
+	MinFavorite    bool   `json:"min_favorite"`
+	Limit          int64  `json:"limit"`
+}
+
+func (q *Queries) ListCurationQuotes(ctx context.Context, arg ListCurationQuotesParams) ([]*Quote, error) {
+	rows, err := q.db.QueryContext(ctx, listCurationQuotes,
+		arg.AfterID,
+		arg.Book,
+		arg.Character,
+		arg.Theme,
+		arg.Theme,
+		arg.MinQuality,
+		arg.MaxTimesPosted,
+		arg.Status,
+		arg.MinFavorite,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Quote{}
+	for rows.Next() {
+		var i Quote
+		if err := rows.Scan(
+			&i.ID,
+			&i.Text,
+			&i.TextHash,
+			&i.SourceBook,
+			&i.Chapter,
+			&i.Character,
+			&i.Themes,
+			&i.ModernRelevance,
+			&i.Embedding,
+			&i.CharCount,
+			&i.TimesPosted,
+			&i.LastPostedAt,
+			&i.CreatedAt,
+			&i.EmbeddingModel,
+			&i.EmbeddingDim,
+			&i.QualityScore,
+			&i.QualityIssues,
+			&i.QualityVerdict,
+			&i.ValidatedAt,
+			&i.SourceStartLine,
+			&i.SourceEndLine,
+			&i.VerbatimEdits,
+			&i.Language,
+			&i.GroupID,
+			&i.CurationStatus,
+			&i.Favorite,
+			&i.CuratedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEvergreenOccasions = `-- name: ListEvergreenOccasions :many
+SELECT id, name, month, end_month, day, weekday, author, source_book, theme, created_at FROM evergreen_occasions ORDER BY name
+`
+
+func (q *Queries) ListEvergreenOccasions(ctx context.Context) ([]*EvergreenOccasion, error) {
+	rows, err := q.db.QueryContext(ctx, listEvergreenOccasions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*EvergreenOccasion{}
+	for rows.Next() {
+		var i EvergreenOccasion
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Month,
+			&i.EndMonth,
+			&i.Day,
+			&i.Weekday,
+			&i.Author,
+			&i.SourceBook,
+			&i.Theme,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEvergreenQuotes = `-- name: ListEvergreenQuotes :many
+SELECT id, text, text_hash, source_book, chapter, character, themes, modern_relevance, embedding, char_count, times_posted, last_posted_at, created_at, embedding_model, embedding_dim, quality_score, quality_issues, quality_verdict, validated_at, source_start_line, source_end_line, verbatim_edits, language, group_id, curation_status, favorite, curated_at FROM quotes
+WHERE COALESCE(quality_verdict, '') != 'reject'
+  AND COALESCE(curation_status, '') != 'rejected'
+  AND source_book LIKE ? ESCAPE '\'
+  AND COALESCE((SELECT author FROM books WHERE books.title = quotes.source_book LIMIT 1), '') LIKE ? ESCAPE '\'
+  AND (? = '' OR id IN (
+    SELECT qt.quote_id FROM quote_themes qt
+    JOIN themes t ON t.id = qt.theme_id
+    WHERE t.name = ?
+  ))
+ORDER BY last_posted_at IS NOT NULL, last_posted_at, favorite DESC, COALESCE(quality_score, 0) DESC, id
+LIMIT ?
+`
+
+type ListEvergreenQuotesParams struct {
+	Book   string `json:"book"`
+	Author string `json:"author"`
+	Theme  string `json:"theme"`
+	Limit  int64  `json:"limit"`
+}
+
+func (q *Queries) ListEvergreenQuotes(ctx context.Context, arg ListEvergreenQuotesParams) ([]*Quote, error) {
+	rows, err := q.db.QueryContext(ctx, listEvergreenQuotes,
+		arg.Book,
+		arg.Author,
+		arg.Theme,
+		arg.Theme,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Quote{}
+	for rows.Next() {
+		var i Quote
+		if err := rows.Scan(
+			&i.ID,
+			&i.Text,
+			&i.TextHash,
+			&i.SourceBook,
+			&i.Chapter,
+			&i.Character,
+			&i.Themes,
+			&i.ModernRelevance,
+			&i.Embedding,
+			&i.CharCount,
+			&i.TimesPosted,
+			&i.LastPostedAt,
+			&i.CreatedAt,
+			&i.EmbeddingModel,
+			&i.EmbeddingDim,
+			&i.QualityScore,
+			&i.QualityIssues,
+			&i.QualityVerdict,
+			&i.ValidatedAt,
+			&i.SourceStartLine,
+			&i.SourceEndLine,
+			&i.VerbatimEdits,
+			&i.Language,
+			&i.GroupID,
+			&i.CurationStatus,
+			&i.Favorite,
+			&i.CuratedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExtractionJobs = `-- name: ListExtractionJobs :many
+SELECT id, book_title, file_path, total_chunks, processed_chunks, quotes_extracted, status, error_message, started_at, completed_at, created_at, file_hash FROM extraction_jobs ORDER BY created_at DESC
+`
+
+func (q *Queries) ListExtractionJobs(ctx context.Context) ([]*ExtractionJob, error) {
+	rows, err := q.db.QueryContext(ctx, listExtractionJobs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*ExtractionJob{}
+	for rows.Next() {
+		var i ExtractionJob
+		if err := rows.Scan(
+			&i.ID,
+			&i.BookTitle,
+			&i.FilePath,
+			&i.TotalChunks,
+			&i.ProcessedChunks,
+			&i.QuotesExtracted,
+			&i.Status,
+			&i.ErrorMessage,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.CreatedAt,
+			&i.FileHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGroupQuotes = `-- name: ListGroupQuotes :many
+SELECT id, text, text_hash, source_book, chapter, character, themes, modern_relevance, embedding, char_count, times_posted, last_posted_at, created_at, embedding_model, embedding_dim, quality_score, quality_issues, quality_verdict, validated_at, source_start_line, source_end_line, verbatim_edits, language, group_id, curation_status, favorite, curated_at FROM quotes WHERE group_id = ? ORDER BY id
 `
 
-func (q *Queries) ListConfig(ctx context.Context) ([]*Config, error) {
-	rows, err := q.db.QueryContext(ctx, listConfig)
+func (q *Queries) ListGroupQuotes(ctx context.Context, groupID sql.NullInt64) ([]*Quote, error) {
+	rows, err := q.db.QueryContext(ctx, listGroupQuotes, groupID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []*Config{}
+	items := []*Quote{}
 	for rows.Next() {
-		var i Config
-		if err := rows.Scan(&i.Key, &i.Value, &i.UpdatedAt); err != nil {
+		var i Quote
+		if err := rows.Scan(
+			&i.ID,
+			&i.Text,
+			&i.TextHash,
+			&i.SourceBook,
+			&i.Chapter,
+			&i.Character,
+			&i.Themes,
+			&i.ModernRelevance,
+			&i.Embedding,
+			&i.CharCount,
+			&i.TimesPosted,
+			&i.LastPostedAt,
+			&i.CreatedAt,
+			&i.EmbeddingModel,
+			&i.EmbeddingDim,
+			&i.QualityScore,
+			&i.QualityIssues,
+			&i.QualityVerdict,
+			&i.ValidatedAt,
+			&i.SourceStartLine,
+			&i.SourceEndLine,
+			&i.VerbatimEdits,
+			&i.Language,
+			&i.GroupID,
+			&i.CurationStatus,
+			&i.Favorite,
+			&i.CuratedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, &i)
@@ -504,31 +1894,36 @@ func (q *Queries) ListConfig(ctx context.Context) ([]*Config, error) {
 	return items, nil
 }
 
-const listExtractionJobs = `-- name: ListExtractionJobs :many
-SELECT id, book_title, file_path, total_chunks, processed_chunks, quotes_extracted, status, error_message, started_at, completed_at, created_at FROM extraction_jobs ORDER BY created_at DESC
+const listOpenPendingPosts = `-- name: ListOpenPendingPosts :many
+SELECT id, quote_id, trend_id, trend_title, trend_source, trend_hash, text, langs, relevance_score, relevance_reasoning, vector_similarity, status, created_at, expires_at, reviewed_at, post_id FROM pending_posts WHERE status IN ('pending', 'approved') ORDER BY id
 `
 
-func (q *Queries) ListExtractionJobs(ctx context.Context) ([]*ExtractionJob, error) {
-	rows, err := q.db.QueryContext(ctx, listExtractionJobs)
+func (q *Queries) ListOpenPendingPosts(ctx context.Context) ([]*PendingPost, error) {
+	rows, err := q.db.QueryContext(ctx, listOpenPendingPosts)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []*ExtractionJob{}
+	items := []*PendingPost{}
 	for rows.Next() {
-		var i ExtractionJob
+		var i PendingPost
 		if err := rows.Scan(
 			&i.ID,
-			&i.BookTitle,
-			&i.FilePath,
-			&i.TotalChunks,
-			&i.ProcessedChunks,
-			&i.QuotesExtracted,
+			&i.QuoteID,
+			&i.TrendID,
+			&i.TrendTitle,
+			&i.TrendSource,
+			&i.TrendHash,
+			&i.Text,
+			&i.Langs,
+			&i.RelevanceScore,
+			&i.RelevanceReasoning,
+			&i.VectorSimilarity,
 			&i.Status,
-			&i.ErrorMessage,
-			&i.StartedAt,
-			&i.CompletedAt,
 			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.ReviewedAt,
+			&i.PostID,
 		); err != nil {
 			return nil, err
 		}
@@ -641,8 +2036,66 @@ func (q *Queries) ListPostsByPlatform(ctx context.Context, arg ListPostsByPlatfo
 	return items, nil
 }
 
+const listQuoteTranslations = `-- name: ListQuoteTranslations :many
+SELECT quotes.id, quotes.text, quotes.text_hash, quotes.source_book, quotes.chapter, quotes.character, quotes.themes, quotes.modern_relevance, quotes.embedding, quotes.char_count, quotes.times_posted, quotes.last_posted_at, quotes.created_at, quotes.embedding_model, quotes.embedding_dim, quotes.quality_score, quotes.quality_issues, quotes.quality_verdict, quotes.validated_at, quotes.source_start_line, quotes.source_end_line, quotes.verbatim_edits, quotes.language, quotes.group_id, quotes.curation_status, quotes.favorite, quotes.curated_at FROM quotes
+JOIN quote_translations qt ON qt.translation_id = quotes.id
+WHERE qt.original_id = ?
+ORDER BY quotes.id
+`
+
+func (q *Queries) ListQuoteTranslations(ctx context.Context, originalID int64) ([]*Quote, error) {
+	rows, err := q.db.QueryContext(ctx, listQuoteTranslations, originalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Quote{}
+	for rows.Next() {
+		var i Quote
+		if err := rows.Scan(
+			&i.ID,
+			&i.Text,
+			&i.TextHash,
+			&i.SourceBook,
+			&i.Chapter,
+			&i.Character,
+			&i.Themes,
+			&i.ModernRelevance,
+			&i.Embedding,
+			&i.CharCount,
+			&i.TimesPosted,
+			&i.LastPostedAt,
+			&i.CreatedAt,
+			&i.EmbeddingModel,
+			&i.EmbeddingDim,
+			&i.QualityScore,
+			&i.QualityIssues,
+			&i.QualityVerdict,
+			&i.ValidatedAt,
+			&i.SourceStartLine,
+			&i.SourceEndLine,
+			&i.VerbatimEdits,
+			&i.Language,
+			&i.GroupID,
+			&i.CurationStatus,
+			&i.Favorite,
+			&i.CuratedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listQuotes = `-- name: ListQuotes :many
-SELECT id, text, text_hash, source_book, chapter, character, themes, modern_relevance, embedding, char_count, times_posted, last_posted_at, created_at FROM quotes ORDER BY created_at DESC LIMIT ? OFFSET ?
+SELECT id, text, text_hash, source_book, chapter, character, themes, modern_relevance, embedding, char_count, times_posted, last_posted_at, created_at, embedding_model, embedding_dim, quality_score, quality_issues, quality_verdict, validated_at, source_start_line, source_end_line, verbatim_edits, language, group_id, curation_status, favorite, curated_at FROM quotes ORDER BY created_at DESC LIMIT ? OFFSET ?
 `
 
 type ListQuotesParams struct {
@@ -673,6 +2126,20 @@ func (q *Queries) ListQuotes(ctx context.Context, arg ListQuotesParams) ([]*Quot
 			&i.TimesPosted,
 			&i.LastPostedAt,
 			&i.CreatedAt,
+			&i.EmbeddingModel,
+			&i.EmbeddingDim,
+			&i.QualityScore,
+			&i.QualityIssues,
+			&i.QualityVerdict,
+			&i.ValidatedAt,
+			&i.SourceStartLine,
+			&i.SourceEndLine,
+			&i.VerbatimEdits,
+			&i.Language,
+			&i.GroupID,
+			&i.CurationStatus,
+			&i.Favorite,
+			&i.CuratedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -688,7 +2155,7 @@ func (q *Queries) ListQuotes(ctx context.Context, arg ListQuotesParams) ([]*Quot
 }
 
 const listQuotesByBook = `-- name: ListQuotesByBook :many
-SELECT id, text, text_hash, source_book, chapter, character, themes, modern_relevance, embedding, char_count, times_posted, last_posted_at, created_at FROM quotes WHERE source_book = ? ORDER BY created_at DESC
+SELECT id, text, text_hash, source_book, chapter, character, themes, modern_relevance, embedding, char_count, times_posted, last_posted_at, created_at, embedding_model, embedding_dim, quality_score, quality_issues, quality_verdict, validated_at, source_start_line, source_end_line, verbatim_edits, language, group_id, curation_status, favorite, curated_at FROM quotes WHERE source_book = ? ORDER BY created_at DESC
 `
 
 func (q *Queries) ListQuotesByBook(ctx context.Context, sourceBook string) ([]*Quote, error) {
@@ -714,6 +2181,20 @@ func (q *Queries) ListQuotesByBook(ctx context.Context, sourceBook string) ([]*Q
 			&i.TimesPosted,
 			&i.LastPostedAt,
 			&i.CreatedAt,
+			&i.EmbeddingModel,
+			&i.EmbeddingDim,
+			&i.QualityScore,
+			&i.QualityIssues,
+			&i.QualityVerdict,
+			&i.ValidatedAt,
+			&i.SourceStartLine,
+			&i.SourceEndLine,
+			&i.VerbatimEdits,
+			&i.Language,
+			&i.GroupID,
+			&i.CurationStatus,
+			&i.Favorite,
+			&i.CuratedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -729,7 +2210,9 @@ func (q *Queries) ListQuotesByBook(ctx context.Context, sourceBook string) ([]*Q
 }
 
 const listQuotesWithEmbeddings = `-- name: ListQuotesWithEmbeddings :many
-SELECT id, text, text_hash, source_book, chapter, character, themes, modern_relevance, embedding, char_count, times_posted, last_posted_at, created_at FROM quotes WHERE embedding IS NOT NULL ORDER BY id
+SELECT id, text, text_hash, source_book, chapter, character, themes, modern_relevance, embedding, char_count, times_posted, last_posted_at, created_at, embedding_model, embedding_dim, quality_score, quality_issues, quality_verdict, validated_at, source_start_line, source_end_line, verbatim_edits, language, group_id, curation_status, favorite, curated_at FROM quotes
+WHERE embedding IS NOT NULL AND (quality_verdict IS NULL OR quality_verdict != 'reject')
+ORDER BY id
 `
 
 func (q *Queries) ListQuotesWithEmbeddings(ctx context.Context) ([]*Quote, error) {
@@ -755,6 +2238,20 @@ func (q *Queries) ListQuotesWithEmbeddings(ctx context.Context) ([]*Quote, error
 			&i.TimesPosted,
 			&i.LastPostedAt,
 			&i.CreatedAt,
+			&i.EmbeddingModel,
+			&i.EmbeddingDim,
+			&i.QualityScore,
+			&i.QualityIssues,
+			&i.QualityVerdict,
+			&i.ValidatedAt,
+			&i.SourceStartLine,
+			&i.SourceEndLine,
+			&i.VerbatimEdits,
+			&i.Language,
+			&i.GroupID,
+			&i.CurationStatus,
+			&i.Favorite,
+			&i.CuratedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -770,11 +2267,235 @@ func (q *Queries) ListQuotesWithEmbeddings(ctx context.Context) ([]*Quote, error
 }
 
 const listQuotesWithoutEmbeddings = `-- name: ListQuotesWithoutEmbeddings :many
-SELECT id, text, text_hash, source_book, chapter, character, themes, modern_relevance, embedding, char_count, times_posted, last_posted_at, created_at FROM quotes WHERE embedding IS NULL ORDER BY id LIMIT ?
+SELECT id, text, text_hash, source_book, chapter, character, themes, modern_relevance, embedding, char_count, times_posted, last_posted_at, created_at, embedding_model, embedding_dim, quality_score, quality_issues, quality_verdict, validated_at, source_start_line, source_end_line, verbatim_edits, language, group_id, curation_status, favorite, curated_at FROM quotes WHERE embedding IS NULL ORDER BY id LIMIT ?
+`
+
+func (q *Queries) ListQuotesWithoutEmbeddings(ctx context.Context, limit int64) ([]*Quote, error) {
+	rows, err := q.db.QueryContext(ctx, listQuotesWithoutEmbeddings, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Quote{}
+	for rows.Next() {
+		var i Quote
+		if err := rows.Scan(
+			&i.ID,
+			&i.Text,
+			&i.TextHash,
+			&i.SourceBook,
+			&i.Chapter,
+			&i.Character,
+			&i.Themes,
+			&i.ModernRelevance,
+			&i.Embedding,
+			&i.CharCount,
+			&i.TimesPosted,
+			&i.LastPostedAt,
+			&i.CreatedAt,
+			&i.EmbeddingModel,
+			&i.EmbeddingDim,
+			&i.QualityScore,
+			&i.QualityIssues,
+			&i.QualityVerdict,
+			&i.ValidatedAt,
+			&i.SourceStartLine,
+			&i.SourceEndLine,
+			&i.VerbatimEdits,
+			&i.Language,
+			&i.GroupID,
+			&i.CurationStatus,
+			&i.Favorite,
+			&i.CuratedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listThemeAliases = `-- name: ListThemeAliases :many
+SELECT alias, theme_id FROM theme_aliases ORDER BY alias
+`
+
+func (q *Queries) ListThemeAliases(ctx context.Context) ([]*ThemeAlias, error) {
+	rows, err := q.db.QueryContext(ctx, listThemeAliases)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*ThemeAlias{}
+	for rows.Next() {
+		var i ThemeAlias
+		if err := rows.Scan(&i.Alias, &i.ThemeID); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listThemeQuotes = `-- name: ListThemeQuotes :many
+SELECT quotes.id, quotes.text, quotes.text_hash, quotes.source_book, quotes.chapter, quotes.character, quotes.themes, quotes.modern_relevance, quotes.embedding, quotes.char_count, quotes.times_posted, quotes.last_posted_at, quotes.created_at, quotes.embedding_model, quotes.embedding_dim, quotes.quality_score, quotes.quality_issues, quotes.quality_verdict, quotes.validated_at, quotes.source_start_line, quotes.source_end_line, quotes.verbatim_edits, quotes.language, quotes.group_id, quotes.curation_status, quotes.favorite, quotes.curated_at FROM quotes
+JOIN quote_themes qt ON qt.quote_id = quotes.id
+WHERE qt.theme_id = ?
+ORDER BY quotes.id
+`
+
+func (q *Queries) ListThemeQuotes(ctx context.Context, themeID int64) ([]*Quote, error) {
+	rows, err := q.db.QueryContext(ctx, listThemeQuotes, themeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Quote{}
+	for rows.Next() {
+		var i Quote
+		if err := rows.Scan(
+			&i.ID,
+			&i.Text,
+			&i.TextHash,
+			&i.SourceBook,
+			&i.Chapter,
+			&i.Character,
+			&i.Themes,
+			&i.ModernRelevance,
+			&i.Embedding,
+			&i.CharCount,
+			&i.TimesPosted,
+			&i.LastPostedAt,
+			&i.CreatedAt,
+			&i.EmbeddingModel,
+			&i.EmbeddingDim,
+			&i.QualityScore,
+			&i.QualityIssues,
+			&i.QualityVerdict,
+			&i.ValidatedAt,
+			&i.SourceStartLine,
+			&i.SourceEndLine,
+			&i.VerbatimEdits,
+			&i.Language,
+			&i.GroupID,
+			&i.CurationStatus,
+			&i.Favorite,
+			&i.CuratedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listThemes = `-- name: ListThemes :many
+SELECT id, name, created_at FROM themes ORDER BY name
+`
+
+func (q *Queries) ListThemes(ctx context.Context) ([]*Theme, error) {
+	rows, err := q.db.QueryContext(ctx, listThemes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Theme{}
+	for rows.Next() {
+		var i Theme
+		if err := rows.Scan(&i.ID, &i.Name, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnmatchedTrends = `-- name: ListUnmatchedTrends :many
+SELECT id, source, external_id, title, url, description, score, embedding, matched, skipped, skip_reason, detected_at, embedding_model, embedding_dim FROM trends
+WHERE matched = FALSE AND skipped = FALSE
+ORDER BY detected_at DESC LIMIT ?
+`
+
+func (q *Queries) ListUnmatchedTrends(ctx context.Context, limit int64) ([]*Trend, error) {
+	rows, err := q.db.QueryContext(ctx, listUnmatchedTrends, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Trend{}
+	for rows.Next() {
+		var i Trend
+		if err := rows.Scan(
+			&i.ID,
+			&i.Source,
+			&i.ExternalID,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.Score,
+			&i.Embedding,
+			&i.Matched,
+			&i.Skipped,
+			&i.SkipReason,
+			&i.DetectedAt,
+			&i.EmbeddingModel,
+			&i.EmbeddingDim,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUntranslatedQuotes = `-- name: ListUntranslatedQuotes :many
+SELECT id, text, text_hash, source_book, chapter, character, themes, modern_relevance, embedding, char_count, times_posted, last_posted_at, created_at, embedding_model, embedding_dim, quality_score, quality_issues, quality_verdict, validated_at, source_start_line, source_end_line, verbatim_edits, language, group_id, curation_status, favorite, curated_at FROM quotes
+WHERE source_book = ?
+  AND (quality_verdict IS NULL OR quality_verdict != 'reject')
+  AND NOT EXISTS (
+      SELECT 1 FROM quote_translations qt
+      JOIN quotes t ON t.id = qt.translation_id
+      WHERE qt.original_id = quotes.id AND t.source_book = ?
+  )
+ORDER BY id
 `
 
-func (q *Queries) ListQuotesWithoutEmbeddings(ctx context.Context, limit int64) ([]*Quote, error) {
-	rows, err := q.db.QueryContext(ctx, listQuotesWithoutEmbeddings, limit)
+type ListUntranslatedQuotesParams struct {
+	OriginalBook    string `json:"original_book"`
+	TranslationBook string `json:"translation_book"`
+}
+
+func (q *Queries) ListUntranslatedQuotes(ctx context.Context, arg ListUntranslatedQuotesParams) ([]*Quote, error) {
+	rows, err := q.db.QueryContext(ctx, listUntranslatedQuotes, arg.OriginalBook, arg.TranslationBook)
 	if err != nil {
 		return nil, err
 	}
@@ -796,6 +2517,20 @@ func (q *Queries) ListQuotesWithoutEmbeddings(ctx context.Context, limit int64)
 			&i.TimesPosted,
 			&i.LastPostedAt,
 			&i.CreatedAt,
+			&i.EmbeddingModel,
+			&i.EmbeddingDim,
+			&i.QualityScore,
+			&i.QualityIssues,
+			&i.QualityVerdict,
+			&i.ValidatedAt,
+			&i.SourceStartLine,
+			&i.SourceEndLine,
+			&i.VerbatimEdits,
+			&i.Language,
+			&i.GroupID,
+			&i.CurationStatus,
+			&i.Favorite,
+			&i.CuratedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -810,34 +2545,47 @@ func (q *Queries) ListQuotesWithoutEmbeddings(ctx context.Context, limit int64)
 	return items, nil
 }
 
-const listUnmatchedTrends = `-- name: ListUnmatchedTrends :many
-SELECT id, source, external_id, title, url, description, score, embedding, matched, skipped, skip_reason, detected_at FROM trends
-WHERE matched = FALSE AND skipped = FALSE
-ORDER BY detected_at DESC LIMIT ?
+const listUnvalidatedQuotes = `-- name: ListUnvalidatedQuotes :many
+SELECT id, text, text_hash, source_book, chapter, character, themes, modern_relevance, embedding, char_count, times_posted, last_posted_at, created_at, embedding_model, embedding_dim, quality_score, quality_issues, quality_verdict, validated_at, source_start_line, source_end_line, verbatim_edits, language, group_id, curation_status, favorite, curated_at FROM quotes WHERE validated_at IS NULL ORDER BY id LIMIT ?
 `
 
-func (q *Queries) ListUnmatchedTrends(ctx context.Context, limit int64) ([]*Trend, error) {
-	rows, err := q.db.QueryContext(ctx, listUnmatchedTrends, limit)
+func (q *Queries) ListUnvalidatedQuotes(ctx context.Context, limit int64) ([]*Quote, error) {
+	rows, err := q.db.QueryContext(ctx, listUnvalidatedQuotes, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []*Trend{}
+	items := []*Quote{}
 	for rows.Next() {
-		var i Trend
+		var i Quote
 		if err := rows.Scan(
 			&i.ID,
-			&i.Source,
-			&i.ExternalID,
-			&i.Title,
-			&i.Url,
-			&i.Description,
-			&i.Score,
+			&i.Text,
+			&i.TextHash,
+			&i.SourceBook,
+			&i.Chapter,
+			&i.Character,
+			&i.Themes,
+			&i.ModernRelevance,
 			&i.Embedding,
-			&i.Matched,
-			&i.Skipped,
-			&i.SkipReason,
-			&i.DetectedAt,
+			&i.CharCount,
+			&i.TimesPosted,
+			&i.LastPostedAt,
+			&i.CreatedAt,
+			&i.EmbeddingModel,
+			&i.EmbeddingDim,
+			&i.QualityScore,
+			&i.QualityIssues,
+			&i.QualityVerdict,
+			&i.ValidatedAt,
+			&i.SourceStartLine,
+			&i.SourceEndLine,
+			&i.VerbatimEdits,
+			&i.Language,
+			&i.GroupID,
+			&i.CurationStatus,
+			&i.Favorite,
+			&i.CuratedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -852,6 +2600,220 @@ func (q *Queries) ListUnmatchedTrends(ctx context.Context, limit int64) ([]*Tren
 	return items, nil
 }
 
+const markPendingPostPosted = `-- name: MarkPendingPostPosted :exec
+UPDATE pending_posts SET status = 'posted', post_id = ? WHERE id = ?
+`
+
+type MarkPendingPostPostedParams struct {
+	PostID sql.NullInt64 `json:"post_id"`
+	ID     int64         `json:"id"`
+}
+
+func (q *Queries) MarkPendingPostPosted(ctx context.Context, arg MarkPendingPostPostedParams) error {
+	_, err := q.db.ExecContext(ctx, markPendingPostPosted, arg.PostID, arg.ID)
+	return err
+}
+
+const moveQuoteGroup = `-- name: MoveQuoteGroup :exec
+UPDATE quotes SET group_id = ? WHERE group_id = ?
+`
+
+type MoveQuoteGroupParams struct {
+	KeeperID sql.NullInt64 `json:"keeper_id"`
+	GroupID  sql.NullInt64 `json:"group_id"`
+}
+
+func (q *Queries) MoveQuoteGroup(ctx context.Context, arg MoveQuoteGroupParams) error {
+	_, err := q.db.ExecContext(ctx, moveQuoteGroup, arg.KeeperID, arg.GroupID)
+	return err
+}
+
+const nextApprovedPendingPost = `-- name: NextApprovedPendingPost :one
+SELECT id, quote_id, trend_id, trend_title, trend_source, trend_hash, text, langs, relevance_score, relevance_reasoning, vector_similarity, status, created_at, expires_at, reviewed_at, post_id FROM pending_posts
+WHERE status = 'approved' AND expires_at > CURRENT_TIMESTAMP
+ORDER BY relevance_score DESC, id
+LIMIT 1
+`
+
+func (q *Queries) NextApprovedPendingPost(ctx context.Context) (*PendingPost, error) {
+	row := q.db.QueryRowContext(ctx, nextApprovedPendingPost)
+	var i PendingPost
+	err := row.Scan(
+		&i.ID,
+		&i.QuoteID,
+		&i.TrendID,
+		&i.TrendTitle,
+		&i.TrendSource,
+		&i.TrendHash,
+		&i.Text,
+		&i.Langs,
+		&i.RelevanceScore,
+		&i.RelevanceReasoning,
+		&i.VectorSimilarity,
+		&i.Status,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.ReviewedAt,
+		&i.PostID,
+	)
+	return &i, err
+}
+
+const reassignCharacterAliases = `-- name: ReassignCharacterAliases :exec
+UPDATE character_aliases SET character_id = ? WHERE character_id = ?
+`
+
+type ReassignCharacterAliasesParams struct {
+	KeeperID    int64 `json:"keeper_id"`
+	DuplicateID int64 `json:"duplicate_id"`
+}
+
+func (q *Queries) ReassignCharacterAliases(ctx context.Context, arg ReassignCharacterAliasesParams) error {
+	_, err := q.db.ExecContext(ctx, reassignCharacterAliases, arg.KeeperID, arg.DuplicateID)
+	return err
+}
+
+const reassignQuotePendingPosts = `-- name: ReassignQuotePendingPosts :exec
+UPDATE pending_posts SET quote_id = ? WHERE quote_id = ?
+`
+
+type ReassignQuotePendingPostsParams struct {
+	KeeperID    int64 `json:"keeper_id"`
+	DuplicateID int64 `json:"duplicate_id"`
+}
+
+func (q *Queries) ReassignQuotePendingPosts(ctx context.Context, arg ReassignQuotePendingPostsParams) error {
+	_, err := q.db.ExecContext(ctx, reassignQuotePendingPosts, arg.KeeperID, arg.DuplicateID)
+	return err
+}
+
+const reassignQuotePosts = `-- name: ReassignQuotePosts :exec
+UPDATE posts SET quote_id = ? WHERE quote_id = ?
+`
+
+type ReassignQuotePostsParams struct {
+	KeeperID    int64 `json:"keeper_id"`
+	DuplicateID int64 `json:"duplicate_id"`
+}
+
+func (q *Queries) ReassignQuotePosts(ctx context.Context, arg ReassignQuotePostsParams) error {
+	_, err := q.db.ExecContext(ctx, reassignQuotePosts, arg.KeeperID, arg.DuplicateID)
+	return err
+}
+
+const reassignQuoteThemes = `-- name: ReassignQuoteThemes :exec
+UPDATE OR IGNORE quote_themes SET quote_id = ? WHERE quote_id = ?
+`
+
+type ReassignQuoteThemesParams struct {
+	KeeperID    int64 `json:"keeper_id"`
+	DuplicateID int64 `json:"duplicate_id"`
+}
+
+func (q *Queries) ReassignQuoteThemes(ctx context.Context, arg ReassignQuoteThemesParams) error {
+	_, err := q.db.ExecContext(ctx, reassignQuoteThemes, arg.KeeperID, arg.DuplicateID)
+	return err
+}
+
+const reassignThemeAliases = `-- name: ReassignThemeAliases :exec
+UPDATE theme_aliases SET theme_id = ? WHERE theme_id = ?
+`
+
+type ReassignThemeAliasesParams struct {
+	KeeperID    int64 `json:"keeper_id"`
+	DuplicateID int64 `json:"duplicate_id"`
+}
+
+func (q *Queries) ReassignThemeAliases(ctx context.Context, arg ReassignThemeAliasesParams) error {
+	_, err := q.db.ExecContext(ctx, reassignThemeAliases, arg.KeeperID, arg.DuplicateID)
+	return err
+}
+
+const reassignTranslationOriginals = `-- name: ReassignTranslationOriginals :exec
+UPDATE OR IGNORE quote_translations SET original_id = ? WHERE original_id = ?
+`
+
+type ReassignTranslationOriginalsParams struct {
+	KeeperID    int64 `json:"keeper_id"`
+	DuplicateID int64 `json:"duplicate_id"`
+}
+
+func (q *Queries) ReassignTranslationOriginals(ctx context.Context, arg ReassignTranslationOriginalsParams) error {
+	_, err := q.db.ExecContext(ctx, reassignTranslationOriginals, arg.KeeperID, arg.DuplicateID)
+	return err
+}
+
+const reassignTranslationTargets = `-- name: ReassignTranslationTargets :exec
+UPDATE OR IGNORE quote_translations SET translation_id = ? WHERE translation_id = ?
+`
+
+type ReassignTranslationTargetsParams struct {
+	KeeperID    int64 `json:"keeper_id"`
+	DuplicateID int64 `json:"duplicate_id"`
+}
+
+func (q *Queries) ReassignTranslationTargets(ctx context.Context, arg ReassignTranslationTargetsParams) error {
+	_, err := q.db.ExecContext(ctx, reassignTranslationTargets, arg.KeeperID, arg.DuplicateID)
+	return err
+}
+
+const recordSelectionCacheHit = `-- name: RecordSelectionCacheHit :exec
+UPDATE selection_cache
+SET hits = hits + 1, last_hit_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+func (q *Queries) RecordSelectionCacheHit(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, recordSelectionCacheHit, id)
+	return err
+}
+
+const renameQuoteCharacter = `-- name: RenameQuoteCharacter :exec
+UPDATE quotes SET character = ?
+WHERE source_book = ? AND character = ?
+`
+
+type RenameQuoteCharacterParams struct {
+	Character    sql.NullString `json:"character"`
+	SourceBook   string         `json:"source_book"`
+	OldCharacter sql.NullString `json:"old_character"`
+}
+
+func (q *Queries) RenameQuoteCharacter(ctx context.Context, arg RenameQuoteCharacterParams) error {
+	_, err := q.db.ExecContext(ctx, renameQuoteCharacter, arg.Character, arg.SourceBook, arg.OldCharacter)
+	return err
+}
+
+const setBookOriginal = `-- name: SetBookOriginal :exec
+UPDATE books SET original_id = ? WHERE id = ?
+`
+
+type SetBookOriginalParams struct {
+	OriginalID sql.NullInt64 `json:"original_id"`
+	ID         int64         `json:"id"`
+}
+
+func (q *Queries) SetBookOriginal(ctx context.Context, arg SetBookOriginalParams) error {
+	_, err := q.db.ExecContext(ctx, setBookOriginal, arg.OriginalID, arg.ID)
+	return err
+}
+
+const setCharacterAlias = `-- name: SetCharacterAlias :exec
+INSERT INTO character_aliases (source_book, alias, character_id) VALUES (?, ?, ?)
+ON CONFLICT (source_book, alias) DO UPDATE SET character_id = excluded.character_id
+`
+
+type SetCharacterAliasParams struct {
+	SourceBook  string `json:"source_book"`
+	Alias       string `json:"alias"`
+	CharacterID int64  `json:"character_id"`
+}
+
+func (q *Queries) SetCharacterAlias(ctx context.Context, arg SetCharacterAliasParams) error {
+	_, err := q.db.ExecContext(ctx, setCharacterAlias, arg.SourceBook, arg.Alias, arg.CharacterID)
+	return err
+}
+
 const setConfig = `-- name: SetConfig :exec
 INSERT INTO config (key, value, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)
 ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = CURRENT_TIMESTAMP
@@ -867,6 +2829,144 @@ func (q *Queries) SetConfig(ctx context.Context, arg SetConfigParams) error {
 	return err
 }
 
+const setPendingPostStatus = `-- name: SetPendingPostStatus :exec
+UPDATE pending_posts SET status = ?, reviewed_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+type SetPendingPostStatusParams struct {
+	Status string `json:"status"`
+	ID     int64  `json:"id"`
+}
+
+func (q *Queries) SetPendingPostStatus(ctx context.Context, arg SetPendingPostStatusParams) error {
+	_, err := q.db.ExecContext(ctx, setPendingPostStatus, arg.Status, arg.ID)
+	return err
+}
+
+const setQuoteCharacter = `-- name: SetQuoteCharacter :exec
+UPDATE quotes SET character = ? WHERE id = ?
+`
+
+type SetQuoteCharacterParams struct {
+	Character sql.NullString `json:"character"`
+	ID        int64          `json:"id"`
+}
+
+func (q *Queries) SetQuoteCharacter(ctx context.Context, arg SetQuoteCharacterParams) error {
+	_, err := q.db.ExecContext(ctx, setQuoteCharacter, arg.Character, arg.ID)
+	return err
+}
+
+const setQuoteCuration = `-- name: SetQuoteCuration :exec
+UPDATE quotes SET curation_status = ?, curated_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+type SetQuoteCurationParams struct {
+	CurationStatus sql.NullString `json:"curation_status"`
+	ID             int64          `json:"id"`
+}
+
+func (q *Queries) SetQuoteCuration(ctx context.Context, arg SetQuoteCurationParams) error {
+	_, err := q.db.ExecContext(ctx, setQuoteCuration, arg.CurationStatus, arg.ID)
+	return err
+}
+
+const setQuoteFavorite = `-- name: SetQuoteFavorite :exec
+UPDATE quotes SET favorite = ?, curated_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+type SetQuoteFavoriteParams struct {
+	Favorite bool  `json:"favorite"`
+	ID       int64 `json:"id"`
+}
+
+func (q *Queries) SetQuoteFavorite(ctx context.Context, arg SetQuoteFavoriteParams) error {
+	_, err := q.db.ExecContext(ctx, setQuoteFavorite, arg.Favorite, arg.ID)
+	return err
+}
+
+const setQuoteGroup = `-- name: SetQuoteGroup :exec
+UPDATE quotes SET group_id = ? WHERE id = ?
+`
+
+type SetQuoteGroupParams struct {
+	GroupID sql.NullInt64 `json:"group_id"`
+	ID      int64         `json:"id"`
+}
+
+func (q *Queries) SetQuoteGroup(ctx context.Context, arg SetQuoteGroupParams) error {
+	_, err := q.db.ExecContext(ctx, setQuoteGroup, arg.GroupID, arg.ID)
+	return err
+}
+
+const setQuotePostHistory = `-- name: SetQuotePostHistory :exec
+UPDATE quotes SET times_posted = ?, last_posted_at = ? WHERE id = ?
+`
+
+type SetQuotePostHistoryParams struct {
+	TimesPosted  sql.NullInt64 `json:"times_posted"`
+	LastPostedAt sql.NullTime  `json:"last_posted_at"`
+	ID           int64         `json:"id"`
+}
+
+func (q *Queries) SetQuotePostHistory(ctx context.Context, arg SetQuotePostHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, setQuotePostHistory, arg.TimesPosted, arg.LastPostedAt, arg.ID)
+	return err
+}
+
+const setQuoteThemes = `-- name: SetQuoteThemes :exec
+UPDATE quotes SET themes = ? WHERE id = ?
+`
+
+type SetQuoteThemesParams struct {
+	Themes string `json:"themes"`
+	ID     int64  `json:"id"`
+}
+
+func (q *Queries) SetQuoteThemes(ctx context.Context, arg SetQuoteThemesParams) error {
+	_, err := q.db.ExecContext(ctx, setQuoteThemes, arg.Themes, arg.ID)
+	return err
+}
+
+const setThemeAlias = `-- name: SetThemeAlias :exec
+INSERT INTO theme_aliases (alias, theme_id) VALUES (?, ?)
+ON CONFLICT(alias) DO UPDATE SET theme_id = excluded.theme_id
+`
+
+type SetThemeAliasParams struct {
+	Alias   string `json:"alias"`
+	ThemeID int64  `json:"theme_id"`
+}
+
+func (q *Queries) SetThemeAlias(ctx context.Context, arg SetThemeAliasParams) error {
+	_, err := q.db.ExecContext(ctx, setThemeAlias, arg.Alias, arg.ThemeID)
+	return err
+}
+
+const updateBookDownload = `-- name: UpdateBookDownload :exec
+UPDATE books
+SET checksum = ?, edition = COALESCE(?, edition), translator = COALESCE(translator, ?),
+    downloaded_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type UpdateBookDownloadParams struct {
+	Checksum   sql.NullString `json:"checksum"`
+	Edition    sql.NullString `json:"edition"`
+	Translator sql.NullString `json:"translator"`
+	ID         int64          `json:"id"`
+}
+
+func (q *Queries) UpdateBookDownload(ctx context.Context, arg UpdateBookDownloadParams) error {
+	_, err := q.db.ExecContext(ctx, updateBookDownload,
+		arg.Checksum,
+		arg.Edition,
+		arg.Translator,
+		arg.ID,
+	)
+	return err
+}
+
 const updateExtractionJobCompleted = `-- name: UpdateExtractionJobCompleted :exec
 UPDATE extraction_jobs
 SET status = 'completed', completed_at = CURRENT_TIMESTAMP
@@ -913,7 +3013,8 @@ func (q *Queries) UpdateExtractionJobProgress(ctx context.Context, arg UpdateExt
 
 const updateExtractionJobStarted = `-- name: UpdateExtractionJobStarted :exec
 UPDATE extraction_jobs
-SET status = 'running', total_chunks = ?, started_at = CURRENT_TIMESTAMP
+SET status = 'running', total_chunks = ?, error_message = NULL, completed_at = NULL,
+    started_at = COALESCE(started_at, CURRENT_TIMESTAMP)
 WHERE id = ?
 `
 
@@ -927,6 +3028,20 @@ func (q *Queries) UpdateExtractionJobStarted(ctx context.Context, arg UpdateExtr
 	return err
 }
 
+const updatePendingPostText = `-- name: UpdatePendingPostText :exec
+UPDATE pending_posts SET text = ? WHERE id = ?
+`
+
+type UpdatePendingPostTextParams struct {
+	Text string `json:"text"`
+	ID   int64  `json:"id"`
+}
+
+func (q *Queries) UpdatePendingPostText(ctx context.Context, arg UpdatePendingPostTextParams) error {
+	_, err := q.db.ExecContext(ctx, updatePendingPostText, arg.Text, arg.ID)
+	return err
+}
+
 const updatePostEngagement = `-- name: UpdatePostEngagement :exec
 UPDATE posts SET likes = ?, reposts = ?, replies = ? WHERE id = ?
 `
@@ -949,16 +3064,23 @@ func (q *Queries) UpdatePostEngagement(ctx context.Context, arg UpdatePostEngage
 }
 
 const updateQuoteEmbedding = `-- name: UpdateQuoteEmbedding :exec
-UPDATE quotes SET embedding = ? WHERE id = ?
+UPDATE quotes SET embedding = ?, embedding_model = ?, embedding_dim = ? WHERE id = ?
 `
 
 type UpdateQuoteEmbeddingParams struct {
-	Embedding []byte `json:"embedding"`
-	ID        int64  `json:"id"`
+	Embedding      []byte         `json:"embedding"`
+	EmbeddingModel sql.NullString `json:"embedding_model"`
+	EmbeddingDim   sql.NullInt64  `json:"embedding_dim"`
+	ID             int64          `json:"id"`
 }
 
 func (q *Queries) UpdateQuoteEmbedding(ctx context.Context, arg UpdateQuoteEmbeddingParams) error {
-	_, err := q.db.ExecContext(ctx, updateQuoteEmbedding, arg.Embedding, arg.ID)
+	_, err := q.db.ExecContext(ctx, updateQuoteEmbedding,
+		arg.Embedding,
+		arg.EmbeddingModel,
+		arg.EmbeddingDim,
+		arg.ID,
+	)
 	return err
 }
 
@@ -973,17 +3095,86 @@ func (q *Queries) UpdateQuotePosted(ctx context.Context, id int64) error {
 	return err
 }
 
+const updateQuoteText = `-- name: UpdateQuoteText :exec
+UPDATE quotes
+SET text = ?, text_hash = ?, char_count = ?, embedding = NULL,
+    source_start_line = NULL, source_end_line = NULL, verbatim_edits = NULL,
+    curated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type UpdateQuoteTextParams struct {
+	Text      string `json:"text"`
+	TextHash  string `json:"text_hash"`
+	CharCount int64  `json:"char_count"`
+	ID        int64  `json:"id"`
+}
+
+func (q *Queries) UpdateQuoteText(ctx context.Context, arg UpdateQuoteTextParams) error {
+	_, err := q.db.ExecContext(ctx, updateQuoteText,
+		arg.Text,
+		arg.TextHash,
+		arg.CharCount,
+		arg.ID,
+	)
+	return err
+}
+
+const updateQuoteThemes = `-- name: UpdateQuoteThemes :exec
+UPDATE quotes SET themes = ?, curated_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+type UpdateQuoteThemesParams struct {
+	Themes string `json:"themes"`
+	ID     int64  `json:"id"`
+}
+
+func (q *Queries) UpdateQuoteThemes(ctx context.Context, arg UpdateQuoteThemesParams) error {
+	_, err := q.db.ExecContext(ctx, updateQuoteThemes, arg.Themes, arg.ID)
+	return err
+}
+
+const updateQuoteValidation = `-- name: UpdateQuoteValidation :exec
+UPDATE quotes
+SET quality_score = ?, quality_issues = ?, quality_verdict = ?, validated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type UpdateQuoteValidationParams struct {
+	QualityScore   sql.NullInt64  `json:"quality_score"`
+	QualityIssues  sql.NullString `json:"quality_issues"`
+	QualityVerdict sql.NullString `json:"quality_verdict"`
+	ID             int64          `json:"id"`
+}
+
+func (q *Queries) UpdateQuoteValidation(ctx context.Context, arg UpdateQuoteValidationParams) error {
+	_, err := q.db.ExecContext(ctx, updateQuoteValidation,
+		arg.QualityScore,
+		arg.QualityIssues,
+		arg.QualityVerdict,
+		arg.ID,
+	)
+	return err
+}
+
 const updateTrendEmbedding = `-- name: UpdateTrendEmbedding :exec
-UPDATE trends SET embedding = ? WHERE id = ?
+UPDATE trends SET embedding = ?, embedding_model = ?, embedding_dim = ? WHERE id = ?
 `
 
 type UpdateTrendEmbeddingParams struct {
-	Embedding []byte `json:"embedding"`
-	ID        int64  `json:"id"`
+	Embedding      []byte         `json:"embedding"`
+	EmbeddingModel sql.NullString `json:"embedding_model"`
+	EmbeddingDim   sql.NullInt64  `json:"embedding_dim"`
+	ID             int64          `json:"id"`
 }
 
 func (q *Queries) UpdateTrendEmbedding(ctx context.Context, arg UpdateTrendEmbeddingParams) error {
-	_, err := q.db.ExecContext(ctx, updateTrendEmbedding, arg.Embedding, arg.ID)
+	_, err := q.db.ExecContext(ctx, updateTrendEmbedding,
+		arg.Embedding,
+		arg.EmbeddingModel,
+		arg.EmbeddingDim,
+		arg.ID,
+	)
 	return err
 }
 
@@ -0,0 +1,282 @@
+package dedupe
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/embedder"
+	"github.com/abdulachik/dostobot/internal/themes"
+)
+
+const (
+	// DefaultSimilarity is the embedding cosine similarity above which two
+	// quotes with some text overlap are the same passage.
+	DefaultSimilarity = 0.95
+
+	// MinTextOverlap is the containment two quotes need before their
+	// embeddings are compared; unrelated passages can embed alike.
+	MinTextOverlap = 0.5
+)
+
+// Options controls what FindGroups treats as a duplicate.
+type Options struct {
+	// Threshold is the containment at which quotes are duplicates on text
+	// alone (default DefaultThreshold).
+	Threshold float64
+
+	// Similarity is the embedding similarity at which quotes sharing at least
+	// half their text are duplicates (default DefaultSimilarity).
+	Similarity float32
+}
+
+// Group is a set of quotes that are the same passage.
+type Group struct {
+	Keeper     *db.Quote
+	Duplicates []*db.Quote
+}
+
+// FindGroups groups quotes that are near-duplicates of each other, directly
+// or through a chain of near-duplicates, and picks the quote each group keeps.
+// embeddings maps quote IDs to their vectors and may be empty, in which case
+// only text similarity is used.
+func FindGroups(quotes []*db.Quote, embeddings map[int64][]float32, opts Options) []Group {
+	opts = opts.withDefaults()
+
+	sigs := make([]Signature, len(quotes))
+	index := NewIndex()
+	for i, q := range quotes {
+		sigs[i] = Sign(q.Text)
+		index.Add(int64(i), sigs[i])
+	}
+
+	// Union-find over positions in quotes.
+	parent := make([]int, len(quotes))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range quotes {
+		for _, j := range index.candidates(sigs[i]) {
+			if j <= i || find(i) == find(j) {
+				continue
+			}
+			containment := sigs[i].Containment(sigs[j])
+			if opts.Duplicate(containment, embeddings[quotes[i].ID], embeddings[quotes[j].ID]) {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	members := make(map[int][]*db.Quote)
+	for i, q := range quotes {
+		root := find(i)
+		members[root] = append(members[root], q)
+	}
+
+	var groups []Group
+	for _, qs := range members {
+		if len(qs) < 2 {
+			continue
+		}
+		sort.Slice(qs, func(a, b int) bool { return keepBefore(qs[a], qs[b]) })
+		groups = append(groups, Group{Keeper: qs[0], Duplicates: qs[1:]})
+	}
+	sort.Slice(groups, func(a, b int) bool { return groups[a].Keeper.ID < groups[b].Keeper.ID })
+
+	return groups
+}
+
+func (o Options) withDefaults() Options {
+	if o.Threshold <= 0 {
+		o.Threshold = DefaultThreshold
+	}
+	if o.Similarity <= 0 {
+		o.Similarity = DefaultSimilarity
+	}
+	return o
+}
+
+// Duplicate reports whether two quotes whose signatures have containment
+// are the same passage: on text alone, or, if they share at least
+// MinTextOverlap of their text, by their embeddings va and vb. Missing
+// embeddings only allow the text check.
+func (o Options) Duplicate(containment float64, va, vb []float32) bool {
+	o = o.withDefaults()
+	if containment >= o.Threshold {
+		return true
+	}
+	if containment < MinTextOverlap {
+		return false
+	}
+	if len(va) == 0 || len(va) != len(vb) {
+		return false
+	}
+	return embedder.CosineSimilarity(va, vb) >= o.Similarity
+}
+
+// keepBefore orders the quotes of a group by which to keep, putting an
+// editor's decisions first: one not discarded by an editor, one approved,
+// a favorite, one an editor edited or otherwise curated. Then one verified
+// against the source text, one not rejected by validation, the most posted,
+// and the oldest.
+func keepBefore(a, b *db.Quote) bool {
+	if ad, bd := a.Discarded(), b.Discarded(); ad != bd {
+		return bd
+	}
+	if aa, ba := a.Approved(), b.Approved(); aa != ba {
+		return aa
+	}
+	if a.Favorite != b.Favorite {
+		return a.Favorite
+	}
+	if ac, bc := a.CuratedAt.Valid, b.CuratedAt.Valid; ac != bc {
+		return ac
+	}
+	if av, bv := a.VerbatimEdits.Valid, b.VerbatimEdits.Valid; av != bv {
+		return av
+	}
+	if ar, br := a.Rejected(), b.Rejected(); ar != br {
+		return br
+	}
+	if a.TimesPosted.Int64 != b.TimesPosted.Int64 {
+		return a.TimesPosted.Int64 > b.TimesPosted.Int64
+	}
+	return a.ID < b.ID
+}
+
+// Merge folds a group into its keeper in one transaction: posts, queued
+// matches, themes, translation links and variant groups of the duplicates
+// are moved to the keeper, their post counts added to its own, and the
+// duplicates deleted. The keeper takes the curation status of the first
+// duplicate with one if it has none, and is a favorite if any of them is.
+func Merge(ctx context.Context, store *db.Store, g Group) error {
+	tx, err := store.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := store.Queries.WithTx(tx)
+
+	tags, seen := themes.Parse(g.Keeper), make(map[string]bool)
+	for _, t := range tags {
+		seen[themes.Key(t)] = true
+	}
+	keeperTags := len(tags)
+	status, favorite := g.Keeper.CurationStatus, g.Keeper.Favorite
+
+	timesPosted := g.Keeper.TimesPosted.Int64
+	lastPosted := g.Keeper.LastPostedAt
+	group := g.Keeper.GroupID
+	for _, dup := range g.Duplicates {
+		if err := qtx.ReassignQuotePosts(ctx, db.ReassignQuotePostsParams{
+			KeeperID:    g.Keeper.ID,
+			DuplicateID: dup.ID,
+		}); err != nil {
+			return fmt.Errorf("reassign posts of quote %d: %w", dup.ID, err)
+		}
+		if err := qtx.ReassignQuotePendingPosts(ctx, db.ReassignQuotePendingPostsParams{
+			KeeperID:    g.Keeper.ID,
+			DuplicateID: dup.ID,
+		}); err != nil {
+			return fmt.Errorf("reassign queued matches of quote %d: %w", dup.ID, err)
+		}
+		if err := qtx.ReassignQuoteThemes(ctx, db.ReassignQuoteThemesParams{
+			KeeperID:    g.Keeper.ID,
+			DuplicateID: dup.ID,
+		}); err != nil {
+			return fmt.Errorf("reassign themes of quote %d: %w", dup.ID, err)
+		}
+		if err := qtx.ReassignTranslationOriginals(ctx, db.ReassignTranslationOriginalsParams{
+			KeeperID:    g.Keeper.ID,
+			DuplicateID: dup.ID,
+		}); err != nil {
+			return fmt.Errorf("reassign translations of quote %d: %w", dup.ID, err)
+		}
+		if err := qtx.ReassignTranslationTargets(ctx, db.ReassignTranslationTargetsParams{
+			KeeperID:    g.Keeper.ID,
+			DuplicateID: dup.ID,
+		}); err != nil {
+			return fmt.Errorf("reassign originals of quote %d: %w", dup.ID, err)
+		}
+		if err := qtx.DeleteQuote(ctx, dup.ID); err != nil {
+			return fmt.Errorf("delete quote %d: %w", dup.ID, err)
+		}
+
+		if dup.GroupID.Valid {
+			if !group.Valid {
+				group = dup.GroupID
+			} else if dup.GroupID.Int64 != group.Int64 {
+				if err := qtx.MoveQuoteGroup(ctx, db.MoveQuoteGroupParams{
+					KeeperID: group,
+					GroupID:  dup.GroupID,
+				}); err != nil {
+					return fmt.Errorf("merge variants of quote %d: %w", dup.ID, err)
+				}
+			}
+		}
+
+		for _, t := range themes.Parse(dup) {
+			if !seen[themes.Key(t)] {
+				seen[themes.Key(t)] = true
+				tags = append(tags, t)
+			}
+		}
+		if !status.Valid {
+			status = dup.CurationStatus
+		}
+		favorite = favorite || dup.Favorite
+		timesPosted += dup.TimesPosted.Int64
+		if dup.LastPostedAt.Valid && (!lastPosted.Valid || dup.LastPostedAt.Time.After(lastPosted.Time)) {
+			lastPosted = dup.LastPostedAt
+		}
+	}
+
+	if err := qtx.SetQuotePostHistory(ctx, db.SetQuotePostHistoryParams{
+		TimesPosted:  sql.NullInt64{Int64: timesPosted, Valid: true},
+		LastPostedAt: lastPosted,
+		ID:           g.Keeper.ID,
+	}); err != nil {
+		return fmt.Errorf("update quote %d: %w", g.Keeper.ID, err)
+	}
+	if status != g.Keeper.CurationStatus {
+		if err := qtx.SetQuoteCuration(ctx, db.SetQuoteCurationParams{CurationStatus: status, ID: g.Keeper.ID}); err != nil {
+			return fmt.Errorf("curate quote %d: %w", g.Keeper.ID, err)
+		}
+	}
+	if favorite != g.Keeper.Favorite {
+		if err := qtx.SetQuoteFavorite(ctx, db.SetQuoteFavoriteParams{Favorite: favorite, ID: g.Keeper.ID}); err != nil {
+			return fmt.Errorf("favorite quote %d: %w", g.Keeper.ID, err)
+		}
+	}
+	if len(tags) > keeperTags {
+		themesJSON, err := json.Marshal(tags)
+		if err != nil {
+			return fmt.Errorf("marshal themes: %w", err)
+		}
+		if err := qtx.SetQuoteThemes(ctx, db.SetQuoteThemesParams{Themes: string(themesJSON), ID: g.Keeper.ID}); err != nil {
+			return fmt.Errorf("set themes of quote %d: %w", g.Keeper.ID, err)
+		}
+	}
+	if group != g.Keeper.GroupID {
+		if err := qtx.SetQuoteGroup(ctx, db.SetQuoteGroupParams{GroupID: group, ID: g.Keeper.ID}); err != nil {
+			return fmt.Errorf("group quote %d: %w", g.Keeper.ID, err)
+		}
+	}
+	if err := qtx.DeleteEmptyQuoteGroups(ctx); err != nil {
+		return fmt.Errorf("delete empty groups: %w", err)
+	}
+
+	return tx.Commit()
+}
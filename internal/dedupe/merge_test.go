@@ -0,0 +1,207 @@
+package dedupe
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/themes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func quote(id int64, text string) *db.Quote {
+	return &db.Quote{ID: id, Text: text, SourceBook: "Crime and Punishment"}
+}
+
+func TestFindGroups(t *testing.T) {
+	a := quote(1, passage)
+	b := quote(2, "Pain and suffering are always inevitable for a large intelligence and a deep heart.")
+	c := quote(3, "Man is fond of counting his troubles, but he does not count his joys.")
+	d := quote(4, "Man is fond of counting his troubles, but he never does count his joys.")
+	e := quote(5, "To go wrong in one's own way is better than to go right in someone else's.")
+
+	t.Run("text only", func(t *testing.T) {
+		groups := FindGroups([]*db.Quote{a, b, c, d, e}, nil, Options{})
+		require.Len(t, groups, 1)
+		assert.Equal(t, a, groups[0].Keeper)
+		assert.Equal(t, []*db.Quote{b}, groups[0].Duplicates)
+	})
+
+	t.Run("embeddings confirm partial overlap", func(t *testing.T) {
+		embeddings := map[int64][]float32{
+			3: {1, 0, 0},
+			4: {0.99, 0.05, 0},
+			5: {0.99, 0.05, 0}, // similar vector but no shared text
+		}
+		groups := FindGroups([]*db.Quote{a, b, c, d, e}, embeddings, Options{})
+		require.Len(t, groups, 2)
+		assert.Equal(t, int64(3), groups[1].Keeper.ID)
+		assert.Equal(t, []*db.Quote{d}, groups[1].Duplicates)
+	})
+
+	t.Run("keeper preference", func(t *testing.T) {
+		verified := quote(9, b.Text)
+		verified.VerbatimEdits = sql.NullInt64{Int64: 0, Valid: true}
+		groups := FindGroups([]*db.Quote{a, verified}, nil, Options{})
+		require.Len(t, groups, 1)
+		assert.Equal(t, int64(9), groups[0].Keeper.ID)
+
+		rejected := quote(1, a.Text)
+		rejected.QualityVerdict = sql.NullString{String: db.VerdictReject, Valid: true}
+		posted := quote(2, b.Text)
+		posted.TimesPosted = sql.NullInt64{Int64: 1, Valid: true}
+		groups = FindGroups([]*db.Quote{rejected, posted}, nil, Options{})
+		require.Len(t, groups, 1)
+		assert.Equal(t, int64(2), groups[0].Keeper.ID)
+
+		// An editor's decisions outrank verification and post history
+		approved := quote(1, a.Text)
+		approved.CurationStatus = sql.NullString{String: db.CurationApproved, Valid: true}
+		groups = FindGroups([]*db.Quote{approved, verified, posted}, nil, Options{})
+		require.Len(t, groups, 1)
+		assert.Equal(t, int64(1), groups[0].Keeper.ID)
+
+		favorite := quote(3, a.Text)
+		favorite.Favorite = true
+		edited := quote(4, b.Text)
+		edited.CuratedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		groups = FindGroups([]*db.Quote{verified, edited, favorite}, nil, Options{})
+		require.Len(t, groups, 1)
+		assert.Equal(t, []int64{3, 4, 9}, []int64{groups[0].Keeper.ID, groups[0].Duplicates[0].ID, groups[0].Duplicates[1].ID})
+
+		discarded := quote(5, a.Text)
+		discarded.CurationStatus = sql.NullString{String: db.CurationRejected, Valid: true}
+		discarded.Favorite = true
+		groups = FindGroups([]*db.Quote{discarded, quote(6, b.Text)}, nil, Options{})
+		require.Len(t, groups, 1)
+		assert.Equal(t, int64(6), groups[0].Keeper.ID)
+	})
+}
+
+func TestMerge(t *testing.T) {
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	require.NoError(t, store.Migrate(ctx))
+
+	create := func(text, hash string) *db.Quote {
+		q, err := store.CreateQuote(ctx, db.CreateQuoteParams{
+			Text:       text,
+			TextHash:   hash,
+			SourceBook: "Crime and Punishment",
+			Themes:     "[]",
+			CharCount:  int64(len(text)),
+		})
+		require.NoError(t, err)
+		return q
+	}
+	post := func(q *db.Quote, hash string) {
+		_, err := store.CreatePost(ctx, db.CreatePostParams{
+			QuoteID:     q.ID,
+			Platform:    "bluesky",
+			TrendTitle:  "trend",
+			TrendSource: "hackernews",
+			TrendHash:   hash,
+		})
+		require.NoError(t, err)
+		require.NoError(t, store.UpdateQuotePosted(ctx, q.ID))
+	}
+
+	keeper := create(passage, "a")
+	dup := create("Pain and suffering are always inevitable for a large intelligence and a deep heart.", "b")
+	post(keeper, "t1")
+	post(dup, "t2")
+	post(dup, "t3")
+
+	original := create("Страдание и боль всегда обязательны для широкого сознания и глубокого сердца.", "c")
+	require.NoError(t, store.CreateQuoteTranslation(ctx, db.CreateQuoteTranslationParams{
+		OriginalID:    original.ID,
+		TranslationID: dup.ID,
+	}))
+
+	group, err := store.CreateQuoteGroup(ctx)
+	require.NoError(t, err)
+	groupID := sql.NullInt64{Int64: group.ID, Valid: true}
+	require.NoError(t, store.SetQuoteGroup(ctx, db.SetQuoteGroupParams{GroupID: groupID, ID: dup.ID}))
+	require.NoError(t, store.SetQuoteGroup(ctx, db.SetQuoteGroupParams{GroupID: groupID, ID: original.ID}))
+
+	taxonomy, err := themes.Load(ctx, store.Queries)
+	require.NoError(t, err)
+	_, err = taxonomy.Tag(ctx, store.Queries, keeper.ID, []string{"suffering"})
+	require.NoError(t, err)
+	_, err = taxonomy.Tag(ctx, store.Queries, dup.ID, []string{"suffering", "faith"})
+	require.NoError(t, err)
+
+	queued, err := store.CreatePendingPost(ctx, db.CreatePendingPostParams{
+		QuoteID:     dup.ID,
+		TrendTitle:  "trend",
+		TrendSource: "hackernews",
+		TrendHash:   "t4",
+		Text:        dup.Text,
+		Langs:       "[]",
+		Status:      "approved",
+		ExpiresIn:   "+1 hours",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, store.SetQuoteCuration(ctx, db.SetQuoteCurationParams{
+		CurationStatus: sql.NullString{String: db.CurationApproved, Valid: true},
+		ID:             dup.ID,
+	}))
+	require.NoError(t, store.SetQuoteFavorite(ctx, db.SetQuoteFavoriteParams{Favorite: true, ID: dup.ID}))
+
+	keeper, err = store.GetQuote(ctx, keeper.ID)
+	require.NoError(t, err)
+	dup, err = store.GetQuote(ctx, dup.ID)
+	require.NoError(t, err)
+	dup.LastPostedAt = sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true}
+
+	require.NoError(t, Merge(ctx, store, Group{Keeper: keeper, Duplicates: []*db.Quote{dup}}))
+
+	_, err = store.GetQuote(ctx, dup.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	merged, err := store.GetQuote(ctx, keeper.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), merged.TimesPosted.Int64)
+	assert.WithinDuration(t, dup.LastPostedAt.Time, merged.LastPostedAt.Time, time.Second)
+
+	posts, err := store.ListPosts(ctx, db.ListPostsParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, posts, 3)
+	for _, p := range posts {
+		assert.Equal(t, keeper.ID, p.QuoteID)
+	}
+
+	translated, err := store.ListQuoteTranslations(ctx, original.ID)
+	require.NoError(t, err)
+	require.Len(t, translated, 1, "translation links move to the keeper")
+	assert.Equal(t, keeper.ID, translated[0].ID)
+
+	assert.True(t, merged.Approved(), "the keeper takes the duplicate's curation")
+	assert.True(t, merged.Favorite)
+
+	pending, err := store.GetPendingPost(ctx, queued.ID)
+	require.NoError(t, err, "queued matches survive the merge")
+	assert.Equal(t, keeper.ID, pending.QuoteID)
+
+	assert.ElementsMatch(t, []string{"suffering", "faith"}, themes.Parse(merged))
+	for _, name := range []string{"suffering", "faith"} {
+		theme, err := store.GetThemeByName(ctx, name)
+		require.NoError(t, err)
+		tagged, err := store.ListThemeQuotes(ctx, theme.ID)
+		require.NoError(t, err)
+		require.Len(t, tagged, 1, name)
+		assert.Equal(t, keeper.ID, tagged[0].ID, name)
+	}
+
+	assert.Equal(t, groupID, merged.GroupID, "the keeper joins the duplicate's variant group")
+	members, err := store.ListGroupQuotes(ctx, groupID)
+	require.NoError(t, err)
+	assert.Len(t, members, 2)
+}
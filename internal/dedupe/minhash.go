@@ -0,0 +1,234 @@
+// Package dedupe finds quotes that are the same passage extracted more than
+// once, typically from the overlap between two chunks, with different
+// punctuation or trimmed at different points.
+//
+// Quotes are compared by MinHash signatures of their word shingles. Because
+// one duplicate is often a trimmed version of the other, similarity is
+// measured as containment (the share of the shorter quote's shingles found
+// in the longer one) rather than plain Jaccard similarity.
+package dedupe
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	numHashes   = 128
+	numBands    = 32 // of numHashes/numBands rows each
+	shingleSize = 3  // words
+)
+
+// DefaultThreshold is the containment at which two quotes are taken to be
+// the same passage.
+const DefaultThreshold = 0.8
+
+// Signature is the MinHash signature of a text's word shingles.
+type Signature struct {
+	mins     [numHashes]uint64
+	shingles int
+}
+
+// Sign returns the signature of text. Case, punctuation and whitespace are
+// ignored.
+func Sign(text string) Signature {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var sig Signature
+	for i := range sig.mins {
+		sig.mins[i] = ^uint64(0)
+	}
+
+	n := len(words) - shingleSize + 1
+	if n < 1 && len(words) > 0 {
+		n = 1 // shorter than one shingle: the whole text is the shingle
+	}
+	seen := make(map[uint64]bool, n)
+	for i := 0; i < n; i++ {
+		h := fnv.New64a()
+		for _, w := range words[i:min(i+shingleSize, len(words))] {
+			h.Write([]byte(w))
+			h.Write([]byte{0})
+		}
+		base := h.Sum64()
+		if seen[base] {
+			continue
+		}
+		seen[base] = true
+
+		for j := range sig.mins {
+			if v := mix(base ^ seeds[j]); v < sig.mins[j] {
+				sig.mins[j] = v
+			}
+		}
+	}
+	sig.shingles = len(seen)
+
+	return sig
+}
+
+// Jaccard estimates the Jaccard similarity of the two shingle sets.
+func (s Signature) Jaccard(o Signature) float64 {
+	if s.shingles == 0 || o.shingles == 0 {
+		return 0
+	}
+	equal := 0
+	for i := range s.mins {
+		if s.mins[i] == o.mins[i] {
+			equal++
+		}
+	}
+	return float64(equal) / numHashes
+}
+
+// Containment estimates the share of the smaller shingle set contained in
+// the larger one: 1 when one quote is a trimmed copy of the other.
+func (s Signature) Containment(o Signature) float64 {
+	j := s.Jaccard(o)
+	if j == 0 {
+		return 0
+	}
+	// |A∩B| = J·|A∪B| and |A∪B| = |A|+|B|-|A∩B|
+	intersection := j * float64(s.shingles+o.shingles) / (1 + j)
+	return min(1, intersection/float64(min(s.shingles, o.shingles)))
+}
+
+// bandKey identifies one band of a signature for locality-sensitive hashing.
+type bandKey struct {
+	band int
+	hash uint64
+}
+
+func (s Signature) bands() [numBands]bandKey {
+	var keys [numBands]bandKey
+	rows := numHashes / numBands
+	for b := range keys {
+		h := uint64(b)
+		for _, v := range s.mins[b*rows : (b+1)*rows] {
+			h = mix(h ^ v)
+		}
+		keys[b] = bandKey{band: b, hash: h}
+	}
+	return keys
+}
+
+// Index finds near-duplicates of new quotes among the quotes added to it.
+// Quotes that share any band of their signature are candidates; only
+// candidates are compared, so lookups stay fast as the index grows.
+// It is safe for concurrent use. A nil *Index is empty.
+type Index struct {
+	mu      sync.RWMutex
+	ids     []int64
+	sigs    []Signature
+	buckets map[bandKey][]int
+}
+
+// NewIndex creates an empty index.
+func NewIndex() *Index {
+	return &Index{buckets: make(map[bandKey][]int)}
+}
+
+// Add indexes the quote id with signature sig.
+func (x *Index) Add(id int64, sig Signature) {
+	if x == nil {
+		return
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	pos := len(x.ids)
+	x.ids = append(x.ids, id)
+	x.sigs = append(x.sigs, sig)
+	for _, key := range sig.bands() {
+		x.buckets[key] = append(x.buckets[key], pos)
+	}
+}
+
+// Match returns the indexed quote most contained in (or containing) sig,
+// if its containment is at least threshold.
+func (x *Index) Match(sig Signature, threshold float64) (int64, float64, bool) {
+	if x == nil {
+		return 0, 0, false
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	var bestID int64
+	best := 0.0
+	for _, pos := range x.candidates(sig) {
+		if c := sig.Containment(x.sigs[pos]); c > best {
+			bestID, best = x.ids[pos], c
+		}
+	}
+	return bestID, best, best >= threshold
+}
+
+// Overlap is an indexed quote sharing text with a signature.
+type Overlap struct {
+	ID          int64
+	Containment float64
+}
+
+// Overlapping returns the indexed quotes whose containment with sig is at
+// least minContainment, most contained first.
+func (x *Index) Overlapping(sig Signature, minContainment float64) []Overlap {
+	if x == nil {
+		return nil
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	var out []Overlap
+	for _, pos := range x.candidates(sig) {
+		if c := sig.Containment(x.sigs[pos]); c >= minContainment {
+			out = append(out, Overlap{ID: x.ids[pos], Containment: c})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Containment > out[j].Containment })
+	return out
+}
+
+// candidates returns the positions of indexed quotes sharing a band with sig.
+// The caller must hold x.mu.
+func (x *Index) candidates(sig Signature) []int {
+	seen := make(map[int]bool)
+	var out []int
+	for _, key := range sig.bands() {
+		for _, pos := range x.buckets[key] {
+			if !seen[pos] {
+				seen[pos] = true
+				out = append(out, pos)
+			}
+		}
+	}
+	return out
+}
+
+// seeds are the per-hash-function seeds, fixed so signatures are stable.
+var seeds = func() [numHashes]uint64 {
+	var s [numHashes]uint64
+	x := uint64(0x9E3779B97F4A7C15)
+	for i := range s {
+		x += 0x9E3779B97F4A7C15
+		s[i] = mix(x)
+	}
+	return s
+}()
+
+// mix is the SplitMix64 finalizer, a fast well-distributed 64-bit hash.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xBF58476D1CE4E5B9
+	x ^= x >> 27
+	x *= 0x94D049BB133111EB
+	x ^= x >> 31
+	return x
+}
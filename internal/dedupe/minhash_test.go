@@ -0,0 +1,67 @@
+package dedupe
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const passage = "Pain and suffering are always inevitable for a large intelligence and a deep heart. " +
+	"The really great men must, I think, have great sadness on earth."
+
+func TestSignature_Containment(t *testing.T) {
+	full := Sign(passage)
+
+	tests := []struct {
+		name string
+		text string
+		min  float64
+		max  float64
+	}{
+		{"identical", passage, 1, 1},
+		{"punctuation and case", "PAIN and suffering are always inevitable, for a large intelligence and a deep heart; " +
+			"the really great men must -- I think -- have great sadness on earth", 1, 1},
+		{"trimmed", "Pain and suffering are always inevitable for a large intelligence and a deep heart.", 0.8, 1},
+		{"unrelated", "Man is fond of counting his troubles, but he does not count his joys.", 0, 0.1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := full.Containment(Sign(tt.text))
+			assert.GreaterOrEqual(t, c, tt.min)
+			assert.LessOrEqual(t, c, tt.max)
+			assert.InDelta(t, c, Sign(tt.text).Containment(full), 1e-9, "containment is symmetric")
+		})
+	}
+}
+
+func TestSignature_Empty(t *testing.T) {
+	assert.Zero(t, Sign("").Containment(Sign(passage)))
+	assert.Zero(t, Sign("...").Containment(Sign("...")))
+	assert.Equal(t, 1.0, Sign("Yes.").Containment(Sign("yes")))
+}
+
+func TestIndex_Match(t *testing.T) {
+	index := NewIndex()
+	for i := 0; i < 50; i++ {
+		index.Add(int64(100+i), Sign(fmt.Sprintf("filler quote number %d about nothing in particular at all", i)))
+	}
+	index.Add(7, Sign(passage))
+
+	id, containment, ok := index.Match(Sign("Pain and suffering are always inevitable for a large intelligence and a deep heart"), DefaultThreshold)
+	assert.True(t, ok)
+	assert.Equal(t, int64(7), id)
+	assert.GreaterOrEqual(t, containment, DefaultThreshold)
+
+	_, _, ok = index.Match(Sign("Man is fond of counting his troubles, but he does not count his joys."), DefaultThreshold)
+	assert.False(t, ok)
+}
+
+func TestIndex_Nil(t *testing.T) {
+	var index *Index
+	index.Add(1, Sign(passage))
+
+	_, _, ok := index.Match(Sign(passage), DefaultThreshold)
+	assert.False(t, ok)
+}
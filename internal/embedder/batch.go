@@ -11,14 +11,14 @@ import (
 
 // BatchEmbedder handles batch embedding operations.
 type BatchEmbedder struct {
-	embedder  *Embedder
+	embedder  Provider
 	store     *db.Store
 	batchSize int
 }
 
 // BatchConfig holds configuration for batch embedding.
 type BatchConfig struct {
-	Embedder  *Embedder
+	Embedder  Provider
 	Store     *db.Store
 	BatchSize int
 }
@@ -39,9 +39,11 @@ func NewBatchEmbedder(cfg BatchConfig) *BatchEmbedder {
 
 // EmbedAllQuotes generates embeddings for all quotes without embeddings.
 func (b *BatchEmbedder) EmbedAllQuotes(ctx context.Context) error {
-	// First, ping Ollama to make sure it's available
-	if err := b.embedder.Ping(ctx); err != nil {
-		return fmt.Errorf("ollama not available: %w", err)
+	// First, ping the provider (if it supports it) to make sure it's available
+	if p, ok := b.embedder.(interface{ Ping(context.Context) error }); ok {
+		if err := p.Ping(ctx); err != nil {
+			return fmt.Errorf("%s not available: %w", b.embedder.Name(), err)
+		}
 	}
 
 	// Get quotes without embeddings
@@ -77,21 +79,27 @@ func (b *BatchEmbedder) EmbedAllQuotes(ctx context.Context) error {
 			"quotes", len(batch),
 		)
 
-		for _, quote := range batch {
-			embedding, err := b.embedder.Embed(ctx, quote.Text)
-			if err != nil {
-				slog.Error("failed to embed quote",
-					"quote_id", quote.ID,
-					"error", err,
-				)
-				continue
-			}
+		texts := make([]string, len(batch))
+		for j, quote := range batch {
+			texts[j] = quote.Text
+		}
+
+		embeddings, err := b.embedder.EmbedBatch(ctx, texts)
+		if err != nil {
+			slog.Error("failed to embed batch", "quotes", len(batch), "error", err)
+			continue
+		}
+
+		for j, quote := range batch {
+			embedding := embeddings[j]
 
 			// Convert to bytes and store
 			data := EmbeddingToBytes(embedding)
 			if err := b.store.UpdateQuoteEmbedding(ctx, db.UpdateQuoteEmbeddingParams{
-				ID:        quote.ID,
-				Embedding: data,
+				ID:             quote.ID,
+				Embedding:      data,
+				EmbeddingModel: nullString(b.embedder.Model()),
+				EmbeddingDim:   sql.NullInt64{Int64: int64(len(embedding)), Valid: true},
 			}); err != nil {
 				slog.Error("failed to store embedding",
 					"quote_id", quote.ID,
@@ -124,8 +132,10 @@ func (b *BatchEmbedder) EmbedTrend(ctx context.Context, trend *db.Trend) ([]floa
 	// Store embedding
 	data := EmbeddingToBytes(embedding)
 	if err := b.store.UpdateTrendEmbedding(ctx, db.UpdateTrendEmbeddingParams{
-		ID:        trend.ID,
-		Embedding: data,
+		ID:             trend.ID,
+		Embedding:      data,
+		EmbeddingModel: nullString(b.embedder.Model()),
+		EmbeddingDim:   sql.NullInt64{Int64: int64(len(embedding)), Valid: true},
 	}); err != nil {
 		return nil, fmt.Errorf("store trend embedding: %w", err)
 	}
@@ -145,14 +155,25 @@ type QuoteWithEmbedding struct {
 }
 
 // LoadAllEmbeddings loads all quotes with their embeddings into memory.
+// Embeddings produced by a different model than the current provider are
+// skipped, since their similarities would be meaningless, and quotes
+// rejected by the quality review are never loaded.
 func (b *BatchEmbedder) LoadAllEmbeddings(ctx context.Context) ([]QuoteWithEmbedding, error) {
 	quotes, err := b.store.ListQuotesWithEmbeddings(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("list quotes: %w", err)
 	}
 
+	model, dim := b.embedder.Model(), b.embedder.Dimension()
+	mismatched := 0
+
 	result := make([]QuoteWithEmbedding, 0, len(quotes))
 	for _, quote := range quotes {
+		if quote.EmbeddingModel.Valid && quote.EmbeddingModel.String != model {
+			mismatched++
+			continue
+		}
+
 		embedding, err := BytesToEmbedding(quote.Embedding)
 		if err != nil {
 			slog.Warn("failed to parse embedding",
@@ -167,20 +188,32 @@ func (b *BatchEmbedder) LoadAllEmbeddings(ctx context.Context) ([]QuoteWithEmbed
 			continue
 		}
 
+		if dim != 0 && len(embedding) != dim {
+			mismatched++
+			continue
+		}
+
 		result = append(result, QuoteWithEmbedding{
 			Quote:     quote,
 			Embedding: embedding,
 		})
 	}
 
+	if mismatched > 0 {
+		slog.Warn("skipped embeddings from a different model",
+			"count", mismatched,
+			"model", model,
+		)
+	}
+
 	slog.Debug("loaded embeddings", "count", len(result))
 	return result, nil
 }
 
 // Stats returns embedding statistics.
 type Stats struct {
-	TotalQuotes       int64
-	QuotesWithEmbed   int64
+	TotalQuotes        int64
+	QuotesWithEmbed    int64
 	QuotesWithoutEmbed int64
 }
 
@@ -197,8 +230,8 @@ func (b *BatchEmbedder) GetStats(ctx context.Context) (*Stats, error) {
 	}
 
 	return &Stats{
-		TotalQuotes:       total,
-		QuotesWithEmbed:   withEmbed,
+		TotalQuotes:        total,
+		QuotesWithEmbed:    withEmbed,
 		QuotesWithoutEmbed: total - withEmbed,
 	}, nil
 }
@@ -0,0 +1,124 @@
+package embedder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// CachedProvider wraps a Provider with an on-disk cache keyed by content hash.
+//
+// Keys include the provider name and model, and cached vectors of the wrong
+// dimension are ignored, so switching models never returns stale vectors.
+// Cache failures are logged and otherwise ignored; the cache is an
+// optimisation, not a source of truth.
+type CachedProvider struct {
+	Provider
+	dir string
+}
+
+// NewCachedProvider wraps p with a cache stored under dir.
+func NewCachedProvider(p Provider, dir string) *CachedProvider {
+	return &CachedProvider{Provider: p, dir: dir}
+}
+
+// Embed returns the cached embedding for text, computing it on a miss.
+func (c *CachedProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	vectors, err := c.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+// EmbedBatch serves hits from disk and sends only the misses to the provider in one batch.
+func (c *CachedProvider) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+
+	var missTexts []string
+	var missIdx []int
+	for i, text := range texts {
+		if v, ok := c.load(text); ok {
+			out[i] = v
+			continue
+		}
+		missTexts = append(missTexts, text)
+		missIdx = append(missIdx, i)
+	}
+
+	if len(missTexts) == 0 {
+		return out, nil
+	}
+
+	vectors, err := c.Provider.EmbedBatch(ctx, missTexts)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(missTexts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(missTexts), len(vectors))
+	}
+
+	for j, v := range vectors {
+		out[missIdx[j]] = v
+		if err := c.store(missTexts[j], v); err != nil {
+			slog.Debug("failed to write embedding cache", "error", err)
+		}
+	}
+
+	return out, nil
+}
+
+// key returns the cache key for text under the current provider and model.
+func (c *CachedProvider) key(text string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", c.Name(), c.Model())
+	h.Write([]byte(text))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// path shards cache files into 256 subdirectories by key prefix.
+func (c *CachedProvider) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".bin")
+}
+
+func (c *CachedProvider) load(text string) ([]float32, bool) {
+	data, err := os.ReadFile(c.path(c.key(text)))
+	if err != nil {
+		return nil, false
+	}
+	v, err := BytesToEmbedding(data)
+	if err != nil || len(v) == 0 {
+		return nil, false
+	}
+	if dim := c.Dimension(); dim != 0 && len(v) != dim {
+		return nil, false
+	}
+	return v, true
+}
+
+// store writes atomically via a temp file so concurrent readers never see a partial vector.
+func (c *CachedProvider) store(text string, v []float32) error {
+	path := c.path(c.key(text))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(EmbeddingToBytes(v)); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
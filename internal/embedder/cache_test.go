@@ -0,0 +1,65 @@
+package embedder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingProvider is a deterministic Provider that records how many texts it embedded.
+type countingProvider struct {
+	model    string
+	embedded []string
+}
+
+func (p *countingProvider) Name() string   { return "fake" }
+func (p *countingProvider) Model() string  { return p.model }
+func (p *countingProvider) Dimension() int { return 2 }
+
+func (p *countingProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	v, err := p.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return v[0], nil
+}
+
+func (p *countingProvider) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	p.embedded = append(p.embedded, texts...)
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		out[i] = []float32{float32(len(t)), 1}
+	}
+	return out, nil
+}
+
+func TestCachedProvider(t *testing.T) {
+	t.Run("only misses reach the provider", func(t *testing.T) {
+		inner := &countingProvider{model: "m1"}
+		c := NewCachedProvider(inner, t.TempDir())
+
+		_, err := c.EmbedBatch(context.Background(), []string{"a", "bb"})
+		require.NoError(t, err)
+
+		got, err := c.EmbedBatch(context.Background(), []string{"bb", "ccc", "a"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"a", "bb", "ccc"}, inner.embedded)
+		assert.Equal(t, [][]float32{{2, 1}, {3, 1}, {1, 1}}, got)
+	})
+
+	t.Run("model is part of the key", func(t *testing.T) {
+		dir := t.TempDir()
+		first := &countingProvider{model: "m1"}
+		second := &countingProvider{model: "m2"}
+
+		_, err := NewCachedProvider(first, dir).Embed(context.Background(), "a")
+		require.NoError(t, err)
+		_, err = NewCachedProvider(second, dir).Embed(context.Background(), "a")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"a"}, second.embedded)
+	})
+}
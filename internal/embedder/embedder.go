@@ -10,19 +10,32 @@ import (
 	"log/slog"
 	"math"
 	"net/http"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
 const (
-	defaultModel    = "nomic-embed-text"
-	embeddingDim    = 768
+	defaultModel     = "nomic-embed-text"
+	embeddingDim     = 768
 	defaultBatchSize = 10
 )
 
-// Embedder generates embeddings using Ollama.
+// ollamaDimensions lists output sizes of common Ollama embedding models so
+// Dimension is known before the first request.
+var ollamaDimensions = map[string]int{
+	"nomic-embed-text":       embeddingDim,
+	"mxbai-embed-large":      1024,
+	"all-minilm":             384,
+	"snowflake-arctic-embed": 1024,
+	"bge-m3":                 1024,
+}
+
+// Embedder generates embeddings using Ollama. It implements Provider.
 type Embedder struct {
 	host       string
 	model      string
+	dimension  atomic.Int64
 	httpClient *http.Client
 }
 
@@ -30,6 +43,13 @@ type Embedder struct {
 type Config struct {
 	Host  string
 	Model string
+
+	// Dimension overrides the vector size; if zero it is looked up for known
+	// models or learned from the first response.
+	Dimension int
+
+	// Timeout for each HTTP request (default: 60s).
+	Timeout time.Duration
 }
 
 // New creates a new Embedder.
@@ -39,13 +59,40 @@ func New(cfg Config) *Embedder {
 		model = defaultModel
 	}
 
-	return &Embedder{
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 60 * time.Second
+	}
+
+	e := &Embedder{
 		host:  cfg.Host,
 		model: model,
 		httpClient: &http.Client{
-			Timeout: 60 * time.Second,
+			Timeout: timeout,
 		},
 	}
+
+	dim := cfg.Dimension
+	if dim == 0 {
+		dim = ollamaDimensions[strings.TrimSuffix(model, ":latest")]
+	}
+	e.dimension.Store(int64(dim))
+
+	return e
+}
+
+// Name returns the provider name.
+func (e *Embedder) Name() string { return "ollama" }
+
+// Model returns the Ollama model used for embeddings.
+func (e *Embedder) Model() string { return e.model }
+
+// Dimension returns the embedding size, or 0 if it is not yet known.
+func (e *Embedder) Dimension() int { return int(e.dimension.Load()) }
+
+// learnDimension records the dimension from a response when it was not known up front.
+func (e *Embedder) learnDimension(n int) {
+	e.dimension.CompareAndSwap(0, int64(n))
 }
 
 // ollamaRequest is the request body for Ollama embedding API.
@@ -108,33 +155,76 @@ func (e *Embedder) Embed(ctx context.Context, text string) ([]float32, error) {
 		embedding[i] = float32(v)
 	}
 
+	e.learnDimension(len(embedding))
+	if err := checkDimensions([][]float32{embedding}, e.Dimension()); err != nil {
+		return nil, err
+	}
+
 	return embedding, nil
 }
 
-// EmbedBatch generates embeddings for multiple texts.
+// ollamaBatchRequest is the request body for Ollama's batch /api/embed endpoint.
+type ollamaBatchRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// ollamaBatchResponse is the response from Ollama's batch /api/embed endpoint.
+type ollamaBatchResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+// EmbedBatch generates embeddings for multiple texts in a single request.
 func (e *Embedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
-	embeddings := make([][]float32, len(texts))
+	if len(texts) == 0 {
+		return nil, nil
+	}
 
-	for i, text := range texts {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
+	body, err := json.Marshal(ollamaBatchRequest{
+		Model: e.model,
+		Input: texts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
 
-		embedding, err := e.Embed(ctx, text)
-		if err != nil {
-			return nil, fmt.Errorf("embed text %d: %w", i, err)
-		}
-		embeddings[i] = embedding
+	url := fmt.Sprintf("%s/api/embed", e.host)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
 
-		// Small delay to avoid overwhelming Ollama
-		if i < len(texts)-1 {
-			time.Sleep(100 * time.Millisecond)
-		}
+	resp, err := e.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Ollama error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var batchResp ollamaBatchResponse
+	if err := json.Unmarshal(respBody, &batchResp); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	if len(batchResp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(batchResp.Embeddings))
+	}
+
+	e.learnDimension(len(batchResp.Embeddings[0]))
+	if err := checkDimensions(batchResp.Embeddings, e.Dimension()); err != nil {
+		return nil, err
 	}
 
-	return embeddings, nil
+	return batchResp.Embeddings, nil
 }
 
 // Ping checks if Ollama is available and has the required model.
@@ -83,6 +83,61 @@ func TestEmbedder_Embed(t *testing.T) {
 	})
 }
 
+func TestEmbedder_EmbedBatch(t *testing.T) {
+	t.Run("sends all texts in one request", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			assert.Equal(t, "/api/embed", r.URL.Path)
+
+			var req ollamaBatchRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			assert.Equal(t, []string{"a", "b", "c"}, req.Input)
+
+			resp := ollamaBatchResponse{}
+			for range req.Input {
+				resp.Embeddings = append(resp.Embeddings, make([]float32, 768))
+			}
+			json.NewEncoder(w).Encode(resp)
+		}))
+		defer server.Close()
+
+		e := New(Config{Host: server.URL})
+		embeddings, err := e.EmbedBatch(context.Background(), []string{"a", "b", "c"})
+
+		require.NoError(t, err)
+		assert.Len(t, embeddings, 3)
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("rejects unexpected dimension", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(ollamaBatchResponse{Embeddings: [][]float32{{1, 2, 3}}})
+		}))
+		defer server.Close()
+
+		e := New(Config{Host: server.URL})
+		_, err := e.EmbedBatch(context.Background(), []string{"a"})
+
+		assert.ErrorIs(t, err, ErrDimensionMismatch)
+	})
+
+	t.Run("learns dimension of unknown models", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(ollamaBatchResponse{Embeddings: [][]float32{{1, 2, 3}}})
+		}))
+		defer server.Close()
+
+		e := New(Config{Host: server.URL, Model: "custom-model"})
+		assert.Equal(t, 0, e.Dimension())
+
+		_, err := e.EmbedBatch(context.Background(), []string{"a"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, e.Dimension())
+	})
+}
+
 func TestEmbedder_Ping(t *testing.T) {
 	t.Run("model found", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,197 @@
+package embedder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+)
+
+const (
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+	defaultOpenAIModel   = "text-embedding-3-small"
+
+	// openAIMaxBatch is the most inputs sent in a single /embeddings request.
+	openAIMaxBatch = 256
+)
+
+// openAIDimensions lists the native output sizes of OpenAI embedding models.
+var openAIDimensions = map[string]int{
+	"text-embedding-3-small": 1536,
+	"text-embedding-3-large": 3072,
+	"text-embedding-ada-002": 1536,
+}
+
+// OpenAIEmbedder generates embeddings using the OpenAI embeddings API
+// (or any compatible endpoint). It implements Provider.
+type OpenAIEmbedder struct {
+	apiKey     string
+	baseURL    string
+	model      string
+	dimension  int
+	httpClient *http.Client
+}
+
+// OpenAIConfig holds configuration for the OpenAI embedder.
+type OpenAIConfig struct {
+	APIKey  string
+	BaseURL string // default: https://api.openai.com/v1
+	Model   string // default: text-embedding-3-small
+
+	// Dimension requests shortened vectors from text-embedding-3 models.
+	// Zero means the model's native size.
+	Dimension int
+
+	// Timeout for each HTTP request (default: 60s).
+	Timeout time.Duration
+}
+
+// NewOpenAI creates a new OpenAIEmbedder.
+func NewOpenAI(cfg OpenAIConfig) *OpenAIEmbedder {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = defaultOpenAIModel
+	}
+
+	dim := cfg.Dimension
+	if dim == 0 {
+		dim = openAIDimensions[model]
+	}
+
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 60 * time.Second
+	}
+
+	return &OpenAIEmbedder{
+		apiKey:    cfg.APIKey,
+		baseURL:   baseURL,
+		model:     model,
+		dimension: dim,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Name returns the provider name.
+func (e *OpenAIEmbedder) Name() string { return "openai" }
+
+// Model returns the OpenAI model used for embeddings.
+func (e *OpenAIEmbedder) Model() string { return e.model }
+
+// Dimension returns the embedding size.
+func (e *OpenAIEmbedder) Dimension() int { return e.dimension }
+
+// openAIRequest is the request body for the embeddings API.
+type openAIRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
+}
+
+// openAIResponse is the response from the embeddings API.
+type openAIResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed generates an embedding for the given text.
+func (e *OpenAIEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	vectors, err := e.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+// EmbedBatch generates embeddings for multiple texts, splitting them into
+// requests of at most openAIMaxBatch inputs.
+func (e *OpenAIEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += openAIMaxBatch {
+		end := min(start+openAIMaxBatch, len(texts))
+		vectors, err := e.embedRequest(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, vectors...)
+	}
+	return out, nil
+}
+
+func (e *OpenAIEmbedder) embedRequest(ctx context.Context, texts []string) ([][]float32, error) {
+	req := openAIRequest{
+		Model: e.model,
+		Input: texts,
+	}
+	// Only ask for a specific size when shortening a known model; older models
+	// and other compatible servers reject the parameter.
+	if native, ok := openAIDimensions[e.model]; ok && native != e.dimension {
+		req.Dimensions = e.dimension
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/embeddings", e.baseURL)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OpenAI error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var apiResp openAIResponse
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	if len(apiResp.Data) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(apiResp.Data))
+	}
+
+	// The API does not guarantee order; index ties each vector to its input.
+	sort.Slice(apiResp.Data, func(i, j int) bool { return apiResp.Data[i].Index < apiResp.Data[j].Index })
+
+	vectors := make([][]float32, len(apiResp.Data))
+	for i, d := range apiResp.Data {
+		vectors[i] = d.Embedding
+	}
+
+	if err := checkDimensions(vectors, e.dimension); err != nil {
+		return nil, err
+	}
+
+	return vectors, nil
+}
@@ -0,0 +1,58 @@
+package embedder
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIEmbedder_EmbedBatch(t *testing.T) {
+	t.Run("restores input order", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/embeddings", r.URL.Path)
+			assert.Equal(t, "Bearer sk-test", r.Header.Get("Authorization"))
+
+			var req openAIRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			assert.Equal(t, 0, req.Dimensions, "size should only be requested when shortening a known model")
+
+			// Reply out of order; index identifies each input.
+			w.Write([]byte(`{"data":[
+				{"index":1,"embedding":[0,1]},
+				{"index":0,"embedding":[1,0]}
+			]}`))
+		}))
+		defer server.Close()
+
+		e := NewOpenAI(OpenAIConfig{APIKey: "sk-test", BaseURL: server.URL, Model: "tiny", Dimension: 2})
+		embeddings, err := e.EmbedBatch(context.Background(), []string{"first", "second"})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]float32{{1, 0}, {0, 1}}, embeddings)
+	})
+
+	t.Run("uses native dimension for known models", func(t *testing.T) {
+		e := NewOpenAI(OpenAIConfig{})
+		assert.Equal(t, defaultOpenAIModel, e.Model())
+		assert.Equal(t, 1536, e.Dimension())
+	})
+
+	t.Run("handles error response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("bad key"))
+		}))
+		defer server.Close()
+
+		e := NewOpenAI(OpenAIConfig{BaseURL: server.URL})
+		_, err := e.Embed(context.Background(), "text")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "status 401")
+	})
+}
@@ -0,0 +1,118 @@
+package embedder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/abdul-hamid-achik/veclite"
+)
+
+// Provider generates embeddings from a single, fixed model.
+//
+// Every vector written to SQLite or VecLite is tagged with the provider's
+// Model and Dimension so vectors from different models are never compared.
+type Provider interface {
+	// Name identifies the backend (e.g. "ollama", "openai", "onnx").
+	Name() string
+
+	// Model is the embedding model name as reported to the backend.
+	Model() string
+
+	// Dimension is the length of the vectors the model produces.
+	Dimension() int
+
+	// Embed generates an embedding for a single text.
+	Embed(ctx context.Context, text string) ([]float32, error)
+
+	// EmbedBatch generates embeddings for several texts, preferably in one request.
+	EmbedBatch(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// ErrDimensionMismatch is returned when a backend returns vectors of an unexpected length.
+var ErrDimensionMismatch = errors.New("embedding dimension mismatch")
+
+// ProviderConfig selects and configures the embedding provider.
+type ProviderConfig struct {
+	// ConfigPath is the path to veclite.yaml (optional). If empty, the usual
+	// search path is used (./veclite.yaml, ~/.veclite/config.yaml).
+	ConfigPath string
+
+	// Provider overrides the provider named in veclite.yaml ("ollama",
+	// "openai" or "onnx"). Empty means use the file's setting.
+	Provider string
+
+	// CacheDir enables the on-disk embedding cache when non-empty.
+	CacheDir string
+}
+
+// LoadProvider builds the embedding provider described by veclite.yaml.
+// It is the single source of truth for both the VecLite index and the
+// legacy SQLite embeddings, so the two can never disagree on model.
+func LoadProvider(cfg ProviderConfig) (Provider, error) {
+	vcfg, err := veclite.LoadConfig(cfg.ConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("load veclite config: %w", err)
+	}
+
+	ec := vcfg.Embedder
+	if cfg.Provider != "" {
+		ec.Provider = cfg.Provider
+	}
+
+	var p Provider
+	switch ec.Provider {
+	case "ollama":
+		p = New(Config{
+			Host:    ec.Ollama.BaseURL,
+			Model:   ec.Ollama.Model,
+			Timeout: veclite.ParseDuration(ec.Ollama.Timeout, 60*time.Second),
+		})
+	case "openai":
+		p = NewOpenAI(OpenAIConfig{
+			APIKey:    ec.OpenAI.APIKey,
+			BaseURL:   ec.OpenAI.BaseURL,
+			Model:     ec.OpenAI.Model,
+			Dimension: ec.OpenAI.Dimension,
+			Timeout:   veclite.ParseDuration(ec.OpenAI.Timeout, 60*time.Second),
+		})
+	case "onnx":
+		// ONNX runs in-process and is only available in builds with -tags onnx.
+		e, err := veclite.NewEmbedderFromConfig(ec)
+		if err != nil {
+			return nil, fmt.Errorf("create onnx embedder: %w", err)
+		}
+		p = FromVecLite("onnx", ec.ONNX.Model, e)
+	case "":
+		return nil, fmt.Errorf("no embedding provider configured")
+	default:
+		return nil, fmt.Errorf("unknown embedding provider: %s", ec.Provider)
+	}
+
+	slog.Info("embedding provider loaded",
+		"provider", p.Name(),
+		"model", p.Model(),
+		"dimension", p.Dimension(),
+	)
+
+	if cfg.CacheDir != "" {
+		p = NewCachedProvider(p, cfg.CacheDir)
+	}
+
+	return p, nil
+}
+
+// checkDimensions verifies that every vector has the expected length.
+func checkDimensions(vectors [][]float32, want int) error {
+	if want == 0 {
+		return nil
+	}
+	for i, v := range vectors {
+		if len(v) != want {
+			return fmt.Errorf("%w: vector %d has %d dimensions, expected %d", ErrDimensionMismatch, i, len(v), want)
+		}
+	}
+	return nil
+}
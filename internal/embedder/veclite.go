@@ -0,0 +1,60 @@
+package embedder
+
+import (
+	"context"
+
+	"github.com/abdul-hamid-achik/veclite"
+)
+
+// vecliteProvider adapts a veclite.Embedder (e.g. the in-process ONNX model) to Provider.
+type vecliteProvider struct {
+	name  string
+	model string
+	e     veclite.Embedder
+}
+
+// FromVecLite wraps a veclite.Embedder so it can be used as a Provider.
+func FromVecLite(name, model string, e veclite.Embedder) Provider {
+	return &vecliteProvider{name: name, model: model, e: e}
+}
+
+func (p *vecliteProvider) Name() string   { return p.name }
+func (p *vecliteProvider) Model() string  { return p.model }
+func (p *vecliteProvider) Dimension() int { return p.e.Dimension() }
+
+func (p *vecliteProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.e.Embed(text)
+}
+
+func (p *vecliteProvider) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.e.EmbedBatch(texts)
+}
+
+// vecliteAdapter exposes a Provider through the context-free veclite.Embedder
+// interface so VecLite collections embed with the same model as everything else.
+type vecliteAdapter struct {
+	p Provider
+}
+
+// AsVecLite returns a veclite.Embedder backed by p.
+func AsVecLite(p Provider) veclite.Embedder {
+	return &vecliteAdapter{p: p}
+}
+
+func (a *vecliteAdapter) Embed(text string) ([]float32, error) {
+	return a.p.Embed(context.Background(), text)
+}
+
+func (a *vecliteAdapter) EmbedBatch(texts []string) ([][]float32, error) {
+	return a.p.EmbedBatch(context.Background(), texts)
+}
+
+func (a *vecliteAdapter) Dimension() int {
+	return a.p.Dimension()
+}
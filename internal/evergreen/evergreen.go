@@ -0,0 +1,193 @@
+// Package evergreen picks quotes to post without a trend, for post slots
+// the match queue has nothing for. Quotes are tied to the day's calendar
+// occasions when it has any, then to a rotation of themes.
+package evergreen
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/themes"
+)
+
+// Source is the trend source recorded for evergreen posts, so they can be
+// told apart from posts about a trend.
+const Source = "evergreen"
+
+// candidates is how many of the least recently posted quotes Pick looks
+// through for an eligible one.
+const candidates = 500
+
+// Choice is a quote picked for an evergreen post, with what it was picked
+// for: an occasion's name, a theme of the rotation, or empty.
+type Choice struct {
+	Quote *db.Quote
+	Title string
+}
+
+// filter narrows the quotes Pick looks through. Empty fields match any.
+type filter struct {
+	title  string
+	author string
+	book   string
+	theme  string
+}
+
+// Pick returns the quote posted longest ago, or never, preferring favorites
+// and quotes the quality review scored highly, among those eligible
+// accepts. It first tries the quotes of each of the occasions of day, most
+// specific first, then those of the day's theme in rotation, then any
+// quote. Themes are matched by their canonical name. It returns nil if no
+// quote is eligible.
+func Pick(ctx context.Context, q *db.Queries, day time.Time, rotation []string, eligible func(*db.Quote) bool) (*Choice, error) {
+	occasions, err := q.ListEvergreenOccasions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list evergreen occasions: %w", err)
+	}
+	taxonomy, err := themes.Load(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	var filters []filter
+	for _, o := range Today(occasions, day) {
+		filters = append(filters, filter{
+			title:  o.Name,
+			author: o.Author.String,
+			book:   o.SourceBook.String,
+			theme:  o.Theme.String,
+		})
+	}
+	if theme := Theme(rotation, day); theme != "" {
+		filters = append(filters, filter{title: theme, theme: theme})
+	}
+	filters = append(filters, filter{})
+
+	for _, f := range filters {
+		if f.theme != "" {
+			f.theme, _ = taxonomy.Canonical(f.theme)
+		}
+		quote, err := pick(ctx, q, f, eligible)
+		if err != nil {
+			return nil, err
+		}
+		if quote != nil {
+			return &Choice{Quote: quote, Title: f.title}, nil
+		}
+	}
+	return nil, nil
+}
+
+func pick(ctx context.Context, q *db.Queries, f filter, eligible func(*db.Quote) bool) (*db.Quote, error) {
+	params := db.ListEvergreenQuotesParams{
+		Book:   exactOrAny(f.book),
+		Author: exactOrAny(f.author),
+		Theme:  f.theme,
+		Limit:  candidates,
+	}
+	quotes, err := q.ListEvergreenQuotes(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("list evergreen quotes: %w", err)
+	}
+	for _, quote := range quotes {
+		if eligible(quote) {
+			return quote, nil
+		}
+	}
+	return nil, nil
+}
+
+// Today returns the occasions falling on day, in day's location: those
+// with a day of the month first, then those of a single month, of a range
+// of months, and of a day of the week.
+func Today(occasions []*db.EvergreenOccasion, day time.Time) []*db.EvergreenOccasion {
+	var today []*db.EvergreenOccasion
+	for _, o := range occasions {
+		if On(o, day) {
+			today = append(today, o)
+		}
+	}
+	slices.SortStableFunc(today, func(a, b *db.EvergreenOccasion) int {
+		return specificity(b) - specificity(a)
+	})
+	return today
+}
+
+func specificity(o *db.EvergreenOccasion) int {
+	switch {
+	case o.Day.Valid:
+		return 3
+	case o.Month.Valid && (!o.EndMonth.Valid || o.EndMonth.Int64 == o.Month.Int64):
+		return 2
+	case o.Month.Valid:
+		return 1
+	}
+	return 0
+}
+
+// On reports whether occasion o falls on day.
+func On(o *db.EvergreenOccasion, day time.Time) bool {
+	month := int64(day.Month())
+	if o.Month.Valid {
+		first, last := o.Month.Int64, o.Month.Int64
+		if o.EndMonth.Valid {
+			last = o.EndMonth.Int64
+		}
+		if first <= last && (month < first || month > last) {
+			return false
+		}
+		// A range past December, such as 12 to 2
+		if first > last && month < first && month > last {
+			return false
+		}
+	}
+	if o.Day.Valid && o.Day.Int64 != int64(day.Day()) {
+		return false
+	}
+	if o.Weekday.Valid && o.Weekday.Int64 != int64(day.Weekday()) {
+		return false
+	}
+	return true
+}
+
+// Theme returns the theme of the rotation themes for day, moving to the
+// next theme each day, or "" if there are none.
+func Theme(themes []string, day time.Time) string {
+	if len(themes) == 0 {
+		return ""
+	}
+	days := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400
+	return themes[days%int64(len(themes))]
+}
+
+// Record returns the post record of an evergreen post of choice made at
+// postedAt. The trend hash only has to be unique per post.
+func Record(choice *Choice, postedAt time.Time) db.CreatePostParams {
+	return db.CreatePostParams{
+		QuoteID:     choice.Quote.ID,
+		TrendTitle:  choice.Title,
+		TrendSource: Source,
+		TrendHash:   fmt.Sprintf("%s:%d:%d", Source, choice.Quote.ID, postedAt.Unix()),
+	}
+}
+
+// exactOrAny returns a LIKE pattern matching s exactly, or anything if s
+// is empty.
+func exactOrAny(s string) string {
+	if s == "" {
+		return "%"
+	}
+	return escapeLike(s)
+}
+
+// likeEscaper escapes the LIKE wildcards, with the query's escape
+// character, so filters match them literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,177 @@
+package evergreen
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/themes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStore(t *testing.T) *db.Store {
+	t.Helper()
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	require.NoError(t, store.Migrate(ctx))
+	return store
+}
+
+func createQuote(t *testing.T, store *db.Store, text, book, themesJSON string, score int64) *db.Quote {
+	t.Helper()
+	q, err := store.CreateQuote(context.Background(), db.CreateQuoteParams{
+		Text:       text,
+		TextHash:   text,
+		SourceBook: book,
+		Themes:     themesJSON,
+		CharCount:  int64(len(text)),
+		Language:   "en",
+	})
+	require.NoError(t, err)
+	if tags := themes.Parse(q); len(tags) > 0 {
+		taxonomy, err := themes.Load(context.Background(), store.Queries)
+		require.NoError(t, err)
+		_, err = taxonomy.Tag(context.Background(), store.Queries, q.ID, tags)
+		require.NoError(t, err)
+	}
+	if score > 0 {
+		require.NoError(t, store.UpdateQuoteValidation(context.Background(), db.UpdateQuoteValidationParams{
+			QualityScore:   sql.NullInt64{Int64: score, Valid: true},
+			QualityVerdict: sql.NullString{String: db.VerdictApprove, Valid: true},
+			ID:             q.ID,
+		}))
+	}
+	return q
+}
+
+// wednesday is a day no seeded occasion narrows to a book or author.
+var wednesday = time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+func TestPick(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	all := func(*db.Quote) bool { return true }
+
+	got, err := Pick(ctx, store.Queries, wednesday, nil, all)
+	require.NoError(t, err)
+	assert.Nil(t, got, "no quotes")
+
+	posted := createQuote(t, store, "posted", "The Idiot", "[]", 10)
+	require.NoError(t, store.UpdateQuotePosted(ctx, posted.ID))
+	plain := createQuote(t, store, "plain", "The Idiot", "[]", 5)
+	best := createQuote(t, store, "best", "The Idiot", "[]", 9)
+
+	got, err = Pick(ctx, store.Queries, wednesday, nil, all)
+	require.NoError(t, err)
+	assert.Equal(t, best.ID, got.Quote.ID, "never posted, highest quality")
+	assert.Empty(t, got.Title)
+
+	got, err = Pick(ctx, store.Queries, wednesday, nil, func(q *db.Quote) bool { return q.ID != best.ID })
+	require.NoError(t, err)
+	assert.Equal(t, plain.ID, got.Quote.ID)
+
+	require.NoError(t, store.UpdateQuotePosted(ctx, best.ID))
+	require.NoError(t, store.UpdateQuotePosted(ctx, plain.ID))
+	got, err = Pick(ctx, store.Queries, wednesday, nil, func(q *db.Quote) bool { return q.ID != posted.ID })
+	require.NoError(t, err)
+	assert.NotEqual(t, posted.ID, got.Quote.ID)
+}
+
+func TestPick_Occasions(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	all := func(*db.Quote) bool { return true }
+
+	_, err := store.CreateBook(ctx, db.CreateBookParams{Title: "Demons", Author: "Fyodor Dostoyevsky", FilePath: "demons.txt", Language: "en"})
+	require.NoError(t, err)
+	other := createQuote(t, store, "other", "War and Peace", `["hope"]`, 10)
+	demons := createQuote(t, store, "demons", "Demons", `["faith"]`, 3)
+
+	birthday := time.Date(2026, time.November, 11, 9, 0, 0, 0, time.UTC)
+	got, err := Pick(ctx, store.Queries, birthday, nil, all)
+	require.NoError(t, err)
+	assert.Equal(t, demons.ID, got.Quote.ID, "the author's quote on his birthday")
+	assert.Equal(t, "Dostoyevsky's birthday", got.Title)
+
+	got, err = Pick(ctx, store.Queries, wednesday, nil, all)
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, got.Quote.ID)
+	assert.Equal(t, "Spring", got.Title)
+
+	// Without the season, the theme rotation decides
+	n, err := store.DeleteEvergreenOccasion(ctx, "Spring")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	got, err = Pick(ctx, store.Queries, wednesday, []string{"faith"}, all)
+	require.NoError(t, err)
+	assert.Equal(t, demons.ID, got.Quote.ID)
+	assert.Equal(t, "faith", got.Title)
+
+	// A theme without quotes falls back to any quote
+	got, err = Pick(ctx, store.Queries, wednesday, []string{"beauty"}, all)
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, got.Quote.ID)
+	assert.Empty(t, got.Title)
+}
+
+func TestToday(t *testing.T) {
+	month := func(first, last int64) (sql.NullInt64, sql.NullInt64) {
+		return sql.NullInt64{Int64: first, Valid: true}, sql.NullInt64{Int64: last, Valid: last > 0}
+	}
+	winterFirst, winterLast := month(12, 2)
+	novFirst, _ := month(11, 0)
+	occasions := []*db.EvergreenOccasion{
+		{Name: "sunday", Weekday: sql.NullInt64{Int64: 0, Valid: true}},
+		{Name: "winter", Month: winterFirst, EndMonth: winterLast},
+		{Name: "birthday", Month: novFirst, Day: sql.NullInt64{Int64: 11, Valid: true}},
+		{Name: "november", Month: novFirst},
+	}
+	names := func(day time.Time) []string {
+		var got []string
+		for _, o := range Today(occasions, day) {
+			got = append(got, o.Name)
+		}
+		return got
+	}
+
+	assert.Equal(t, []string{"winter", "sunday"}, names(time.Date(2026, time.January, 4, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, []string{"winter"}, names(time.Date(2026, time.December, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Empty(t, names(time.Date(2026, time.March, 4, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, []string{"birthday", "november"}, names(time.Date(2026, time.November, 11, 0, 0, 0, 0, time.UTC)))
+
+	// The day is the one in the time's location
+	late := time.Date(2026, time.November, 10, 23, 0, 0, 0, time.UTC)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"november"}, names(late))
+	assert.Equal(t, []string{"birthday", "november"}, names(late.In(berlin)))
+}
+
+func TestTheme(t *testing.T) {
+	assert.Empty(t, Theme(nil, wednesday))
+
+	themes := []string{"hope", "faith", "suffering"}
+	seen := map[string]bool{}
+	for i := range len(themes) {
+		theme := Theme(themes, wednesday.AddDate(0, 0, i))
+		assert.Equal(t, theme, Theme(themes, wednesday.AddDate(0, 0, i).Add(6*time.Hour)), "one theme all day")
+		seen[theme] = true
+	}
+	assert.Len(t, seen, len(themes), "each theme in turn")
+}
+
+func TestRecord(t *testing.T) {
+	choice := &Choice{Quote: &db.Quote{ID: 7}, Title: "Spring"}
+	at := time.Unix(1700000000, 0)
+	r := Record(choice, at)
+	assert.Equal(t, int64(7), r.QuoteID)
+	assert.Equal(t, Source, r.TrendSource)
+	assert.Equal(t, "Spring", r.TrendTitle)
+	assert.NotEqual(t, r.TrendHash, Record(choice, at.Add(time.Hour)).TrendHash)
+}
@@ -1,21 +1,23 @@
 package extractor
 
 import (
-	"bufio"
-	"os"
 	"strings"
 	"unicode"
+
+	"github.com/abdulachik/dostobot/internal/ingest"
 )
 
 // Chunk represents a portion of text from a book.
 type Chunk struct {
 	Text       string
-	StartLine  int
+	StartLine  int // 0-based line of the source the first line of Text is on
 	EndLine    int
-	Chapter    string
+	Chapter    string // location of StartLine, e.g. "Part III, Chapter 5"
 	WordCount  int
 	CharCount  int
 	ChunkIndex int
+
+	structure *Structure
 }
 
 // ChunkerConfig holds configuration for the chunker.
@@ -47,24 +49,31 @@ func NewChunker(config ChunkerConfig) *Chunker {
 	return &Chunker{config: config}
 }
 
-// ChunkFile reads a file and splits it into chunks.
+// ChunkFile reads a book in plain text, HTML or EPUB and splits it into
+// chunks.
 func (c *Chunker) ChunkFile(path string) ([]Chunk, error) {
-	file, err := os.Open(path)
+	doc, err := ingest.Load(path)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
-	var lines []string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
-	}
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
+	return c.ChunkDocument(doc), nil
+}
 
-	return c.ChunkLines(lines), nil
+// ChunkDocument splits a book into chunks. Chapters are taken from the
+// book's table of contents if it has one, and found in the text
+// otherwise.
+func (c *Chunker) ChunkDocument(doc *ingest.Document) []Chunk {
+	return c.chunk(doc.Lines, DocumentStructure(doc))
+}
+
+// DocumentStructure returns the structure of a book: from its table of
+// contents if it has one, and from the headings in its text otherwise.
+func DocumentStructure(doc *ingest.Document) *Structure {
+	if len(doc.Chapters) > 0 {
+		return TOCStructure(doc.Lines, doc.Chapters)
+	}
+	return ParseStructure(doc.Lines)
 }
 
 // ChunkText splits text into chunks.
@@ -73,30 +82,45 @@ func (c *Chunker) ChunkText(text string) []Chunk {
 	return c.ChunkLines(lines)
 }
 
-// ChunkLines splits lines into chunks.
+// ChunkLines splits lines into chunks. A chunk starts at each part, book
+// or chapter heading, unless the text before it is shorter than MinWords,
+// and longer chapters are split at paragraph boundaries.
 func (c *Chunker) ChunkLines(lines []string) []Chunk {
-	// First, strip Gutenberg header/footer
-	lines = stripGutenbergBoilerplate(lines)
+	return c.chunk(lines, ParseStructure(lines))
+}
+
+// chunk splits lines into chunks, starting one at each section of
+// structure.
+func (c *Chunker) chunk(lines []string, structure *Structure) []Chunk {
+	// First, strip Gutenberg header/footer, remembering where the body
+	// starts so chunk line numbers refer to the source
+	offset, end := gutenbergBody(lines)
+	lines = lines[offset:end]
 
 	// Build chunks
 	var chunks []Chunk
 	var currentChunk strings.Builder
 	var currentWords int
 	var startLine int
-	var currentChapter string
 	var chunkIndex int
 
 	for i, line := range lines {
-		// Detect chapter headings
-		if chapter := detectChapter(line); chapter != "" {
-			currentChapter = chapter
+		// Start a new chunk at a heading, without overlap: the text
+		// before it belongs to another chapter
+		if structure.isHeading(offset+i) && currentWords >= c.config.MinWords {
+			chunks = append(chunks, newChunk(structure, currentChunk.String(), offset+startLine, offset+i-1, chunkIndex))
+			chunkIndex++
+			currentChunk.Reset()
+			currentWords = 0
+			startLine = i
 		}
 
 		// Count words in this line
 		lineWords := countWords(line)
 
-		// Add line to current chunk
-		if currentChunk.Len() > 0 {
+		// Add line to current chunk, keeping blank lines so line
+		// numbers can be recovered from the text
+		if i > startLine {
 			currentChunk.WriteString("\n")
 		}
 		currentChunk.WriteString(line)
@@ -109,16 +133,7 @@ func (c *Chunker) ChunkLines(lines []string) []Chunk {
 			breakPoint := findBreakPoint(chunkText, c.config.TargetWords, c.config.OverlapWords)
 
 			if breakPoint > 0 && breakPoint < len(chunkText) {
-				chunk := Chunk{
-					Text:       strings.TrimSpace(chunkText[:breakPoint]),
-					StartLine:  startLine,
-					EndLine:    i,
-					Chapter:    currentChapter,
-					WordCount:  countWords(chunkText[:breakPoint]),
-					CharCount:  len(chunkText[:breakPoint]),
-					ChunkIndex: chunkIndex,
-				}
-
+				chunk := newChunk(structure, chunkText[:breakPoint], offset+startLine, offset+i, chunkIndex)
 				if chunk.WordCount >= c.config.MinWords {
 					chunks = append(chunks, chunk)
 					chunkIndex++
@@ -136,24 +151,55 @@ func (c *Chunker) ChunkLines(lines []string) []Chunk {
 
 	// Add final chunk if it has enough content
 	if currentWords >= c.config.MinWords {
-		chunkText := currentChunk.String()
-		chunk := Chunk{
-			Text:       strings.TrimSpace(chunkText),
-			StartLine:  startLine,
-			EndLine:    len(lines) - 1,
-			Chapter:    currentChapter,
-			WordCount:  currentWords,
-			CharCount:  len(chunkText),
-			ChunkIndex: chunkIndex,
-		}
-		chunks = append(chunks, chunk)
+		chunks = append(chunks, newChunk(structure, currentChunk.String(), offset+startLine, offset+len(lines)-1, chunkIndex))
 	}
 
 	return chunks
 }
 
+// newChunk builds the chunk of text, whose first line is source line
+// start, locating it in structure.
+func newChunk(structure *Structure, text string, start, end, index int) Chunk {
+	trimmed, leading := trimChunk(text)
+	return Chunk{
+		Text:       trimmed,
+		StartLine:  start + leading,
+		EndLine:    end,
+		Chapter:    structure.Location(start + leading),
+		WordCount:  countWords(text),
+		CharCount:  len(text),
+		ChunkIndex: index,
+		structure:  structure,
+	}
+}
+
+// LocationAt returns the location of source line within the book, e.g.
+// "Part III, Chapter 5". It falls back to the location of the chunk's
+// start for chunks not built by a Chunker.
+func (c Chunk) LocationAt(line int) string {
+	if c.structure == nil {
+		return c.Chapter
+	}
+	return c.structure.Location(line)
+}
+
+// trimChunk trims surrounding whitespace from chunk text, returning the
+// trimmed text and the number of lines removed from its start.
+func trimChunk(text string) (string, int) {
+	trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+	leading := countNewlines(text[:len(text)-len(trimmed)])
+	return strings.TrimRightFunc(trimmed, unicode.IsSpace), leading
+}
+
 // stripGutenbergBoilerplate removes Project Gutenberg header and footer.
 func stripGutenbergBoilerplate(lines []string) []string {
+	start, end := gutenbergBody(lines)
+	return lines[start:end]
+}
+
+// gutenbergBody returns the range of lines between the Project Gutenberg
+// header and footer, or all lines if the markers are missing.
+func gutenbergBody(lines []string) (int, int) {
 	startIdx := 0
 	endIdx := len(lines)
 
@@ -179,54 +225,10 @@ func stripGutenbergBoilerplate(lines []string) []string {
 	}
 
 	if startIdx >= endIdx {
-		return lines
-	}
-
-	return lines[startIdx:endIdx]
-}
-
-// detectChapter checks if a line is a chapter heading.
-func detectChapter(line string) string {
-	line = strings.TrimSpace(line)
-	upper := strings.ToUpper(line)
-
-	// Common chapter patterns
-	patterns := []string{
-		"CHAPTER",
-		"PART",
-		"BOOK",
-		"EPILOGUE",
-		"PROLOGUE",
-	}
-
-	for _, pattern := range patterns {
-		if strings.HasPrefix(upper, pattern) {
-			return line
-		}
-	}
-
-	// Roman numerals alone (I, II, III, IV, V, etc.)
-	if isRomanNumeral(line) && len(line) <= 10 {
-		return line
-	}
-
-	return ""
-}
-
-// isRomanNumeral checks if a string is a valid Roman numeral.
-func isRomanNumeral(s string) bool {
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return false
+		return 0, len(lines)
 	}
 
-	validChars := "IVXLCDMivxlcdm."
-	for _, c := range s {
-		if !strings.ContainsRune(validChars, c) && !unicode.IsSpace(c) {
-			return false
-		}
-	}
-	return true
+	return startIdx, endIdx
 }
 
 // countWords counts words in text.
@@ -242,7 +244,7 @@ func countNewlines(text string) int {
 // findBreakPoint finds a good place to break the text.
 func findBreakPoint(text string, targetWords, overlapWords int) int {
 	// Try to break at paragraph boundary (double newline)
-	targetChars := estimateChars(targetWords - overlapWords)
+	targetChars := estimateChars(text, targetWords-overlapWords)
 	if targetChars >= len(text) {
 		return len(text)
 	}
@@ -270,8 +272,13 @@ func findBreakPoint(text string, targetWords, overlapWords int) int {
 	return targetChars
 }
 
-// estimateChars estimates character count from word count.
-func estimateChars(words int) int {
-	// Average English word is ~5 chars + space
-	return words * 6
+// estimateChars estimates the byte length of words words of text.
+func estimateChars(text string, words int) int {
+	// Average English word is ~5 chars + space; words of other languages,
+	// such as Russian in two-byte Cyrillic, take more
+	perWord := 6
+	if n := countWords(text); n > 0 {
+		perWord = max(perWord, len(text)/n)
+	}
+	return words * perWord
 }
@@ -87,8 +87,27 @@ Second chapter content with many words ` + strings.Repeat("more ", 600)
 		chunks := chunker.ChunkText(text)
 		require.Greater(t, len(chunks), 0)
 
-		// First chunk should have Chapter I
-		assert.Contains(t, chunks[0].Chapter, "CHAPTER")
+		// Each chapter starts a chunk
+		require.Len(t, chunks, 2)
+		assert.Equal(t, "Chapter 1", chunks[0].Chapter)
+		assert.Equal(t, "Chapter 2", chunks[1].Chapter)
+		assert.True(t, strings.HasPrefix(chunks[1].Text, "CHAPTER II"))
+		assert.Equal(t, "Chapter 2", chunks[0].LocationAt(chunks[1].StartLine))
+	})
+
+	t.Run("short chapters share a chunk", func(t *testing.T) {
+		text := "CHAPTER I\n\n" + strings.Repeat("word ", 50) + "\n\nCHAPTER II\n\n" + strings.Repeat("more ", 300)
+
+		chunker := NewChunker(ChunkerConfig{
+			TargetWords:  500,
+			OverlapWords: 50,
+			MinWords:     100,
+		})
+
+		chunks := chunker.ChunkText(text)
+		require.Len(t, chunks, 1)
+		assert.Equal(t, "Chapter 1", chunks[0].Chapter)
+		assert.Equal(t, "Chapter 2", chunks[0].LocationAt(6))
 	})
 
 	t.Run("chunk indices are sequential", func(t *testing.T) {
@@ -134,33 +153,6 @@ func TestStripGutenbergBoilerplate(t *testing.T) {
 	})
 }
 
-func TestDetectChapter(t *testing.T) {
-	tests := []struct {
-		input    string
-		expected string
-	}{
-		{"CHAPTER I", "CHAPTER I"},
-		{"Chapter 1", "Chapter 1"},
-		{"PART ONE", "PART ONE"},
-		{"BOOK FIRST", "BOOK FIRST"},
-		{"EPILOGUE", "EPILOGUE"},
-		{"PROLOGUE", "PROLOGUE"},
-		{"I", "I"},
-		{"II", "II"},
-		{"III", "III"},
-		{"IV", "IV"},
-		{"Regular text", ""},
-		{"The chapter begins", ""},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			result := detectChapter(tt.input)
-			assert.Equal(t, tt.expected, result)
-		})
-	}
-}
-
 func TestCountWords(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -180,30 +172,3 @@ func TestCountWords(t *testing.T) {
 		})
 	}
 }
-
-func TestIsRomanNumeral(t *testing.T) {
-	tests := []struct {
-		input    string
-		expected bool
-	}{
-		{"I", true},
-		{"II", true},
-		{"III", true},
-		{"IV", true},
-		{"V", true},
-		{"VI", true},
-		{"X", true},
-		{"XII", true},
-		{"Hello", false},
-		{"1", false},
-		{"", false},
-		{"I.", true},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			result := isRomanNumeral(tt.input)
-			assert.Equal(t, tt.expected, result)
-		})
-	}
-}
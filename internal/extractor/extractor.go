@@ -6,73 +6,138 @@ import (
 	"database/sql"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
-	"strings"
+	"sync"
+	"unicode/utf8"
 
+	"github.com/abdulachik/dostobot/internal/characters"
 	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/dedupe"
+	"github.com/abdulachik/dostobot/internal/embedder"
+	"github.com/abdulachik/dostobot/internal/llm"
+	"github.com/abdulachik/dostobot/internal/themes"
 )
 
-// BookInfo maps file names to book titles.
-var BookInfo = map[string]string{
-	"crime-and-punishment.txt":  "Crime and Punishment",
-	"brothers-karamazov.txt":    "The Brothers Karamazov",
-	"notes-from-underground.txt": "Notes from Underground",
-	"the-idiot.txt":             "The Idiot",
-	"the-possessed.txt":         "The Possessed",
-	"the-gambler.txt":           "The Gambler",
-	"poor-folk.txt":             "Poor Folk",
-}
+// Job statuses stored in extraction_jobs.status.
+const (
+	jobPending   = "pending"
+	jobRunning   = "running"
+	jobCompleted = "completed"
+	jobFailed    = "failed"
+)
 
 // Extractor handles quote extraction from books.
 type Extractor struct {
-	store    *db.Store
-	claude   *ClaudeClient
-	chunker  *Chunker
-	booksDir string
+	store       *db.Store
+	llm         llm.Completer
+	validator   llm.Completer
+	chunker     *Chunker
+	budget      *llm.Budget
+	booksDir    string
+	force       bool
+	concurrency int
+
+	// dups indexes the quotes of the book being extracted, so passages
+	// extracted again from overlapping chunks are not saved twice.
+	dups *dedupe.Index
+
+	// embedder, if set, confirms quotes sharing part of their text with a
+	// stored quote as the same passage. vectors caches the embeddings of
+	// stored quotes by ID.
+	embedder  embedder.Provider
+	vectorsMu sync.Mutex
+	vectors   map[int64][]float32
+
+	// taxonomy normalizes the themes of saved quotes. It is loaded with the
+	// first quote saved.
+	taxonomy *themes.Taxonomy
+
+	// casts hold the character registries of the books quotes were saved
+	// from, by title.
+	casts map[string]*characters.Registry
 }
 
 // Config holds configuration for the extractor.
 type Config struct {
 	Store    *db.Store
-	APIKey   string
 	BooksDir string
+
+	// LLM extracts quotes from each chunk.
+	LLM llm.Completer
+
+	// Validator, if set, reviews every new quote against the quality rubric
+	// before it is saved. Without it quotes are saved unvalidated and can be
+	// reviewed later with the validate command.
+	Validator llm.Completer
+
+	// Budget, if set, is the budget LLM charges; it is only read here to
+	// report spend.
+	Budget *llm.Budget
+
+	// Force re-extracts books that already have a completed job for the
+	// same file. By default they are skipped.
+	Force bool
+
+	// Concurrency is the number of chunks sent to the LLM in parallel (default 1).
+	Concurrency int
+
+	// Embedder, if set, catches near-duplicates the text check alone
+	// misses: a quote sharing at least half its text with a stored quote
+	// is skipped if their embeddings are nearly identical, as with the
+	// dedupe command. Without it only text containment is checked.
+	Embedder embedder.Provider
 }
 
 // New creates a new Extractor.
 func New(cfg Config) *Extractor {
+	concurrency := cfg.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
 	return &Extractor{
-		store: cfg.Store,
-		claude: NewClaudeClient(ClaudeConfig{
-			APIKey: cfg.APIKey,
-		}),
-		chunker:  NewChunker(DefaultChunkerConfig()),
-		booksDir: cfg.BooksDir,
+		store:       cfg.Store,
+		llm:         cfg.LLM,
+		validator:   cfg.Validator,
+		chunker:     NewChunker(DefaultChunkerConfig()),
+		budget:      cfg.Budget,
+		booksDir:    cfg.BooksDir,
+		force:       cfg.Force,
+		concurrency: concurrency,
+		embedder:    cfg.Embedder,
+		vectors:     make(map[int64][]float32),
 	}
 }
 
-// ExtractAll extracts quotes from all available books.
+// Spent returns the USD spent on the LLM by this extractor so far.
+func (e *Extractor) Spent() float64 {
+	return e.budget.Spent()
+}
+
+// ExtractAll extracts quotes from every catalog book that has been
+// downloaded.
 func (e *Extractor) ExtractAll(ctx context.Context) error {
-	files, err := os.ReadDir(e.booksDir)
+	books, err := e.store.ListBooks(ctx)
 	if err != nil {
-		return fmt.Errorf("read books directory: %w", err)
+		return fmt.Errorf("list books: %w", err)
 	}
 
-	for _, file := range files {
-		if file.IsDir() || !strings.HasSuffix(file.Name(), ".txt") {
+	for _, book := range books {
+		if _, err := os.Stat(filepath.Join(e.booksDir, book.FilePath)); os.IsNotExist(err) {
+			slog.Warn("book file not found, skipping", "book", book.Title, "file", book.FilePath)
 			continue
 		}
 
-		bookTitle, ok := BookInfo[file.Name()]
-		if !ok {
-			slog.Warn("unknown book file, skipping", "file", file.Name())
-			continue
-		}
-
-		if err := e.ExtractBook(ctx, bookTitle); err != nil {
-			slog.Error("failed to extract book", "book", bookTitle, "error", err)
+		if err := e.ExtractBook(ctx, book.Title); err != nil {
+			if abortsExtraction(err) || ctx.Err() != nil {
+				return err
+			}
+			slog.Error("failed to extract book", "book", book.Title, "error", err)
 			// Continue with other books
 		}
 	}
@@ -80,84 +145,125 @@ func (e *Extractor) ExtractAll(ctx context.Context) error {
 	return nil
 }
 
-// ExtractBook extracts quotes from a specific book.
+// ExtractBook extracts quotes from a catalog book.
 func (e *Extractor) ExtractBook(ctx context.Context, bookTitle string) error {
-	// Find the file for this book
-	var filePath string
-	for file, title := range BookInfo {
-		if title == bookTitle {
-			filePath = filepath.Join(e.booksDir, file)
-			break
-		}
+	book, err := e.store.GetBookByTitle(ctx, bookTitle)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("unknown book: %s (add it with 'dostobot book add')", bookTitle)
 	}
-
-	if filePath == "" {
-		return fmt.Errorf("unknown book: %s", bookTitle)
+	if err != nil {
+		return fmt.Errorf("find book: %w", err)
 	}
 
 	// Check if file exists
+	filePath := filepath.Join(e.booksDir, book.FilePath)
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return fmt.Errorf("book file not found: %s (run 'task download' first)", filePath)
+		return fmt.Errorf("book file not found: %s (run 'dostobot download' first)", filePath)
 	}
 
-	slog.Info("starting extraction", "book", bookTitle, "file", filePath)
+	fileHash, err := hashFile(filePath)
+	if err != nil {
+		return fmt.Errorf("hash book file: %w", err)
+	}
 
-	// Create extraction job
-	job, err := e.store.CreateExtractionJob(ctx, db.CreateExtractionJobParams{
+	latest, err := e.store.GetLatestExtractionJobForFile(ctx, db.GetLatestExtractionJobForFileParams{
 		BookTitle: bookTitle,
-		FilePath:  filePath,
+		FileHash:  sql.NullString{String: fileHash, Valid: true},
 	})
-	if err != nil {
-		return fmt.Errorf("create extraction job: %w", err)
+	if err == sql.ErrNoRows {
+		latest = nil
+	} else if err != nil {
+		return fmt.Errorf("find previous extraction job: %w", err)
 	}
 
+	if latest != nil && latest.Status.String == jobCompleted && !e.force {
+		slog.Info("book already extracted, skipping (use --force to re-extract)",
+			"book", bookTitle,
+			"job", latest.ID,
+			"quotes", latest.QuotesExtracted.Int64,
+		)
+		return nil
+	}
+
+	slog.Info("starting extraction", "book", bookTitle, "file", filePath)
+
 	// Chunk the book
-	chunks, err := e.chunker.ChunkFile(filePath)
-	if err != nil {
+	chunks, chunkErr := e.chunker.ChunkFile(filePath)
+
+	// Resume an interrupted job for the same file, or start a new one
+	var job *db.ExtractionJob
+	start, totalQuotes := 0, 0
+	if next, ok := resumePoint(latest, len(chunks)); ok && chunkErr == nil {
+		job = latest
+		start = next
+		totalQuotes = int(latest.QuotesExtracted.Int64)
+		slog.Info("resuming extraction job",
+			"book", bookTitle,
+			"job", job.ID,
+			"from_chunk", start+1,
+			"total", len(chunks),
+		)
+	} else {
+		job, err = e.store.CreateExtractionJob(ctx, db.CreateExtractionJobParams{
+			BookTitle: bookTitle,
+			FilePath:  filePath,
+			FileHash:  sql.NullString{String: fileHash, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("create extraction job: %w", err)
+		}
+	}
+
+	if chunkErr != nil {
 		e.store.UpdateExtractionJobFailed(ctx, db.UpdateExtractionJobFailedParams{
 			ID:           job.ID,
-			ErrorMessage: sql.NullString{String: err.Error(), Valid: true},
+			ErrorMessage: sql.NullString{String: chunkErr.Error(), Valid: true},
 		})
-		return fmt.Errorf("chunk file: %w", err)
+		return fmt.Errorf("chunk file: %w", chunkErr)
 	}
 
 	slog.Info("chunked book", "book", bookTitle, "chunks", len(chunks))
 
+	if err := e.indexExistingQuotes(ctx, bookTitle); err != nil {
+		return err
+	}
+
 	// Update job with total chunks
 	e.store.UpdateExtractionJobStarted(ctx, db.UpdateExtractionJobStartedParams{
 		ID:          job.ID,
 		TotalChunks: sql.NullInt64{Int64: int64(len(chunks)), Valid: true},
 	})
 
-	// Process each chunk
-	totalQuotes := 0
-	for i, chunk := range chunks {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
-		slog.Info("processing chunk",
-			"book", bookTitle,
-			"chunk", i+1,
-			"total", len(chunks),
-			"words", chunk.WordCount,
-		)
-
-		quotes, err := e.claude.ExtractQuotes(ctx, bookTitle, chunk.Text)
-		if err != nil {
+	// Fan the remaining chunks out to the worker pool. Results arrive out of
+	// order; quotes are saved here, on a single goroutine, and only the
+	// contiguous prefix of finished chunks is recorded as progress.
+	dispatchCtx, stopDispatch := context.WithCancel(ctx)
+	defer stopDispatch()
+
+	progress := newWatermark(start)
+	var abortErr error
+	for res := range e.extractChunks(ctx, dispatchCtx, book, chunks, start) {
+		if res.err != nil {
+			if abortsExtraction(res.err) || ctx.Err() != nil {
+				// Not processed: stop handing out chunks and let the
+				// requests already in flight finish. The chunk stays
+				// beyond the watermark and is retried on resume.
+				if abortErr == nil {
+					abortErr = res.err
+					stopDispatch()
+				}
+				continue
+			}
 			slog.Error("failed to extract quotes from chunk",
 				"book", bookTitle,
-				"chunk", i,
-				"error", err,
+				"chunk", res.index,
+				"error", res.err,
 			)
-			continue
 		}
 
 		// Save quotes
-		for _, q := range quotes {
-			if err := e.saveQuote(ctx, bookTitle, chunk, q); err != nil {
+		for _, q := range res.quotes {
+			if err := e.saveQuote(ctx, book, chunks[res.index], q); err != nil {
 				slog.Error("failed to save quote",
 					"book", bookTitle,
 					"error", err,
@@ -170,27 +276,326 @@ func (e *Extractor) ExtractBook(ctx context.Context, bookTitle string) error {
 		// Update progress
 		e.store.UpdateExtractionJobProgress(ctx, db.UpdateExtractionJobProgressParams{
 			ID:              job.ID,
-			ProcessedChunks: sql.NullInt64{Int64: int64(i + 1), Valid: true},
+			ProcessedChunks: sql.NullInt64{Int64: int64(progress.complete(res.index)), Valid: true},
 			QuotesExtracted: sql.NullInt64{Int64: int64(totalQuotes), Valid: true},
 		})
 	}
 
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if abortErr != nil {
+		e.store.UpdateExtractionJobFailed(ctx, db.UpdateExtractionJobFailedParams{
+			ID:           job.ID,
+			ErrorMessage: sql.NullString{String: abortErr.Error(), Valid: true},
+		})
+		slog.Warn("extraction stopped",
+			"book", bookTitle,
+			"processed_chunks", progress.next,
+			"total", len(chunks),
+			"spent_usd", fmt.Sprintf("%.2f", e.budget.Spent()),
+		)
+		return fmt.Errorf("extract %s: %w", bookTitle, abortErr)
+	}
+
 	// Mark job complete
 	e.store.UpdateExtractionJobCompleted(ctx, job.ID)
 
 	slog.Info("extraction complete",
 		"book", bookTitle,
 		"total_quotes", totalQuotes,
+		"spent_usd", fmt.Sprintf("%.2f", e.budget.Spent()),
 	)
 
 	return nil
 }
 
-// saveQuote saves an extracted quote to the database.
-func (e *Extractor) saveQuote(ctx context.Context, bookTitle string, chunk Chunk, quote ExtractedQuote) error {
+// indexExistingQuotes builds the near-duplicate index from the quotes
+// already stored for bookTitle.
+func (e *Extractor) indexExistingQuotes(ctx context.Context, bookTitle string) error {
+	quotes, err := e.store.ListQuotesByBook(ctx, bookTitle)
+	if err != nil {
+		return fmt.Errorf("list existing quotes: %w", err)
+	}
+
+	e.dups = dedupe.NewIndex()
+	for _, q := range quotes {
+		e.dups.Add(q.ID, dedupe.Sign(q.Text))
+	}
+	return nil
+}
+
+// nearDuplicate returns the stored quote of the book that text, with
+// signature sig, duplicates, and their containment. Quotes sharing at least
+// dedupe.MinTextOverlap of their text are compared by embedding when an
+// embedder is set; failing to embed only leaves the text check.
+func (e *Extractor) nearDuplicate(ctx context.Context, text string, sig dedupe.Signature) (int64, float64, bool) {
+	if e.embedder == nil {
+		return e.dups.Match(sig, dedupe.DefaultThreshold)
+	}
+
+	overlaps := e.dups.Overlapping(sig, dedupe.MinTextOverlap)
+	if len(overlaps) == 0 {
+		return 0, 0, false
+	}
+	if best := overlaps[0]; best.Containment >= dedupe.DefaultThreshold {
+		return best.ID, best.Containment, true
+	}
+
+	vector, err := e.embedder.Embed(ctx, text)
+	if err != nil {
+		slog.Warn("failed to embed quote for duplicate check", "error", err)
+		return 0, 0, false
+	}
+	for _, o := range overlaps {
+		stored, err := e.storedVector(ctx, o.ID)
+		if err != nil {
+			slog.Warn("failed to embed stored quote for duplicate check", "quote", o.ID, "error", err)
+			continue
+		}
+		if (dedupe.Options{}).Duplicate(o.Containment, vector, stored) {
+			return o.ID, o.Containment, true
+		}
+	}
+	return 0, 0, false
+}
+
+// storedVector returns the embedding of the stored quote id.
+func (e *Extractor) storedVector(ctx context.Context, id int64) ([]float32, error) {
+	e.vectorsMu.Lock()
+	vector, ok := e.vectors[id]
+	e.vectorsMu.Unlock()
+	if ok {
+		return vector, nil
+	}
+
+	q, err := e.store.GetQuote(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get quote: %w", err)
+	}
+	vector, err = e.embedder.Embed(ctx, q.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	e.vectorsMu.Lock()
+	e.vectors[id] = vector
+	e.vectorsMu.Unlock()
+	return vector, nil
+}
+
+// abortsExtraction reports whether a chunk error should stop the run rather
+// than skip the chunk. Exhausted budgets, bad credentials and API failures
+// that survived every retry would fail the following chunks too; a chunk
+// that is too long or otherwise rejected is specific to that chunk.
+func abortsExtraction(err error) bool {
+	return errors.Is(err, llm.ErrBudgetExceeded) || errors.Is(err, llm.ErrAuth) || llm.IsRetryable(err)
+}
+
+// chunkResult is the outcome of extracting quotes from one chunk.
+type chunkResult struct {
+	index  int
+	quotes []candidate
+	err    error
+}
+
+// candidate is an extracted quote that was found in the source text, ready
+// to be saved.
+type candidate struct {
+	ExtractedQuote
+	span       Span
+	validation *Validation // nil if not reviewed
+}
+
+// extractChunks sends chunks[start:] to Claude on e.concurrency workers and
+// returns their results in completion order. No new chunks are handed out
+// once dispatchCtx is done, but requests in flight run to completion under
+// ctx. The channel is closed after the last worker exits; it must be drained.
+func (e *Extractor) extractChunks(ctx, dispatchCtx context.Context, book *db.Book, chunks []Chunk, start int) <-chan chunkResult {
+	indexes := make(chan int)
+	results := make(chan chunkResult)
+
+	go func() {
+		defer close(indexes)
+		for i := start; i < len(chunks); i++ {
+			select {
+			case indexes <- i:
+			case <-dispatchCtx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < e.concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				slog.Info("processing chunk",
+					"book", book.Title,
+					"chunk", i+1,
+					"total", len(chunks),
+					"words", chunks[i].WordCount,
+				)
+
+				var quotes []candidate
+				extracted, err := ExtractQuotes(ctx, e.llm, book.Title, book.Author, book.Language, chunks[i].Text)
+				if err == nil {
+					quotes = verifyQuotes(book.Title, chunks[i], extracted)
+					err = e.validateQuotes(ctx, book.Title, quotes)
+				}
+				results <- chunkResult{index: i, quotes: quotes, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// verifyQuotes replaces each extracted quote with the verbatim passage of
+// the chunk it came from. Quotes that cannot be found in the chunk were
+// paraphrased or invented and are dropped.
+func verifyQuotes(bookTitle string, chunk Chunk, quotes []ExtractedQuote) []candidate {
+	verified := make([]candidate, 0, len(quotes))
+	for _, q := range quotes {
+		text, span, ok := AlignQuote(chunk, q.Text)
+		if !ok {
+			slog.Warn("quote not found in source text, dropping",
+				"book", bookTitle,
+				"chunk", chunk.ChunkIndex,
+				"text", q.Text,
+			)
+			continue
+		}
+		if text != q.Text {
+			slog.Debug("corrected quote to source text",
+				"book", bookTitle,
+				"edits", span.Edits,
+				"extracted", q.Text,
+				"verbatim", text,
+			)
+			q.Text = text
+		}
+		verified = append(verified, candidate{ExtractedQuote: q, span: span})
+	}
+	return verified
+}
+
+// validateQuotes reviews the quotes of one chunk that are not already
+// stored. It fails only with errors that abort extraction, so the chunk is
+// extracted again on resume; a quote whose review fails otherwise is saved
+// unvalidated.
+func (e *Extractor) validateQuotes(ctx context.Context, bookTitle string, quotes []candidate) error {
+	if e.validator == nil {
+		return nil
+	}
+
+	for i, q := range quotes {
+		if _, err := e.store.GetQuoteByHash(ctx, quoteHash(q.Text)); err == nil {
+			continue // duplicate; saveQuote will skip it
+		}
+		if _, _, dup := e.nearDuplicate(ctx, q.Text, dedupe.Sign(q.Text)); dup {
+			continue
+		}
+
+		v, err := ValidateQuote(ctx, e.validator, q.Text, bookTitle, q.Character)
+		if err != nil {
+			if abortsExtraction(err) || ctx.Err() != nil {
+				return err
+			}
+			slog.Warn("failed to validate quote", "book", bookTitle, "error", err)
+			continue
+		}
+		quotes[i].validation = v
+	}
+	return nil
+}
+
+// watermark tracks the contiguous prefix of processed chunks. Workers finish
+// out of order, but only this prefix is stored as processed_chunks, so a
+// resumed job never skips a chunk. Chunks finished beyond it are extracted
+// again on resume and their quotes are deduplicated by hash.
+type watermark struct {
+	next int
+	done map[int]bool
+}
+
+func newWatermark(start int) *watermark {
+	return &watermark{next: start, done: make(map[int]bool)}
+}
+
+// complete marks chunk i as processed and returns the number of chunks in
+// the contiguous processed prefix.
+func (w *watermark) complete(i int) int {
+	w.done[i] = true
+	for w.done[w.next] {
+		delete(w.done, w.next)
+		w.next++
+	}
+	return w.next
+}
+
+// resumePoint reports whether job can be continued for a book that now splits
+// into totalChunks chunks, and if so the index of the next chunk to process.
+// Completed jobs and jobs whose chunk count no longer matches (e.g. after a
+// chunker change) start over.
+func resumePoint(job *db.ExtractionJob, totalChunks int) (int, bool) {
+	if job == nil || totalChunks == 0 {
+		return 0, false
+	}
+
+	switch job.Status.String {
+	case jobPending, jobRunning, jobFailed:
+	default:
+		return 0, false
+	}
+
+	if job.Status.String != jobPending && (!job.TotalChunks.Valid || job.TotalChunks.Int64 != int64(totalChunks)) {
+		return 0, false
+	}
+
+	processed := int(job.ProcessedChunks.Int64)
+	if processed < 0 || processed > totalChunks {
+		return 0, false
+	}
+	return processed, true
+}
+
+// hashFile returns the hex SHA-256 of a file's contents.
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// quoteHash returns the hash quotes are deduplicated by.
+func quoteHash(text string) string {
+	hash := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(hash[:])
+}
+
+// saveQuote saves a verified quote to the database, in the language of its
+// book, with its source lines and its review if it has one.
+func (e *Extractor) saveQuote(ctx context.Context, book *db.Book, chunk Chunk, quote candidate) error {
+	bookTitle := book.Title
+
 	// Generate hash for deduplication
-	hash := sha256.Sum256([]byte(quote.Text))
-	textHash := hex.EncodeToString(hash[:])
+	textHash := quoteHash(quote.Text)
 
 	// Check if quote already exists
 	_, err := e.store.GetQuoteByHash(ctx, textHash)
@@ -202,35 +607,111 @@ func (e *Extractor) saveQuote(ctx context.Context, bookTitle string, chunk Chunk
 		return fmt.Errorf("check existing quote: %w", err)
 	}
 
-	// Serialize themes to JSON
-	themesJSON, err := json.Marshal(quote.Themes)
+	sig := dedupe.Sign(quote.Text)
+	if id, containment, dup := e.nearDuplicate(ctx, quote.Text, sig); dup {
+		slog.Debug("near-duplicate of existing quote, skipping",
+			"book", bookTitle,
+			"quote", id,
+			"containment", fmt.Sprintf("%.2f", containment),
+		)
+		return nil
+	}
+
+	// Locate the quote by its own first line rather than the chunk's
+	location := chunk.Chapter
+	if quote.span.StartLine > 0 {
+		location = chunk.LocationAt(quote.span.StartLine - 1)
+	}
+
+	// Credit the quote to the character's canonical name
+	cast, err := e.loadCast(ctx, bookTitle)
+	if err != nil {
+		return err
+	}
+	character, err := cast.Resolve(ctx, e.store.Queries, quote.Character)
+	if err != nil {
+		return err
+	}
+
+	// Map themes onto the taxonomy and serialize them to JSON
+	taxonomy, err := e.loadTaxonomy(ctx)
+	if err != nil {
+		return err
+	}
+	quoteThemes := taxonomy.Normalize(quote.Themes)
+	themesJSON, err := json.Marshal(quoteThemes)
 	if err != nil {
 		return fmt.Errorf("marshal themes: %w", err)
 	}
 
 	// Create the quote
-	_, err = e.store.CreateQuote(ctx, db.CreateQuoteParams{
+	created, err := e.store.CreateQuote(ctx, db.CreateQuoteParams{
 		Text:       quote.Text,
 		TextHash:   textHash,
 		SourceBook: bookTitle,
-		Chapter:    sql.NullString{String: chunk.Chapter, Valid: chunk.Chapter != ""},
-		Character:  sql.NullString{String: quote.Character, Valid: quote.Character != ""},
+		Chapter:    sql.NullString{String: location, Valid: location != ""},
+		Character:  sql.NullString{String: character, Valid: character != ""},
 		Themes:     string(themesJSON),
 		ModernRelevance: sql.NullString{
 			String: quote.ModernRelevance,
 			Valid:  quote.ModernRelevance != "",
 		},
-		CharCount: int64(len(quote.Text)),
+		CharCount:       int64(utf8.RuneCountInString(quote.Text)),
+		SourceStartLine: sql.NullInt64{Int64: int64(quote.span.StartLine), Valid: quote.span.StartLine > 0},
+		SourceEndLine:   sql.NullInt64{Int64: int64(quote.span.EndLine), Valid: quote.span.EndLine > 0},
+		VerbatimEdits:   sql.NullInt64{Int64: int64(quote.span.Edits), Valid: quote.span.StartLine > 0},
+		Language:        book.Language,
 	})
 	if err != nil {
 		return fmt.Errorf("create quote: %w", err)
 	}
 
+	e.dups.Add(created.ID, sig)
+
+	if _, err := taxonomy.Tag(ctx, e.store.Queries, created.ID, quoteThemes); err != nil {
+		return err
+	}
+
+	if quote.validation != nil {
+		if err := SaveValidation(ctx, e.store, created.ID, quote.validation); err != nil {
+			return err
+		}
+	}
+
 	slog.Debug("saved quote",
 		"book", bookTitle,
 		"length", len(quote.Text),
-		"themes", quote.Themes,
+		"themes", quoteThemes,
 	)
 
 	return nil
 }
+
+// loadCast returns the character registry of a book, loading it on first
+// use.
+func (e *Extractor) loadCast(ctx context.Context, book string) (*characters.Registry, error) {
+	if cast, ok := e.casts[book]; ok {
+		return cast, nil
+	}
+	cast, err := characters.Load(ctx, e.store.Queries, book)
+	if err != nil {
+		return nil, fmt.Errorf("load characters: %w", err)
+	}
+	if e.casts == nil {
+		e.casts = make(map[string]*characters.Registry)
+	}
+	e.casts[book] = cast
+	return cast, nil
+}
+
+// loadTaxonomy returns the theme taxonomy, loading it on first use.
+func (e *Extractor) loadTaxonomy(ctx context.Context) (*themes.Taxonomy, error) {
+	if e.taxonomy == nil {
+		taxonomy, err := themes.Load(ctx, e.store.Queries)
+		if err != nil {
+			return nil, fmt.Errorf("load themes: %w", err)
+		}
+		e.taxonomy = taxonomy
+	}
+	return e.taxonomy, nil
+}
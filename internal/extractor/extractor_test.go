@@ -2,11 +2,14 @@ package extractor
 
 import (
 	"context"
+	"database/sql"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/llm"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -25,12 +28,12 @@ func TestNew(t *testing.T) {
 
 	extractor := New(Config{
 		Store:    store,
-		APIKey:   "test-key",
+		LLM:      &llm.Fake{},
 		BooksDir: "books",
 	})
 
 	assert.NotNil(t, extractor)
-	assert.NotNil(t, extractor.claude)
+	assert.NotNil(t, extractor.llm)
 	assert.NotNil(t, extractor.chunker)
 	assert.Equal(t, "books", extractor.booksDir)
 }
@@ -49,7 +52,7 @@ func TestExtractor_ExtractBook_FileNotFound(t *testing.T) {
 
 	extractor := New(Config{
 		Store:    store,
-		APIKey:   "test-key",
+		LLM:      &llm.Fake{},
 		BooksDir: tmpDir, // Empty directory
 	})
 
@@ -72,7 +75,7 @@ func TestExtractor_ExtractBook_UnknownBook(t *testing.T) {
 
 	extractor := New(Config{
 		Store:    store,
-		APIKey:   "test-key",
+		LLM:      &llm.Fake{},
 		BooksDir: tmpDir,
 	})
 
@@ -95,22 +98,26 @@ func TestExtractor_saveQuote(t *testing.T) {
 
 	extractor := New(Config{
 		Store:    store,
-		APIKey:   "test-key",
+		LLM:      &llm.Fake{},
 		BooksDir: tmpDir,
 	})
 
+	book := &db.Book{Title: "Crime and Punishment", Language: "en"}
 	chunk := Chunk{
 		Chapter: "Chapter I",
 	}
 
-	quote := ExtractedQuote{
-		Text:            "Pain and suffering are always inevitable for a large intelligence and a deep heart.",
-		Character:       "Raskolnikov",
-		Themes:          []string{"suffering", "intelligence"},
-		ModernRelevance: "Speaks to the burden of awareness.",
+	quote := candidate{
+		ExtractedQuote: ExtractedQuote{
+			Text:            "Pain and suffering are always inevitable for a large intelligence and a deep heart.",
+			Character:       "Rodion Romanovitch",
+			Themes:          []string{"Pain", "intelligence"},
+			ModernRelevance: "Speaks to the burden of awareness.",
+		},
+		span: Span{StartLine: 4210, EndLine: 4211, Edits: 1},
 	}
 
-	err = extractor.saveQuote(ctx, "Crime and Punishment", chunk, quote)
+	err = extractor.saveQuote(ctx, book, chunk, quote)
 	require.NoError(t, err)
 
 	// Verify it was saved
@@ -118,8 +125,17 @@ func TestExtractor_saveQuote(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, int64(1), count)
 
+	saved, err := store.GetQuoteByHash(ctx, quoteHash(quote.Text))
+	require.NoError(t, err)
+	assert.Equal(t, int64(4210), saved.SourceStartLine.Int64)
+	assert.Equal(t, int64(4211), saved.SourceEndLine.Int64)
+	assert.Equal(t, int64(1), saved.VerbatimEdits.Int64)
+	assert.Equal(t, "en", saved.Language)
+	assert.Equal(t, "Raskolnikov", saved.Character.String, "credited to the canonical name")
+	assert.Equal(t, `["suffering","intelligence"]`, saved.Themes)
+
 	// Verify duplicate is skipped
-	err = extractor.saveQuote(ctx, "Crime and Punishment", chunk, quote)
+	err = extractor.saveQuote(ctx, book, chunk, quote)
 	require.NoError(t, err)
 
 	count, err = store.CountQuotes(ctx)
@@ -127,23 +143,233 @@ func TestExtractor_saveQuote(t *testing.T) {
 	assert.Equal(t, int64(1), count) // Still 1, duplicate skipped
 }
 
-func TestBookInfo(t *testing.T) {
-	// Verify all expected books are mapped
-	expectedBooks := []string{
-		"crime-and-punishment.txt",
-		"brothers-karamazov.txt",
-		"notes-from-underground.txt",
-		"the-idiot.txt",
-		"the-possessed.txt",
-		"the-gambler.txt",
-		"poor-folk.txt",
+func TestExtractor_saveQuote_NearDuplicate(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, filepath.Join(tmpDir, "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	require.NoError(t, store.Migrate(ctx))
+
+	extractor := New(Config{Store: store, LLM: &llm.Fake{}, BooksDir: tmpDir})
+	book := &db.Book{Title: "Crime and Punishment", Language: "en"}
+
+	full := candidate{ExtractedQuote: ExtractedQuote{
+		Text: "Pain and suffering are always inevitable for a large intelligence and a deep heart. " +
+			"The really great men must, I think, have great sadness on earth.",
+	}}
+	require.NoError(t, extractor.saveQuote(ctx, book, Chunk{}, full))
+
+	// The index is built from the quotes already stored for the book.
+	require.NoError(t, extractor.indexExistingQuotes(ctx, book.Title))
+
+	// The same passage from the next, overlapping chunk, trimmed differently.
+	trimmed := candidate{ExtractedQuote: ExtractedQuote{
+		Text: "Pain and suffering are always inevitable for a large intelligence and a deep heart!",
+	}}
+	require.NoError(t, extractor.saveQuote(ctx, book, Chunk{}, trimmed))
+
+	other := candidate{ExtractedQuote: ExtractedQuote{
+		Text: "Man is fond of counting his troubles, but he does not count his joys.",
+	}}
+	require.NoError(t, extractor.saveQuote(ctx, book, Chunk{}, other))
+
+	// Quotes saved during the run are indexed too.
+	require.NoError(t, extractor.saveQuote(ctx, book, Chunk{}, candidate{ExtractedQuote: ExtractedQuote{
+		Text: "Man is fond of counting his troubles but he does not count his joys",
+	}}))
+
+	count, err := store.CountQuotes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+// vectorProvider embeds texts as the vectors it was given, and any other
+// text as an unrelated vector.
+type vectorProvider map[string][]float32
+
+func (p vectorProvider) Name() string   { return "fake" }
+func (p vectorProvider) Model() string  { return "fake" }
+func (p vectorProvider) Dimension() int { return 3 }
+
+func (p vectorProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	if v, ok := p[text]; ok {
+		return v, nil
+	}
+	return []float32{0, 0, 1}, nil
+}
+
+func (p vectorProvider) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i], _ = p.Embed(ctx, text)
 	}
+	return out, nil
+}
 
-	for _, file := range expectedBooks {
-		title, ok := BookInfo[file]
-		assert.True(t, ok, "missing book mapping for %s", file)
-		assert.NotEmpty(t, title, "empty title for %s", file)
+func TestExtractor_saveQuote_SimilarEmbedding(t *testing.T) {
+	const (
+		stored   = "Man is fond of counting his troubles, but he does not count his joys."
+		reworded = "Man is fond of counting his troubles, but he never does count his joys."
+	)
+
+	save := func(t *testing.T, provider vectorProvider) int64 {
+		ctx := context.Background()
+		store, err := db.NewStore(ctx, filepath.Join(t.TempDir(), "test.db"))
+		require.NoError(t, err)
+		defer store.Close()
+		require.NoError(t, store.Migrate(ctx))
+
+		cfg := Config{Store: store, LLM: &llm.Fake{}}
+		if provider != nil {
+			cfg.Embedder = provider
+		}
+		extractor := New(cfg)
+		book := &db.Book{Title: "Crime and Punishment", Language: "en"}
+		require.NoError(t, extractor.indexExistingQuotes(ctx, book.Title))
+		for _, text := range []string{stored, reworded} {
+			require.NoError(t, extractor.saveQuote(ctx, book, Chunk{}, candidate{ExtractedQuote: ExtractedQuote{Text: text}}))
+		}
+		count, err := store.CountQuotes(ctx)
+		require.NoError(t, err)
+		return count
 	}
+
+	t.Run("text alone keeps both", func(t *testing.T) {
+		assert.Equal(t, int64(2), save(t, nil))
+	})
+
+	t.Run("nearly identical embeddings skip the second", func(t *testing.T) {
+		assert.Equal(t, int64(1), save(t, vectorProvider{
+			stored:   {1, 0, 0},
+			reworded: {0.99, 0.05, 0},
+		}))
+	})
+
+	t.Run("different embeddings keep both", func(t *testing.T) {
+		assert.Equal(t, int64(2), save(t, vectorProvider{
+			stored:   {1, 0, 0},
+			reworded: {0, 1, 0},
+		}))
+	})
+}
+
+func TestResumePoint(t *testing.T) {
+	job := func(status string, total, processed int64) *db.ExtractionJob {
+		return &db.ExtractionJob{
+			Status:          sql.NullString{String: status, Valid: true},
+			TotalChunks:     sql.NullInt64{Int64: total, Valid: total > 0},
+			ProcessedChunks: sql.NullInt64{Int64: processed, Valid: true},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		job      *db.ExtractionJob
+		chunks   int
+		wantNext int
+		wantOK   bool
+	}{
+		{"no previous job", nil, 10, 0, false},
+		{"interrupted run resumes", job(jobRunning, 10, 4), 10, 4, true},
+		{"failed run resumes", job(jobFailed, 10, 7), 10, 7, true},
+		{"pending job starts at zero", job(jobPending, 0, 0), 10, 0, true},
+		{"completed job starts over", job(jobCompleted, 10, 10), 10, 0, false},
+		{"chunk count changed starts over", job(jobRunning, 12, 4), 10, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, ok := resumePoint(tt.job, tt.chunks)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantNext, next)
+		})
+	}
+}
+
+func TestExtractor_ExtractBook_SkipsCompleted(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, dbPath)
+	require.NoError(t, err)
+	defer store.Close()
+
+	err = store.Migrate(ctx)
+	require.NoError(t, err)
+
+	bookPath := filepath.Join(tmpDir, "poor-folk.txt")
+	require.NoError(t, os.WriteFile(bookPath, []byte("My dear Barbara Alexievna,"), 0o644))
+	hash, err := hashFile(bookPath)
+	require.NoError(t, err)
+
+	job, err := store.CreateExtractionJob(ctx, db.CreateExtractionJobParams{
+		BookTitle: "Poor Folk",
+		FilePath:  bookPath,
+		FileHash:  sql.NullString{String: hash, Valid: true},
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.UpdateExtractionJobCompleted(ctx, job.ID))
+
+	// No API key is usable here, so any attempt to extract would fail.
+	extractor := New(Config{
+		Store:    store,
+		LLM:      &llm.Fake{},
+		BooksDir: tmpDir,
+	})
+
+	err = extractor.ExtractBook(ctx, "Poor Folk")
+	require.NoError(t, err)
+
+	jobs, err := store.ListExtractionJobs(ctx)
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1, "completed book should not start a new job")
+}
+
+func TestExtractor_ExtractAll_Catalog(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, filepath.Join(tmpDir, "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	require.NoError(t, store.Migrate(ctx))
+
+	// Seeded books have no files here and are skipped; a book added to the
+	// catalog is extracted without code changes.
+	_, err = store.CreateBook(ctx, db.CreateBookParams{
+		Title:    "White Nights",
+		Author:   "Fyodor Dostoyevsky",
+		FilePath: "white-nights.txt",
+		Language: "en",
+	})
+	require.NoError(t, err)
+
+	quote := "Is a whole moment of happiness too little for the whole of a man's life?"
+	text := quote + "\n\n" + strings.Repeat("It was a wonderful night, such a night as is only possible when we are young.\n", 40)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "white-nights.txt"), []byte(text), 0o644))
+
+	fake := &llm.Fake{ToolInputs: map[string]string{
+		extractionTool.Name: `{"quotes": [{"text": "` + quote + `", "character": "Narrator", "themes": ["happiness"], "modern_relevance": "r"}]}`,
+	}}
+	extractor := New(Config{Store: store, LLM: fake, BooksDir: tmpDir})
+
+	require.NoError(t, extractor.ExtractAll(ctx))
+
+	quotes, err := store.ListQuotesByBook(ctx, "White Nights")
+	require.NoError(t, err)
+	require.Len(t, quotes, 1)
+	assert.Equal(t, quote, quotes[0].Text)
+
+	calls := fake.Calls()
+	require.NotEmpty(t, calls)
+	assert.Contains(t, calls[0].User, `"White Nights" by Fyodor Dostoyevsky`)
+
+	jobs, err := store.ListExtractionJobs(ctx)
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1, "books without a file should be skipped")
 }
 
 // Integration test - requires API key and books
@@ -174,7 +400,7 @@ func TestExtractor_ExtractBook_Integration(t *testing.T) {
 
 	extractor := New(Config{
 		Store:    store,
-		APIKey:   os.Getenv("ANTHROPIC_API_KEY"),
+		LLM:      llm.NewAnthropic(llm.AnthropicConfig{APIKey: os.Getenv("ANTHROPIC_API_KEY")}),
 		BooksDir: booksDir,
 	})
 
@@ -183,3 +409,12 @@ func TestExtractor_ExtractBook_Integration(t *testing.T) {
 	_ = extractor
 	t.Log("Integration test scaffolded - uncomment to run actual extraction")
 }
+
+func TestWatermark(t *testing.T) {
+	w := newWatermark(3)
+
+	assert.Equal(t, 3, w.complete(5), "gap at 3 holds the watermark")
+	assert.Equal(t, 3, w.complete(4))
+	assert.Equal(t, 6, w.complete(3), "filling the gap advances past finished chunks")
+	assert.Equal(t, 7, w.complete(6))
+}
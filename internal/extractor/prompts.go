@@ -1,7 +1,7 @@
 package extractor
 
 // SystemPrompt is the system prompt for quote extraction.
-const SystemPrompt = `You are an expert literary analyst specializing in Dostoyevsky's works. Your task is to extract memorable, profound quotes that could resonate with modern readers on social media.
+const SystemPrompt = `You are an expert literary analyst specializing in classic literature. Your task is to extract memorable, profound quotes that could resonate with modern readers on social media.
 
 Guidelines for selecting quotes:
 1. UNIVERSAL THEMES: Choose quotes about human nature, morality, psychology, society, freedom, suffering, redemption, or existential questions that remain relevant today
@@ -17,7 +17,7 @@ For each quote, provide:
 - A brief note on why this quote would resonate with modern readers`
 
 // ExtractionPrompt is the user prompt template for extraction.
-const ExtractionPrompt = `Analyze the following passage from "%s" and extract 3-7 memorable quotes that would resonate with modern social media audiences.
+const ExtractionPrompt = `Analyze the following passage from "%s" by %s and extract 3-7 memorable quotes that would resonate with modern social media audiences.
 
 Remember:
 - Quotes should be self-contained and meaningful without plot context
@@ -30,25 +30,45 @@ Passage:
 %s
 ---
 
-Respond with a JSON array of quotes. Each quote object should have:
-- "text": the exact quote (preserve original text exactly)
-- "character": who says it (character name or "Narrator")
-- "themes": array of 2-4 theme tags (e.g., ["suffering", "redemption", "human-nature"])
-- "modern_relevance": brief explanation of why this resonates today (1-2 sentences)
-
-Example response format:
-[
-  {
-    "text": "Pain and suffering are always inevitable for a large intelligence and a deep heart.",
-    "character": "Raskolnikov",
-    "themes": ["suffering", "intelligence", "sensitivity"],
-    "modern_relevance": "Speaks to the burden of awareness and empathy that thoughtful people carry."
-  }
-]
-
-If no suitable quotes are found in this passage, respond with an empty array: []`
-
-// ValidationPrompt helps verify quote quality.
+Record the quotes with the record_quotes tool. For each quote give:
+- the exact text (preserve the original exactly; quotes are checked against the passage, so never paraphrase or join sentences from different places)
+- who says it (character name or "Narrator")
+- 2-4 theme tags (e.g. suffering, redemption, human-nature)
+- a brief explanation of why it resonates today (1-2 sentences)
+
+If no suitable quotes are found in this passage, call the tool with an empty list.`
+
+// OriginalLanguagePrompt is added to the extraction prompt for books not in
+// English, naming their language twice.
+const OriginalLanguagePrompt = `
+
+The passage is in %s, the language the book was written in. Copy each quote exactly as it appears in %s; never translate it. Give character names in their usual English spelling (e.g. Raskolnikov), and write the theme tags and explanations in English.`
+
+// TranslationSystemPrompt is the system prompt for aligning a quote with
+// its translation.
+const TranslationSystemPrompt = `You are a literary translator comparing an original novel with a published translation. Given a quote from the original and a passage of the translation, you find the sentences of the passage that translate the quote. You copy them exactly as printed and never write a translation of your own.`
+
+// TranslationPrompt is the user prompt template for aligning a quote with
+// its translation.
+const TranslationPrompt = `This quote is from the %s original of "%s":
+
+"%s"
+
+Below is the same part of the book in a %s translation. Find the sentences that translate the quote, from the first word of the quote to its last.
+
+Passage:
+---
+%s
+---
+
+Record the result with the record_translation tool:
+- found: whether the passage contains a translation of the quote
+- text: the translated sentences, copied exactly from the passage (they are checked against it, so never correct, shorten or retranslate them); empty if not found`
+
+// ValidationSystemPrompt is the system prompt for the quality review.
+const ValidationSystemPrompt = `You are the editor of a literary quotes account posting Dostoyevsky to social media. You review quotes extracted by an assistant and reject the ones that would confuse or bore readers: plot-bound dialogue, fragments that stop mid-thought, lines that depend on knowing the characters, and passages too long or too short to post. Be strict; a smaller collection of strong quotes is better than a large one.`
+
+// ValidationPrompt is the user prompt template for the quality review.
 const ValidationPrompt = `Review this potential Dostoyevsky quote for social media posting:
 
 Quote: "%s"
@@ -56,18 +76,14 @@ Source: %s
 Character: %s
 
 Evaluate:
-1. Does it make sense standalone, without knowing the plot? (yes/no)
-2. Is it between 100-500 characters? (yes/no)
-3. Does it contain universal wisdom applicable today? (yes/no)
-4. Could it reasonably appear on a literary quotes account? (yes/no)
-
-Respond with JSON:
-{
-  "standalone": true/false,
-  "appropriate_length": true/false,
-  "universal_wisdom": true/false,
-  "suitable_for_posting": true/false,
-  "overall_quality": 1-10,
-  "issues": ["list of any issues"],
-  "recommendation": "approve" | "reject" | "edit"
-}`
+1. Does it make sense standalone, without knowing the plot?
+2. Is it between 100-500 characters?
+3. Does it contain universal wisdom applicable today?
+4. Could it reasonably appear on a literary quotes account?
+
+Then rate its overall quality from 1 to 10, list any issues, and recommend:
+- approve: post as is
+- edit: worth keeping, but the text needs trimming or fixing
+- reject: not suitable
+
+Record your review with the record_validation tool.`
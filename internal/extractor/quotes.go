@@ -0,0 +1,83 @@
+package extractor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/abdulachik/dostobot/internal/llm"
+)
+
+// ExtractedQuote represents a quote extracted by the LLM.
+type ExtractedQuote struct {
+	Text            string   `json:"text"`
+	Character       string   `json:"character"`
+	Themes          []string `json:"themes"`
+	ModernRelevance string   `json:"modern_relevance"`
+}
+
+// extractionTool is the tool the LLM calls to return the quotes it found.
+var extractionTool = llm.Tool{
+	Name:        "record_quotes",
+	Description: "Record the memorable quotes found in the passage. Call with an empty list if there are none.",
+	InputSchema: &llm.Schema{
+		Type:     "object",
+		Required: []string{"quotes"},
+		Properties: map[string]*llm.Schema{
+			"quotes": {
+				Type: "array",
+				Items: &llm.Schema{
+					Type:     "object",
+					Required: []string{"text", "character", "themes", "modern_relevance"},
+					Properties: map[string]*llm.Schema{
+						"text":             {Type: "string", Description: "The exact quote, preserving the original text, punctuation and capitalization"},
+						"character":        {Type: "string", Description: `Who says it: a character name or "Narrator"`},
+						"themes":           {Type: "array", Description: "2-4 theme tags, e.g. suffering, redemption, human-nature", Items: &llm.Schema{Type: "string"}},
+						"modern_relevance": {Type: "string", Description: "Why this resonates today, in 1-2 sentences"},
+					},
+				},
+			},
+		},
+	},
+}
+
+// ExtractQuotes extracts quotes from a text chunk of a book with the given
+// model. language is the book's language code; quotes are kept in it, with
+// themes and explanations in English.
+func ExtractQuotes(ctx context.Context, c llm.Completer, bookTitle, author, language, text string) ([]ExtractedQuote, error) {
+	prompt := fmt.Sprintf(ExtractionPrompt, bookTitle, author, text)
+	if language != "" && language != "en" {
+		name := LanguageName(language)
+		prompt += fmt.Sprintf(OriginalLanguagePrompt, name, name)
+	}
+
+	var result struct {
+		Quotes []ExtractedQuote `json:"quotes"`
+	}
+	if err := c.CompleteTool(ctx, SystemPrompt, prompt, extractionTool, &result); err != nil {
+		return nil, fmt.Errorf("complete: %w", err)
+	}
+
+	return result.Quotes, nil
+}
+
+// languageNames names the languages books are likely to be in, by their
+// ISO 639-1 code as stored in books.language.
+var languageNames = map[string]string{
+	"de": "German",
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"it": "Italian",
+	"pl": "Polish",
+	"ru": "Russian",
+	"uk": "Ukrainian",
+}
+
+// LanguageName returns the English name of a language code, or the code
+// itself if it is not known.
+func LanguageName(code string) string {
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return code
+}
@@ -0,0 +1,78 @@
+package extractor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/abdulachik/dostobot/internal/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractQuotes(t *testing.T) {
+	t.Run("decodes quotes containing brackets and braces", func(t *testing.T) {
+		fake := &llm.Fake{ToolInputs: map[string]string{
+			extractionTool.Name: `{"quotes": [
+				{"text": "If there is no God, everything is permitted]}", "character": "Ivan", "themes": ["faith", "morality"], "modern_relevance": "Still argued about."},
+				{"text": "Quote 2", "character": "Narrator", "themes": ["t2"], "modern_relevance": "r2"}
+			]}`,
+		}}
+
+		quotes, err := ExtractQuotes(context.Background(), fake, "The Brothers Karamazov", "Fyodor Dostoyevsky", "en", "the passage")
+		require.NoError(t, err)
+		require.Len(t, quotes, 2)
+		assert.Equal(t, "If there is no God, everything is permitted]}", quotes[0].Text)
+		assert.Equal(t, []string{"faith", "morality"}, quotes[0].Themes)
+
+		calls := fake.Calls()
+		require.Len(t, calls, 1)
+		assert.Equal(t, extractionTool.Name, calls[0].Tool)
+		assert.Contains(t, calls[0].User, "The Brothers Karamazov")
+		assert.Contains(t, calls[0].User, "Fyodor Dostoyevsky")
+		assert.Contains(t, calls[0].User, "the passage")
+		assert.NotContains(t, calls[0].User, "never translate")
+	})
+
+	t.Run("books in other languages keep their language", func(t *testing.T) {
+		fake := &llm.Fake{ToolInputs: map[string]string{extractionTool.Name: `{"quotes": []}`}}
+
+		_, err := ExtractQuotes(context.Background(), fake, "Бедные люди", "Фёдор Достоевский", "ru", "отрывок")
+		require.NoError(t, err)
+
+		calls := fake.Calls()
+		require.Len(t, calls, 1)
+		assert.Contains(t, calls[0].User, "The passage is in Russian")
+		assert.Contains(t, calls[0].User, "never translate")
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		fake := &llm.Fake{ToolInputs: map[string]string{extractionTool.Name: `{"quotes": []}`}}
+
+		quotes, err := ExtractQuotes(context.Background(), fake, "Poor Folk", "Fyodor Dostoyevsky", "en", "passage")
+		require.NoError(t, err)
+		assert.Empty(t, quotes)
+	})
+
+	t.Run("input violating the schema is an error", func(t *testing.T) {
+		fake := &llm.Fake{ToolInputs: map[string]string{
+			extractionTool.Name: `{"quotes": [{"text": "no themes", "character": "A", "modern_relevance": "r"}]}`,
+		}}
+
+		_, err := ExtractQuotes(context.Background(), fake, "Poor Folk", "Fyodor Dostoyevsky", "en", "passage")
+		assert.ErrorIs(t, err, llm.ErrInvalidToolInput)
+	})
+}
+
+func TestAbortsExtraction(t *testing.T) {
+	wrap := func(kind error) error {
+		return fmt.Errorf("complete: %w", &llm.APIError{Kind: kind})
+	}
+
+	assert.True(t, abortsExtraction(wrap(llm.ErrAuth)))
+	assert.True(t, abortsExtraction(wrap(llm.ErrOverloaded)))
+	assert.True(t, abortsExtraction(fmt.Errorf("complete: %w", llm.ErrBudgetExceeded)))
+	assert.False(t, abortsExtraction(wrap(llm.ErrContextLength)))
+	assert.False(t, abortsExtraction(errors.New("parse response: unexpected end of JSON input")))
+}
@@ -0,0 +1,385 @@
+package extractor
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/abdulachik/dostobot/internal/ingest"
+)
+
+// SectionKind is the level of a heading in a book's structure.
+type SectionKind int
+
+const (
+	KindPart SectionKind = iota + 1
+	KindPrologue
+	KindEpilogue
+	KindBook
+	KindChapter
+)
+
+// level orders kinds from outermost to innermost. A prologue or epilogue
+// sits at the level of a part and may contain chapters of its own.
+func (k SectionKind) level() int {
+	switch k {
+	case KindPart, KindPrologue, KindEpilogue:
+		return 1
+	case KindBook:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func (k SectionKind) String() string {
+	switch k {
+	case KindPart:
+		return "Part"
+	case KindPrologue:
+		return "Prologue"
+	case KindEpilogue:
+		return "Epilogue"
+	case KindBook:
+		return "Book"
+	case KindChapter:
+		return "Chapter"
+	}
+	return "Section"
+}
+
+// Section is a part, book or chapter of a novel.
+type Section struct {
+	Kind     SectionKind
+	Number   int    // 0 for a prologue or epilogue
+	Title    string // e.g. "The History of a Family", if the heading names one
+	Line     int    // 0-based source line of the heading
+	Parent   *Section
+	Children []*Section
+
+	// bare is set for chapters headed by a numeral alone, e.g. "IV."
+	bare bool
+	// named is set for table of contents entries that are not a numbered
+	// heading, e.g. "The History of a Family", which are labelled by Title.
+	named bool
+}
+
+// Label names the section, e.g. "Part III" or "Chapter 5". Parts and books
+// are numbered in roman numerals and chapters in arabic ones, whatever
+// the edition uses.
+func (s *Section) Label() string {
+	switch {
+	case s.named:
+		return s.Title
+	case s.Number == 0:
+		return s.Kind.String()
+	case s.Kind == KindChapter:
+		return fmt.Sprintf("%s %d", s.Kind, s.Number)
+	default:
+		return s.Kind.String() + " " + toRoman(s.Number)
+	}
+}
+
+// Location is the section's full path, e.g. "Part III, Chapter 5".
+func (s *Section) Location() string {
+	var labels []string
+	for p := s; p != nil; p = p.Parent {
+		labels = append(labels, p.Label())
+	}
+	for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+		labels[i], labels[j] = labels[j], labels[i]
+	}
+	return strings.Join(labels, ", ")
+}
+
+// Structure is the Part → Book → Chapter tree of a novel.
+type Structure struct {
+	Sections []*Section // top-level sections, in order
+
+	headings []*Section // every section, in line order
+}
+
+// ParseStructure finds the part, book and chapter headings in the body of
+// a Gutenberg text. Section lines are indexes into lines.
+//
+// A heading stands on its own after a blank line: "PART III", "Book I. The
+// History of a Family", "CHAPTER V", "EPILOGUE", or in a Russian original
+// "ЧАСТЬ ТРЕТЬЯ", "Глава V". Sections are labelled in English either way,
+// so a quote of the original and one of its translation share a location.
+// A numeral alone ("IV.") between blank lines is a chapter, unless it
+// follows a "CHAPTER" heading, in which case it numbers a section of that
+// chapter and is ignored.
+func ParseStructure(lines []string) *Structure {
+	start, end := gutenbergBody(lines)
+
+	s := &Structure{}
+	var open []*Section // the current path, outermost first
+	for i := start; i < end; i++ {
+		if i > start && strings.TrimSpace(lines[i-1]) != "" {
+			continue
+		}
+		sec, ok := parseHeading(lines[i])
+		if !ok {
+			continue
+		}
+		if sec.bare {
+			if i+1 < end && strings.TrimSpace(lines[i+1]) != "" {
+				continue
+			}
+			if n := len(open); n > 0 && open[n-1].Kind == KindChapter && !open[n-1].bare {
+				continue
+			}
+		}
+		sec.Line = i
+
+		for len(open) > 0 && open[len(open)-1].Kind.level() >= sec.Kind.level() {
+			open = open[:len(open)-1]
+		}
+		if len(open) > 0 {
+			sec.Parent = open[len(open)-1]
+			sec.Parent.Children = append(sec.Parent.Children, sec)
+		} else {
+			s.Sections = append(s.Sections, sec)
+		}
+		open = append(open, sec)
+		s.headings = append(s.headings, sec)
+	}
+
+	return s
+}
+
+// TOCStructure builds the structure of a book from the chapters of its
+// table of contents, nested by their depth. Entries outside the Gutenberg
+// body of lines, such as the license, are dropped.
+//
+// Entries that read as headings ("PART III", "Chapter 5") are labelled
+// like the headings ParseStructure finds; others by their title.
+func TOCStructure(lines []string, chapters []ingest.Chapter) *Structure {
+	start, end := gutenbergBody(lines)
+
+	s := &Structure{}
+	var open []*Section
+	var depths []int // the depth of each section of open
+	for _, ch := range chapters {
+		if ch.Line < start || ch.Line >= end {
+			continue
+		}
+		sec, ok := parseHeading(ch.Title)
+		if !ok {
+			sec = &Section{Kind: KindChapter, Title: ch.Title, named: true}
+		}
+		sec.Line = ch.Line
+
+		for len(open) > 0 && depths[len(depths)-1] >= ch.Depth {
+			open, depths = open[:len(open)-1], depths[:len(depths)-1]
+		}
+		if len(open) > 0 {
+			sec.Parent = open[len(open)-1]
+			sec.Parent.Children = append(sec.Parent.Children, sec)
+		} else {
+			s.Sections = append(s.Sections, sec)
+		}
+		open, depths = append(open, sec), append(depths, ch.Depth)
+		s.headings = append(s.headings, sec)
+	}
+
+	return s
+}
+
+// At returns the innermost section containing source line, or nil before
+// the first heading.
+func (s *Structure) At(line int) *Section {
+	if s == nil {
+		return nil
+	}
+	i := sort.Search(len(s.headings), func(i int) bool { return s.headings[i].Line > line })
+	if i == 0 {
+		return nil
+	}
+	return s.headings[i-1]
+}
+
+// Location returns the full location of source line, e.g.
+// "Part III, Chapter 5", or "" before the first heading.
+func (s *Structure) Location(line int) string {
+	if sec := s.At(line); sec != nil {
+		return sec.Location()
+	}
+	return ""
+}
+
+// Bounds returns the source lines of the section at location, e.g.
+// "Part III, Chapter 5": from its heading up to the next section not
+// inside it, or up to end for the last one.
+func (s *Structure) Bounds(location string, end int) (start, stop int, ok bool) {
+	for i, sec := range s.headings {
+		if sec.Location() != location {
+			continue
+		}
+		for _, next := range s.headings[i+1:] {
+			if !next.within(sec) {
+				return sec.Line, next.Line, true
+			}
+		}
+		return sec.Line, end, true
+	}
+	return 0, 0, false
+}
+
+// within reports whether s is inside ancestor.
+func (s *Section) within(ancestor *Section) bool {
+	for p := s.Parent; p != nil; p = p.Parent {
+		if p == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// isHeading reports whether a section starts at source line.
+func (s *Structure) isHeading(line int) bool {
+	if sec := s.At(line); sec != nil {
+		return sec.Line == line
+	}
+	return false
+}
+
+var (
+	// keywordHeading matches "PART III", "Book the First", "Chapter 5:
+	// Title", "ЧАСТЬ ПЕРВАЯ", "Глава II" and the like. The number is
+	// checked by parseNumber.
+	keywordHeading = regexp.MustCompile(`(?i)^(part|book|chapter|часть|книга|глава)\s+(?:the\s+)?([\p{L}0-9]+)\.?(?:\s*[.:—–-]\s*(.*?))?\.?$`)
+	// framingHeading matches "PROLOGUE" and "EPILOGUE", optionally titled,
+	// and their Russian forms, including the pre-reform "ЭПИЛОГЪ".
+	framingHeading = regexp.MustCompile(`(?i)^(prologue|epilogue|пролог|эпилог)ъ?\.?(?:\s*[.:—–-]\s*(.*?))?\.?$`)
+	// bareHeading matches an upper-case roman numeral alone, e.g. "IV.".
+	bareHeading = regexp.MustCompile(`^([IVXLC]+)\.?$`)
+)
+
+// maxHeadingLen bounds the length of heading lines, so a sentence that
+// happens to start with "Book" is not taken for one.
+const maxHeadingLen = 80
+
+// parseHeading parses a heading line, without its position.
+func parseHeading(line string) (*Section, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || utf8.RuneCountInString(line) > maxHeadingLen {
+		return nil, false
+	}
+
+	if m := keywordHeading.FindStringSubmatch(line); m != nil {
+		n, ok := parseNumber(m[2])
+		if !ok {
+			return nil, false
+		}
+		kind := KindChapter
+		switch strings.ToLower(m[1]) {
+		case "part", "часть":
+			kind = KindPart
+		case "book", "книга":
+			kind = KindBook
+		}
+		return &Section{Kind: kind, Number: n, Title: m[3]}, true
+	}
+
+	if m := framingHeading.FindStringSubmatch(line); m != nil {
+		kind := KindEpilogue
+		if strings.EqualFold(m[1], "prologue") || strings.EqualFold(m[1], "пролог") {
+			kind = KindPrologue
+		}
+		return &Section{Kind: kind, Title: m[2]}, true
+	}
+
+	if m := bareHeading.FindStringSubmatch(line); m != nil {
+		if n, ok := parseRoman(m[1]); ok {
+			return &Section{Kind: KindChapter, Number: n, bare: true}, true
+		}
+	}
+
+	return nil, false
+}
+
+var numberWords = map[string]int{
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+	"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5, "sixth": 6,
+	"seventh": 7, "eighth": 8, "ninth": 9, "tenth": 10, "eleventh": 11, "twelfth": 12,
+}
+
+// russianOrdinals are the stems of the Russian ordinals, which take an
+// ending by gender: "первая" (часть), "первый", "первое".
+var russianOrdinals = []struct {
+	stem   string
+	number int
+}{
+	{"перв", 1}, {"втор", 2}, {"трет", 3}, {"четверт", 4}, {"четвёрт", 4},
+	{"пят", 5}, {"шест", 6}, {"седьм", 7}, {"восьм", 8}, {"девят", 9},
+	{"десят", 10}, {"одиннадцат", 11}, {"двенадцат", 12},
+}
+
+// parseNumber reads the number of a heading: a roman numeral, digits, or
+// a number word such as "ONE", "First" or "ПЕРВАЯ".
+func parseNumber(s string) (int, bool) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, n > 0
+	}
+	lower := strings.ToLower(s)
+	if n, ok := numberWords[lower]; ok {
+		return n, true
+	}
+	for _, o := range russianOrdinals {
+		if ending, ok := strings.CutPrefix(lower, o.stem); ok && ending != "" && utf8.RuneCountInString(ending) <= 3 {
+			return o.number, true
+		}
+	}
+	return parseRoman(strings.ToUpper(s))
+}
+
+var romanValues = map[byte]int{'I': 1, 'V': 5, 'X': 10, 'L': 50, 'C': 100, 'D': 500, 'M': 1000}
+
+// parseRoman reads an upper-case roman numeral. Only canonical numerals
+// are accepted, so words like "DID" or "LID" are not.
+func parseRoman(s string) (int, bool) {
+	if s == "" {
+		return 0, false
+	}
+	n := 0
+	for i := 0; i < len(s); i++ {
+		v, ok := romanValues[s[i]]
+		if !ok {
+			return 0, false
+		}
+		if i+1 < len(s) && v < romanValues[s[i+1]] {
+			n -= v
+		} else {
+			n += v
+		}
+	}
+	if n <= 0 || toRoman(n) != s {
+		return 0, false
+	}
+	return n, true
+}
+
+// toRoman formats n as a roman numeral.
+func toRoman(n int) string {
+	numerals := []struct {
+		value  int
+		symbol string
+	}{
+		{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+		{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+		{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+	}
+	var b strings.Builder
+	for _, r := range numerals {
+		for n >= r.value {
+			b.WriteString(r.symbol)
+			n -= r.value
+		}
+	}
+	return b.String()
+}
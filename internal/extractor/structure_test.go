@@ -0,0 +1,262 @@
+package extractor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abdulachik/dostobot/internal/ingest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// locations returns the location of every line containing marker.
+func locations(s *Structure, lines []string, marker string) []string {
+	var locs []string
+	for i, line := range lines {
+		if strings.Contains(line, marker) {
+			locs = append(locs, s.Location(i))
+		}
+	}
+	return locs
+}
+
+func TestParseStructure(t *testing.T) {
+	t.Run("parts, chapters and an epilogue of numbered chapters", func(t *testing.T) {
+		// The layout of Crime and Punishment
+		lines := strings.Split(`*** START OF THE PROJECT GUTENBERG EBOOK ***
+
+PART I
+
+CHAPTER I
+
+On an exceptionally hot evening early in July a young man (quote)
+
+CHAPTER II
+
+He was not used to crowds. Book in hand, he went out. (quote)
+
+PART III
+
+CHAPTER V
+
+Raskolnikov was already entering the room. (quote)
+
+EPILOGUE
+
+I
+
+Siberia. On the banks of a broad, solitary river (quote)
+
+II
+
+He had been in prison nine months. (quote)
+
+*** END OF THE PROJECT GUTENBERG EBOOK ***`, "\n")
+
+		s := ParseStructure(lines)
+		assert.Equal(t, []string{
+			"Part I, Chapter 1",
+			"Part I, Chapter 2",
+			"Part III, Chapter 5",
+			"Epilogue, Chapter 1",
+			"Epilogue, Chapter 2",
+		}, locations(s, lines, "(quote)"))
+
+		require.Len(t, s.Sections, 3)
+		assert.Equal(t, KindPart, s.Sections[0].Kind)
+		assert.Len(t, s.Sections[0].Children, 2)
+		assert.Equal(t, KindEpilogue, s.Sections[2].Kind)
+		assert.Len(t, s.Sections[2].Children, 2)
+		assert.Equal(t, "", s.Location(1), "no location before the first heading")
+	})
+
+	t.Run("parts, books and titled chapters", func(t *testing.T) {
+		// The layout of The Brothers Karamazov
+		lines := strings.Split(`PART I
+
+Book I. The History Of A Family
+
+Chapter I. Fyodor Pavlovitch Karamazov
+
+Alexey Fyodorovitch Karamazov was the third son (quote)
+
+Book II. An Unfortunate Gathering
+
+Chapter III. Peasant Women Who Have Faith
+
+Near the wooden portico below (quote)
+
+PART II
+
+Book IV. Lacerations
+
+Chapter I. Father Ferapont
+
+It was very early, before daybreak (quote)`, "\n")
+
+		s := ParseStructure(lines)
+		assert.Equal(t, []string{
+			"Part I, Book I, Chapter 1",
+			"Part I, Book II, Chapter 3",
+			"Part II, Book IV, Chapter 1",
+		}, locations(s, lines, "(quote)"))
+
+		book := s.Sections[0].Children[0]
+		assert.Equal(t, KindBook, book.Kind)
+		assert.Equal(t, "The History Of A Family", book.Title)
+		assert.Equal(t, "Fyodor Pavlovitch Karamazov", book.Children[0].Title)
+		assert.Same(t, book, book.Children[0].Parent)
+	})
+
+	t.Run("numerals are chapters unless inside a chapter", func(t *testing.T) {
+		// The Idiot numbers its chapters "I."; The Possessed numbers
+		// sections within chapters "I", "II"
+		lines := strings.Split(`PART I
+
+I.
+
+Towards the end of November (quote)
+
+II.
+
+General Epanchin lived in his own house (quote)
+
+PART II
+
+CHAPTER I. INTRODUCTORY
+
+In undertaking to describe (quote)
+
+II
+
+He was, in fact, a man of great intellect (quote)`, "\n")
+
+		s := ParseStructure(lines)
+		assert.Equal(t, []string{
+			"Part I, Chapter 1",
+			"Part I, Chapter 2",
+			"Part II, Chapter 1",
+			"Part II, Chapter 1",
+		}, locations(s, lines, "(quote)"))
+	})
+
+	t.Run("prose is not mistaken for headings", func(t *testing.T) {
+		lines := strings.Split(`CHAPTER I
+
+Book in hand, he sat down by the window. (quote)
+
+Part of him wanted to leave.
+
+I.
+He was not sure. Then, as if in answer,
+I.
+
+DID
+
+said I.
+
+Book the First chapter of his life was over. (quote)`, "\n")
+
+		s := ParseStructure(lines)
+		require.Len(t, s.Sections, 1)
+		assert.Empty(t, s.Sections[0].Children)
+		assert.Equal(t, []string{"Chapter 1", "Chapter 1"}, locations(s, lines, "(quote)"))
+	})
+}
+
+func TestTOCStructure(t *testing.T) {
+	lines := strings.Split(`*** START OF THE PROJECT GUTENBERG EBOOK ***
+
+PART ONE
+
+A hot evening (quote)
+
+The Dream
+
+A dream of childhood (quote)
+
+PART TWO
+
+Another day (quote)
+
+*** END OF THE PROJECT GUTENBERG EBOOK ***
+
+License`, "\n")
+
+	s := TOCStructure(lines, []ingest.Chapter{
+		{Title: "Title page", Line: 0, Depth: 0},
+		{Title: "PART ONE", Line: 2, Depth: 0},
+		{Title: "The Dream", Line: 6, Depth: 1},
+		{Title: "PART TWO", Line: 10, Depth: 0},
+		{Title: "License", Line: 16, Depth: 0},
+	})
+
+	require.Len(t, s.Sections, 2)
+	assert.Equal(t, 2, s.Sections[1].Number)
+	assert.Equal(t, []string{
+		"Part I",
+		"Part I, The Dream",
+		"Part II",
+	}, locations(s, lines, "(quote)"))
+	assert.True(t, s.isHeading(6))
+}
+
+func TestParseHeading(t *testing.T) {
+	tests := []struct {
+		input string
+		label string // "" if not a heading
+	}{
+		{"CHAPTER I", "Chapter 1"},
+		{"Chapter 1", "Chapter 1"},
+		{"CHAPTER XII.", "Chapter 12"},
+		{"PART ONE", "Part I"},
+		{"PART III", "Part III"},
+		{"BOOK FIRST", "Book I"},
+		{"Book the Second", "Book II"},
+		{"Book VI. The Russian Monk", "Book VI"},
+		{"EPILOGUE", "Epilogue"},
+		{"PROLOGUE", "Prologue"},
+		{"IV.", "Chapter 4"},
+		{"XIV", "Chapter 14"},
+		{"ЧАСТЬ ПЕРВАЯ", "Part I"},
+		{"Часть третья", "Part III"},
+		{"КНИГА ВТОРАЯ", "Book II"},
+		{"ГЛАВА V", "Chapter 5"},
+		{"Глава четвёртая. Сон", "Chapter 4"},
+		{"ЭПИЛОГЪ", "Epilogue"},
+		{"Regular text", ""},
+		{"The chapter begins", ""},
+		{"Book in hand", ""},
+		{"Part of it", ""},
+		{"IIII", ""},
+		{"DID", ""},
+		{"i.", ""},
+		{"Часть жизни", ""},
+		{"Глава перевала", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			sec, ok := parseHeading(tt.input)
+			if tt.label == "" {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, tt.label, sec.Label())
+		})
+	}
+}
+
+func TestParseRoman(t *testing.T) {
+	for s, want := range map[string]int{"I": 1, "IV": 4, "IX": 9, "XIV": 14, "XL": 40, "MCMXC": 1990} {
+		n, ok := parseRoman(s)
+		assert.True(t, ok, s)
+		assert.Equal(t, want, n, s)
+		assert.Equal(t, s, toRoman(n))
+	}
+	for _, s := range []string{"", "IIII", "VX", "IC", "MIX!", "iv"} {
+		_, ok := parseRoman(s)
+		assert.False(t, ok, s)
+	}
+}
@@ -0,0 +1,371 @@
+package extractor
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/dedupe"
+	"github.com/abdulachik/dostobot/internal/ingest"
+	"github.com/abdulachik/dostobot/internal/llm"
+	"github.com/abdulachik/dostobot/internal/themes"
+)
+
+// maxAlignWords bounds the passage of a translation searched for a quote.
+// Longer chapters are narrowed to this many words around the position the
+// quote has in the original's chapter.
+const maxAlignWords = 3000
+
+// translationTool is the tool the LLM calls to return the passage of a
+// translation that renders a quote.
+var translationTool = llm.Tool{
+	Name:        "record_translation",
+	Description: "Record the sentences of the passage that translate the quote. Set found to false if the passage does not contain them.",
+	InputSchema: &llm.Schema{
+		Type:     "object",
+		Required: []string{"found", "text"},
+		Properties: map[string]*llm.Schema{
+			"found": {Type: "boolean", Description: "Whether the passage contains a translation of the quote"},
+			"text":  {Type: "string", Description: "The translated sentences, copied exactly from the passage, or empty"},
+		},
+	},
+}
+
+// FindTranslation asks the model for the sentences of text, a passage of a
+// translation in language translated, that render quote, a quote of the
+// original in language original. It returns "" if the passage has none.
+func FindTranslation(ctx context.Context, c llm.Completer, bookTitle, quote, original, translated, text string) (string, error) {
+	prompt := fmt.Sprintf(TranslationPrompt, LanguageName(original), bookTitle, quote, LanguageName(translated), text)
+
+	var result struct {
+		Found bool   `json:"found"`
+		Text  string `json:"text"`
+	}
+	if err := c.CompleteTool(ctx, TranslationSystemPrompt, prompt, translationTool, &result); err != nil {
+		return "", fmt.Errorf("complete: %w", err)
+	}
+
+	if !result.Found {
+		return "", nil
+	}
+	return strings.TrimSpace(result.Text), nil
+}
+
+// bookText is the text of a book with its structure.
+type bookText struct {
+	lines     []string
+	structure *Structure
+}
+
+// loadBook reads a catalog book's file.
+func (e *Extractor) loadBook(book *db.Book) (*bookText, error) {
+	doc, err := ingest.Load(filepath.Join(e.booksDir, book.FilePath))
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", book.Title, err)
+	}
+	return &bookText{lines: doc.Lines, structure: DocumentStructure(doc)}, nil
+}
+
+// AlignAll aligns the quotes of every catalog book that has a translation.
+func (e *Extractor) AlignAll(ctx context.Context) (int, error) {
+	books, err := e.store.ListBooks(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list books: %w", err)
+	}
+
+	titles := make(map[int64]string, len(books))
+	for _, book := range books {
+		titles[book.ID] = book.Title
+	}
+
+	linked := 0
+	aligned := make(map[int64]bool)
+	for _, book := range books {
+		if !book.OriginalID.Valid || aligned[book.OriginalID.Int64] {
+			continue
+		}
+		aligned[book.OriginalID.Int64] = true
+
+		n, err := e.AlignTranslations(ctx, titles[book.OriginalID.Int64])
+		linked += n
+		if err != nil {
+			if abortsExtraction(err) || ctx.Err() != nil {
+				return linked, err
+			}
+			slog.Error("failed to align book", "book", titles[book.OriginalID.Int64], "error", err)
+		}
+	}
+	return linked, nil
+}
+
+// AlignTranslations links the quotes of an original to the passages of its
+// translations that render them. Each translated passage is saved as a
+// quote of the translation, in its language, unless it is already stored,
+// and linked to the original quote. Quotes already linked to a quote of a
+// translation are skipped. It returns the number of links made.
+func (e *Extractor) AlignTranslations(ctx context.Context, originalTitle string) (int, error) {
+	original, err := e.store.GetBookByTitle(ctx, originalTitle)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("unknown book: %s (add it with 'dostobot book add')", originalTitle)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("find book: %w", err)
+	}
+
+	translations, err := e.store.ListBookTranslations(ctx, sql.NullInt64{Int64: original.ID, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("list translations: %w", err)
+	}
+	if len(translations) == 0 {
+		return 0, fmt.Errorf("%s has no translations (add one with 'dostobot book add --original')", originalTitle)
+	}
+
+	source, err := e.loadBook(original)
+	if err != nil {
+		return 0, err
+	}
+
+	linked := 0
+	for _, translation := range translations {
+		n, err := e.alignBook(ctx, original, source, translation)
+		linked += n
+		if err != nil {
+			if abortsExtraction(err) || ctx.Err() != nil {
+				return linked, err
+			}
+			slog.Error("failed to align translation",
+				"book", originalTitle,
+				"translation", translation.Title,
+				"error", err,
+			)
+		}
+	}
+
+	slog.Info("alignment complete",
+		"book", originalTitle,
+		"linked", linked,
+		"spent_usd", fmt.Sprintf("%.2f", e.budget.Spent()),
+	)
+	return linked, nil
+}
+
+// alignBook links the quotes of original that are not yet linked to a
+// quote of translation.
+func (e *Extractor) alignBook(ctx context.Context, original *db.Book, source *bookText, translation *db.Book) (int, error) {
+	quotes, err := e.store.ListUntranslatedQuotes(ctx, db.ListUntranslatedQuotesParams{
+		OriginalBook:    original.Title,
+		TranslationBook: translation.Title,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("list quotes: %w", err)
+	}
+	if len(quotes) == 0 {
+		return 0, nil
+	}
+
+	target, err := e.loadBook(translation)
+	if err != nil {
+		return 0, err
+	}
+	if err := e.indexExistingQuotes(ctx, translation.Title); err != nil {
+		return 0, err
+	}
+
+	slog.Info("aligning quotes", "book", original.Title, "translation", translation.Title, "quotes", len(quotes))
+
+	linked := 0
+	for _, q := range quotes {
+		chunk, ok := source.counterpart(q, target)
+		if !ok {
+			slog.Warn("quote location not found in translation, skipping",
+				"quote", q.ID,
+				"chapter", q.Chapter.String,
+				"translation", translation.Title,
+			)
+			continue
+		}
+
+		text, err := FindTranslation(ctx, e.llm, original.Title, q.Text, original.Language, translation.Language, chunk.Text)
+		if err != nil {
+			if abortsExtraction(err) || ctx.Err() != nil {
+				return linked, err
+			}
+			slog.Error("failed to find translation", "quote", q.ID, "error", err)
+			continue
+		}
+		if text == "" {
+			slog.Info("no translation found", "quote", q.ID, "translation", translation.Title)
+			continue
+		}
+
+		verbatim, span, ok := AlignQuote(chunk, text)
+		if !ok {
+			slog.Warn("translation not found in source text, dropping",
+				"quote", q.ID,
+				"translation", translation.Title,
+				"text", text,
+			)
+			continue
+		}
+
+		id, err := e.saveTranslation(ctx, translation, chunk, q, verbatim, span)
+		if err != nil {
+			slog.Error("failed to save translation", "quote", q.ID, "error", err)
+			continue
+		}
+		err = e.store.CreateQuoteTranslation(ctx, db.CreateQuoteTranslationParams{
+			OriginalID:    q.ID,
+			TranslationID: id,
+			Edits:         sql.NullInt64{Int64: int64(span.Edits), Valid: true},
+		})
+		if err != nil {
+			slog.Error("failed to link translation", "quote", q.ID, "translation", id, "error", err)
+			continue
+		}
+		linked++
+	}
+
+	return linked, nil
+}
+
+// counterpart returns the passage of target to search for the translation
+// of q, a quote of b: the chapter at q's location, narrowed to about
+// maxAlignWords words around the position q has in its own chapter.
+// Sections are labelled alike in every language, so the location of the
+// original names the chapter of the translation.
+func (b *bookText) counterpart(q *db.Quote, target *bookText) (Chunk, bool) {
+	if !q.Chapter.Valid || q.Chapter.String == "" {
+		return Chunk{}, false
+	}
+
+	at := 0.5
+	_, bodyEnd := gutenbergBody(b.lines)
+	if start, stop, ok := b.structure.Bounds(q.Chapter.String, bodyEnd); ok && q.SourceStartLine.Valid {
+		at = wordFraction(b.lines, start, stop, int(q.SourceStartLine.Int64)-1)
+	}
+
+	_, bodyEnd = gutenbergBody(target.lines)
+	start, stop, ok := target.structure.Bounds(q.Chapter.String, bodyEnd)
+	if !ok {
+		return Chunk{}, false
+	}
+	start, stop = alignWindow(target.lines, start, stop, at)
+
+	return newChunk(target.structure, strings.Join(target.lines[start:stop], "\n"), start, stop-1, 0), true
+}
+
+// wordFraction returns how far into lines[start:stop] line is, by words.
+func wordFraction(lines []string, start, stop, line int) float64 {
+	line = min(max(line, start), stop)
+	before, total := 0, 0
+	for i := start; i < stop; i++ {
+		n := countWords(lines[i])
+		if i < line {
+			before += n
+		}
+		total += n
+	}
+	if total == 0 {
+		return 0.5
+	}
+	return float64(before) / float64(total)
+}
+
+// alignWindow narrows lines[start:stop] to about maxAlignWords words
+// centred at fraction at of its words. Short ranges are kept whole.
+func alignWindow(lines []string, start, stop int, at float64) (int, int) {
+	words := make([]int, stop-start)
+	total := 0
+	for i := range words {
+		words[i] = countWords(lines[start+i])
+		total += words[i]
+	}
+	if total <= maxAlignWords {
+		return start, stop
+	}
+
+	lo := int(at*float64(total)) - maxAlignWords/2
+	lo = min(max(lo, 0), total-maxAlignWords)
+	hi := lo + maxAlignWords
+
+	from, to := start, stop
+	seen := 0
+	for i, n := range words {
+		if seen <= lo {
+			from = start + i
+		}
+		seen += n
+		if seen >= hi {
+			to = start + i + 1
+			break
+		}
+	}
+	return from, to
+}
+
+// saveTranslation saves the verbatim passage of a translation that renders
+// the quote original, with the original's character, themes and
+// relevance, and returns its id. A passage already stored, exactly or
+// nearly, is not saved again; the stored quote's id is returned.
+func (e *Extractor) saveTranslation(ctx context.Context, book *db.Book, chunk Chunk, original *db.Quote, text string, span Span) (int64, error) {
+	textHash := quoteHash(text)
+	existing, err := e.store.GetQuoteByHash(ctx, textHash)
+	if err == nil {
+		return existing.ID, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, fmt.Errorf("check existing quote: %w", err)
+	}
+
+	sig := dedupe.Sign(text)
+	if id, _, dup := e.dups.Match(sig, dedupe.DefaultThreshold); dup {
+		return id, nil
+	}
+
+	// Credit the character by its name in the translation if its registry
+	// has the original's name as an alias. The original's name is not
+	// registered as a character of the translation.
+	cast, err := e.loadCast(ctx, book.Title)
+	if err != nil {
+		return 0, err
+	}
+	character := original.Character
+	if c, ok := cast.Lookup(original.Character.String); ok && character.Valid {
+		character.String = c.Name
+	}
+
+	location := chunk.LocationAt(span.StartLine - 1)
+	created, err := e.store.CreateQuote(ctx, db.CreateQuoteParams{
+		Text:            text,
+		TextHash:        textHash,
+		SourceBook:      book.Title,
+		Chapter:         sql.NullString{String: location, Valid: location != ""},
+		Character:       character,
+		Themes:          original.Themes,
+		ModernRelevance: original.ModernRelevance,
+		CharCount:       int64(utf8.RuneCountInString(text)),
+		SourceStartLine: sql.NullInt64{Int64: int64(span.StartLine), Valid: true},
+		SourceEndLine:   sql.NullInt64{Int64: int64(span.EndLine), Valid: true},
+		VerbatimEdits:   sql.NullInt64{Int64: int64(span.Edits), Valid: true},
+		Language:        book.Language,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("create quote: %w", err)
+	}
+
+	e.dups.Add(created.ID, sig)
+
+	taxonomy, err := e.loadTaxonomy(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := taxonomy.Tag(ctx, e.store.Queries, created.ID, themes.Parse(original)); err != nil {
+		return 0, err
+	}
+	return created.ID, nil
+}
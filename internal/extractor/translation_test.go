@@ -0,0 +1,134 @@
+package extractor
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractor_AlignTranslations(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, filepath.Join(tmpDir, "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	require.NoError(t, store.Migrate(ctx))
+
+	original, err := store.CreateBook(ctx, db.CreateBookParams{
+		Title:    "Белые ночи",
+		Author:   "Фёдор Достоевский",
+		FilePath: "belye-nochi.txt",
+		Language: "ru",
+	})
+	require.NoError(t, err)
+	translation, err := store.CreateBook(ctx, db.CreateBookParams{
+		Title:    "White Nights",
+		Author:   "Fyodor Dostoyevsky",
+		FilePath: "white-nights.txt",
+		Language: "en",
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.SetBookOriginal(ctx, db.SetBookOriginalParams{
+		OriginalID: sql.NullInt64{Int64: original.ID, Valid: true},
+		ID:         translation.ID,
+	}))
+
+	russian := "НОЧЬ ПЕРВАЯ\n\nГЛАВА I\n\nБыла чудная ночь.\n\nГЛАВА II\n\nБоже мой! Минута блаженства! Да разве этого мало хоть бы и на всю жизнь человеческую?\n"
+	english := "NIGHT THE FIRST\n\nCHAPTER I\n\nIt was a wonderful night.\n\nCHAPTER II\n\nMy God, a moment of bliss!\nWhy, isn't that enough for the whole of a man's life?\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "belye-nochi.txt"), []byte(russian), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "white-nights.txt"), []byte(english), 0o644))
+
+	quote, err := store.CreateQuote(ctx, db.CreateQuoteParams{
+		Text:            "Боже мой! Минута блаженства! Да разве этого мало хоть бы и на всю жизнь человеческую?",
+		TextHash:        "h1",
+		SourceBook:      original.Title,
+		Chapter:         sql.NullString{String: "Chapter 2", Valid: true},
+		Character:       sql.NullString{String: "Narrator", Valid: true},
+		Themes:          `["happiness"]`,
+		SourceStartLine: sql.NullInt64{Int64: 9, Valid: true},
+		Language:        "ru",
+	})
+	require.NoError(t, err)
+
+	fake := &llm.Fake{ToolInputs: map[string]string{
+		translationTool.Name: `{"found": true, "text": "My God, a moment of bliss! Why, isn't that enough for the whole of a man's life"}`,
+	}}
+	extractor := New(Config{Store: store, LLM: fake, BooksDir: tmpDir})
+
+	linked, err := extractor.AlignTranslations(ctx, original.Title)
+	require.NoError(t, err)
+	assert.Equal(t, 1, linked)
+
+	calls := fake.Calls()
+	require.Len(t, calls, 1)
+	assert.Contains(t, calls[0].User, "Russian original")
+	assert.Contains(t, calls[0].User, "English translation")
+	assert.Contains(t, calls[0].User, "a moment of bliss")
+	assert.NotContains(t, calls[0].User, "wonderful night", "only the quote's chapter is searched")
+
+	translated, err := store.ListQuoteTranslations(ctx, quote.ID)
+	require.NoError(t, err)
+	require.Len(t, translated, 1)
+	assert.Equal(t, "My God, a moment of bliss! Why, isn't that enough for the whole of a man's life?", translated[0].Text)
+	assert.Equal(t, "en", translated[0].Language)
+	assert.Equal(t, "White Nights", translated[0].SourceBook)
+	assert.Equal(t, "Chapter 2", translated[0].Chapter.String)
+	assert.Equal(t, "Narrator", translated[0].Character.String)
+	assert.Equal(t, int64(9), translated[0].SourceStartLine.Int64)
+
+	back, err := store.GetQuoteOriginal(ctx, translated[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, quote.ID, back.ID)
+
+	// Linked quotes are not aligned again
+	linked, err = extractor.AlignTranslations(ctx, original.Title)
+	require.NoError(t, err)
+	assert.Zero(t, linked)
+	assert.Len(t, fake.Calls(), 1)
+}
+
+func TestExtractor_AlignTranslations_NoTranslations(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, filepath.Join(tmpDir, "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	require.NoError(t, store.Migrate(ctx))
+
+	_, err = store.CreateBook(ctx, db.CreateBookParams{Title: "Бесы", Author: "Фёдор Достоевский", FilePath: "besy.txt", Language: "ru"})
+	require.NoError(t, err)
+
+	extractor := New(Config{Store: store, LLM: &llm.Fake{}, BooksDir: tmpDir})
+	_, err = extractor.AlignTranslations(ctx, "Бесы")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no translations")
+}
+
+func TestAlignWindow(t *testing.T) {
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = strings.Repeat("word ", 100)
+	}
+
+	start, stop := alignWindow(lines, 10, 20, 0.5)
+	assert.Equal(t, 10, start, "short ranges are kept whole")
+	assert.Equal(t, 20, stop)
+
+	start, stop = alignWindow(lines, 0, 100, 0.5)
+	assert.Equal(t, 35, start)
+	assert.Equal(t, 65, stop)
+
+	start, stop = alignWindow(lines, 0, 100, 0.99)
+	assert.Equal(t, 70, start, "the window stays inside the range")
+	assert.Equal(t, 100, stop)
+}
@@ -0,0 +1,81 @@
+package extractor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/llm"
+)
+
+// Validation is the quality rubric's verdict on a quote.
+type Validation struct {
+	Standalone         bool     `json:"standalone"`
+	AppropriateLength  bool     `json:"appropriate_length"`
+	UniversalWisdom    bool     `json:"universal_wisdom"`
+	SuitableForPosting bool     `json:"suitable_for_posting"`
+	OverallQuality     int      `json:"overall_quality"`
+	Issues             []string `json:"issues"`
+	Recommendation     string   `json:"recommendation"` // db.VerdictApprove, db.VerdictReject or db.VerdictEdit
+}
+
+// validationTool is the tool the LLM calls to record its review of a quote.
+var validationTool = llm.Tool{
+	Name:        "record_validation",
+	Description: "Record the quality review of the quote.",
+	InputSchema: &llm.Schema{
+		Type: "object",
+		Required: []string{
+			"standalone", "appropriate_length", "universal_wisdom", "suitable_for_posting",
+			"overall_quality", "issues", "recommendation",
+		},
+		Properties: map[string]*llm.Schema{
+			"standalone":           {Type: "boolean", Description: "Makes sense without knowing the plot"},
+			"appropriate_length":   {Type: "boolean", Description: "Between 100 and 500 characters"},
+			"universal_wisdom":     {Type: "boolean", Description: "Contains wisdom applicable today"},
+			"suitable_for_posting": {Type: "boolean", Description: "Could appear on a literary quotes account"},
+			"overall_quality":      {Type: "integer", Minimum: llm.Float(1), Maximum: llm.Float(10)},
+			"issues":               {Type: "array", Description: "Any problems with the quote", Items: &llm.Schema{Type: "string"}},
+			"recommendation":       {Type: "string", Enum: []string{db.VerdictApprove, db.VerdictEdit, db.VerdictReject}},
+		},
+	},
+}
+
+// ValidateQuote reviews a quote against the quality rubric with the given model.
+func ValidateQuote(ctx context.Context, c llm.Completer, text, source, character string) (*Validation, error) {
+	if character == "" {
+		character = "Unknown"
+	}
+	prompt := fmt.Sprintf(ValidationPrompt, text, source, character)
+
+	var v Validation
+	if err := c.CompleteTool(ctx, ValidationSystemPrompt, prompt, validationTool, &v); err != nil {
+		return nil, fmt.Errorf("complete: %w", err)
+	}
+	return &v, nil
+}
+
+// SaveValidation stores a verdict in the quote's quality columns.
+func SaveValidation(ctx context.Context, store *db.Store, quoteID int64, v *Validation) error {
+	issues := v.Issues
+	if issues == nil {
+		issues = []string{}
+	}
+	issuesJSON, err := json.Marshal(issues)
+	if err != nil {
+		return fmt.Errorf("marshal issues: %w", err)
+	}
+
+	err = store.UpdateQuoteValidation(ctx, db.UpdateQuoteValidationParams{
+		QualityScore:   sql.NullInt64{Int64: int64(v.OverallQuality), Valid: true},
+		QualityIssues:  sql.NullString{String: string(issuesJSON), Valid: true},
+		QualityVerdict: sql.NullString{String: v.Recommendation, Valid: true},
+		ID:             quoteID,
+	})
+	if err != nil {
+		return fmt.Errorf("update quote validation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package extractor
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rejectedReview = `{
+	"standalone": false, "appropriate_length": true, "universal_wisdom": false, "suitable_for_posting": false,
+	"overall_quality": 3, "issues": ["depends on the plot"], "recommendation": "reject"
+}`
+
+func TestValidateQuote(t *testing.T) {
+	fake := &llm.Fake{ToolInputs: map[string]string{validationTool.Name: rejectedReview}}
+
+	v, err := ValidateQuote(context.Background(), fake, "Give me the letter, Varvara.", "Poor Folk", "")
+	require.NoError(t, err)
+	assert.False(t, v.Standalone)
+	assert.Equal(t, 3, v.OverallQuality)
+	assert.Equal(t, []string{"depends on the plot"}, v.Issues)
+	assert.Equal(t, db.VerdictReject, v.Recommendation)
+
+	calls := fake.Calls()
+	require.Len(t, calls, 1)
+	assert.Contains(t, calls[0].User, "Give me the letter, Varvara.")
+	assert.Contains(t, calls[0].User, "Character: Unknown")
+
+	t.Run("rejects an unknown verdict", func(t *testing.T) {
+		fake := &llm.Fake{ToolInputs: map[string]string{validationTool.Name: `{
+			"standalone": true, "appropriate_length": true, "universal_wisdom": true, "suitable_for_posting": true,
+			"overall_quality": 8, "issues": [], "recommendation": "maybe"
+		}`}}
+
+		_, err := ValidateQuote(context.Background(), fake, "text", "Poor Folk", "Narrator")
+		assert.ErrorIs(t, err, llm.ErrInvalidToolInput)
+	})
+}
+
+func TestExtractor_saveQuote_WithValidation(t *testing.T) {
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	require.NoError(t, store.Migrate(ctx))
+
+	fake := &llm.Fake{ToolInputs: map[string]string{validationTool.Name: rejectedReview}}
+	ext := New(Config{Store: store, LLM: &llm.Fake{}, Validator: fake})
+
+	quotes := []candidate{{ExtractedQuote: ExtractedQuote{Text: "Give me the letter, Varvara.", Character: "Makar", Themes: []string{"letters"}}}}
+	require.NoError(t, ext.validateQuotes(ctx, "Poor Folk", quotes))
+	require.NotNil(t, quotes[0].validation)
+	require.NoError(t, ext.saveQuote(ctx, &db.Book{Title: "Poor Folk", Language: "en"}, Chunk{}, quotes[0]))
+
+	saved, err := store.GetQuoteByHash(ctx, quoteHash(quotes[0].Text))
+	require.NoError(t, err)
+	assert.True(t, saved.Rejected())
+	assert.Equal(t, int64(3), saved.QualityScore.Int64)
+	assert.JSONEq(t, `["depends on the plot"]`, saved.QualityIssues.String)
+	assert.True(t, saved.ValidatedAt.Valid)
+
+	// Stored quotes are not reviewed again.
+	require.NoError(t, ext.validateQuotes(ctx, "Poor Folk", quotes))
+	assert.Len(t, fake.Calls(), 1)
+
+	unvalidated, err := store.ListUnvalidatedQuotes(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, unvalidated)
+
+	// Rejected quotes never reach the matcher's in-memory index.
+	require.NoError(t, store.UpdateQuoteEmbedding(ctx, db.UpdateQuoteEmbeddingParams{Embedding: []byte{0, 0, 128, 63}, ID: saved.ID}))
+	withEmbeddings, err := store.ListQuotesWithEmbeddings(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, withEmbeddings)
+}
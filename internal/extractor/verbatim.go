@@ -0,0 +1,153 @@
+package extractor
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxVerbatimEditRatio is the largest word edit distance, relative to the
+// quote's length, at which an extracted quote is still taken to be the
+// source passage with slips (a changed word, dropped punctuation, a
+// modernised spelling) rather than a paraphrase or invention.
+const maxVerbatimEditRatio = 0.2
+
+// Span locates a quote in the source book.
+type Span struct {
+	Start, End         int // byte offsets of the passage in Chunk.Text
+	StartLine, EndLine int // 1-based lines of the passage in the book's text
+	Edits              int // word edits between the extracted text and the passage
+}
+
+// token is a word of text with its byte offsets.
+type token struct {
+	word       string
+	start, end int
+}
+
+// tokenize splits text into lowercase words, ignoring punctuation, quote
+// marks, Gutenberg _italics_ and line wrapping, so only the words have to
+// match. Apostrophes inside words are kept and curly ones straightened.
+func tokenize(text string) []token {
+	var tokens []token
+	var word strings.Builder
+	start := -1
+
+	flush := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, token{word: word.String(), start: start, end: end})
+			word.Reset()
+			start = -1
+		}
+	}
+
+	for i, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if start < 0 {
+				start = i
+			}
+			word.WriteRune(unicode.ToLower(r))
+		case (r == '\'' || r == '’') && start >= 0 && nextIsLetter(text, i+utf8.RuneLen(r)):
+			word.WriteRune('\'')
+		default:
+			flush(i)
+		}
+	}
+	flush(len(text))
+
+	return tokens
+}
+
+func nextIsLetter(text string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	return unicode.IsLetter(r)
+}
+
+// AlignQuote finds the passage of chunk that an extracted quote was taken
+// from. It aligns the quote's words against the chunk with a word-level
+// edit distance in which the passage may start and end anywhere in the
+// chunk (semi-global alignment). When the best passage is within
+// maxVerbatimEditRatio it returns the passage's original text, whitespace
+// normalised, and its location; otherwise the quote is not in the source
+// and ok is false.
+func AlignQuote(chunk Chunk, quote string) (text string, span Span, ok bool) {
+	q := tokenize(quote)
+	c := tokenize(chunk.Text)
+	if len(q) == 0 || len(c) == 0 {
+		return "", Span{}, false
+	}
+
+	// dist[i][j] is the cost of aligning q[:i] to a passage ending at c[j-1];
+	// from[i][j] is the index in c where that passage starts.
+	n, m := len(q), len(c)
+	prev, cur := make([]int, m+1), make([]int, m+1)
+	prevFrom, curFrom := make([]int, m+1), make([]int, m+1)
+	for j := 0; j <= m; j++ {
+		prev[j], prevFrom[j] = 0, j // the passage may start anywhere
+	}
+
+	for i := 1; i <= n; i++ {
+		cur[0], curFrom[0] = i, 0
+		for j := 1; j <= m; j++ {
+			// substitute or match
+			best, from := prev[j-1], prevFrom[j-1]
+			if q[i-1].word != c[j-1].word {
+				best++
+			}
+			// quote word missing from the passage
+			if d := prev[j] + 1; d < best {
+				best, from = d, prevFrom[j]
+			}
+			// passage word missing from the quote
+			if d := cur[j-1] + 1; d < best {
+				best, from = d, curFrom[j-1]
+			}
+			cur[j], curFrom[j] = best, from
+		}
+		prev, cur = cur, prev
+		prevFrom, curFrom = curFrom, prevFrom
+	}
+
+	end := 1
+	for j := 2; j <= m; j++ {
+		if prev[j] < prev[end] {
+			end = j
+		}
+	}
+	edits, first := prev[end], prevFrom[end]
+	if first >= end || float64(edits) > maxVerbatimEditRatio*float64(n) {
+		return "", Span{}, false
+	}
+
+	span = Span{
+		Start: c[first].start,
+		End:   extendPunctuation(chunk.Text, c[end-1].end),
+		Edits: edits,
+	}
+	span.StartLine = chunk.StartLine + strings.Count(chunk.Text[:span.Start], "\n") + 1
+	span.EndLine = chunk.StartLine + strings.Count(chunk.Text[:span.End], "\n") + 1
+
+	return normalizePassage(chunk.Text[span.Start:span.End]), span, true
+}
+
+// extendPunctuation moves end past the punctuation closing the last word,
+// so a passage keeps its final full stop. Quote marks are left out: the
+// opening mark is never part of the passage either.
+func extendPunctuation(text string, end int) int {
+	for end < len(text) {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if !strings.ContainsRune(".,;:!?…)", r) {
+			break
+		}
+		end += size
+	}
+	return end
+}
+
+// normalizePassage joins the lines Gutenberg wrapped at 70 columns into a
+// single line and drops _italic_ markers.
+func normalizePassage(text string) string {
+	text = strings.ReplaceAll(text, "_", "")
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,81 @@
+package extractor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// verbatimChunk is a Gutenberg-wrapped passage starting on line 101 of its book.
+var verbatimChunk = Chunk{
+	StartLine: 100,
+	Text: strings.Join([]string{
+		"The old woman was only a sickness... I was in a hurry to overstep... I",
+		"didn't kill a human being, but a principle! I killed the principle, but",
+		"I didn't overstep, I stopped on this side.",
+		"",
+		"“Pain and suffering are always inevitable for a _large_ intelligence",
+		"and a deep heart. The really great men must, I think, have great sadness",
+		"on earth,” he added dreamily.",
+	}, "\n"),
+}
+
+func TestAlignQuote(t *testing.T) {
+	t.Run("exact quote across wrapped lines", func(t *testing.T) {
+		text, span, ok := AlignQuote(verbatimChunk, "Pain and suffering are always inevitable for a large intelligence and a deep heart.")
+		assert.True(t, ok)
+		assert.Equal(t, "Pain and suffering are always inevitable for a large intelligence and a deep heart.", text)
+		assert.Equal(t, 0, span.Edits)
+		assert.Equal(t, 105, span.StartLine)
+		assert.Equal(t, 106, span.EndLine)
+		assert.Equal(t, "Pain", verbatimChunk.Text[span.Start:span.Start+4])
+	})
+
+	t.Run("corrects small slips to the original", func(t *testing.T) {
+		text, span, ok := AlignQuote(verbatimChunk, "Pain and suffering is always inevitable for a large intellect and a deep heart")
+		assert.True(t, ok)
+		assert.Equal(t, "Pain and suffering are always inevitable for a large intelligence and a deep heart.", text)
+		assert.Equal(t, 2, span.Edits)
+	})
+
+	t.Run("normalises punctuation and curly apostrophes", func(t *testing.T) {
+		text, span, ok := AlignQuote(verbatimChunk, "I didn’t kill a human being, but a principle!")
+		assert.True(t, ok)
+		assert.Equal(t, "I didn't kill a human being, but a principle!", text)
+		assert.Equal(t, 0, span.Edits)
+		assert.Equal(t, 101, span.StartLine)
+		assert.Equal(t, 102, span.EndLine)
+	})
+
+	t.Run("rejects paraphrase", func(t *testing.T) {
+		_, _, ok := AlignQuote(verbatimChunk, "Great minds and deep hearts are always destined to suffer on this earth.")
+		assert.False(t, ok)
+	})
+
+	t.Run("rejects sentences stitched from different places", func(t *testing.T) {
+		_, _, ok := AlignQuote(verbatimChunk, "I killed the principle. The really great men must have great sadness on earth.")
+		assert.False(t, ok)
+	})
+
+	t.Run("rejects invention", func(t *testing.T) {
+		_, _, ok := AlignQuote(verbatimChunk, "To go wrong in one's own way is better than to go right in someone else's.")
+		assert.False(t, ok)
+	})
+}
+
+func TestChunker_LinesAreSourceRelative(t *testing.T) {
+	lines := []string{"Project Gutenberg header", "*** START OF THE PROJECT GUTENBERG EBOOK ***", "", ""}
+	for i := 0; i < 40; i++ {
+		lines = append(lines, "word word word word word word word word word word", "")
+	}
+
+	chunker := NewChunker(ChunkerConfig{TargetWords: 100, OverlapWords: 10, MinWords: 50})
+	chunks := chunker.ChunkLines(lines)
+	assert.NotEmpty(t, chunks)
+
+	for _, c := range chunks {
+		first := strings.SplitN(c.Text, "\n", 2)[0]
+		assert.Equal(t, lines[c.StartLine], first, "chunk %d", c.ChunkIndex)
+	}
+}
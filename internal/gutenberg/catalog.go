@@ -0,0 +1,193 @@
+// Package gutenberg reads Project Gutenberg's offline catalog and downloads
+// plain-text ebooks from it.
+//
+// The catalog is the CSV file published at
+// https://www.gutenberg.org/cache/epub/feeds/pg_catalog.csv; a local copy
+// lets works and translations be searched without network access.
+package gutenberg
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Entry is one work in the catalog.
+type Entry struct {
+	ID          int64
+	Type        string // "Text" for ebooks
+	Issued      string // release date, YYYY-MM-DD
+	Title       string
+	Language    string   // e.g. "en"; several are joined with "; "
+	Authors     []string // display names, e.g. "Fyodor Dostoyevsky"
+	Translators []string
+}
+
+// Author returns the first author, or "" if the work has none.
+func (e Entry) Author() string {
+	if len(e.Authors) == 0 {
+		return ""
+	}
+	return e.Authors[0]
+}
+
+// Translator returns the translators joined with " and ", or "".
+func (e Entry) Translator() string {
+	return strings.Join(e.Translators, " and ")
+}
+
+// catalogColumns are the columns of pg_catalog.csv that are read.
+var catalogColumns = []string{"Text#", "Type", "Issued", "Title", "Language", "Authors"}
+
+// LoadCatalog reads the catalog CSV at path.
+func LoadCatalog(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open catalog: %w", err)
+	}
+	defer f.Close()
+
+	return ParseCatalog(f)
+}
+
+// ParseCatalog parses the Gutenberg catalog CSV.
+func ParseCatalog(r io.Reader) ([]Entry, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read catalog header: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.TrimPrefix(name, "\ufeff")] = i
+	}
+	for _, name := range catalogColumns {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("catalog has no %q column", name)
+		}
+	}
+
+	var entries []Entry
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read catalog: %w", err)
+		}
+
+		field := func(name string) string {
+			if i := index[name]; i < len(record) {
+				return strings.Join(strings.Fields(record[i]), " ")
+			}
+			return ""
+		}
+
+		id, err := strconv.ParseInt(field("Text#"), 10, 64)
+		if err != nil {
+			continue // malformed row
+		}
+		e := Entry{
+			ID:       id,
+			Type:     field("Type"),
+			Issued:   field("Issued"),
+			Title:    field("Title"),
+			Language: field("Language"),
+		}
+		e.Authors, e.Translators = parseContributors(field("Authors"))
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// parseContributors splits the catalog's Authors field, e.g.
+// "Dostoyevsky, Fyodor, 1821-1881; Garnett, Constance, 1861-1946 [Translator]",
+// into authors and translators. Editors, illustrators and other roles are
+// dropped.
+func parseContributors(field string) (authors, translators []string) {
+	for _, c := range strings.Split(field, ";") {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+
+		role := ""
+		if i := strings.LastIndex(c, "["); i >= 0 && strings.HasSuffix(c, "]") {
+			role = strings.TrimSpace(c[i+1 : len(c)-1])
+			c = strings.TrimSpace(c[:i])
+		}
+
+		name := DisplayName(c)
+		switch role {
+		case "", "Author":
+			authors = append(authors, name)
+		case "Translator":
+			translators = append(translators, name)
+		}
+	}
+	return authors, translators
+}
+
+// DisplayName turns a catalog name, "Dostoyevsky, Fyodor, 1821-1881", into
+// "Fyodor Dostoyevsky". Life dates are dropped.
+func DisplayName(name string) string {
+	var parts []string
+	for _, p := range strings.Split(name, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" || strings.IndexFunc(p, unicode.IsDigit) >= 0 {
+			continue
+		}
+		parts = append(parts, p)
+	}
+	if len(parts) >= 2 {
+		return parts[1] + " " + parts[0]
+	}
+	return strings.Join(parts, " ")
+}
+
+// Search returns the text works by an author whose name contains author,
+// ignoring case, sorted by title and then ID.
+func Search(entries []Entry, author string) []Entry {
+	needle := strings.ToLower(strings.TrimSpace(author))
+
+	var found []Entry
+	for _, e := range entries {
+		if e.Type != "" && e.Type != "Text" {
+			continue
+		}
+		for _, a := range e.Authors {
+			if strings.Contains(strings.ToLower(a), needle) {
+				found = append(found, e)
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].Title != found[j].Title {
+			return found[i].Title < found[j].Title
+		}
+		return found[i].ID < found[j].ID
+	})
+	return found
+}
+
+// Find returns the entry with the given ebook number.
+func Find(entries []Entry, id int64) (Entry, bool) {
+	for _, e := range entries {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
@@ -0,0 +1,68 @@
+package gutenberg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const catalogCSV = "\ufeffText#,Type,Issued,Title,Language,Authors,Subjects,LoCC,Bookshelves\n" +
+	`2554,Text,2006-03-28,Crime and Punishment,en,"Dostoyevsky, Fyodor, 1821-1881; Garnett, Constance, 1861-1946 [Translator]",Psychological fiction,PG,Best Books Ever Listings` + "\n" +
+	`36034,Text,2011-05-06,"White Nights
+and Other Stories",en,"Dostoyevsky, Fyodor, 1821-1881; Garnett, Constance, 1861-1946 [Translator]",Short stories,PG,` + "\n" +
+	`46221,Sound,2014-07-15,Crime and Punishment,en,"Dostoyevsky, Fyodor, 1821-1881",,,` + "\n" +
+	`2600,Text,2001-04-01,War and Peace,en,"Tolstoy, Leo, graf, 1828-1910; Maude, Aylmer, 1858-1938 [Translator]; Maude, Louise, 1855-1939 [Translator]",,PG,` + "\n" +
+	`abc,Text,,Broken row,en,,,,` + "\n" +
+	`2638,Text,2001-06-01,The Idiot,en,"Dostoyevsky, Fyodor, 1821-1881; Martin, Eva [Translator]; Someone, Else [Illustrator]",,PG,` + "\n"
+
+func TestParseCatalog(t *testing.T) {
+	entries, err := ParseCatalog(strings.NewReader(catalogCSV))
+	require.NoError(t, err)
+	require.Len(t, entries, 5, "malformed rows are skipped")
+
+	crime := entries[0]
+	assert.Equal(t, int64(2554), crime.ID)
+	assert.Equal(t, "Text", crime.Type)
+	assert.Equal(t, "2006-03-28", crime.Issued)
+	assert.Equal(t, "Crime and Punishment", crime.Title)
+	assert.Equal(t, "en", crime.Language)
+	assert.Equal(t, "Fyodor Dostoyevsky", crime.Author())
+	assert.Equal(t, "Constance Garnett", crime.Translator())
+
+	assert.Equal(t, "White Nights and Other Stories", entries[1].Title, "line breaks in titles are joined")
+
+	war, ok := Find(entries, 2600)
+	require.True(t, ok)
+	assert.Equal(t, "Leo Tolstoy", war.Author(), "titles and dates are dropped from names")
+	assert.Equal(t, "Aylmer Maude and Louise Maude", war.Translator())
+
+	idiot, ok := Find(entries, 2638)
+	require.True(t, ok)
+	assert.Equal(t, []string{"Fyodor Dostoyevsky"}, idiot.Authors)
+	assert.Equal(t, []string{"Eva Martin"}, idiot.Translators)
+
+	_, ok = Find(entries, 1)
+	assert.False(t, ok)
+}
+
+func TestParseCatalog_MissingColumn(t *testing.T) {
+	_, err := ParseCatalog(strings.NewReader("Text#,Title\n1,A\n"))
+	assert.ErrorContains(t, err, "Type")
+}
+
+func TestSearch(t *testing.T) {
+	entries, err := ParseCatalog(strings.NewReader(catalogCSV))
+	require.NoError(t, err)
+
+	works := Search(entries, "dostoyevsky")
+	var ids []int64
+	for _, e := range works {
+		ids = append(ids, e.ID)
+	}
+	assert.Equal(t, []int64{2554, 2638, 36034}, ids, "text works only, sorted by title")
+
+	assert.Empty(t, Search(entries, "Garnett"), "translators are not authors")
+	assert.Len(t, Search(entries, "Tolstoy"), 1)
+}
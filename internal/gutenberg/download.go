@@ -0,0 +1,247 @@
+package gutenberg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// defaultBaseURL is the Project Gutenberg site; any mirror with the same
+// cache/epub layout works.
+const defaultBaseURL = "https://www.gutenberg.org"
+
+// ErrChecksumMismatch is returned when a downloaded text differs from the
+// edition recorded for the book.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// errNotRetryable marks download failures that retrying cannot fix.
+var errNotRetryable = errors.New("not retryable")
+
+// Client downloads plain-text ebooks.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	retries    int
+	backoff    time.Duration
+}
+
+// ClientConfig holds configuration for the download client.
+type ClientConfig struct {
+	BaseURL string // default: https://www.gutenberg.org
+
+	// Retries is the number of retries after a failed attempt (default 3);
+	// Backoff the delay before the first retry, doubled each time (default 2s).
+	Retries int
+	Backoff time.Duration
+}
+
+// NewClient creates a download client.
+func NewClient(cfg ClientConfig) *Client {
+	baseURL := strings.TrimSuffix(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	retries := cfg.Retries
+	if retries <= 0 {
+		retries = 3
+	}
+	backoff := cfg.Backoff
+	if backoff <= 0 {
+		backoff = 2 * time.Second
+	}
+
+	return &Client{
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		baseURL:    baseURL,
+		retries:    retries,
+		backoff:    backoff,
+	}
+}
+
+// URL returns the plain-text download URL of ebook id.
+func (c *Client) URL(id int64) string {
+	return fmt.Sprintf("%s/cache/epub/%d/pg%d.txt", c.baseURL, id, id)
+}
+
+// Download fetches ebook id to path and returns the SHA-256 of its
+// contents. If want is not empty the text must have that checksum; a
+// different text fails with ErrChecksumMismatch and path is left untouched.
+// Network errors, server errors and truncated bodies are retried.
+func (c *Client) Download(ctx context.Context, id int64, path, want string) (string, error) {
+	var data []byte
+	var err error
+	for attempt := 0; ; attempt++ {
+		data, err = c.fetch(ctx, c.URL(id))
+		if err == nil || errors.Is(err, errNotRetryable) || attempt >= c.retries || ctx.Err() != nil {
+			break
+		}
+
+		delay := c.backoff << attempt
+		slog.Warn("download failed, retrying", "ebook", id, "attempt", attempt+1, "delay", delay, "error", err)
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	sum := Checksum(data)
+	if want != "" && sum != want {
+		return "", fmt.Errorf("%w: ebook %d has checksum %s, recorded edition %s", ErrChecksumMismatch, id, short(sum), short(want))
+	}
+
+	// Write to a temporary file first so an interrupted write never leaves a
+	// partial book behind.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".download-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+
+	return sum, nil
+}
+
+// fetch makes one GET request and returns the complete body.
+func (c *Client) fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errNotRetryable, err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	default:
+		return nil, fmt.Errorf("%w: HTTP %d", errNotRetryable, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	if resp.ContentLength >= 0 && int64(len(data)) != resp.ContentLength {
+		return nil, fmt.Errorf("truncated: got %d of %d bytes", len(data), resp.ContentLength)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty body")
+	}
+
+	return data, nil
+}
+
+// Checksum returns the hex SHA-256 of data.
+func Checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// FileChecksum returns the hex SHA-256 of the file at path.
+func FileChecksum(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return Checksum(data), nil
+}
+
+func short(sum string) string {
+	if len(sum) > 12 {
+		return sum[:12]
+	}
+	return sum
+}
+
+// Header is the metadata block Gutenberg puts at the top of every text.
+type Header struct {
+	Title       string
+	Author      string
+	Translator  string
+	ReleaseDate string // e.g. "March 28, 2006 [eBook #2554]"
+	Updated     string // "Most recently updated" date, if any
+	Language    string
+}
+
+// Edition describes the release of the text, e.g.
+// "March 28, 2006 [eBook #2554], updated June 26, 2021", or "" if the
+// header has no dates.
+func (h Header) Edition() string {
+	if h.Updated == "" {
+		return h.ReleaseDate
+	}
+	if h.ReleaseDate == "" {
+		return h.Updated
+	}
+	return h.ReleaseDate + ", updated " + h.Updated
+}
+
+// ParseHeader reads the metadata lines before the "*** START OF" marker of
+// a Gutenberg text.
+func ParseHeader(data []byte) Header {
+	var h Header
+	fields := map[string]*string{
+		"title":                 &h.Title,
+		"author":                &h.Author,
+		"translator":            &h.Translator,
+		"translated by":         &h.Translator,
+		"release date":          &h.ReleaseDate,
+		"most recently updated": &h.Updated,
+		"language":              &h.Language,
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if strings.HasPrefix(line, "*** START OF") || strings.HasPrefix(line, "***START OF") {
+			break
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		if dst, ok := fields[strings.ToLower(strings.TrimSpace(key))]; ok && *dst == "" {
+			*dst = strings.TrimSpace(value)
+		}
+	}
+	return h
+}
+
+// ReadHeader parses the header of the text file at path.
+func ReadHeader(path string) (Header, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Header{}, err
+	}
+	return ParseHeader(data), nil
+}
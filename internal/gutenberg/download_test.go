@@ -0,0 +1,108 @@
+package gutenberg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bookText = "\ufeffThe Project Gutenberg eBook of Crime and Punishment\n\n" +
+	"Title: Crime and Punishment\n\n" +
+	"Author: Fyodor Dostoyevsky\n\n" +
+	"Translator: Constance Garnett\n\n" +
+	"Release date: March 28, 2006 [eBook #2554]\n" +
+	"                Most recently updated: June 26, 2021\n\n" +
+	"Language: English\n\n" +
+	"*** START OF THE PROJECT GUTENBERG EBOOK CRIME AND PUNISHMENT ***\n\n" +
+	"Title: not a header line\n"
+
+func TestClient_Download(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		switch r.URL.Path {
+		case "/cache/epub/2554/pg2554.txt":
+			if n == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(bookText))
+		case "/cache/epub/7/pg7.txt":
+			// Promise more than is sent: a truncated transfer
+			w.Header().Set("Content-Length", "1000")
+			w.Write([]byte("partial"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient(ClientConfig{BaseURL: srv.URL, Retries: 2, Backoff: time.Millisecond})
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	t.Run("retries server errors and records the checksum", func(t *testing.T) {
+		requests.Store(0)
+		path := filepath.Join(dir, "crime.txt")
+
+		sum, err := client.Download(ctx, 2554, path, "")
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), requests.Load())
+		assert.Equal(t, Checksum([]byte(bookText)), sum)
+
+		fileSum, err := FileChecksum(path)
+		require.NoError(t, err)
+		assert.Equal(t, sum, fileSum)
+
+		_, err = client.Download(ctx, 2554, path, sum)
+		assert.NoError(t, err, "the recorded edition verifies")
+	})
+
+	t.Run("refuses a changed edition", func(t *testing.T) {
+		path := filepath.Join(dir, "changed.txt")
+		require.NoError(t, os.WriteFile(path, []byte("old edition"), 0o644))
+
+		_, err := client.Download(ctx, 2554, path, Checksum([]byte("old edition")))
+		assert.ErrorIs(t, err, ErrChecksumMismatch)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "old edition", string(data), "file is left untouched")
+	})
+
+	t.Run("truncated body is retried then fails", func(t *testing.T) {
+		requests.Store(0)
+		_, err := client.Download(ctx, 7, filepath.Join(dir, "seven.txt"), "")
+		assert.Error(t, err)
+		assert.Equal(t, int32(3), requests.Load())
+		assert.NoFileExists(t, filepath.Join(dir, "seven.txt"))
+	})
+
+	t.Run("not found is not retried", func(t *testing.T) {
+		requests.Store(0)
+		_, err := client.Download(ctx, 99, filepath.Join(dir, "missing.txt"), "")
+		assert.ErrorContains(t, err, "HTTP 404")
+		assert.Equal(t, int32(1), requests.Load())
+	})
+}
+
+func TestParseHeader(t *testing.T) {
+	h := ParseHeader([]byte(bookText))
+
+	assert.Equal(t, "Crime and Punishment", h.Title)
+	assert.Equal(t, "Fyodor Dostoyevsky", h.Author)
+	assert.Equal(t, "Constance Garnett", h.Translator)
+	assert.Equal(t, "English", h.Language)
+	assert.Equal(t, "March 28, 2006 [eBook #2554], updated June 26, 2021", h.Edition())
+
+	assert.Equal(t, "", ParseHeader([]byte("no header here")).Edition())
+	assert.Equal(t, "May 1, 2001", Header{ReleaseDate: "May 1, 2001"}.Edition())
+}
@@ -0,0 +1,365 @@
+package ingest
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ReadEPUB reads an EPUB 2 or 3 book: the documents of its spine, in
+// reading order, and the chapters of its table of contents.
+func ReadEPUB(file string) (*Document, error) {
+	zr, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("open epub: %w", err)
+	}
+	defer zr.Close()
+
+	return readEPUB(&zr.Reader)
+}
+
+// container is META-INF/container.xml, which names the package document.
+type container struct {
+	Rootfiles []struct {
+		FullPath  string `xml:"full-path,attr"`
+		MediaType string `xml:"media-type,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+// opf is the package document: the book's files and their reading order.
+type opf struct {
+	Manifest []manifestItem `xml:"manifest>item"`
+	Spine    struct {
+		Toc      string `xml:"toc,attr"`
+		Itemrefs []struct {
+			IDRef  string `xml:"idref,attr"`
+			Linear string `xml:"linear,attr"`
+		} `xml:"itemref"`
+	} `xml:"spine"`
+}
+
+type manifestItem struct {
+	ID         string `xml:"id,attr"`
+	Href       string `xml:"href,attr"`
+	MediaType  string `xml:"media-type,attr"`
+	Properties string `xml:"properties,attr"`
+}
+
+// ncx is the EPUB 2 table of contents.
+type ncx struct {
+	NavPoints []navPoint `xml:"navMap>navPoint"`
+}
+
+type navPoint struct {
+	Label   string `xml:"navLabel>text"`
+	Content struct {
+		Src string `xml:"src,attr"`
+	} `xml:"content"`
+	Children []navPoint `xml:"navPoint"`
+}
+
+// tocEntry is an entry of a table of contents, before it is located in the
+// text. Target is the path of the document in the archive, with the
+// fragment if any.
+type tocEntry struct {
+	Title  string
+	Target string
+	Depth  int
+}
+
+func readEPUB(zr *zip.Reader) (*Document, error) {
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var c container
+	if err := decodeXML(files, "META-INF/container.xml", &c); err != nil {
+		return nil, err
+	}
+	var rootfile string
+	for _, r := range c.Rootfiles {
+		if r.MediaType == "" || r.MediaType == "application/oebps-package+xml" {
+			rootfile = r.FullPath
+			break
+		}
+	}
+	if rootfile == "" {
+		return nil, fmt.Errorf("epub has no package document")
+	}
+
+	var pkg opf
+	if err := decodeXML(files, rootfile, &pkg); err != nil {
+		return nil, err
+	}
+	base := path.Dir(rootfile)
+
+	items := make(map[string]manifestItem, len(pkg.Manifest))
+	var nav, toc string
+	for _, item := range pkg.Manifest {
+		items[item.ID] = item
+		if hasToken(item.Properties, "nav") {
+			nav = resolve(base, item.Href)
+		}
+		if item.ID == pkg.Spine.Toc || (toc == "" && item.MediaType == "application/x-dtbncx+xml") {
+			toc = resolve(base, item.Href)
+		}
+	}
+
+	// Read the spine into one text, remembering where each document and
+	// element id starts
+	var t text
+	for _, ref := range pkg.Spine.Itemrefs {
+		item, ok := items[ref.IDRef]
+		if !ok || ref.Linear == "no" || !isHTML(item.MediaType) {
+			continue
+		}
+		name := resolve(base, item.Href)
+		if name == nav {
+			continue
+		}
+		if err := parseFile(files, name, &t); err != nil {
+			return nil, err
+		}
+	}
+	if len(t.lines) == 0 {
+		return nil, fmt.Errorf("epub has no text")
+	}
+
+	// Prefer the EPUB 3 navigation document, which replaces the NCX
+	var entries []tocEntry
+	if nav != "" {
+		var err error
+		if entries, err = readNav(files, nav); err != nil {
+			return nil, err
+		}
+	}
+	if len(entries) == 0 && toc != "" {
+		var err error
+		if entries, err = readNCX(files, toc); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Document{Lines: t.lines, Chapters: locate(entries, t.anchors)}, nil
+}
+
+// locate finds the line each table of contents entry starts on, dropping
+// entries that point outside the text, and orders them by line. Entries
+// on the same line keep their order, so a part comes before its first
+// chapter.
+func locate(entries []tocEntry, anchors map[string]int) []Chapter {
+	var chapters []Chapter
+	for _, e := range entries {
+		line, ok := anchors[e.Target]
+		if !ok {
+			continue
+		}
+		chapters = append(chapters, Chapter{Title: e.Title, Line: line, Depth: e.Depth})
+	}
+	sort.SliceStable(chapters, func(i, j int) bool { return chapters[i].Line < chapters[j].Line })
+	return chapters
+}
+
+// readNCX reads the entries of an EPUB 2 NCX file.
+func readNCX(files map[string]*zip.File, name string) ([]tocEntry, error) {
+	var doc ncx
+	if err := decodeXML(files, name, &doc); err != nil {
+		return nil, err
+	}
+
+	var entries []tocEntry
+	var walk func(points []navPoint, depth int)
+	walk = func(points []navPoint, depth int) {
+		for _, p := range points {
+			if title := collapseSpace(p.Label); title != "" && p.Content.Src != "" {
+				entries = append(entries, tocEntry{
+					Title:  title,
+					Target: resolve(path.Dir(name), p.Content.Src),
+					Depth:  depth,
+				})
+			}
+			walk(p.Children, depth+1)
+		}
+	}
+	walk(doc.NavPoints, 0)
+	return entries, nil
+}
+
+// readNav reads the entries of the toc nav element of an EPUB 3
+// navigation document: the links of its nested lists.
+func readNav(files map[string]*zip.File, name string) ([]tocEntry, error) {
+	f, ok := files[name]
+	if !ok {
+		return nil, fmt.Errorf("epub is missing %s", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	d := xml.NewDecoder(rc)
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+	d.CharsetReader = charsetReader
+
+	var entries []tocEntry
+	var (
+		inToc  bool // inside the toc nav
+		navs   int  // depth of nav elements while inToc
+		lists  int  // depth of ol elements inside the toc
+		link   *tocEntry
+		labels strings.Builder
+	)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF || (err != nil && isTruncation(err)) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", name, err)
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			switch tok.Name.Local {
+			case "nav":
+				if inToc {
+					navs++
+				} else if typ := navType(tok); typ == "" || hasToken(typ, "toc") {
+					inToc, navs = true, 1
+				}
+			case "ol":
+				if inToc {
+					lists++
+				}
+			case "a":
+				if inToc && lists > 0 {
+					href := attr(tok, "href")
+					if href != "" {
+						link = &tocEntry{Target: resolve(path.Dir(name), href), Depth: lists - 1}
+						labels.Reset()
+					}
+				}
+			}
+		case xml.EndElement:
+			switch tok.Name.Local {
+			case "nav":
+				if inToc {
+					if navs--; navs == 0 {
+						inToc = false
+						if len(entries) > 0 {
+							return entries, nil
+						}
+					}
+				}
+			case "ol":
+				if inToc && lists > 0 {
+					lists--
+				}
+			case "a":
+				if link != nil {
+					if link.Title = collapseSpace(labels.String()); link.Title != "" {
+						entries = append(entries, *link)
+					}
+					link = nil
+				}
+			}
+		case xml.CharData:
+			if link != nil {
+				labels.Write(tok)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// navType returns the epub:type of a nav element.
+func navType(el xml.StartElement) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == "type" && a.Name.Space != "" {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// parseFile appends the text of the document name in the archive to t,
+// recording its anchors under name.
+func parseFile(files map[string]*zip.File, name string, t *text) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("epub is missing %s", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("open %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	if err := t.parse(rc, name); err != nil {
+		return fmt.Errorf("parse %s: %w", name, err)
+	}
+	return nil
+}
+
+// decodeXML unmarshals the XML file name in the archive into v.
+func decodeXML(files map[string]*zip.File, name string, v any) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("epub is missing %s", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("open %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	d := xml.NewDecoder(rc)
+	d.CharsetReader = charsetReader
+	if err := d.Decode(v); err != nil {
+		return fmt.Errorf("parse %s: %w", name, err)
+	}
+	return nil
+}
+
+// resolve returns the archive path of href, relative to the directory dir
+// of the file it appears in. The fragment, if any, is kept.
+func resolve(dir, href string) string {
+	ref, fragment, _ := strings.Cut(href, "#")
+	if unescaped, err := url.PathUnescape(ref); err == nil {
+		ref = unescaped
+	}
+	p := path.Join(dir, ref)
+	if fragment != "" {
+		return p + "#" + fragment
+	}
+	return p
+}
+
+// isHTML reports whether a manifest media type is a content document.
+func isHTML(mediaType string) bool {
+	return mediaType == "application/xhtml+xml" || mediaType == "text/html"
+}
+
+// hasToken reports whether the space-separated list s contains token.
+func hasToken(s, token string) bool {
+	for _, f := range strings.Fields(s) {
+		if f == token {
+			return true
+		}
+	}
+	return false
+}
+
+// collapseSpace trims s and reduces its runs of whitespace to one space.
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,203 @@
+package ingest
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeEPUB writes an EPUB of files, keyed by their path in the archive,
+// and returns its path.
+func writeEPUB(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "book.epub")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return path
+}
+
+const epubContainer = `<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>`
+
+const epubPartOne = `<?xml version="1.0" encoding="utf-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<body>
+<section id="part1">
+<h1>PART I</h1>
+<h2 id="ch1">CHAPTER I</h2>
+<p>On an exceptionally hot evening<a epub:type="noteref" href="notes.xhtml#n1">1</a> early in July.</p>
+<h2 id="ch2">CHAPTER II</h2>
+<p>He was not used to crowds.</p>
+</section>
+</body>
+</html>`
+
+const epubPartTwo = `<?xml version="1.0" encoding="utf-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<body>
+<h1>The Dream</h1>
+<p>He dreamed of his childhood.</p>
+</body>
+</html>`
+
+const epubNotes = `<html xmlns="http://www.w3.org/1999/xhtml"><body>
+<p id="n1">1. A note.</p>
+</body></html>`
+
+var wantLines = []string{
+	"PART I",
+	"",
+	"CHAPTER I",
+	"",
+	"On an exceptionally hot evening early in July.",
+	"",
+	"CHAPTER II",
+	"",
+	"He was not used to crowds.",
+	"",
+	"The Dream",
+	"",
+	"He dreamed of his childhood.",
+}
+
+var wantChapters = []Chapter{
+	{Title: "Part I", Line: 0, Depth: 0},
+	{Title: "Chapter I", Line: 2, Depth: 1},
+	{Title: "Chapter II", Line: 6, Depth: 1},
+	{Title: "The Dream", Line: 10, Depth: 0},
+}
+
+func TestReadEPUB(t *testing.T) {
+	t.Run("EPUB 3 with a navigation document", func(t *testing.T) {
+		path := writeEPUB(t, map[string]string{
+			"mimetype":               "application/epub+zip",
+			"META-INF/container.xml": epubContainer,
+			"OEBPS/content.opf": `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+  <manifest>
+    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="p1" href="text/part%201.xhtml" media-type="application/xhtml+xml"/>
+    <item id="p2" href="text/part2.xhtml" media-type="application/xhtml+xml"/>
+    <item id="notes" href="text/notes.xhtml" media-type="application/xhtml+xml"/>
+    <item id="cover" href="cover.jpg" media-type="image/jpeg"/>
+  </manifest>
+  <spine>
+    <itemref idref="nav"/>
+    <itemref idref="p1"/>
+    <itemref idref="p2"/>
+    <itemref idref="notes" linear="no"/>
+  </spine>
+</package>`,
+			"OEBPS/nav.xhtml": `<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops"><body>
+<nav epub:type="toc"><ol>
+  <li><a href="text/part%201.xhtml#part1">Part I</a>
+    <ol>
+      <li><a href="text/part%201.xhtml#ch1">Chapter I</a></li>
+      <li><a href="text/part%201.xhtml#ch2">Chapter
+        II</a></li>
+    </ol>
+  </li>
+  <li><a href="text/part2.xhtml">The Dream</a></li>
+  <li><a href="text/missing.xhtml">Missing</a></li>
+</ol></nav>
+<nav epub:type="landmarks"><ol><li><a href="text/notes.xhtml">Notes</a></li></ol></nav>
+</body></html>`,
+			"OEBPS/text/part 1.xhtml": epubPartOne,
+			"OEBPS/text/part2.xhtml":  epubPartTwo,
+			"OEBPS/text/notes.xhtml":  epubNotes,
+		})
+
+		doc, err := ReadEPUB(path)
+		require.NoError(t, err)
+		assert.Equal(t, wantLines, doc.Lines)
+		assert.Equal(t, wantChapters, doc.Chapters)
+	})
+
+	t.Run("EPUB 2 with an NCX", func(t *testing.T) {
+		path := writeEPUB(t, map[string]string{
+			"META-INF/container.xml": epubContainer,
+			"OEBPS/content.opf": `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="2.0">
+  <manifest>
+    <item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+    <item id="p1" href="text/part%201.xhtml" media-type="application/xhtml+xml"/>
+    <item id="p2" href="text/part2.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+  <spine toc="ncx">
+    <itemref idref="p1"/>
+    <itemref idref="p2"/>
+  </spine>
+</package>`,
+			"OEBPS/toc.ncx": `<?xml version="1.0"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+  <navMap>
+    <navPoint id="np1" playOrder="1">
+      <navLabel><text>Part I</text></navLabel>
+      <content src="text/part%201.xhtml#part1"/>
+      <navPoint id="np2" playOrder="2">
+        <navLabel><text>Chapter I</text></navLabel>
+        <content src="text/part%201.xhtml#ch1"/>
+      </navPoint>
+      <navPoint id="np3" playOrder="3">
+        <navLabel><text>Chapter II</text></navLabel>
+        <content src="text/part%201.xhtml#ch2"/>
+      </navPoint>
+    </navPoint>
+    <navPoint id="np4" playOrder="4">
+      <navLabel><text>The Dream</text></navLabel>
+      <content src="text/part2.xhtml"/>
+    </navPoint>
+  </navMap>
+</ncx>`,
+			"OEBPS/text/part 1.xhtml": epubPartOne,
+			"OEBPS/text/part2.xhtml":  epubPartTwo,
+		})
+
+		doc, err := ReadEPUB(path)
+		require.NoError(t, err)
+		assert.Equal(t, wantLines, doc.Lines)
+		assert.Equal(t, wantChapters, doc.Chapters)
+	})
+
+	t.Run("not an EPUB", func(t *testing.T) {
+		path := writeEPUB(t, map[string]string{"README": "hello"})
+		_, err := ReadEPUB(path)
+		assert.ErrorContains(t, err, "container.xml")
+	})
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	txt := filepath.Join(dir, "book.txt")
+	require.NoError(t, os.WriteFile(txt, []byte("CHAPTER I\n\nIt was late.\n"), 0o644))
+	doc, err := Load(txt)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CHAPTER I", "", "It was late."}, doc.Lines)
+	assert.Empty(t, doc.Chapters)
+
+	html := filepath.Join(dir, "book.HTML")
+	require.NoError(t, os.WriteFile(html, []byte("<h2>CHAPTER I</h2><p>It was late.</p>"), 0o644))
+	doc, err = Load(html)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CHAPTER I", "", "It was late."}, doc.Lines)
+}
@@ -0,0 +1,349 @@
+package ingest
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParseHTML reads the text of an HTML or XHTML document as lines, one per
+// paragraph with blank lines between them.
+func ParseHTML(r io.Reader) ([]string, error) {
+	var t text
+	if err := t.parse(r, ""); err != nil {
+		return nil, err
+	}
+	return t.lines, nil
+}
+
+// blockElements end the paragraph before them and the one inside them.
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"body": true, "center": true, "dd": true, "div": true, "dl": true,
+	"dt": true, "figcaption": true, "figure": true, "footer": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "li": true, "ol": true, "p": true,
+	"pre": true, "section": true, "table": true, "td": true, "th": true,
+	"tr": true, "ul": true,
+}
+
+// skippedElements hold no text of the book. A table of contents in a nav
+// element is read separately, as Document.Chapters.
+var skippedElements = map[string]bool{
+	"head": true, "nav": true, "script": true, "style": true, "template": true,
+}
+
+// noteTypes are the epub:type and role values of footnotes, endnotes and
+// references to them.
+var noteTypes = map[string]bool{
+	"footnote": true, "footnotes": true, "endnote": true, "endnotes": true,
+	"rearnote": true, "rearnotes": true, "note": true, "noteref": true,
+	"doc-footnote": true, "doc-endnote": true, "doc-endnotes": true,
+	"doc-noteref": true,
+}
+
+// noteMarker matches the text of a footnote reference: "1", "[12]", "*",
+// "(†)".
+var noteMarker = regexp.MustCompile(`^[\[(]?(?:\d+|[*†‡§¶]+)[\])]?$`)
+
+// text accumulates the lines of one or more documents.
+type text struct {
+	lines []string
+	para  []string        // finished lines of the current paragraph
+	line  strings.Builder // the current line, with whitespace collapsed
+	space bool            // whitespace is pending before the next word
+	pre   int             // depth of pre elements, whose whitespace is kept
+
+	// anchors maps "doc#id" to the line the element with that id starts
+	// on, and "doc" to the line the document starts on.
+	anchors map[string]int
+}
+
+// marker remembers the state of the current line at the start of an
+// element that may be a footnote reference, so it can be taken back.
+type marker struct {
+	depth int
+	len   int
+	space bool
+	lines int
+	para  int
+}
+
+// parse appends the text of the document read from r, recording its
+// anchors under doc.
+func (t *text) parse(r io.Reader, doc string) error {
+	d := xml.NewDecoder(r)
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+	d.CharsetReader = charsetReader
+
+	t.endParagraph()
+	t.anchor(doc)
+
+	depth := 0
+	skip := 0 // depth of the skipped element being read, or 0
+	var markers []marker
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Unclosed elements at the end of a sloppy document are
+			// reported as a syntax error; the text is complete anyway.
+			if isTruncation(err) {
+				break
+			}
+			return err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if skip > 0 {
+				continue
+			}
+			name := strings.ToLower(tok.Name.Local)
+			if skippedElements[name] || isNote(tok) {
+				skip = depth
+				continue
+			}
+			for _, a := range tok.Attr {
+				if a.Name.Local == "id" || (name == "a" && a.Name.Local == "name") {
+					t.anchor(doc + "#" + a.Value)
+				}
+			}
+			switch {
+			case name == "br":
+				t.breakLine()
+			case blockElements[name]:
+				t.endParagraph()
+				if name == "pre" {
+					t.pre++
+				}
+			case name == "sup" || (name == "a" && strings.Contains(attr(tok, "href"), "#")):
+				markers = append(markers, marker{
+					depth: depth,
+					len:   t.line.Len(),
+					space: t.space,
+					lines: len(t.lines),
+					para:  len(t.para),
+				})
+			}
+
+		case xml.EndElement:
+			name := strings.ToLower(tok.Name.Local)
+			switch {
+			case skip > 0:
+				if depth == skip {
+					skip = 0
+				}
+			case blockElements[name]:
+				t.endParagraph()
+				if name == "pre" && t.pre > 0 {
+					t.pre--
+				}
+			}
+			if n := len(markers); n > 0 && markers[n-1].depth == depth {
+				t.dropMarker(markers[n-1])
+				markers = markers[:n-1]
+			}
+			depth--
+
+		case xml.CharData:
+			if skip == 0 {
+				t.write(string(tok))
+			}
+		}
+	}
+
+	t.endParagraph()
+	return nil
+}
+
+// isTruncation reports whether err is the decoder's complaint about
+// elements left open at the end of the input.
+func isTruncation(err error) bool {
+	var syntaxErr *xml.SyntaxError
+	return errors.As(err, &syntaxErr) && syntaxErr.Msg == "unexpected EOF"
+}
+
+// write adds character data to the current line.
+func (t *text) write(s string) {
+	if t.pre > 0 {
+		for i, l := range strings.Split(s, "\n") {
+			if i > 0 {
+				t.breakLine()
+			}
+			t.line.WriteString(strings.TrimRightFunc(l, unicode.IsSpace))
+		}
+		return
+	}
+
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			t.space = t.line.Len() > 0
+			continue
+		}
+		if t.space {
+			t.line.WriteByte(' ')
+			t.space = false
+		}
+		t.line.WriteRune(r)
+	}
+}
+
+// breakLine ends the current line of the paragraph.
+func (t *text) breakLine() {
+	t.para = append(t.para, t.line.String())
+	t.line.Reset()
+	t.space = false
+}
+
+// endParagraph adds the current paragraph to the lines, after a blank
+// line.
+func (t *text) endParagraph() {
+	if t.line.Len() > 0 {
+		t.breakLine()
+	}
+	para := t.para
+	for len(para) > 0 && strings.TrimSpace(para[0]) == "" {
+		para = para[1:]
+	}
+	for len(para) > 0 && strings.TrimSpace(para[len(para)-1]) == "" {
+		para = para[:len(para)-1]
+	}
+	if len(para) > 0 {
+		if len(t.lines) > 0 {
+			t.lines = append(t.lines, "")
+		}
+		t.lines = append(t.lines, para...)
+	}
+	t.para = t.para[:0]
+	t.line.Reset()
+	t.space = false
+}
+
+// nextLine returns the line the text written next will be on.
+func (t *text) nextLine() int {
+	n := len(t.lines)
+	if n > 0 {
+		n++ // the blank line before the paragraph
+	}
+	return n + len(t.para)
+}
+
+// anchor records that key starts at the next line, unless it was seen
+// before.
+func (t *text) anchor(key string) {
+	if t.anchors == nil {
+		t.anchors = make(map[string]int)
+	}
+	if _, ok := t.anchors[key]; !ok {
+		t.anchors[key] = t.nextLine()
+	}
+}
+
+// dropMarker removes the text written since m if it is a footnote
+// reference such as "[1]".
+func (t *text) dropMarker(m marker) {
+	if len(t.lines) != m.lines || len(t.para) != m.para || t.line.Len() < m.len {
+		return
+	}
+	line := t.line.String()
+	if !noteMarker.MatchString(strings.TrimSpace(line[m.len:])) {
+		return
+	}
+	t.line.Reset()
+	t.line.WriteString(line[:m.len])
+	t.space = m.space
+}
+
+// isNote reports whether el is a footnote, an endnote or a reference to
+// one, by its epub:type, role or class.
+func isNote(el xml.StartElement) bool {
+	for _, a := range el.Attr {
+		switch {
+		case a.Name.Local == "type" && a.Name.Space != "", a.Name.Local == "role":
+			for _, v := range strings.Fields(a.Value) {
+				if noteTypes[strings.ToLower(v)] {
+					return true
+				}
+			}
+		case a.Name.Local == "class":
+			for _, v := range strings.Fields(strings.ToLower(a.Value)) {
+				if strings.Contains(v, "footnote") || strings.Contains(v, "endnote") ||
+					v == "fnanchor" || v == "noteref" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// attr returns the value of the attribute of el named local, or "".
+func attr(el xml.StartElement, local string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// charsetReader decodes the encodings declared by older XHTML files.
+// UTF-8 needs no decoding and is not passed here.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "us-ascii", "ascii":
+		return input, nil
+	case "iso-8859-1", "latin1", "latin-1":
+		return &byteReader{r: bufio.NewReader(input)}, nil
+	case "windows-1252", "cp1252":
+		return &byteReader{r: bufio.NewReader(input), table: &cp1252}, nil
+	}
+	return nil, fmt.Errorf("unsupported charset %q", charset)
+}
+
+// cp1252 maps the bytes 0x80-0x9F of Windows-1252, where it differs from
+// ISO-8859-1. Undefined bytes are kept as their ISO-8859-1 control codes.
+var cp1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+// byteReader decodes a single-byte encoding to UTF-8: ISO-8859-1, or
+// Windows-1252 if table is set.
+type byteReader struct {
+	r     *bufio.Reader
+	table *[32]rune
+	buf   []byte
+}
+
+func (b *byteReader) Read(p []byte) (int, error) {
+	for len(b.buf) < len(p) {
+		c, err := b.r.ReadByte()
+		if err != nil {
+			if len(b.buf) > 0 {
+				break
+			}
+			return 0, err
+		}
+		r := rune(c)
+		if b.table != nil && c >= 0x80 && c < 0xA0 {
+			r = b.table[c-0x80]
+		}
+		b.buf = utf8.AppendRune(b.buf, r)
+	}
+	n := copy(p, b.buf)
+	b.buf = b.buf[n:]
+	return n, nil
+}
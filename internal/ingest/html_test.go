@@ -0,0 +1,77 @@
+package ingest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHTML(t *testing.T) {
+	t.Run("one line per paragraph", func(t *testing.T) {
+		lines, err := ParseHTML(strings.NewReader(`<!DOCTYPE html>
+<html><head><title>Poor Folk</title><style>p { margin: 0 }</style></head>
+<body>
+<h2>CHAPTER I</h2>
+<p>On an exceptionally hot evening
+   early in July a young man came out of the garret&nbsp;in which he
+   lodged.</p>
+<p>He had <i>successfully</i> avoided &ldquo;meeting&rdquo; his landlady.</p>
+</body></html>`))
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"CHAPTER I",
+			"",
+			"On an exceptionally hot evening early in July a young man came out of the garret in which he lodged.",
+			"",
+			"He had successfully avoided “meeting” his landlady.",
+		}, lines)
+	})
+
+	t.Run("line breaks and preformatted text", func(t *testing.T) {
+		lines, err := ParseHTML(strings.NewReader(`<body>
+<p class="poem">I loved you once,<br/>and still, perhaps, love lingers</p>
+<pre>  kept
+  as is</pre>
+</body>`))
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"I loved you once,",
+			"and still, perhaps, love lingers",
+			"",
+			"  kept",
+			"  as is",
+		}, lines)
+	})
+
+	t.Run("footnotes and their references are dropped", func(t *testing.T) {
+		lines, err := ParseHTML(strings.NewReader(`<body>
+<p>He went to the Haymarket<a href="#Footnote_1" class="fnanchor">[1]</a> at once.</p>
+<p>She smiled<sup>2</sup>, and he said<a epub:type="noteref" href="notes.xhtml#n3">3</a> nothing.</p>
+<p>See <a href="#ch2">Chapter 2</a>.</p>
+<div class="footnote"><p><a id="Footnote_1" href="#FNanchor_1">[1]</a> A square in Petersburg.</p></div>
+<aside epub:type="footnote"><p>A note.</p></aside>
+</body>`))
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"He went to the Haymarket at once.",
+			"",
+			"She smiled, and he said nothing.",
+			"",
+			"See Chapter 2.",
+		}, lines)
+	})
+
+	t.Run("unclosed elements", func(t *testing.T) {
+		lines, err := ParseHTML(strings.NewReader(`<html><body><p>First<p>Second`))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"First", "", "Second"}, lines)
+	})
+
+	t.Run("windows-1252 encoding", func(t *testing.T) {
+		lines, err := ParseHTML(strings.NewReader("<?xml version=\"1.0\" encoding=\"windows-1252\"?>\n<html><body><p>\x93Yes,\x94 she said \x97 caf\xe9.</p></body></html>"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"“Yes,” she said — café."}, lines)
+	})
+}
@@ -0,0 +1,80 @@
+// Package ingest reads books in plain text, HTML and EPUB as lines of plain
+// text, the form the extractor chunks.
+//
+// Markup is reduced to one line per paragraph, with blank lines between
+// paragraphs; footnotes and their references are dropped. For EPUB the
+// chapters of the table of contents are kept with the line each starts on.
+package ingest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Document is the text of a book.
+type Document struct {
+	Lines []string
+
+	// Chapters are the entries of the book's table of contents, in reading
+	// order. Empty for formats without one, whose headings are found in
+	// the text instead.
+	Chapters []Chapter
+}
+
+// Chapter is an entry of a table of contents.
+type Chapter struct {
+	Title string
+	Line  int // 0-based line of Lines the chapter starts on
+	Depth int // 0 for top-level entries, 1 for their children, ...
+}
+
+// Load reads the book at path, choosing the format by its extension:
+// .epub, .html, .htm or .xhtml, and plain text otherwise.
+func Load(path string) (*Document, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".epub":
+		return ReadEPUB(path)
+	case ".html", ".htm", ".xhtml":
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		lines, err := ParseHTML(f)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", filepath.Base(path), err)
+		}
+		return &Document{Lines: lines}, nil
+	default:
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		lines, err := ReadText(f)
+		if err != nil {
+			return nil, err
+		}
+		return &Document{Lines: lines}, nil
+	}
+}
+
+// ReadText reads plain text as lines.
+func ReadText(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
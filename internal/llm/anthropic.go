@@ -0,0 +1,309 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultAnthropicURL = "https://api.anthropic.com"
+	claudeAPIVersion    = "2023-06-01"
+	defaultModel        = "claude-sonnet-4-20250514"
+	maxTokens           = 4096
+)
+
+// Anthropic is a Completer backed by the Anthropic Messages API.
+type Anthropic struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	model      string
+	pricing    Pricing
+	retry      RetryConfig
+	limiter    *RateLimiter
+	budget     *Budget
+}
+
+// AnthropicConfig holds configuration for the Anthropic client.
+type AnthropicConfig struct {
+	APIKey  string
+	Model   string // default: claude-sonnet-4-20250514
+	BaseURL string // default: https://api.anthropic.com
+
+	// Retry overrides DefaultRetryConfig when non-zero.
+	Retry RetryConfig
+
+	// Limiter, if set, paces requests and honours the API's rate-limit headers.
+	// It may be shared between clients.
+	Limiter *RateLimiter
+
+	// Budget, if set, records spend and refuses requests that could take it
+	// past its limit.
+	Budget *Budget
+}
+
+// NewAnthropic creates a new Anthropic API client.
+func NewAnthropic(config AnthropicConfig) *Anthropic {
+	model := config.Model
+	if model == "" {
+		model = defaultModel
+	}
+
+	baseURL := strings.TrimSuffix(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultAnthropicURL
+	}
+
+	retry := config.Retry
+	if retry == (RetryConfig{}) {
+		retry = DefaultRetryConfig
+	}
+
+	return &Anthropic{
+		apiKey:  config.APIKey,
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 120 * time.Second,
+		},
+		model:   model,
+		pricing: PricingFor(model),
+		retry:   retry,
+		limiter: config.Limiter,
+		budget:  config.Budget,
+	}
+}
+
+// Model returns the model requests are sent to.
+func (c *Anthropic) Model() string {
+	return c.model
+}
+
+// Message represents a message in the conversation.
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// toolChoice forces Claude to call a specific tool.
+type toolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+// cacheControl marks the end of a prompt prefix for Anthropic prompt caching.
+type cacheControl struct {
+	Type string `json:"type"`
+}
+
+// systemBlock is a text block of the system prompt.
+type systemBlock struct {
+	Type         string        `json:"type"`
+	Text         string        `json:"text"`
+	CacheControl *cacheControl `json:"cache_control,omitempty"`
+}
+
+// claudeRequest is the request body for the Claude API.
+type claudeRequest struct {
+	Model      string        `json:"model"`
+	MaxTokens  int           `json:"max_tokens"`
+	System     []systemBlock `json:"system,omitempty"`
+	Messages   []Message     `json:"messages"`
+	Tools      []Tool        `json:"tools,omitempty"`
+	ToolChoice *toolChoice   `json:"tool_choice,omitempty"`
+}
+
+// contentBlock is a text or tool_use block in a Claude response.
+type contentBlock struct {
+	Type  string          `json:"type"`
+	Text  string          `json:"text,omitempty"`
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+}
+
+// claudeResponse is the response from the Claude API.
+type claudeResponse struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	Role       string         `json:"role"`
+	Content    []contentBlock `json:"content"`
+	StopReason string         `json:"stop_reason"`
+	Usage      struct {
+		InputTokens              int `json:"input_tokens"`
+		OutputTokens             int `json:"output_tokens"`
+		CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+		CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Complete sends a completion request to Claude and returns the text of the reply.
+func (c *Anthropic) Complete(ctx context.Context, system, user string) (string, error) {
+	resp, err := c.do(ctx, c.newRequest(system, user))
+	if err != nil {
+		return "", err
+	}
+
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			return block.Text, nil
+		}
+	}
+	return "", fmt.Errorf("empty response from API")
+}
+
+// CompleteTool forces Claude to answer by calling tool, validates the input
+// it passes against the tool's schema and decodes it into out.
+func (c *Anthropic) CompleteTool(ctx context.Context, system, user string, tool Tool, out any) error {
+	req := c.newRequest(system, user, tool)
+	req.ToolChoice = &toolChoice{Type: "tool", Name: tool.Name}
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	for _, block := range resp.Content {
+		if block.Type != "tool_use" || block.Name != tool.Name {
+			continue
+		}
+		if resp.StopReason == "max_tokens" {
+			return fmt.Errorf("%w: %s input truncated at max_tokens", ErrInvalidToolInput, tool.Name)
+		}
+		return decodeToolInput(tool, block.Input, out)
+	}
+
+	return fmt.Errorf("%w: no %s call in response (stop reason %q)", ErrInvalidToolInput, tool.Name, resp.StopReason)
+}
+
+// newRequest builds a request for a single user turn offering tools. The
+// tools and system prompt are static for every caller, so when together they
+// reach the model's minimum cacheable length the system prompt is marked for
+// prompt caching: they are then read from Anthropic's cache at a tenth of the
+// input price on repeat requests within a few minutes. Shorter prefixes are
+// left unmarked, as Anthropic would not cache them anyway.
+func (c *Anthropic) newRequest(system, user string, tools ...Tool) claudeRequest {
+	req := claudeRequest{
+		Model:     c.model,
+		MaxTokens: maxTokens,
+		Messages: []Message{
+			{Role: "user", Content: user},
+		},
+		Tools: tools,
+	}
+	if system != "" {
+		req.System = []systemBlock{{Type: "text", Text: system}}
+		if prefixTokens(system, tools) >= minCacheableTokens(c.model) {
+			req.System[0].CacheControl = &cacheControl{Type: "ephemeral"}
+		}
+	}
+	return req
+}
+
+// minCacheableTokens returns the shortest prompt prefix Anthropic caches for
+// model.
+func minCacheableTokens(model string) int {
+	if strings.Contains(model, "haiku") {
+		return 2048
+	}
+	return 1024
+}
+
+// prefixTokens under-estimates the token count of the cached prefix of a
+// request: its tools and system prompt, at four characters per token.
+func prefixTokens(system string, tools []Tool) int {
+	n := len(system)
+	if len(tools) > 0 {
+		schema, _ := json.Marshal(tools)
+		n += len(schema)
+	}
+	return n / 4
+}
+
+// do sends req to the messages endpoint. Rate-limit, overload and server
+// errors are retried with jittered exponential backoff; the final error is
+// an *APIError whose kind callers can test with errors.Is.
+func (c *Anthropic) do(ctx context.Context, req claudeRequest) (*claudeResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	estimate := Usage{
+		InputTokens:  estimateTokens(string(body)),
+		OutputTokens: req.MaxTokens,
+	}
+
+	return withRetry(ctx, c.retry, c.model, func() (*claudeResponse, error) {
+		return c.send(ctx, body, estimate)
+	})
+}
+
+// send makes a single request to the messages endpoint.
+func (c *Anthropic) send(ctx context.Context, body []byte, estimate Usage) (*claudeResponse, error) {
+	reserved, err := c.budget.Reserve(c.pricing.Cost(estimate))
+	if err != nil {
+		return nil, err
+	}
+	var usage Usage
+	defer func() { c.budget.Settle(reserved, c.pricing.Cost(usage), usage) }()
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", c.apiKey)
+	httpReq.Header.Set("anthropic-version", claudeAPIVersion)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, &APIError{Kind: ErrUnavailable, Message: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	c.limiter.Update(resp.Header)
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &APIError{Kind: ErrUnavailable, StatusCode: resp.StatusCode, Message: "read response: " + err.Error()}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, classifyResponse(resp.StatusCode, resp.Header, respBody)
+	}
+
+	var claudeResp claudeResponse
+	if err := json.Unmarshal(respBody, &claudeResp); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+	usage = Usage{
+		InputTokens:      claudeResp.Usage.InputTokens,
+		OutputTokens:     claudeResp.Usage.OutputTokens,
+		CacheWriteTokens: claudeResp.Usage.CacheCreationInputTokens,
+		CacheReadTokens:  claudeResp.Usage.CacheReadInputTokens,
+	}
+
+	if claudeResp.Error != nil {
+		return nil, classifyError(resp.StatusCode, claudeResp.Error.Type, claudeResp.Error.Message)
+	}
+
+	return &claudeResp, nil
+}
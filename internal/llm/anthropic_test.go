@@ -0,0 +1,263 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastRetry keeps retry tests quick.
+var fastRetry = RetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+func textResponse(text string) claudeResponse {
+	resp := claudeResponse{
+		ID:         "msg_123",
+		Type:       "message",
+		Role:       "assistant",
+		Content:    []contentBlock{{Type: "text", Text: text}},
+		StopReason: "end_turn",
+	}
+	resp.Usage.InputTokens = 10
+	resp.Usage.OutputTokens = 5
+	return resp
+}
+
+func TestAnthropic_Complete(t *testing.T) {
+	t.Run("successful completion", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, "/v1/messages", r.URL.Path)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "test-api-key", r.Header.Get("x-api-key"))
+			assert.Equal(t, claudeAPIVersion, r.Header.Get("anthropic-version"))
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(textResponse("Hello, world!"))
+		}))
+		defer server.Close()
+
+		budget := NewBudget(0)
+		client := NewAnthropic(AnthropicConfig{APIKey: "test-api-key", BaseURL: server.URL, Budget: budget})
+
+		text, err := client.Complete(context.Background(), "system", "user")
+		require.NoError(t, err)
+		assert.Equal(t, "Hello, world!", text)
+		assert.Equal(t, Usage{InputTokens: 10, OutputTokens: 5}, budget.Usage())
+	})
+
+	t.Run("leaves short system prompts unmarked", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req claudeRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			require.Len(t, req.System, 1)
+			assert.Nil(t, req.System[0].CacheControl, "below the minimum cacheable length")
+			json.NewEncoder(w).Encode(textResponse("short"))
+		}))
+		defer server.Close()
+
+		client := NewAnthropic(AnthropicConfig{BaseURL: server.URL})
+		_, err := client.Complete(context.Background(), "system", "user")
+		require.NoError(t, err)
+	})
+
+	t.Run("marks long system prompts for caching", func(t *testing.T) {
+		system := strings.Repeat("Guidelines for evaluation. ", 200)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req claudeRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			require.Len(t, req.System, 1)
+			assert.Equal(t, system, req.System[0].Text)
+			require.NotNil(t, req.System[0].CacheControl)
+			assert.Equal(t, "ephemeral", req.System[0].CacheControl.Type)
+
+			resp := textResponse("cached")
+			resp.Usage.CacheReadInputTokens = 2000
+			json.NewEncoder(w).Encode(resp)
+		}))
+		defer server.Close()
+
+		budget := NewBudget(0)
+		client := NewAnthropic(AnthropicConfig{BaseURL: server.URL, Budget: budget})
+
+		_, err := client.Complete(context.Background(), system, "user")
+		require.NoError(t, err)
+		assert.Equal(t, 2000, budget.Usage().CacheReadTokens)
+		assert.InDelta(t, DefaultPricing.Cost(Usage{InputTokens: 10, OutputTokens: 5, CacheReadTokens: 2000}), budget.Spent(), 1e-12)
+	})
+
+	t.Run("retries overloaded errors", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(529)
+				w.Write([]byte(`{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`))
+				return
+			}
+			json.NewEncoder(w).Encode(textResponse("ok"))
+		}))
+		defer server.Close()
+
+		client := NewAnthropic(AnthropicConfig{BaseURL: server.URL, Retry: fastRetry})
+
+		text, err := client.Complete(context.Background(), "system", "user")
+		require.NoError(t, err)
+		assert.Equal(t, "ok", text)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"type":"error","error":{"type":"rate_limit_error","message":"slow down"}}`))
+		}))
+		defer server.Close()
+
+		client := NewAnthropic(AnthropicConfig{BaseURL: server.URL, Retry: fastRetry})
+
+		_, err := client.Complete(context.Background(), "system", "user")
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.True(t, IsRetryable(err))
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("does not retry auth errors", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`))
+		}))
+		defer server.Close()
+
+		client := NewAnthropic(AnthropicConfig{APIKey: "invalid", BaseURL: server.URL, Retry: fastRetry})
+
+		_, err := client.Complete(context.Background(), "system", "user")
+		assert.ErrorIs(t, err, ErrAuth)
+		assert.False(t, IsRetryable(err))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestRetryConfig_backoff(t *testing.T) {
+	cfg := RetryConfig{MaxRetries: 5, BaseDelay: time.Second, MaxDelay: 4 * time.Second}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		delay := cfg.backoff(attempt, &APIError{Kind: ErrOverloaded})
+		assert.Greater(t, delay, time.Duration(0))
+		assert.LessOrEqual(t, delay, 4*time.Second)
+	}
+
+	delay := cfg.backoff(0, &APIError{Kind: ErrRateLimited, RetryAfter: 20 * time.Second})
+	assert.Equal(t, 20*time.Second, delay, "retry-after wins over a shorter backoff")
+}
+
+func toolResponse(name, input string) claudeResponse {
+	return claudeResponse{
+		ID:         "msg_123",
+		Type:       "message",
+		Role:       "assistant",
+		Content:    []contentBlock{{Type: "tool_use", ID: "toolu_1", Name: name, Input: json.RawMessage(input)}},
+		StopReason: "tool_use",
+	}
+}
+
+// testTool is a small tool with a nested schema.
+var testTool = Tool{
+	Name:        "record_items",
+	Description: "Record items.",
+	InputSchema: &Schema{
+		Type:     "object",
+		Required: []string{"items"},
+		Properties: map[string]*Schema{
+			"items": {
+				Type: "array",
+				Items: &Schema{
+					Type:     "object",
+					Required: []string{"text", "tags"},
+					Properties: map[string]*Schema{
+						"text": {Type: "string"},
+						"tags": {Type: "array", Items: &Schema{Type: "string"}},
+					},
+				},
+			},
+		},
+	},
+}
+
+type testItems struct {
+	Items []struct {
+		Text string   `json:"text"`
+		Tags []string `json:"tags"`
+	} `json:"items"`
+}
+
+func TestAnthropic_CompleteTool(t *testing.T) {
+	serve := func(t *testing.T, resp claudeResponse) *Anthropic {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req claudeRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			require.Len(t, req.Tools, 1)
+			assert.Equal(t, testTool.Name, req.Tools[0].Name)
+			assert.Equal(t, &toolChoice{Type: "tool", Name: testTool.Name}, req.ToolChoice)
+
+			json.NewEncoder(w).Encode(resp)
+		}))
+		t.Cleanup(server.Close)
+		return NewAnthropic(AnthropicConfig{BaseURL: server.URL, Retry: fastRetry})
+	}
+
+	t.Run("decodes input containing brackets and braces", func(t *testing.T) {
+		client := serve(t, toolResponse(testTool.Name, `{"items": [
+			{"text": "If there is no God, everything is permitted]}", "tags": ["faith", "morality"]},
+			{"text": "second", "tags": []}
+		]}`))
+
+		var out testItems
+		require.NoError(t, client.CompleteTool(context.Background(), "system", "user", testTool, &out))
+		require.Len(t, out.Items, 2)
+		assert.Equal(t, "If there is no God, everything is permitted]}", out.Items[0].Text)
+		assert.Equal(t, []string{"faith", "morality"}, out.Items[0].Tags)
+	})
+
+	t.Run("input violating the schema is an error", func(t *testing.T) {
+		client := serve(t, toolResponse(testTool.Name, `{"items": [{"text": "no tags"}]}`))
+
+		var out testItems
+		err := client.CompleteTool(context.Background(), "system", "user", testTool, &out)
+		assert.ErrorIs(t, err, ErrInvalidToolInput)
+		assert.Contains(t, err.Error(), `missing required property "tags"`)
+	})
+
+	t.Run("missing tool call is an error", func(t *testing.T) {
+		client := serve(t, textResponse("Here are some items: []"))
+
+		var out testItems
+		err := client.CompleteTool(context.Background(), "system", "user", testTool, &out)
+		assert.ErrorIs(t, err, ErrInvalidToolInput)
+	})
+}
+
+func TestNewAnthropic(t *testing.T) {
+	t.Run("uses default model", func(t *testing.T) {
+		client := NewAnthropic(AnthropicConfig{APIKey: "test"})
+		assert.Equal(t, defaultModel, client.model)
+	})
+
+	t.Run("uses custom model", func(t *testing.T) {
+		client := NewAnthropic(AnthropicConfig{
+			APIKey: "test",
+			Model:  "claude-3-opus",
+		})
+		assert.Equal(t, "claude-3-opus", client.model)
+	})
+}
@@ -0,0 +1,172 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrBudgetExceeded is returned when a request would take spend past the
+// configured budget. No request is sent once it has been returned.
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// Usage is the token count reported in an API response. InputTokens
+// excludes prompt tokens written to or read from the prompt cache.
+type Usage struct {
+	InputTokens      int
+	OutputTokens     int
+	CacheWriteTokens int
+	CacheReadTokens  int
+}
+
+// Add returns the sum of two usages.
+func (u Usage) Add(o Usage) Usage {
+	return Usage{
+		InputTokens:      u.InputTokens + o.InputTokens,
+		OutputTokens:     u.OutputTokens + o.OutputTokens,
+		CacheWriteTokens: u.CacheWriteTokens + o.CacheWriteTokens,
+		CacheReadTokens:  u.CacheReadTokens + o.CacheReadTokens,
+	}
+}
+
+// Pricing is the price in USD per million tokens.
+type Pricing struct {
+	InputPerMTok  float64
+	OutputPerMTok float64
+}
+
+// DefaultPricing is the list price of the default Claude model, used for
+// Claude models not in the pricing table.
+var DefaultPricing = Pricing{InputPerMTok: 3, OutputPerMTok: 15}
+
+// modelPricing lists Anthropic prices by model name prefix.
+var modelPricing = map[string]Pricing{
+	"claude-opus-4":     {InputPerMTok: 15, OutputPerMTok: 75},
+	"claude-sonnet-4":   {InputPerMTok: 3, OutputPerMTok: 15},
+	"claude-3-7-sonnet": {InputPerMTok: 3, OutputPerMTok: 15},
+	"claude-3-5-sonnet": {InputPerMTok: 3, OutputPerMTok: 15},
+	"claude-haiku-4":    {InputPerMTok: 1, OutputPerMTok: 5},
+	"claude-3-5-haiku":  {InputPerMTok: 0.8, OutputPerMTok: 4},
+	"claude-3-haiku":    {InputPerMTok: 0.25, OutputPerMTok: 1.25},
+}
+
+// PricingFor returns the price of a Claude model, matching the longest
+// known name prefix so dated snapshots share their family's price.
+func PricingFor(model string) Pricing {
+	best, found := "", false
+	for prefix := range modelPricing {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best, found = prefix, true
+		}
+	}
+	if !found {
+		return DefaultPricing
+	}
+	return modelPricing[best]
+}
+
+// Prompt cache writes cost 25% more than regular input tokens; reads cost 10%.
+const (
+	cacheWriteMultiplier = 1.25
+	cacheReadMultiplier  = 0.1
+)
+
+// Cost returns the price of u in USD.
+func (p Pricing) Cost(u Usage) float64 {
+	input := float64(u.InputTokens) +
+		float64(u.CacheWriteTokens)*cacheWriteMultiplier +
+		float64(u.CacheReadTokens)*cacheReadMultiplier
+	return (input*p.InputPerMTok + float64(u.OutputTokens)*p.OutputPerMTok) / 1e6
+}
+
+// Budget tracks API spend and enforces a hard limit on it. Before each
+// request the client reserves its worst-case cost (the prompt plus
+// max_tokens of output), and settles the reservation with the actual cost
+// once the response arrives. Concurrent requests therefore can never
+// overshoot the limit together. One budget may be shared by clients for
+// different models.
+//
+// A nil *Budget is unlimited and records nothing.
+type Budget struct {
+	mu       sync.Mutex
+	limit    float64
+	spent    float64
+	reserved float64
+	usage    Usage
+}
+
+// NewBudget creates a budget of limitUSD. A non-positive limit only tracks spend.
+func NewBudget(limitUSD float64) *Budget {
+	return &Budget{limit: limitUSD}
+}
+
+// Reserve sets aside cost USD, returning the reserved amount to pass to
+// Settle. It fails with ErrBudgetExceeded if that would exceed the limit.
+func (b *Budget) Reserve(cost float64) (float64, error) {
+	if b == nil {
+		return 0, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.limit > 0 && b.spent+b.reserved+cost > b.limit {
+		return 0, fmt.Errorf("%w: spent $%.2f of $%.2f", ErrBudgetExceeded, b.spent, b.limit)
+	}
+	b.reserved += cost
+	return cost, nil
+}
+
+// Settle releases a reservation and records the actual cost and usage of the request.
+func (b *Budget) Settle(reserved, cost float64, actual Usage) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.reserved -= reserved
+	if b.reserved < 0 {
+		b.reserved = 0
+	}
+	b.spent += cost
+	b.usage = b.usage.Add(actual)
+}
+
+// Spent returns the USD spent so far.
+func (b *Budget) Spent() float64 {
+	if b == nil {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spent
+}
+
+// Usage returns the tokens used so far.
+func (b *Budget) Usage() Usage {
+	if b == nil {
+		return Usage{}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.usage
+}
+
+// Limit returns the budget in USD; zero means unlimited.
+func (b *Budget) Limit() float64 {
+	if b == nil {
+		return 0
+	}
+	return b.limit
+}
+
+// estimateTokens over-estimates the token count of s. English prose averages
+// about four characters per token; three keeps reservations on the safe side.
+func estimateTokens(s string) int {
+	return len(s)/3 + 1
+}
@@ -0,0 +1,59 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPricing_Cost(t *testing.T) {
+	cost := DefaultPricing.Cost(Usage{InputTokens: 1_000_000, OutputTokens: 100_000})
+	assert.InDelta(t, 4.5, cost, 1e-9)
+
+	// Cache writes are billed at 1.25x the input price, reads at 0.1x.
+	cost = DefaultPricing.Cost(Usage{CacheWriteTokens: 1_000_000, CacheReadTokens: 1_000_000})
+	assert.InDelta(t, 3*1.25+3*0.1, cost, 1e-9)
+}
+
+func TestPricingFor(t *testing.T) {
+	assert.Equal(t, Pricing{InputPerMTok: 3, OutputPerMTok: 15}, PricingFor("claude-sonnet-4-20250514"))
+	assert.Equal(t, Pricing{InputPerMTok: 0.8, OutputPerMTok: 4}, PricingFor("claude-3-5-haiku-20241022"))
+	assert.Equal(t, DefaultPricing, PricingFor("claude-unknown"))
+}
+
+func TestBudget(t *testing.T) {
+	t.Run("reservations count against the limit", func(t *testing.T) {
+		b := NewBudget(1)
+
+		r1, err := b.Reserve(0.6)
+		require.NoError(t, err)
+
+		_, err = b.Reserve(0.6)
+		assert.ErrorIs(t, err, ErrBudgetExceeded, "two in-flight requests would overshoot")
+
+		b.Settle(r1, 0.1, Usage{InputTokens: 100_000})
+		assert.InDelta(t, 0.1, b.Spent(), 1e-9)
+
+		_, err = b.Reserve(0.6)
+		assert.NoError(t, err, "settling releases the unused reservation")
+	})
+
+	t.Run("zero limit only tracks spend", func(t *testing.T) {
+		b := NewBudget(0)
+
+		r, err := b.Reserve(1e6)
+		require.NoError(t, err)
+		b.Settle(r, 0.01, Usage{InputTokens: 10, OutputTokens: 20})
+
+		assert.Equal(t, Usage{InputTokens: 10, OutputTokens: 20}, b.Usage())
+	})
+
+	t.Run("nil budget is unlimited", func(t *testing.T) {
+		var b *Budget
+		r, err := b.Reserve(1e6)
+		require.NoError(t, err)
+		b.Settle(r, 1, Usage{InputTokens: 1})
+		assert.Zero(t, b.Spent())
+	})
+}
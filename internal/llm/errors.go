@@ -0,0 +1,110 @@
+package llm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error kinds returned by LLM backends. Every *APIError wraps exactly one
+// of them, so callers can branch with errors.Is.
+var (
+	// ErrRateLimited means the account's rate limit was hit (HTTP 429).
+	ErrRateLimited = errors.New("rate limited")
+	// ErrOverloaded means Anthropic is temporarily overloaded (HTTP 529).
+	ErrOverloaded = errors.New("overloaded")
+	// ErrUnavailable covers server errors and requests that never got a response.
+	ErrUnavailable = errors.New("unavailable")
+	// ErrInvalidRequest means the request itself was rejected; retrying it will not help.
+	ErrInvalidRequest = errors.New("invalid request")
+	// ErrContextLength means the prompt does not fit the model's context window.
+	ErrContextLength = errors.New("context length exceeded")
+	// ErrAuth means the API key is missing, invalid or lacks permission.
+	ErrAuth = errors.New("authentication failed")
+)
+
+// APIError is a classified error response from an LLM backend.
+type APIError struct {
+	Kind       error  // one of the Err* kinds above
+	StatusCode int    // HTTP status, 0 if no response was received
+	Type       string // Anthropic error type, e.g. "overloaded_error"
+	Message    string
+	RetryAfter time.Duration // server-requested delay before retrying, if any
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("LLM API %s: %s", e.Kind, e.Message)
+	}
+	return fmt.Sprintf("LLM API %s (status %d, %s): %s", e.Kind, e.StatusCode, e.Type, e.Message)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Kind
+}
+
+// IsRetryable reports whether err is a transient API failure that may
+// succeed if the request is sent again later.
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrRateLimited) ||
+		errors.Is(err, ErrOverloaded) ||
+		errors.Is(err, ErrUnavailable)
+}
+
+// classifyResponse builds an APIError from a non-200 response.
+func classifyResponse(status int, header http.Header, body []byte) *APIError {
+	var parsed struct {
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	message := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &parsed) == nil && parsed.Error.Type != "" {
+		message = parsed.Error.Message
+	}
+
+	apiErr := classifyError(status, parsed.Error.Type, message)
+	if secs, err := strconv.Atoi(header.Get("retry-after")); err == nil && secs > 0 {
+		apiErr.RetryAfter = time.Duration(secs) * time.Second
+	}
+	return apiErr
+}
+
+// classifyError maps an HTTP status and Anthropic error type to an APIError.
+func classifyError(status int, errType, message string) *APIError {
+	apiErr := &APIError{StatusCode: status, Type: errType, Message: message}
+
+	switch {
+	case errType == "rate_limit_error" || status == http.StatusTooManyRequests:
+		apiErr.Kind = ErrRateLimited
+	case errType == "overloaded_error" || status == 529:
+		apiErr.Kind = ErrOverloaded
+	case errType == "authentication_error" || errType == "permission_error" ||
+		status == http.StatusUnauthorized || status == http.StatusForbidden:
+		apiErr.Kind = ErrAuth
+	case errType == "request_too_large" || status == http.StatusRequestEntityTooLarge || isContextLengthMessage(message):
+		apiErr.Kind = ErrContextLength
+	case errType == "api_error" || status >= 500:
+		apiErr.Kind = ErrUnavailable
+	default:
+		apiErr.Kind = ErrInvalidRequest
+	}
+
+	return apiErr
+}
+
+// isContextLengthMessage recognises the invalid_request_error messages
+// Anthropic and OpenAI-compatible servers return when the prompt plus
+// max_tokens is too long.
+func isContextLengthMessage(message string) bool {
+	m := strings.ToLower(message)
+	return strings.Contains(m, "prompt is too long") ||
+		strings.Contains(m, "context limit") ||
+		strings.Contains(m, "context window") ||
+		strings.Contains(m, "maximum context length")
+}
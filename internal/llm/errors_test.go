@@ -0,0 +1,46 @@
+package llm
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"rate limited", 429, `{"type":"error","error":{"type":"rate_limit_error","message":"Number of requests has exceeded your rate limit"}}`, ErrRateLimited},
+		{"overloaded", 529, `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`, ErrOverloaded},
+		{"server error", 500, `{"type":"error","error":{"type":"api_error","message":"Internal server error"}}`, ErrUnavailable},
+		{"bad gateway without body", 502, `<html>bad gateway</html>`, ErrUnavailable},
+		{"auth", 401, `{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`, ErrAuth},
+		{"permission", 403, `{"type":"error","error":{"type":"permission_error","message":"no access"}}`, ErrAuth},
+		{"prompt too long", 400, `{"type":"error","error":{"type":"invalid_request_error","message":"prompt is too long: 210000 tokens > 200000 maximum"}}`, ErrContextLength},
+		{"request too large", 413, `{"type":"error","error":{"type":"request_too_large","message":"Request exceeds the maximum allowed number of bytes."}}`, ErrContextLength},
+		{"invalid request", 400, `{"type":"error","error":{"type":"invalid_request_error","message":"max_tokens: field required"}}`, ErrInvalidRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyResponse(tt.status, http.Header{}, []byte(tt.body))
+			assert.ErrorIs(t, err, tt.want)
+			assert.Equal(t, tt.status, err.StatusCode)
+		})
+	}
+}
+
+func TestClassifyResponse_RetryAfter(t *testing.T) {
+	h := http.Header{}
+	h.Set("retry-after", "12")
+
+	err := classifyResponse(429, h, []byte(`{"type":"error","error":{"type":"rate_limit_error","message":"slow down"}}`))
+
+	assert.Equal(t, 12*time.Second, err.RetryAfter)
+	assert.Equal(t, "slow down", err.Message)
+}
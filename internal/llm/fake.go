@@ -0,0 +1,78 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Fake is a deterministic Completer for tests. It answers Complete with
+// Text and CompleteTool with the canned JSON input registered for the tool,
+// which is validated against the tool's schema exactly like a real reply.
+type Fake struct {
+	// ModelName is returned by Model (default "fake").
+	ModelName string
+
+	// Text is the reply to Complete.
+	Text string
+
+	// ToolInputs maps a tool name to the JSON input the fake "calls" it with.
+	ToolInputs map[string]string
+
+	// Err, if set, is returned by every call.
+	Err error
+
+	mu    sync.Mutex
+	calls []FakeCall
+}
+
+// FakeCall records one request made to a Fake.
+type FakeCall struct {
+	System string
+	User   string
+	Tool   string // empty for Complete
+}
+
+// Model returns the fake model name.
+func (f *Fake) Model() string {
+	if f.ModelName == "" {
+		return "fake"
+	}
+	return f.ModelName
+}
+
+// Complete returns f.Text.
+func (f *Fake) Complete(ctx context.Context, system, user string) (string, error) {
+	f.record(FakeCall{System: system, User: user})
+	if f.Err != nil {
+		return "", f.Err
+	}
+	return f.Text, nil
+}
+
+// CompleteTool decodes the canned input for tool into out.
+func (f *Fake) CompleteTool(ctx context.Context, system, user string, tool Tool, out any) error {
+	f.record(FakeCall{System: system, User: user, Tool: tool.Name})
+	if f.Err != nil {
+		return f.Err
+	}
+
+	input, ok := f.ToolInputs[tool.Name]
+	if !ok {
+		return fmt.Errorf("%w: no %s call in response", ErrInvalidToolInput, tool.Name)
+	}
+	return decodeToolInput(tool, []byte(input), out)
+}
+
+// Calls returns the requests made so far.
+func (f *Fake) Calls() []FakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FakeCall(nil), f.calls...)
+}
+
+func (f *Fake) record(call FakeCall) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, call)
+}
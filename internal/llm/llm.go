@@ -0,0 +1,166 @@
+// Package llm provides the language-model backends used for extraction,
+// selection and filtering: the Anthropic API, any OpenAI-compatible chat
+// server (Ollama, llama.cpp, vLLM, OpenAI itself), and a deterministic fake
+// for tests.
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+// Completer is a language model that can answer a prompt with free text or
+// with a structured tool call.
+type Completer interface {
+	// Model returns the model name requests are sent to.
+	Model() string
+
+	// Complete answers the user prompt with text.
+	Complete(ctx context.Context, system, user string) (string, error)
+
+	// CompleteTool forces the model to answer by calling tool, validates the
+	// input it passes against the tool's schema and decodes it into out.
+	CompleteTool(ctx context.Context, system, user string, tool Tool, out any) error
+}
+
+// Tool is a tool the model can be asked to call. Forcing a tool call is how
+// we get structured output: the input the model passes is JSON matching
+// InputSchema.
+type Tool struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	InputSchema *Schema `json:"input_schema"`
+}
+
+// decodeToolInput validates a tool call's input against the tool's schema
+// and decodes it into out.
+func decodeToolInput(tool Tool, input []byte, out any) error {
+	if err := tool.InputSchema.Validate(input); err != nil {
+		return fmt.Errorf("%s: %w", tool.Name, err)
+	}
+	if err := json.Unmarshal(input, out); err != nil {
+		return fmt.Errorf("decode %s input: %w", tool.Name, err)
+	}
+	return nil
+}
+
+// Provider names accepted by Backend.
+const (
+	ProviderAnthropic = "anthropic"
+	ProviderOpenAI    = "openai"
+)
+
+// Backend describes where requests go; models are chosen per task.
+type Backend struct {
+	Provider string // "anthropic" (default) or "openai" for any OpenAI-compatible server
+	BaseURL  string // default depends on the provider
+	APIKey   string
+
+	// Limiter and Budget are shared by every Completer created from the backend.
+	Limiter *RateLimiter
+	Budget  *Budget
+}
+
+// New returns a Completer for model on this backend.
+func (b Backend) New(model string) (Completer, error) {
+	switch b.Provider {
+	case ProviderAnthropic, "":
+		return NewAnthropic(AnthropicConfig{
+			APIKey:  b.APIKey,
+			BaseURL: b.BaseURL,
+			Model:   model,
+			Limiter: b.Limiter,
+			Budget:  b.Budget,
+		}), nil
+	case ProviderOpenAI:
+		return NewOpenAI(OpenAIConfig{
+			APIKey:  b.APIKey,
+			BaseURL: b.BaseURL,
+			Model:   model,
+			Limiter: b.Limiter,
+			Budget:  b.Budget,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown LLM provider: %s", b.Provider)
+	}
+}
+
+// NewOptional is like New but returns a nil Completer when model is empty,
+// for tasks that are skipped unless a model is configured.
+func (b Backend) NewOptional(model string) (Completer, error) {
+	if model == "" {
+		return nil, nil
+	}
+	return b.New(model)
+}
+
+// RetryConfig controls how transient API failures are retried.
+type RetryConfig struct {
+	MaxRetries int           // retries after the first attempt
+	BaseDelay  time.Duration // backoff before the first retry, doubled each time
+	MaxDelay   time.Duration // cap on a single backoff
+}
+
+// DefaultRetryConfig retries rate-limit, overload and server errors for
+// roughly a minute before giving up.
+var DefaultRetryConfig = RetryConfig{
+	MaxRetries: 5,
+	BaseDelay:  2 * time.Second,
+	MaxDelay:   30 * time.Second,
+}
+
+// withRetry calls send until it succeeds, fails with an error that is not
+// retryable, or MaxRetries retries have been made. Retries wait a jittered
+// exponential backoff; the last error is returned unchanged.
+func withRetry[T any](ctx context.Context, cfg RetryConfig, model string, send func() (T, error)) (T, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := send()
+		if err == nil || !IsRetryable(err) || attempt >= cfg.MaxRetries || ctx.Err() != nil {
+			return resp, err
+		}
+
+		delay := cfg.backoff(attempt, err)
+		slog.Warn("llm request failed, retrying",
+			"model", model,
+			"attempt", attempt+1,
+			"max_retries", cfg.MaxRetries,
+			"delay", delay.Round(time.Millisecond),
+			"error", err,
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			var zero T
+			return zero, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the delay before retry number attempt+1: a random duration
+// up to BaseDelay*2^attempt (capped at MaxDelay), but never less than the
+// retry-after the API asked for.
+func (cfg RetryConfig) backoff(attempt int, err error) time.Duration {
+	ceiling := cfg.BaseDelay << attempt
+	if ceiling <= 0 || ceiling > cfg.MaxDelay {
+		ceiling = cfg.MaxDelay
+	}
+
+	var delay time.Duration
+	if ceiling > 0 {
+		delay = time.Duration(rand.Int64N(int64(ceiling)) + 1)
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+		delay = apiErr.RetryAfter
+	}
+	return delay
+}
@@ -0,0 +1,231 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultOpenAIURL is a local Ollama server's OpenAI-compatible endpoint.
+const defaultOpenAIURL = "http://localhost:11434/v1"
+
+// OpenAI is a Completer for any server speaking the OpenAI chat completions
+// API: Ollama, llama.cpp's server, vLLM or OpenAI itself. Structured output
+// uses forced function calls, which the server's model must support.
+//
+// Requests are not priced, so a Budget only records token usage; a local
+// model costs nothing per token.
+type OpenAI struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	model      string
+	retry      RetryConfig
+	limiter    *RateLimiter
+	budget     *Budget
+}
+
+// OpenAIConfig holds configuration for the OpenAI-compatible client.
+type OpenAIConfig struct {
+	APIKey  string // optional for local servers
+	Model   string
+	BaseURL string // default: http://localhost:11434/v1
+
+	// Retry overrides DefaultRetryConfig when non-zero.
+	Retry RetryConfig
+
+	Limiter *RateLimiter
+	Budget  *Budget
+}
+
+// NewOpenAI creates a client for an OpenAI-compatible server.
+func NewOpenAI(config OpenAIConfig) *OpenAI {
+	baseURL := strings.TrimSuffix(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultOpenAIURL
+	}
+
+	retry := config.Retry
+	if retry == (RetryConfig{}) {
+		retry = DefaultRetryConfig
+	}
+
+	return &OpenAI{
+		apiKey:  config.APIKey,
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			// Local models on modest hardware can be slow.
+			Timeout: 5 * time.Minute,
+		},
+		model:   config.Model,
+		retry:   retry,
+		limiter: config.Limiter,
+		budget:  config.Budget,
+	}
+}
+
+// Model returns the model requests are sent to.
+func (c *OpenAI) Model() string {
+	return c.model
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIFunction struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Parameters  *Schema `json:"parameters,omitempty"`
+}
+
+type openAITool struct {
+	Type     string         `json:"type"`
+	Function openAIFunction `json:"function"`
+}
+
+type openAIRequest struct {
+	Model      string          `json:"model"`
+	MaxTokens  int             `json:"max_tokens"`
+	Messages   []openAIMessage `json:"messages"`
+	Tools      []openAITool    `json:"tools,omitempty"`
+	ToolChoice *openAITool     `json:"tool_choice,omitempty"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message struct {
+			Content   string `json:"content"`
+			ToolCalls []struct {
+				ID       string `json:"id"`
+				Type     string `json:"type"`
+				Function struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// Complete answers the user prompt with text.
+func (c *OpenAI) Complete(ctx context.Context, system, user string) (string, error) {
+	resp, err := c.do(ctx, c.newRequest(system, user))
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == "" {
+		return "", fmt.Errorf("empty response from API")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// CompleteTool forces a call to tool and decodes its validated arguments into out.
+func (c *OpenAI) CompleteTool(ctx context.Context, system, user string, tool Tool, out any) error {
+	fn := openAITool{
+		Type:     "function",
+		Function: openAIFunction{Name: tool.Name, Description: tool.Description, Parameters: tool.InputSchema},
+	}
+	req := c.newRequest(system, user)
+	req.Tools = []openAITool{fn}
+	req.ToolChoice = &openAITool{Type: "function", Function: openAIFunction{Name: tool.Name}}
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	for _, choice := range resp.Choices {
+		for _, call := range choice.Message.ToolCalls {
+			if call.Function.Name != tool.Name {
+				continue
+			}
+			if choice.FinishReason == "length" {
+				return fmt.Errorf("%w: %s input truncated at max_tokens", ErrInvalidToolInput, tool.Name)
+			}
+			return decodeToolInput(tool, []byte(call.Function.Arguments), out)
+		}
+	}
+
+	return fmt.Errorf("%w: no %s call in response", ErrInvalidToolInput, tool.Name)
+}
+
+func (c *OpenAI) newRequest(system, user string) openAIRequest {
+	return openAIRequest{
+		Model:     c.model,
+		MaxTokens: maxTokens,
+		Messages: []openAIMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: user},
+		},
+	}
+}
+
+// do sends req, retrying transient failures.
+func (c *OpenAI) do(ctx context.Context, req openAIRequest) (*openAIResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	return withRetry(ctx, c.retry, c.model, func() (*openAIResponse, error) {
+		return c.send(ctx, body)
+	})
+}
+
+// send makes a single request to the chat completions endpoint.
+func (c *OpenAI) send(ctx context.Context, body []byte) (*openAIResponse, error) {
+	var usage Usage
+	defer func() { c.budget.Settle(0, 0, usage) }()
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, &APIError{Kind: ErrUnavailable, Message: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &APIError{Kind: ErrUnavailable, StatusCode: resp.StatusCode, Message: "read response: " + err.Error()}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, classifyResponse(resp.StatusCode, resp.Header, respBody)
+	}
+
+	var out openAIResponse
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+	usage = Usage{InputTokens: out.Usage.PromptTokens, OutputTokens: out.Usage.CompletionTokens}
+
+	return &out, nil
+}
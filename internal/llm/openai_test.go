@@ -0,0 +1,78 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAI_CompleteTool(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer local-key", r.Header.Get("Authorization"))
+
+		var req openAIRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "qwen2.5:7b", req.Model)
+		require.Len(t, req.Messages, 2)
+		assert.Equal(t, "system", req.Messages[0].Role)
+		require.Len(t, req.Tools, 1)
+		assert.Equal(t, testTool.Name, req.ToolChoice.Function.Name)
+
+		w.Write([]byte(`{
+			"choices": [{
+				"message": {"tool_calls": [{"id": "call_1", "type": "function",
+					"function": {"name": "record_items", "arguments": "{\"items\": [{\"text\": \"a\", \"tags\": [\"x\"]}]}"}}]},
+				"finish_reason": "tool_calls"
+			}],
+			"usage": {"prompt_tokens": 12, "completion_tokens": 7}
+		}`))
+	}))
+	defer server.Close()
+
+	budget := NewBudget(0)
+	client := NewOpenAI(OpenAIConfig{APIKey: "local-key", BaseURL: server.URL + "/v1", Model: "qwen2.5:7b", Budget: budget})
+
+	var out testItems
+	require.NoError(t, client.CompleteTool(context.Background(), "system", "user", testTool, &out))
+	require.Len(t, out.Items, 1)
+	assert.Equal(t, "a", out.Items[0].Text)
+	assert.Equal(t, Usage{InputTokens: 12, OutputTokens: 7}, budget.Usage())
+	assert.Zero(t, budget.Spent(), "OpenAI-compatible requests are not priced")
+}
+
+func TestOpenAI_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": {"message": "This model's maximum context length is 8192 tokens", "type": "invalid_request_error", "code": "context_length_exceeded"}}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenAI(OpenAIConfig{BaseURL: server.URL, Model: "m", Retry: fastRetry})
+
+	_, err := client.Complete(context.Background(), "system", "user")
+	assert.ErrorIs(t, err, ErrContextLength)
+}
+
+func TestBackend_New(t *testing.T) {
+	c, err := Backend{}.New("claude-3-5-haiku-20241022")
+	require.NoError(t, err)
+	assert.IsType(t, &Anthropic{}, c)
+	assert.Equal(t, "claude-3-5-haiku-20241022", c.Model())
+
+	c, err = Backend{Provider: ProviderOpenAI}.New("llama3.1")
+	require.NoError(t, err)
+	assert.IsType(t, &OpenAI{}, c)
+
+	c, err = Backend{}.NewOptional("")
+	require.NoError(t, err)
+	assert.Nil(t, c)
+
+	_, err = Backend{Provider: "gemini"}.New("m")
+	assert.Error(t, err)
+}
@@ -0,0 +1,133 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateLimitHeaders are the limits Anthropic reports on every response as
+// anthropic-ratelimit-<name>-remaining and anthropic-ratelimit-<name>-reset.
+var rateLimitHeaders = []string{"requests", "tokens", "input-tokens", "output-tokens"}
+
+// RateLimiter is a token bucket shared by all requests to the Claude API.
+// It spaces requests to a requests-per-minute rate and additionally honours
+// the rate-limit headers Anthropic returns: when a limit is exhausted (or the
+// API answers with retry-after) every caller waits until it resets.
+//
+// A nil *RateLimiter never blocks.
+type RateLimiter struct {
+	mu          sync.Mutex
+	rate        float64 // tokens per second; 0 until the API reports a limit
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	now         func() time.Time
+}
+
+// NewRateLimiter creates a limiter allowing requestsPerMinute requests.
+// With a non-positive rate it only follows the API's headers: requests go
+// out unspaced until a response reports the account's request limit, and
+// exhausted limits and retry-after still pause every caller.
+func NewRateLimiter(requestsPerMinute int) *RateLimiter {
+	return &RateLimiter{
+		rate:   max(float64(requestsPerMinute)/60, 0),
+		tokens: 1,
+		now:    time.Now,
+	}
+}
+
+// Wait blocks until a request may be sent or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+
+	for {
+		delay := l.reserve()
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token if one is available and otherwise returns how long
+// to wait before trying again.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.rate == 0 {
+		return 0
+	}
+
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > 1 {
+			l.tokens = 1
+		}
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// Update adjusts the limiter from the headers of an API response.
+func (l *RateLimiter) Update(h http.Header) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	// Never go faster than the account's request limit.
+	if limit, err := strconv.Atoi(h.Get("anthropic-ratelimit-requests-limit")); err == nil && limit > 0 {
+		if rate := float64(limit) / 60; l.rate == 0 || rate < l.rate {
+			l.rate = rate
+		}
+	}
+
+	for _, name := range rateLimitHeaders {
+		remaining, err := strconv.Atoi(h.Get("anthropic-ratelimit-" + name + "-remaining"))
+		if err != nil || remaining > 0 {
+			continue
+		}
+		reset, err := time.Parse(time.RFC3339, h.Get("anthropic-ratelimit-"+name+"-reset"))
+		if err != nil {
+			continue
+		}
+		l.pauseUntil(reset)
+	}
+
+	if secs, err := strconv.Atoi(h.Get("retry-after")); err == nil && secs > 0 {
+		l.pauseUntil(now.Add(time.Duration(secs) * time.Second))
+	}
+}
+
+// pauseUntil holds back all requests until t. Must be called with l.mu held.
+func (l *RateLimiter) pauseUntil(t time.Time) {
+	if t.After(l.pausedUntil) {
+		l.pausedUntil = t
+	}
+}
@@ -0,0 +1,101 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLimiter(rpm int, now *time.Time) *RateLimiter {
+	l := NewRateLimiter(rpm)
+	l.now = func() time.Time { return *now }
+	return l
+}
+
+func TestRateLimiter_Bucket(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newTestLimiter(60, &now)
+
+	assert.Zero(t, l.reserve(), "first request goes straight through")
+	assert.Equal(t, time.Second, l.reserve(), "second waits for a refill")
+
+	now = now.Add(time.Second)
+	assert.Zero(t, l.reserve())
+}
+
+func TestRateLimiter_Update(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("exhausted limit pauses until reset", func(t *testing.T) {
+		l := newTestLimiter(600, &now)
+		h := http.Header{}
+		h.Set("anthropic-ratelimit-tokens-remaining", "0")
+		h.Set("anthropic-ratelimit-tokens-reset", now.Add(30*time.Second).Format(time.RFC3339))
+
+		l.Update(h)
+
+		assert.Equal(t, 30*time.Second, l.reserve())
+	})
+
+	t.Run("retry-after pauses", func(t *testing.T) {
+		l := newTestLimiter(600, &now)
+		h := http.Header{}
+		h.Set("retry-after", "7")
+
+		l.Update(h)
+
+		assert.Equal(t, 7*time.Second, l.reserve())
+	})
+
+	t.Run("lower account limit slows the bucket", func(t *testing.T) {
+		l := newTestLimiter(600, &now)
+		h := http.Header{}
+		h.Set("anthropic-ratelimit-requests-limit", "30")
+		h.Set("anthropic-ratelimit-requests-remaining", "29")
+
+		l.Update(h)
+
+		assert.Zero(t, l.reserve())
+		assert.Equal(t, 2*time.Second, l.reserve())
+	})
+}
+
+func TestRateLimiter_HeadersOnly(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newTestLimiter(0, &now)
+
+	assert.Zero(t, l.reserve())
+	assert.Zero(t, l.reserve(), "no local rate")
+
+	h := http.Header{}
+	h.Set("retry-after", "5")
+	l.Update(h)
+	assert.Equal(t, 5*time.Second, l.reserve(), "server pauses still apply")
+
+	now = now.Add(5 * time.Second)
+	h = http.Header{}
+	h.Set("anthropic-ratelimit-requests-limit", "60")
+	l.Update(h)
+	assert.Zero(t, l.reserve())
+	assert.Equal(t, time.Second, l.reserve(), "the account's limit becomes the rate")
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	t.Run("nil limiter never blocks", func(t *testing.T) {
+		var l *RateLimiter
+		require.NoError(t, l.Wait(context.Background()))
+	})
+
+	t.Run("honours context cancellation", func(t *testing.T) {
+		l := NewRateLimiter(1)
+		require.NoError(t, l.Wait(context.Background()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, l.Wait(ctx), context.DeadlineExceeded)
+	})
+}
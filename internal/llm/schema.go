@@ -0,0 +1,171 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// ErrInvalidToolInput is returned when a tool call's input does not match
+// the tool's schema.
+var ErrInvalidToolInput = errors.New("tool input does not match schema")
+
+// Schema is the subset of JSON Schema used for tool inputs: enough to
+// describe a tool to Claude and to check the input it sends back before it
+// is decoded into a Go type.
+type Schema struct {
+	Type        string             `json:"type"` // object, array, string, number, integer or boolean
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Minimum     *float64           `json:"minimum,omitempty"`
+	Maximum     *float64           `json:"maximum,omitempty"`
+	MinItems    *int               `json:"minItems,omitempty"`
+	MaxItems    *int               `json:"maxItems,omitempty"`
+
+	// AdditionalProperties is always sent as false for objects: tool inputs
+	// never carry fields the Go type does not know about.
+	AdditionalProperties *bool `json:"additionalProperties,omitempty"`
+}
+
+// Float returns a pointer to v, for Minimum and Maximum.
+func Float(v float64) *float64 { return &v }
+
+// Int returns a pointer to v, for MinItems and MaxItems.
+func Int(v int) *int { return &v }
+
+// MarshalJSON closes objects to unknown properties.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	type plain Schema
+	out := plain(*s)
+	if out.Type == "object" && out.AdditionalProperties == nil {
+		closed := false
+		out.AdditionalProperties = &closed
+	}
+	return json.Marshal(out)
+}
+
+// Validate checks that data is a JSON document matching the schema.
+func (s *Schema) Validate(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidToolInput, err)
+	}
+	if err := s.validate("$", v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidToolInput, err)
+	}
+	return nil
+}
+
+func (s *Schema) validate(path string, v any) error {
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return typeError(path, s.Type, v)
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := s.Properties[name]
+			if !ok {
+				return fmt.Errorf("%s: unexpected property %q", path, name)
+			}
+			if err := prop.validate(path+"."+name, obj[name]); err != nil {
+				return err
+			}
+		}
+
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return typeError(path, s.Type, v)
+		}
+		if s.MinItems != nil && len(arr) < *s.MinItems {
+			return fmt.Errorf("%s: %d items, want at least %d", path, len(arr), *s.MinItems)
+		}
+		if s.MaxItems != nil && len(arr) > *s.MaxItems {
+			return fmt.Errorf("%s: %d items, want at most %d", path, len(arr), *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range arr {
+				if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+					return err
+				}
+			}
+		}
+
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return typeError(path, s.Type, v)
+		}
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, str) {
+			return fmt.Errorf("%s: %q is not one of %v", path, str, s.Enum)
+		}
+
+	case "number", "integer":
+		num, ok := v.(json.Number)
+		if !ok {
+			return typeError(path, s.Type, v)
+		}
+		if s.Type == "integer" {
+			if _, err := num.Int64(); err != nil {
+				return typeError(path, s.Type, v)
+			}
+		}
+		f, err := num.Float64()
+		if err != nil {
+			return typeError(path, s.Type, v)
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			return fmt.Errorf("%s: %v is below minimum %v", path, f, *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			return fmt.Errorf("%s: %v is above maximum %v", path, f, *s.Maximum)
+		}
+
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return typeError(path, s.Type, v)
+		}
+
+	default:
+		return fmt.Errorf("%s: unsupported schema type %q", path, s.Type)
+	}
+
+	return nil
+}
+
+func typeError(path, want string, v any) error {
+	got := "null"
+	switch v.(type) {
+	case map[string]any:
+		got = "object"
+	case []any:
+		got = "array"
+	case string:
+		got = "string"
+	case json.Number:
+		got = "number"
+	case bool:
+		got = "boolean"
+	}
+	return fmt.Errorf("%s: got %s, want %s", path, got, want)
+}
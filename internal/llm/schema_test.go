@@ -0,0 +1,64 @@
+package llm
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	schema := &Schema{
+		Type:     "object",
+		Required: []string{"index", "score"},
+		Properties: map[string]*Schema{
+			"index":   {Type: "integer", Minimum: Float(-1)},
+			"score":   {Type: "number", Minimum: Float(0), Maximum: Float(1)},
+			"verdict": {Type: "string", Enum: []string{"post", "skip"}},
+			"tags":    {Type: "array", MaxItems: Int(2), Items: &Schema{Type: "string"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"valid", `{"index": 2, "score": 0.8, "verdict": "post", "tags": ["a"]}`, ""},
+		{"missing required", `{"index": 2}`, `missing required property "score"`},
+		{"unknown property", `{"index": 2, "score": 0.5, "extra": true}`, `unexpected property "extra"`},
+		{"wrong type", `{"index": "2", "score": 0.5}`, "$.index: got string, want integer"},
+		{"fraction for integer", `{"index": 1.5, "score": 0.5}`, "$.index: got number, want integer"},
+		{"above maximum", `{"index": 0, "score": 1.2}`, "above maximum"},
+		{"not in enum", `{"index": 0, "score": 0.5, "verdict": "maybe"}`, "is not one of"},
+		{"too many items", `{"index": 0, "score": 0.5, "tags": ["a", "b", "c"]}`, "want at most 2"},
+		{"bad item", `{"index": 0, "score": 0.5, "tags": [1]}`, "$.tags[0]: got number, want string"},
+		{"not json", `{"index": `, "tool input does not match schema"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate([]byte(tt.input))
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidToolInput)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSchema_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(testTool.InputSchema)
+	require.NoError(t, err)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "object", decoded["type"])
+	assert.Equal(t, false, decoded["additionalProperties"])
+
+	item := decoded["properties"].(map[string]any)["items"].(map[string]any)["items"].(map[string]any)
+	assert.Equal(t, false, item["additionalProperties"], "nested objects are closed too")
+}
@@ -0,0 +1,109 @@
+package matcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/llm"
+)
+
+// selectionPromptVersion identifies the prompts and tool schema a cached
+// evaluation was made with; editing any of them invalidates the cache.
+var selectionPromptVersion = promptVersion(SelectionSystemPrompt, BatchSelectionPrompt, batchEvaluationTool)
+
+func promptVersion(system, prompt string, tool llm.Tool) string {
+	schema, _ := json.Marshal(tool)
+	h := sha256.New()
+	for _, part := range []string{system, prompt, string(schema)} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// selectionCacheKey identifies a batch evaluation: the trend's content, the
+// candidate quotes in the order they were numbered in the prompt along with
+// their text, so edited quotes are judged again, the prompt version and the
+// model that judged them.
+func selectionCacheKey(trend *db.Trend, quotes []*db.Quote, model string) db.GetSelectionCacheParams {
+	h := sha256.Sum256([]byte(trend.Title + "\x00" + trend.Description.String))
+
+	ids := make([]string, len(quotes))
+	text := sha256.New()
+	for i, q := range quotes {
+		ids[i] = strconv.FormatInt(q.ID, 10)
+		text.Write([]byte(q.Text))
+		text.Write([]byte{0})
+	}
+
+	return db.GetSelectionCacheParams{
+		TrendHash:     hex.EncodeToString(h[:16]),
+		QuoteIds:      strings.Join(ids, ","),
+		QuotesHash:    hex.EncodeToString(text.Sum(nil)[:16]),
+		PromptVersion: selectionPromptVersion,
+		Model:         model,
+	}
+}
+
+// cachedEvaluation returns a stored evaluation for key, counting the hit.
+// Cache failures are logged and treated as a miss.
+func (s *Selector) cachedEvaluation(ctx context.Context, key db.GetSelectionCacheParams) (*BatchEvaluationResult, bool) {
+	if s.cache == nil {
+		return nil, false
+	}
+
+	entry, err := s.cache.GetSelectionCache(ctx, key)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Warn("read selection cache", "error", err)
+		}
+		return nil, false
+	}
+
+	var result BatchEvaluationResult
+	if err := json.Unmarshal([]byte(entry.Result), &result); err != nil {
+		slog.Warn("decode cached selection", "id", entry.ID, "error", err)
+		return nil, false
+	}
+
+	if err := s.cache.RecordSelectionCacheHit(ctx, entry.ID); err != nil {
+		slog.Warn("record selection cache hit", "error", err)
+	}
+	slog.Debug("selection cache hit", "trend_hash", key.TrendHash, "hits", entry.Hits+1)
+
+	return &result, true
+}
+
+// storeEvaluation saves result under key. Failures are logged; the
+// evaluation is still returned to the caller.
+func (s *Selector) storeEvaluation(ctx context.Context, key db.GetSelectionCacheParams, result *BatchEvaluationResult) {
+	if s.cache == nil {
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		slog.Warn("encode selection for cache", "error", err)
+		return
+	}
+
+	err = s.cache.CreateSelectionCache(ctx, db.CreateSelectionCacheParams{
+		TrendHash:     key.TrendHash,
+		QuoteIds:      key.QuoteIds,
+		QuotesHash:    key.QuotesHash,
+		PromptVersion: key.PromptVersion,
+		Model:         key.Model,
+		Result:        string(data),
+	})
+	if err != nil {
+		slog.Warn("write selection cache", "error", err)
+	}
+}
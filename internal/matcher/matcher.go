@@ -7,6 +7,8 @@ import (
 
 	"github.com/abdulachik/dostobot/internal/db"
 	"github.com/abdulachik/dostobot/internal/embedder"
+	"github.com/abdulachik/dostobot/internal/llm"
+	"github.com/abdulachik/dostobot/internal/themes"
 	"github.com/abdulachik/dostobot/internal/vectorstore"
 )
 
@@ -22,25 +24,32 @@ type MatchResult struct {
 // Matcher orchestrates the quote matching process.
 type Matcher struct {
 	store          *db.Store
-	embedder       *embedder.Embedder
+	embedder       embedder.Provider
 	batchEmbedder  *embedder.BatchEmbedder
 	selector       *Selector
-	vectorIndex    *VectorIndex           // Legacy in-memory index (used if quoteStore is nil)
+	vectorIndex    *VectorIndex            // Legacy in-memory index (used if quoteStore is nil)
 	quoteStore     *vectorstore.QuoteStore // VecLite-based store (preferred)
 	minSimilarity  float32
 	minRelevance   float64
 	candidateCount int
+	language       string
+	approvedOnly   bool
+	themes         map[string]bool
 }
 
 // Config holds configuration for the matcher.
 type Config struct {
 	Store          *db.Store
-	Embedder       *embedder.Embedder
+	Embedder       embedder.Provider
 	QuoteStore     *vectorstore.QuoteStore // Optional: use VecLite instead of in-memory index
-	APIKey         string
-	MinSimilarity  float32 // Minimum vector similarity (default: 0.5)
-	MinRelevance   float64 // Minimum Claude relevance score (default: 0.6)
-	CandidateCount int     // Number of vector search candidates (default: 10)
+	LLM            llm.Completer           // Judges candidate quotes (the strong model)
+	FilterLLM      llm.Completer           // Optional: screens trends first (a cheap model)
+	MinSimilarity  float32                 // Minimum vector similarity (default: 0.5)
+	MinRelevance   float64                 // Minimum LLM relevance score (default: 0.6)
+	CandidateCount int                     // Number of vector search candidates (default: 10)
+	Language       string                  // Only match quotes in this language (default: any)
+	ApprovedOnly   bool                    // Only match quotes an editor approved (curation mode)
+	Themes         []string                // Only match quotes with one of these themes (default: any)
 }
 
 // New creates a new Matcher.
@@ -62,6 +71,14 @@ func New(cfg Config) *Matcher {
 		candCount = 10
 	}
 
+	var onlyThemes map[string]bool
+	if len(cfg.Themes) > 0 {
+		onlyThemes = make(map[string]bool, len(cfg.Themes))
+		for _, t := range cfg.Themes {
+			onlyThemes[themes.Key(t)] = true
+		}
+	}
+
 	return &Matcher{
 		store:    cfg.Store,
 		embedder: cfg.Embedder,
@@ -69,12 +86,55 @@ func New(cfg Config) *Matcher {
 			Embedder: cfg.Embedder,
 			Store:    cfg.Store,
 		}),
-		selector:       NewSelector(SelectorConfig{APIKey: cfg.APIKey}),
+		selector:       NewSelector(SelectorConfig{LLM: cfg.LLM, Filter: cfg.FilterLLM, Cache: cfg.Store}),
 		quoteStore:     cfg.QuoteStore,
 		minSimilarity:  minSim,
 		minRelevance:   minRel,
 		candidateCount: candCount,
+		language:       cfg.Language,
+		approvedOnly:   cfg.ApprovedOnly,
+		themes:         onlyThemes,
+	}
+}
+
+// Eligible reports whether a quote may be posted: it was not rejected by
+// the quality review or an editor, is in the language posted, has one of
+// the themes posting is steered to and, in curation mode, was approved by
+// an editor.
+func (m *Matcher) Eligible(q *db.Quote) bool {
+	if q.Rejected() || q.Discarded() || (m.approvedOnly && !q.Approved()) {
+		return false
 	}
+	if m.language != "" && q.Language != m.language {
+		return false
+	}
+	return m.hasTheme(q)
+}
+
+// hasTheme reports whether a quote has one of the configured themes, or
+// whether no themes are configured.
+func (m *Matcher) hasTheme(q *db.Quote) bool {
+	if m.themes == nil {
+		return true
+	}
+	for _, t := range themes.Parse(q) {
+		if m.themes[themes.Key(t)] {
+			return true
+		}
+	}
+	return false
+}
+
+// SetIndex swaps the embedding provider and the VecLite store used for
+// search, e.g. after a reindex with a new embedding model. The two must
+// agree on the model. Passing a nil store falls back to the in-memory index.
+func (m *Matcher) SetIndex(provider embedder.Provider, qs *vectorstore.QuoteStore) {
+	m.embedder = provider
+	m.batchEmbedder = embedder.NewBatchEmbedder(embedder.BatchConfig{
+		Embedder: provider,
+		Store:    m.store,
+	})
+	m.quoteStore = qs
 }
 
 // UseVecLite returns true if VecLite is configured.
@@ -93,6 +153,9 @@ func (m *Matcher) LoadIndex(ctx context.Context) error {
 	}
 
 	// Fall back to legacy in-memory index
+	if m.embedder == nil {
+		return fmt.Errorf("no embedding provider configured")
+	}
 	slog.Info("loading in-memory vector index")
 
 	quotesWithEmbed, err := m.batchEmbedder.LoadAllEmbeddings(ctx)
@@ -100,7 +163,14 @@ func (m *Matcher) LoadIndex(ctx context.Context) error {
 		return fmt.Errorf("load embeddings: %w", err)
 	}
 
-	m.vectorIndex = NewVectorIndex(quotesWithEmbed)
+	eligible := quotesWithEmbed[:0]
+	for _, q := range quotesWithEmbed {
+		if m.Eligible(q.Quote) {
+			eligible = append(eligible, q)
+		}
+	}
+
+	m.vectorIndex = NewVectorIndex(eligible)
 	slog.Info("vector index loaded", "quotes", m.vectorIndex.Size())
 
 	return nil
@@ -130,6 +200,17 @@ func (m *Matcher) Match(ctx context.Context, trend *db.Trend) (*MatchResult, err
 		return nil, fmt.Errorf("no quotes in index")
 	}
 
+	// Let the cheap filter model reject unpromising trends before any
+	// search or selection work
+	suitable, reason, err := m.selector.Screen(ctx, trend)
+	if err != nil {
+		return nil, fmt.Errorf("screen trend: %w", err)
+	}
+	if !suitable {
+		slog.Debug("trend screened out", "trend", trend.Title, "reason", reason)
+		return nil, nil
+	}
+
 	// Generate embedding for trend
 	trendText := trend.Title
 	if trend.Description.Valid && trend.Description.String != "" {
@@ -160,6 +241,9 @@ func (m *Matcher) Match(ctx context.Context, trend *db.Trend) (*MatchResult, err
 				slog.Warn("quote not found in SQLite", "sqlite_id", r.SQLiteID, "error", err)
 				continue
 			}
+			if !m.Eligible(quote) {
+				continue
+			}
 			candidates = append(candidates, VectorMatch{
 				Quote:      quote,
 				Similarity: r.Similarity,
@@ -174,6 +258,10 @@ func (m *Matcher) Match(ctx context.Context, trend *db.Trend) (*MatchResult, err
 		candidates = m.vectorIndex.SearchWithThreshold(trendEmbed, m.minSimilarity, m.candidateCount)
 	}
 
+	// Translations of one passage compete as a single candidate; the
+	// best-phrased one is chosen once the passage wins
+	candidates = distinctPassages(candidates)
+
 	if len(candidates) == 0 {
 		slog.Debug("no candidates above similarity threshold",
 			"trend", trend.Title,
@@ -193,7 +281,7 @@ func (m *Matcher) Match(ctx context.Context, trend *db.Trend) (*MatchResult, err
 		quotes[i] = c.Quote
 	}
 
-	// Use Claude to select the best match
+	// Use the LLM to select the best match
 	batchResult, err := m.selector.EvaluateBatch(ctx, trend, quotes)
 	if err != nil {
 		return nil, fmt.Errorf("evaluate batch: %w", err)
@@ -238,7 +326,7 @@ func (m *Matcher) Match(ctx context.Context, trend *db.Trend) (*MatchResult, err
 	}
 
 	return &MatchResult{
-		Quote:            bestCandidate.Quote,
+		Quote:            m.pickVariant(ctx, trend, bestCandidate.Quote),
 		Trend:            trend,
 		VectorSimilarity: bestCandidate.Similarity,
 		RelevanceScore:   relevance,
@@ -246,6 +334,62 @@ func (m *Matcher) Match(ctx context.Context, trend *db.Trend) (*MatchResult, err
 	}, nil
 }
 
+// distinctPassages drops candidates in the same variant group as a more
+// similar candidate, keeping the order of the rest.
+func distinctPassages(candidates []VectorMatch) []VectorMatch {
+	seen := make(map[int64]bool)
+	kept := candidates[:0]
+	for _, c := range candidates {
+		if g := c.Quote.GroupID; g.Valid {
+			if seen[g.Int64] {
+				continue
+			}
+			seen[g.Int64] = true
+		}
+		kept = append(kept, c)
+	}
+	return kept
+}
+
+// pickVariant returns the translation of q's passage, among the eligible
+// quotes of its variant group, that the LLM finds reads best as a post
+// about trend. q is returned when it has no variants or the choice fails.
+func (m *Matcher) pickVariant(ctx context.Context, trend *db.Trend, q *db.Quote) *db.Quote {
+	if !q.GroupID.Valid {
+		return q
+	}
+
+	group, err := m.store.ListGroupQuotes(ctx, q.GroupID)
+	if err != nil {
+		slog.Warn("failed to load quote variants", "quote", q.ID, "error", err)
+		return q
+	}
+	var variants []*db.Quote
+	for _, v := range group {
+		if m.Eligible(v) {
+			variants = append(variants, v)
+		}
+	}
+	if len(variants) < 2 {
+		return q
+	}
+
+	best, reasoning, err := m.selector.PickVariant(ctx, trend, variants)
+	if err != nil {
+		slog.Warn("failed to pick quote variant, keeping match", "quote", q.ID, "error", err)
+		return q
+	}
+	if variants[best].ID != q.ID {
+		slog.Info("picked translation variant",
+			"matched", q.ID,
+			"posted", variants[best].ID,
+			"book", variants[best].SourceBook,
+			"reasoning", reasoning,
+		)
+	}
+	return variants[best]
+}
+
 // MatchText matches a text query (not a stored trend) to a quote.
 func (m *Matcher) MatchText(ctx context.Context, text string) (*MatchResult, error) {
 	// Ensure index is loaded
@@ -262,22 +406,23 @@ func (m *Matcher) MatchText(ctx context.Context, text string) (*MatchResult, err
 			return nil, fmt.Errorf("veclite search: %w", err)
 		}
 
-		if len(results) == 0 {
-			return nil, nil
-		}
-
-		best := results[0]
+		// Return the best result the quality review did not reject, in the
+		// language posted
+		for _, r := range results {
+			quote, err := m.store.GetQuote(ctx, r.SQLiteID)
+			if err != nil {
+				return nil, fmt.Errorf("get quote: %w", err)
+			}
+			if !m.Eligible(quote) {
+				continue
+			}
 
-		// Get full quote from SQLite
-		quote, err := m.store.GetQuote(ctx, best.SQLiteID)
-		if err != nil {
-			return nil, fmt.Errorf("get quote: %w", err)
+			return &MatchResult{
+				Quote:            quote,
+				VectorSimilarity: r.Similarity,
+			}, nil
 		}
-
-		return &MatchResult{
-			Quote:            quote,
-			VectorSimilarity: best.Similarity,
-		}, nil
+		return nil, nil
 	}
 
 	// Fall back to legacy in-memory search
@@ -1,71 +1,32 @@
 package matcher
 
 import (
+	"context"
+	"database/sql"
+	"path/filepath"
 	"testing"
 
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/llm"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func TestExtractJSON(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    string
-		expected string
-	}{
-		{
-			name:     "clean json",
-			input:    `{"key": "value"}`,
-			expected: `{"key": "value"}`,
-		},
-		{
-			name:     "json with preamble",
-			input:    `Here is the result:\n{"key": "value"}`,
-			expected: `{"key": "value"}`,
-		},
-		{
-			name:     "json with suffix",
-			input:    `{"key": "value"}\n\nHope this helps!`,
-			expected: `{"key": "value"}`,
-		},
-		{
-			name: "nested json",
-			input: `{"outer": {"inner": "value"}}`,
-			expected: `{"outer": {"inner": "value"}}`,
-		},
-		{
-			name:     "no json",
-			input:    "Just plain text",
-			expected: "",
-		},
-		{
-			name:     "incomplete json",
-			input:    `{"key": "value"`,
-			expected: "",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := extractJSON(tt.input)
-			assert.Equal(t, tt.expected, result)
-		})
-	}
-}
-
 func TestNew(t *testing.T) {
 	m := New(Config{
-		APIKey: "test-key",
+		LLM: &llm.Fake{},
 	})
 
 	assert.NotNil(t, m)
-	assert.Equal(t, float32(0.5), m.minSimilarity)
+	assert.Equal(t, float32(0.01), m.minSimilarity)
 	assert.Equal(t, float64(0.6), m.minRelevance)
 	assert.Equal(t, 10, m.candidateCount)
 }
 
 func TestNew_CustomConfig(t *testing.T) {
 	m := New(Config{
-		APIKey:         "test-key",
+		LLM:            &llm.Fake{},
 		MinSimilarity:  0.7,
 		MinRelevance:   0.8,
 		CandidateCount: 20,
@@ -75,3 +36,85 @@ func TestNew_CustomConfig(t *testing.T) {
 	assert.Equal(t, float64(0.8), m.minRelevance)
 	assert.Equal(t, 20, m.candidateCount)
 }
+
+func TestDistinctPassages(t *testing.T) {
+	group := sql.NullInt64{Int64: 1, Valid: true}
+	candidates := []VectorMatch{
+		{Quote: &db.Quote{ID: 1, GroupID: group}, Similarity: 0.9},
+		{Quote: &db.Quote{ID: 2}, Similarity: 0.8},
+		{Quote: &db.Quote{ID: 3, GroupID: group}, Similarity: 0.7},
+		{Quote: &db.Quote{ID: 4}, Similarity: 0.6},
+	}
+
+	kept := distinctPassages(candidates)
+	require.Len(t, kept, 3)
+	assert.Equal(t, []int64{1, 2, 4}, []int64{kept[0].Quote.ID, kept[1].Quote.ID, kept[2].Quote.ID})
+}
+
+func TestMatcher_PickVariant(t *testing.T) {
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	require.NoError(t, store.Migrate(ctx))
+
+	group, err := store.CreateQuoteGroup(ctx)
+	require.NoError(t, err)
+	groupID := sql.NullInt64{Int64: group.ID, Valid: true}
+
+	create := func(book, language, text string) *db.Quote {
+		q, err := store.CreateQuote(ctx, db.CreateQuoteParams{
+			Text:       text,
+			TextHash:   text,
+			SourceBook: book,
+			Themes:     "[]",
+			Language:   language,
+		})
+		require.NoError(t, err)
+		require.NoError(t, store.SetQuoteGroup(ctx, db.SetQuoteGroupParams{GroupID: groupID, ID: q.ID}))
+		q.GroupID = groupID
+		return q
+	}
+	garnett := create("Crime and Punishment (Garnett)", "en", "Pain and suffering are always inevitable for a large intelligence and a deep heart.")
+	pevear := create("Crime and Punishment (Pevear and Volokhonsky)", "en", "Suffering and pain are always obligatory for a broad consciousness and a deep heart.")
+	create("Преступление и наказание", "ru", "Страдание и боль всегда обязательны для широкого сознания и глубокого сердца.")
+
+	fake := &llm.Fake{ToolInputs: map[string]string{
+		variantTool.Name: `{"best_index": 1, "reasoning": "plainer"}`,
+	}}
+	m := New(Config{Store: store, LLM: fake, Language: "en"})
+
+	picked := m.pickVariant(ctx, &db.Trend{Title: "Burnout"}, garnett)
+	assert.Equal(t, pevear.ID, picked.ID)
+
+	calls := fake.Calls()
+	require.Len(t, calls, 1)
+	assert.NotContains(t, calls[0].User, "Страдание", "only quotes in the posted language are offered")
+
+	// Quotes outside a group are kept without a call
+	alone := &db.Quote{ID: 99, Text: "alone"}
+	assert.Same(t, alone, m.pickVariant(ctx, &db.Trend{Title: "Burnout"}, alone))
+	assert.Len(t, fake.Calls(), 1)
+}
+
+func TestMatcher_Eligible(t *testing.T) {
+	approved := &db.Quote{Language: "en", CurationStatus: sql.NullString{String: db.CurationApproved, Valid: true}}
+	pending := &db.Quote{Language: "en"}
+	discarded := &db.Quote{Language: "en", CurationStatus: sql.NullString{String: db.CurationRejected, Valid: true}}
+	russian := &db.Quote{Language: "ru", CurationStatus: approved.CurationStatus}
+
+	m := New(Config{LLM: &llm.Fake{}, Language: "en"})
+	assert.True(t, m.Eligible(approved))
+	assert.True(t, m.Eligible(pending))
+	assert.False(t, m.Eligible(discarded), "quotes an editor rejected are never matched")
+	assert.False(t, m.Eligible(russian))
+
+	curated := New(Config{LLM: &llm.Fake{}, Language: "en", ApprovedOnly: true})
+	assert.True(t, curated.Eligible(approved))
+	assert.False(t, curated.Eligible(pending), "curation mode only matches approved quotes")
+
+	steered := New(Config{LLM: &llm.Fake{}, Themes: []string{"Human Nature", "faith"}})
+	assert.True(t, steered.Eligible(&db.Quote{Themes: `["love","human nature"]`}))
+	assert.False(t, steered.Eligible(&db.Quote{Themes: `["love"]`}), "quotes without a configured theme are not matched")
+	assert.False(t, steered.Eligible(pending))
+}
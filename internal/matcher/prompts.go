@@ -1,5 +1,15 @@
 package matcher
 
+// ScreeningSystemPrompt is the system prompt for the cheap trend screen.
+const ScreeningSystemPrompt = `You screen trending topics for a bot that replies to them with quotes from Dostoyevsky. Pass topics with a human, moral, psychological or social angle that a 19th-century novelist could speak to. Reject topics that are purely technical, promotional, trivial, or so raw (tragedy, active conflict) that a literary quote would seem glib.`
+
+// ScreeningPrompt is the user prompt template for the trend screen.
+const ScreeningPrompt = `TRENDING TOPIC:
+Title: %s
+Description: %s
+
+Is this topic worth pairing with a Dostoyevsky quote? Record your verdict with the screen_trend tool.`
+
 // SelectionSystemPrompt is the system prompt for quote selection.
 const SelectionSystemPrompt = `You are an expert at connecting Dostoyevsky's literary wisdom to contemporary topics. Your task is to evaluate whether a quote would make a thoughtful, relevant social media post in response to a current trending topic.
 
@@ -35,13 +45,7 @@ Please evaluate:
 3. Does the quote add meaningful perspective to the topic?
 4. Could this pairing be seen as insensitive or inappropriate?
 
-Respond with JSON:
-{
-  "relevance_score": 0.0-1.0,
-  "reasoning": "Brief explanation of the connection or lack thereof",
-  "concerns": ["Any concerns about posting this pairing"],
-  "recommendation": "post" | "skip"
-}`
+Record your evaluation with the record_evaluation tool.`
 
 // BatchSelectionPrompt is for evaluating multiple quotes at once.
 const BatchSelectionPrompt = `Evaluate these candidate quotes for responding to the following trending topic.
@@ -56,15 +60,16 @@ CANDIDATE QUOTES:
 For each quote, provide a relevance score (0.0-1.0) and brief reasoning.
 Return the single best match, or indicate if none are suitable.
 
-Respond with JSON:
-{
-  "best_match_index": 0-based index or -1 if none suitable,
-  "evaluations": [
-    {
-      "index": 0,
-      "score": 0.0-1.0,
-      "reasoning": "brief explanation"
-    }
-  ],
-  "recommendation": "The best quote is #X because..." or "None are suitable because..."
-}`
+Record your evaluation with the record_batch_evaluation tool.`
+
+// VariantSystemPrompt is the system prompt for choosing between translations.
+const VariantSystemPrompt = `You are a careful reader of Dostoyevsky in translation. Several translations render the same passage very differently. Choose the one that reads best as a standalone social media post: clear, vivid and faithful in spirit, with no awkward or archaic phrasing that would distract a modern reader.`
+
+// VariantPrompt is the user prompt template for choosing between translations.
+const VariantPrompt = `The following quote was chosen as a post about this trending topic:
+Title: %s
+
+These are translations of the same passage:
+%s
+
+Which one should be posted? Record your choice with the record_variant_choice tool.`
@@ -2,33 +2,139 @@ package matcher
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/abdulachik/dostobot/internal/db"
-	"github.com/abdulachik/dostobot/internal/extractor"
+	"github.com/abdulachik/dostobot/internal/llm"
 )
 
-// Selector uses Claude to evaluate quote-trend matches.
+// Selector uses an LLM to evaluate quote-trend matches.
 type Selector struct {
-	claude *extractor.ClaudeClient
+	llm    llm.Completer
+	filter llm.Completer
+	cache  *db.Store
 }
 
 // SelectorConfig holds configuration for the selector.
 type SelectorConfig struct {
-	APIKey string
+	// LLM judges candidate quotes; use a strong model.
+	LLM llm.Completer
+
+	// Filter, if set, screens trends before any quote is evaluated; a cheap
+	// model is enough. Without it every trend goes straight to selection.
+	Filter llm.Completer
+
+	// Cache, if set, stores batch evaluations so a trend re-evaluated against
+	// the same candidates is answered without another LLM call.
+	Cache *db.Store
 }
 
 // NewSelector creates a new selector.
 func NewSelector(cfg SelectorConfig) *Selector {
 	return &Selector{
-		claude: extractor.NewClaudeClient(extractor.ClaudeConfig{
-			APIKey: cfg.APIKey,
-		}),
+		llm:    cfg.LLM,
+		filter: cfg.Filter,
+		cache:  cfg.Cache,
 	}
 }
 
+// screeningTool is the tool the filter model calls to judge a trend.
+var screeningTool = llm.Tool{
+	Name:        "screen_trend",
+	Description: "Record whether the trending topic is worth pairing with a literary quote.",
+	InputSchema: &llm.Schema{
+		Type:     "object",
+		Required: []string{"suitable", "reason"},
+		Properties: map[string]*llm.Schema{
+			"suitable": {Type: "boolean"},
+			"reason":   {Type: "string", Description: "One short sentence"},
+		},
+	},
+}
+
+// evaluationTool is the tool the LLM calls to score a single quote-trend pairing.
+var evaluationTool = llm.Tool{
+	Name:        "record_evaluation",
+	Description: "Record the evaluation of the candidate quote for the trending topic.",
+	InputSchema: &llm.Schema{
+		Type:     "object",
+		Required: []string{"relevance_score", "reasoning", "concerns", "recommendation"},
+		Properties: map[string]*llm.Schema{
+			"relevance_score": {Type: "number", Minimum: llm.Float(0), Maximum: llm.Float(1)},
+			"reasoning":       {Type: "string", Description: "Brief explanation of the connection or lack thereof"},
+			"concerns":        {Type: "array", Description: "Any concerns about posting this pairing", Items: &llm.Schema{Type: "string"}},
+			"recommendation":  {Type: "string", Enum: []string{"post", "skip"}},
+		},
+	},
+}
+
+// batchEvaluationTool is the tool the LLM calls to score a batch of candidates.
+var batchEvaluationTool = llm.Tool{
+	Name:        "record_batch_evaluation",
+	Description: "Record the evaluation of every candidate quote and the single best match.",
+	InputSchema: &llm.Schema{
+		Type:     "object",
+		Required: []string{"best_match_index", "evaluations", "recommendation"},
+		Properties: map[string]*llm.Schema{
+			"best_match_index": {Type: "integer", Description: "0-based index of the best quote, or -1 if none are suitable", Minimum: llm.Float(-1)},
+			"evaluations": {
+				Type: "array",
+				Items: &llm.Schema{
+					Type:     "object",
+					Required: []string{"index", "score", "reasoning"},
+					Properties: map[string]*llm.Schema{
+						"index":     {Type: "integer", Description: "0-based index of the quote", Minimum: llm.Float(0)},
+						"score":     {Type: "number", Minimum: llm.Float(0), Maximum: llm.Float(1)},
+						"reasoning": {Type: "string", Description: "Brief explanation"},
+					},
+				},
+			},
+			"recommendation": {Type: "string", Description: `"The best quote is #X because..." or "None are suitable because..."`},
+		},
+	},
+}
+
+// variantTool is the tool the LLM calls to choose between translations of
+// a quote.
+var variantTool = llm.Tool{
+	Name:        "record_variant_choice",
+	Description: "Record which translation of the passage to post.",
+	InputSchema: &llm.Schema{
+		Type:     "object",
+		Required: []string{"best_index", "reasoning"},
+		Properties: map[string]*llm.Schema{
+			"best_index": {Type: "integer", Description: "0-based index of the translation to post", Minimum: llm.Float(0)},
+			"reasoning":  {Type: "string", Description: "One short sentence"},
+		},
+	},
+}
+
+// Screen asks the filter model whether a trend is worth matching at all,
+// returning its verdict and reason. Every trend passes when no filter model
+// is configured.
+func (s *Selector) Screen(ctx context.Context, trend *db.Trend) (bool, string, error) {
+	if s.filter == nil {
+		return true, "", nil
+	}
+
+	description := ""
+	if trend.Description.Valid {
+		description = trend.Description.String
+	}
+
+	var result struct {
+		Suitable bool   `json:"suitable"`
+		Reason   string `json:"reason"`
+	}
+	prompt := fmt.Sprintf(ScreeningPrompt, trend.Title, description)
+	if err := s.filter.CompleteTool(ctx, ScreeningSystemPrompt, prompt, screeningTool, &result); err != nil {
+		return false, "", fmt.Errorf("llm complete: %w", err)
+	}
+
+	return result.Suitable, result.Reason, nil
+}
+
 // SelectionResult contains the evaluation of a quote-trend match.
 type SelectionResult struct {
 	RelevanceScore float64
@@ -52,28 +158,14 @@ func (s *Selector) Evaluate(ctx context.Context, trend *db.Trend, quote *db.Quot
 		quote.Themes,
 	)
 
-	response, err := s.claude.Complete(ctx, SelectionSystemPrompt, prompt)
-	if err != nil {
-		return nil, fmt.Errorf("claude complete: %w", err)
-	}
-
-	// Parse JSON response
 	var result struct {
 		RelevanceScore float64  `json:"relevance_score"`
 		Reasoning      string   `json:"reasoning"`
 		Concerns       []string `json:"concerns"`
 		Recommendation string   `json:"recommendation"`
 	}
-
-	if err := json.Unmarshal([]byte(response), &result); err != nil {
-		// Try to extract JSON from response
-		jsonStr := extractJSON(response)
-		if jsonStr == "" {
-			return nil, fmt.Errorf("parse response: %w", err)
-		}
-		if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
-			return nil, fmt.Errorf("parse extracted json: %w", err)
-		}
+	if err := s.llm.CompleteTool(ctx, SelectionSystemPrompt, prompt, evaluationTool, &result); err != nil {
+		return nil, fmt.Errorf("llm complete: %w", err)
 	}
 
 	return &SelectionResult{
@@ -86,34 +178,40 @@ func (s *Selector) Evaluate(ctx context.Context, trend *db.Trend, quote *db.Quot
 
 // BatchEvaluationResult contains the evaluation of multiple quotes.
 type BatchEvaluationResult struct {
-	BestMatchIndex int
-	Evaluations    []QuoteEvaluation
-	Recommendation string
+	BestMatchIndex int               `json:"best_match_index"`
+	Evaluations    []QuoteEvaluation `json:"evaluations"`
+	Recommendation string            `json:"recommendation"`
 }
 
 // QuoteEvaluation contains the evaluation of a single quote in a batch.
 type QuoteEvaluation struct {
-	Index     int
-	Score     float64
-	Reasoning string
+	Index     int     `json:"index"`
+	Score     float64 `json:"score"`
+	Reasoning string  `json:"reasoning"`
 }
 
-// EvaluateBatch evaluates multiple quotes against a trend.
+// EvaluateBatch evaluates multiple quotes against a trend. Results are
+// cached, so repeating a call with the same trend and candidates is free.
 func (s *Selector) EvaluateBatch(ctx context.Context, trend *db.Trend, quotes []*db.Quote) (*BatchEvaluationResult, error) {
 	if len(quotes) == 0 {
 		return &BatchEvaluationResult{BestMatchIndex: -1}, nil
 	}
 
+	key := selectionCacheKey(trend, quotes, s.llm.Model())
+	if result, ok := s.cachedEvaluation(ctx, key); ok {
+		return result, nil
+	}
+
 	description := ""
 	if trend.Description.Valid {
 		description = trend.Description.String
 	}
 
-	// Build quotes list for prompt
+	// Build quotes list for prompt, numbered by the 0-based index the tool reports
 	var quotesList strings.Builder
 	for i, q := range quotes {
-		quotesList.WriteString(fmt.Sprintf("\n%d. \"%s\"\n   — From %s\n   Themes: %s\n",
-			i+1, q.Text, q.SourceBook, q.Themes))
+		quotesList.WriteString(fmt.Sprintf("\n[%d] \"%s\"\n   — From %s\n   Themes: %s\n",
+			i, q.Text, q.SourceBook, q.Themes))
 	}
 
 	prompt := fmt.Sprintf(BatchSelectionPrompt,
@@ -122,12 +220,6 @@ func (s *Selector) EvaluateBatch(ctx context.Context, trend *db.Trend, quotes []
 		quotesList.String(),
 	)
 
-	response, err := s.claude.Complete(ctx, SelectionSystemPrompt, prompt)
-	if err != nil {
-		return nil, fmt.Errorf("claude complete: %w", err)
-	}
-
-	// Parse JSON response
 	var result struct {
 		BestMatchIndex int `json:"best_match_index"`
 		Evaluations    []struct {
@@ -137,14 +229,8 @@ func (s *Selector) EvaluateBatch(ctx context.Context, trend *db.Trend, quotes []
 		} `json:"evaluations"`
 		Recommendation string `json:"recommendation"`
 	}
-
-	jsonStr := extractJSON(response)
-	if jsonStr == "" {
-		jsonStr = response
-	}
-
-	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
-		return nil, fmt.Errorf("parse response: %w", err)
+	if err := s.llm.CompleteTool(ctx, SelectionSystemPrompt, prompt, batchEvaluationTool, &result); err != nil {
+		return nil, fmt.Errorf("llm complete: %w", err)
 	}
 
 	evals := make([]QuoteEvaluation, len(result.Evaluations))
@@ -156,34 +242,40 @@ func (s *Selector) EvaluateBatch(ctx context.Context, trend *db.Trend, quotes []
 		}
 	}
 
-	return &BatchEvaluationResult{
+	batch := &BatchEvaluationResult{
 		BestMatchIndex: result.BestMatchIndex,
 		Evaluations:    evals,
 		Recommendation: result.Recommendation,
-	}, nil
+	}
+	s.storeEvaluation(ctx, key, batch)
+
+	return batch, nil
 }
 
-// extractJSON finds and extracts a JSON object from text.
-func extractJSON(text string) string {
-	// Find opening brace
-	start := strings.Index(text, "{")
-	if start == -1 {
-		return ""
-	}
-
-	// Find matching closing brace
-	depth := 0
-	for i := start; i < len(text); i++ {
-		switch text[i] {
-		case '{':
-			depth++
-		case '}':
-			depth--
-			if depth == 0 {
-				return text[start : i+1]
-			}
-		}
+// PickVariant asks the LLM which of variants, translations of the same
+// passage, reads best as a post about trend, returning its index and the
+// model's reasoning. A single variant is returned without a call.
+func (s *Selector) PickVariant(ctx context.Context, trend *db.Trend, variants []*db.Quote) (int, string, error) {
+	if len(variants) < 2 {
+		return 0, "", nil
+	}
+
+	var list strings.Builder
+	for i, q := range variants {
+		list.WriteString(fmt.Sprintf("\n[%d] \"%s\"\n   — From %s\n", i, q.Text, q.SourceBook))
+	}
+
+	var result struct {
+		BestIndex int    `json:"best_index"`
+		Reasoning string `json:"reasoning"`
+	}
+	prompt := fmt.Sprintf(VariantPrompt, trend.Title, list.String())
+	if err := s.llm.CompleteTool(ctx, VariantSystemPrompt, prompt, variantTool, &result); err != nil {
+		return 0, "", fmt.Errorf("llm complete: %w", err)
+	}
+	if result.BestIndex >= len(variants) {
+		return 0, "", fmt.Errorf("variant index %d out of range", result.BestIndex)
 	}
 
-	return ""
+	return result.BestIndex, result.Reasoning, nil
 }
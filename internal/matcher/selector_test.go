@@ -0,0 +1,155 @@
+package matcher
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelector_EvaluateBatch(t *testing.T) {
+	fake := &llm.Fake{ToolInputs: map[string]string{
+		batchEvaluationTool.Name: `{
+			"best_match_index": 1,
+			"evaluations": [
+				{"index": 0, "score": 0.3, "reasoning": "weak"},
+				{"index": 1, "score": 0.85, "reasoning": "strong {connection}"}
+			],
+			"recommendation": "The best quote is [1]"
+		}`,
+	}}
+	s := NewSelector(SelectorConfig{LLM: fake})
+
+	trend := &db.Trend{Title: "Burnout at work", Description: sql.NullString{String: "Everyone is tired", Valid: true}}
+	quotes := []*db.Quote{{Text: "first", SourceBook: "Poor Folk"}, {Text: "second", SourceBook: "The Idiot"}}
+
+	result, err := s.EvaluateBatch(context.Background(), trend, quotes)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.BestMatchIndex)
+	require.Len(t, result.Evaluations, 2)
+	assert.Equal(t, 0.85, result.Evaluations[1].Score)
+	assert.Equal(t, "strong {connection}", result.Evaluations[1].Reasoning)
+
+	calls := fake.Calls()
+	require.Len(t, calls, 1)
+	assert.Contains(t, calls[0].User, `[1] "second"`)
+}
+
+func TestSelector_EvaluateBatch_RejectsOutOfRangeScore(t *testing.T) {
+	fake := &llm.Fake{ToolInputs: map[string]string{
+		batchEvaluationTool.Name: `{"best_match_index": 0, "evaluations": [{"index": 0, "score": 7, "reasoning": "x"}], "recommendation": "r"}`,
+	}}
+	s := NewSelector(SelectorConfig{LLM: fake})
+
+	_, err := s.EvaluateBatch(context.Background(), &db.Trend{Title: "t"}, []*db.Quote{{Text: "q"}})
+	assert.ErrorIs(t, err, llm.ErrInvalidToolInput)
+}
+
+func TestSelector_Screen(t *testing.T) {
+	trend := &db.Trend{Title: "New JavaScript bundler released"}
+
+	t.Run("passes everything without a filter model", func(t *testing.T) {
+		ok, _, err := NewSelector(SelectorConfig{LLM: &llm.Fake{}}).Screen(context.Background(), trend)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("uses the filter model", func(t *testing.T) {
+		strong := &llm.Fake{}
+		cheap := &llm.Fake{ToolInputs: map[string]string{
+			screeningTool.Name: `{"suitable": false, "reason": "purely technical"}`,
+		}}
+		s := NewSelector(SelectorConfig{LLM: strong, Filter: cheap})
+
+		ok, reason, err := s.Screen(context.Background(), trend)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, "purely technical", reason)
+		assert.Len(t, cheap.Calls(), 1)
+		assert.Empty(t, strong.Calls(), "screening never touches the selection model")
+	})
+}
+
+func TestSelector_EvaluateBatch_Cache(t *testing.T) {
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	require.NoError(t, store.Migrate(ctx))
+
+	fake := &llm.Fake{ToolInputs: map[string]string{
+		batchEvaluationTool.Name: `{"best_match_index": 0, "evaluations": [{"index": 0, "score": 0.9, "reasoning": "r"}], "recommendation": "The best quote is [0]"}`,
+	}}
+	s := NewSelector(SelectorConfig{LLM: fake, Cache: store})
+
+	trend := &db.Trend{ID: 1, Title: "Loneliness epidemic"}
+	quotes := []*db.Quote{{ID: 7, Text: "first"}, {ID: 9, Text: "second"}}
+
+	first, err := s.EvaluateBatch(ctx, trend, quotes)
+	require.NoError(t, err)
+
+	// The same trend content under a new ID and the same candidates is a hit.
+	second, err := s.EvaluateBatch(ctx, &db.Trend{ID: 2, Title: "Loneliness epidemic"}, quotes)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Len(t, fake.Calls(), 1)
+
+	// Reordered candidates change the prompt's numbering, so they miss.
+	_, err = s.EvaluateBatch(ctx, trend, []*db.Quote{quotes[1], quotes[0]})
+	require.NoError(t, err)
+	assert.Len(t, fake.Calls(), 2)
+
+	// So does an edited quote under the same ID.
+	_, err = s.EvaluateBatch(ctx, trend, []*db.Quote{quotes[0], {ID: 9, Text: "second, edited"}})
+	require.NoError(t, err)
+	assert.Len(t, fake.Calls(), 3)
+
+	// And a different model.
+	other := NewSelector(SelectorConfig{LLM: &llm.Fake{ModelName: "other", ToolInputs: fake.ToolInputs}, Cache: store})
+	_, err = other.EvaluateBatch(ctx, trend, quotes)
+	require.NoError(t, err)
+
+	stats, err := store.GetSelectionCacheStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), stats.Entries)
+	assert.Equal(t, int64(1), stats.Hits)
+}
+
+func TestSelector_PickVariant(t *testing.T) {
+	fake := &llm.Fake{ToolInputs: map[string]string{
+		variantTool.Name: `{"best_index": 1, "reasoning": "plainer modern English"}`,
+	}}
+	s := NewSelector(SelectorConfig{LLM: fake})
+
+	trend := &db.Trend{Title: "Burnout at work"}
+	variants := []*db.Quote{
+		{Text: "Pain and suffering are always inevitable for a large intelligence and a deep heart.", SourceBook: "Crime and Punishment (Garnett)"},
+		{Text: "Suffering and pain are always obligatory for a broad consciousness and a deep heart.", SourceBook: "Crime and Punishment (Pevear and Volokhonsky)"},
+	}
+
+	best, reasoning, err := s.PickVariant(context.Background(), trend, variants)
+	require.NoError(t, err)
+	assert.Equal(t, 1, best)
+	assert.Equal(t, "plainer modern English", reasoning)
+
+	calls := fake.Calls()
+	require.Len(t, calls, 1)
+	assert.Contains(t, calls[0].User, "— From Crime and Punishment (Pevear and Volokhonsky)")
+
+	// A single variant needs no call
+	best, _, err = s.PickVariant(context.Background(), trend, variants[:1])
+	require.NoError(t, err)
+	assert.Zero(t, best)
+	assert.Len(t, fake.Calls(), 1)
+
+	t.Run("rejects an index out of range", func(t *testing.T) {
+		fake := &llm.Fake{ToolInputs: map[string]string{variantTool.Name: `{"best_index": 5, "reasoning": "r"}`}}
+		_, _, err := NewSelector(SelectorConfig{LLM: fake}).PickVariant(context.Background(), trend, variants)
+		assert.Error(t, err)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"database/sql"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log/slog"
 
@@ -40,8 +41,11 @@ func NewAggregator(cfg AggregatorConfig) *Aggregator {
 }
 
 // FetchAndStore fetches trends from all monitors, filters them, and stores new ones.
+// A failing monitor is logged and skipped; an error is returned only when
+// every monitor fails.
 func (a *Aggregator) FetchAndStore(ctx context.Context) ([]Trend, error) {
 	var allTrends []Trend
+	var errs []error
 
 	// Fetch from all monitors
 	for _, monitor := range a.monitors {
@@ -53,6 +57,7 @@ func (a *Aggregator) FetchAndStore(ctx context.Context) ([]Trend, error) {
 				"source", monitor.Name(),
 				"error", err,
 			)
+			errs = append(errs, fmt.Errorf("%s: %w", monitor.Name(), err))
 			continue
 		}
 
@@ -64,6 +69,11 @@ func (a *Aggregator) FetchAndStore(ctx context.Context) ([]Trend, error) {
 		allTrends = append(allTrends, trends...)
 	}
 
+	// With every monitor down there is nothing to store
+	if len(errs) > 0 && len(errs) == len(a.monitors) {
+		return nil, fmt.Errorf("all monitors failed: %w", errors.Join(errs...))
+	}
+
 	// Filter trends
 	filtered := a.filter.FilterTrends(allTrends)
 	slog.Debug("filtered trends",
@@ -2,6 +2,7 @@ package monitor
 
 import (
 	"context"
+	"errors"
 	"path/filepath"
 	"testing"
 
@@ -85,6 +86,36 @@ func TestAggregator_FetchAndStore(t *testing.T) {
 	assert.Len(t, newTrends, 0)
 }
 
+func TestAggregator_FetchAndStore_MonitorFails(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, dbPath)
+	require.NoError(t, err)
+	defer store.Close()
+
+	err = store.Migrate(ctx)
+	require.NoError(t, err)
+
+	down := &mockMonitor{name: "down", err: errors.New("connection refused")}
+	up := &mockMonitor{
+		name:   "up",
+		trends: []Trend{{Source: "up", ExternalID: "1", Title: "Test Trend", Score: 100}},
+	}
+
+	// One failing monitor is skipped
+	agg := NewAggregator(AggregatorConfig{Store: store, Monitors: []Monitor{down, up}})
+	newTrends, err := agg.FetchAndStore(ctx)
+	require.NoError(t, err)
+	assert.Len(t, newTrends, 1)
+
+	// With every monitor failing the fetch fails
+	agg = NewAggregator(AggregatorConfig{Store: store, Monitors: []Monitor{down}})
+	_, err = agg.FetchAndStore(ctx)
+	assert.ErrorContains(t, err, "connection refused")
+}
+
 func TestAggregator_FetchAndStore_WithFilter(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
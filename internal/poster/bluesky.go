@@ -54,10 +54,10 @@ type createSessionRequest struct {
 
 // createSessionResponse is the response from session creation.
 type createSessionResponse struct {
-	DID         string `json:"did"`
-	Handle      string `json:"handle"`
-	AccessJwt   string `json:"accessJwt"`
-	RefreshJwt  string `json:"refreshJwt"`
+	DID        string `json:"did"`
+	Handle     string `json:"handle"`
+	AccessJwt  string `json:"accessJwt"`
+	RefreshJwt string `json:"refreshJwt"`
 }
 
 // ValidateCredentials authenticates and validates the credentials.
@@ -120,17 +120,17 @@ func (b *BlueskyPoster) authenticate(ctx context.Context) error {
 
 // createRecordRequest is the request body for creating a post.
 type createRecordRequest struct {
-	Repo       string      `json:"repo"`
-	Collection string      `json:"collection"`
-	Record     postRecord  `json:"record"`
+	Repo       string     `json:"repo"`
+	Collection string     `json:"collection"`
+	Record     postRecord `json:"record"`
 }
 
 // postRecord represents a Bluesky post.
 type postRecord struct {
-	Type      string    `json:"$type"`
-	Text      string    `json:"text"`
-	CreatedAt string    `json:"createdAt"`
-	Langs     []string  `json:"langs,omitempty"`
+	Type      string   `json:"$type"`
+	Text      string   `json:"text"`
+	CreatedAt string   `json:"createdAt"`
+	Langs     []string `json:"langs,omitempty"`
 }
 
 // createRecordResponse is the response from creating a post.
@@ -160,12 +160,17 @@ func (b *BlueskyPoster) Post(ctx context.Context, content PostContent) (*PostRes
 		text = FormatQuote(truncated, content.SourceBook, "")
 	}
 
+	langs := content.Langs
+	if len(langs) == 0 {
+		langs = []string{"en"}
+	}
+
 	// Create the post
 	record := postRecord{
 		Type:      "app.bsky.feed.post",
 		Text:      text,
 		CreatedAt: time.Now().UTC().Format(time.RFC3339),
-		Langs:     []string{"en"},
+		Langs:     langs,
 	}
 
 	reqBody := createRecordRequest{
@@ -3,6 +3,7 @@ package poster
 import (
 	"fmt"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 )
 
@@ -14,30 +15,66 @@ const (
 	TwitterMaxLength = 280
 )
 
-// FormatQuote formats a quote for posting.
+// FormatQuote formats a quote for posting. author is the character who
+// says it, by the canonical name from the book's character registry.
 func FormatQuote(quoteText, sourceBook, author string) string {
-	// Build attribution
-	attribution := fmt.Sprintf("— %s", sourceBook)
+	// Format: "Quote text"\n\n— Attribution
+	return fmt.Sprintf("\"%s\"\n\n%s", quoteText, attribution(sourceBook, author))
+}
+
+// FormatBilingual formats a quote in the language it was written in with
+// its translation underneath, attributed to the translation's book. It
+// reports false if the post would be longer than limit; the translation
+// is then posted alone.
+func FormatBilingual(originalText, quoteText, sourceBook, author string, limit int) (string, bool) {
+	// Format: "Original"\n\n"Translation"\n\n— Attribution
+	formatted := fmt.Sprintf("\"%s\"\n\n\"%s\"\n\n%s", originalText, quoteText, attribution(sourceBook, author))
+	return formatted, FitsInLimit(formatted, limit)
+}
+
+// attribution credits a quote to the character who says it, if not the
+// narrator, and its book: "— Sonya, Crime and Punishment".
+func attribution(sourceBook, author string) string {
 	if author != "" && author != "Narrator" {
-		attribution = fmt.Sprintf("— %s, %s", author, sourceBook)
+		return fmt.Sprintf("— %s, %s", author, sourceBook)
 	}
-
-	// Format: "Quote text"\n\n— Attribution
-	return fmt.Sprintf("\"%s\"\n\n%s", quoteText, attribution)
+	return fmt.Sprintf("— %s", sourceBook)
 }
 
-// FormatWithTrend formats a quote with trend context (optional).
-func FormatWithTrend(quoteText, sourceBook, author, trendTitle string, includeTrend bool) string {
+// FormatWithTrend formats a quote with trend context (optional). bookAuthor
+// is the author of sourceBook from the book catalog.
+func FormatWithTrend(quoteText, sourceBook, author, bookAuthor, trendTitle string, includeTrend bool) string {
 	base := FormatQuote(quoteText, sourceBook, author)
 
 	if includeTrend && trendTitle != "" {
 		// Add subtle trend reference
-		return fmt.Sprintf("%s\n\n#Dostoyevsky", base)
+		if tag := AuthorHashtag(bookAuthor); tag != "" {
+			return fmt.Sprintf("%s\n\n%s", base, tag)
+		}
 	}
 
 	return base
 }
 
+// AuthorHashtag returns the hashtag for an author, their surname:
+// "Fyodor Dostoyevsky" becomes "#Dostoyevsky".
+func AuthorHashtag(author string) string {
+	names := strings.Fields(author)
+	if len(names) == 0 {
+		return ""
+	}
+	surname := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, names[len(names)-1])
+	if surname == "" {
+		return ""
+	}
+	return "#" + surname
+}
+
 // TruncateQuote truncates a quote to fit within a character limit.
 func TruncateQuote(quote string, maxLen int, attribution string) string {
 	// Calculate available space for quote
@@ -80,10 +117,7 @@ func SplitLongQuote(quoteText, sourceBook, author string, limit int) []string {
 	}
 
 	// For very long quotes, split into parts
-	attribution := fmt.Sprintf("— %s", sourceBook)
-	if author != "" && author != "Narrator" {
-		attribution = fmt.Sprintf("— %s, %s", author, sourceBook)
-	}
+	attribution := attribution(sourceBook, author)
 
 	// Calculate how much text we can fit per post
 	// Part 1: "Quote text...(1/2)
@@ -1,6 +1,7 @@
 package poster
 
 import (
+	"strings"
 	"testing"
 	"unicode/utf8"
 
@@ -27,6 +28,39 @@ func TestFormatQuote(t *testing.T) {
 	})
 }
 
+func TestFormatBilingual(t *testing.T) {
+	t.Run("original above the translation", func(t *testing.T) {
+		result, ok := FormatBilingual("Красота спасёт мир.", "Beauty will save the world.", "The Idiot", "Myshkin", BlueskyMaxLength)
+		assert.True(t, ok)
+		assert.Equal(t, "\"Красота спасёт мир.\"\n\n\"Beauty will save the world.\"\n\n— Myshkin, The Idiot", result)
+	})
+
+	t.Run("too long", func(t *testing.T) {
+		long := strings.Repeat("слово ", 60)
+		_, ok := FormatBilingual(long, "Beauty will save the world.", "The Idiot", "", BlueskyMaxLength)
+		assert.False(t, ok)
+	})
+}
+
+func TestFormatWithTrend(t *testing.T) {
+	t.Run("tags the author", func(t *testing.T) {
+		result := FormatWithTrend("Beauty will save the world.", "The Idiot", "Myshkin", "Fyodor Dostoyevsky", "Art funding", true)
+		assert.Equal(t, "\"Beauty will save the world.\"\n\n— Myshkin, The Idiot\n\n#Dostoyevsky", result)
+	})
+
+	t.Run("no trend", func(t *testing.T) {
+		result := FormatWithTrend("Beauty will save the world.", "The Idiot", "", "Fyodor Dostoyevsky", "", true)
+		assert.NotContains(t, result, "#")
+	})
+}
+
+func TestAuthorHashtag(t *testing.T) {
+	assert.Equal(t, "#Dostoyevsky", AuthorHashtag("Fyodor Dostoyevsky"))
+	assert.Equal(t, "#Tolstoy", AuthorHashtag("Leo Tolstoy"))
+	assert.Equal(t, "#Chekhov", AuthorHashtag("Anton P. Chekhov."))
+	assert.Equal(t, "", AuthorHashtag(""))
+}
+
 func TestTruncateQuote(t *testing.T) {
 	t.Run("short quote unchanged", func(t *testing.T) {
 		quote := "Short quote."
@@ -55,9 +89,9 @@ func TestTruncateQuote(t *testing.T) {
 
 func TestFitsInLimit(t *testing.T) {
 	tests := []struct {
-		text   string
-		limit  int
-		fits   bool
+		text  string
+		limit int
+		fits  bool
 	}{
 		{"Hello", 10, true},
 		{"Hello", 5, true},
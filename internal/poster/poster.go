@@ -10,6 +10,10 @@ type PostContent struct {
 	QuoteText  string
 	SourceBook string
 	TrendTitle string
+
+	// Langs are the languages of Text, e.g. ["ru", "en"] for a bilingual
+	// post (default: ["en"]).
+	Langs []string
 }
 
 // PostResult represents the result of a post.
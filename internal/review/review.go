@@ -0,0 +1,174 @@
+// Package review queues matches until they are posted. The scheduler
+// matches trends as they arrive and queues each match; every post slot
+// posts the best approved match. With post approval off, matches are queued
+// approved; with it, they wait for a reviewer to approve, reject or edit
+// them with 'dostobot review' or the review page. Matches not posted in
+// time expire.
+package review
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/poster"
+)
+
+// Statuses stored in pending_posts.status.
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+	StatusExpired  = "expired"
+	StatusPosted   = "posted"
+)
+
+// ErrClosed is returned when reviewing a match that was already rejected,
+// expired or posted.
+var ErrClosed = errors.New("no longer open for review")
+
+// Enqueue queues a match with status StatusPending, to wait for a
+// reviewer, or StatusApproved, ready to post. record describes the match as
+// it will be recorded once posted; content is the post. The match expires
+// after ttl.
+func Enqueue(ctx context.Context, store *db.Store, record db.CreatePostParams, content poster.PostContent, status string, ttl time.Duration) (*db.PendingPost, error) {
+	langs, err := json.Marshal(content.Langs)
+	if err != nil {
+		return nil, fmt.Errorf("marshal languages: %w", err)
+	}
+	return store.CreatePendingPost(ctx, db.CreatePendingPostParams{
+		QuoteID:            record.QuoteID,
+		TrendID:            record.TrendID,
+		TrendTitle:         record.TrendTitle,
+		TrendSource:        record.TrendSource,
+		TrendHash:          record.TrendHash,
+		Text:               content.Text,
+		Langs:              string(langs),
+		RelevanceScore:     record.RelevanceScore,
+		RelevanceReasoning: record.RelevanceReasoning,
+		VectorSimilarity:   record.VectorSimilarity,
+		Status:             status,
+		ExpiresIn:          fmt.Sprintf("%+d seconds", int64(ttl.Seconds())),
+	})
+}
+
+// Open expires stale matches and returns the ones still waiting to be
+// reviewed or posted, oldest first.
+func Open(ctx context.Context, store *db.Store) ([]*db.PendingPost, error) {
+	if err := store.ExpirePendingPosts(ctx); err != nil {
+		return nil, fmt.Errorf("expire pending posts: %w", err)
+	}
+	return store.ListOpenPendingPosts(ctx)
+}
+
+// Approve approves a match for posting.
+func Approve(ctx context.Context, store *db.Store, id int64) error {
+	if _, err := open(ctx, store, id); err != nil {
+		return err
+	}
+	return store.SetPendingPostStatus(ctx, db.SetPendingPostStatusParams{Status: StatusApproved, ID: id})
+}
+
+// Reject rejects a match; it is never posted.
+func Reject(ctx context.Context, store *db.Store, id int64) error {
+	if _, err := open(ctx, store, id); err != nil {
+		return err
+	}
+	return store.SetPendingPostStatus(ctx, db.SetPendingPostStatusParams{Status: StatusRejected, ID: id})
+}
+
+// Edit replaces the text of a match's post. The match keeps its status.
+func Edit(ctx context.Context, store *db.Store, id int64, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("text is empty")
+	}
+	if !poster.FitsInLimit(text, poster.BlueskyMaxLength) {
+		return fmt.Errorf("text is longer than %d characters", poster.BlueskyMaxLength)
+	}
+	if _, err := open(ctx, store, id); err != nil {
+		return err
+	}
+	return store.UpdatePendingPostText(ctx, db.UpdatePendingPostTextParams{Text: text, ID: id})
+}
+
+// open returns match id if it is still open for review.
+func open(ctx context.Context, store *db.Store, id int64) (*db.PendingPost, error) {
+	if err := store.ExpirePendingPosts(ctx); err != nil {
+		return nil, fmt.Errorf("expire pending posts: %w", err)
+	}
+	p, err := store.GetPendingPost(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("unknown pending post: %d", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get pending post: %w", err)
+	}
+	if p.Status != StatusPending && p.Status != StatusApproved {
+		return nil, fmt.Errorf("pending post %d is %s: %w", id, p.Status, ErrClosed)
+	}
+	return p, nil
+}
+
+// Next expires stale matches and returns the approved match to post next,
+// the one with the highest relevance score, or nil if there is none.
+func Next(ctx context.Context, store *db.Store) (*db.PendingPost, error) {
+	if err := store.ExpirePendingPosts(ctx); err != nil {
+		return nil, fmt.Errorf("expire pending posts: %w", err)
+	}
+	p, err := store.NextApprovedPendingPost(ctx)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return p, err
+}
+
+// Content returns the post of an approved match.
+func Content(ctx context.Context, store *db.Store, p *db.PendingPost) (poster.PostContent, error) {
+	quote, err := store.GetQuote(ctx, p.QuoteID)
+	if err != nil {
+		return poster.PostContent{}, fmt.Errorf("get quote %d: %w", p.QuoteID, err)
+	}
+	content := poster.PostContent{
+		Text:       p.Text,
+		QuoteText:  quote.Text,
+		SourceBook: quote.SourceBook,
+		TrendTitle: p.TrendTitle,
+	}
+	if err := json.Unmarshal([]byte(p.Langs), &content.Langs); err != nil {
+		return poster.PostContent{}, fmt.Errorf("parse languages of pending post %d: %w", p.ID, err)
+	}
+	return content, nil
+}
+
+// Record returns the post record of an approved match, without the
+// platform's post ID and URL.
+func Record(p *db.PendingPost) db.CreatePostParams {
+	return db.CreatePostParams{
+		QuoteID:            p.QuoteID,
+		TrendID:            p.TrendID,
+		TrendTitle:         p.TrendTitle,
+		TrendSource:        p.TrendSource,
+		TrendHash:          p.TrendHash,
+		RelevanceScore:     p.RelevanceScore,
+		RelevanceReasoning: p.RelevanceReasoning,
+		VectorSimilarity:   p.VectorSimilarity,
+	}
+}
+
+// MarkPosted records that match p was posted as the post postID, and
+// drops the other queued matches of its quote so it is not posted twice.
+func MarkPosted(ctx context.Context, store *db.Store, p *db.PendingPost, postID int64) error {
+	if err := store.MarkPendingPostPosted(ctx, db.MarkPendingPostPostedParams{
+		PostID: sql.NullInt64{Int64: postID, Valid: postID != 0},
+		ID:     p.ID,
+	}); err != nil {
+		return err
+	}
+	return store.DiscardQuotePendingPosts(ctx, p.QuoteID)
+}
@@ -0,0 +1,207 @@
+package review
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/poster"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStore(t *testing.T) *db.Store {
+	t.Helper()
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	require.NoError(t, store.Migrate(ctx))
+	return store
+}
+
+func enqueue(t *testing.T, store *db.Store, text string, ttl time.Duration) *db.PendingPost {
+	t.Helper()
+	ctx := context.Background()
+	q, err := store.CreateQuote(ctx, db.CreateQuoteParams{
+		Text:       text,
+		TextHash:   text,
+		SourceBook: "The Idiot",
+		Themes:     "[]",
+		Language:   "en",
+	})
+	require.NoError(t, err)
+
+	p, err := Enqueue(ctx, store, db.CreatePostParams{
+		QuoteID:            q.ID,
+		TrendTitle:         "trend",
+		TrendSource:        "hackernews",
+		TrendHash:          "hash-" + text,
+		RelevanceScore:     0.7,
+		RelevanceReasoning: sql.NullString{String: "fits", Valid: true},
+	}, poster.PostContent{
+		Text:       "\"" + text + "\"\n\n— The Idiot",
+		QuoteText:  text,
+		SourceBook: "The Idiot",
+		TrendTitle: "trend",
+		Langs:      []string{"en"},
+	}, StatusPending, ttl)
+	require.NoError(t, err)
+	return p
+}
+
+func TestReview(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	a := enqueue(t, store, "a", time.Hour)
+	b := enqueue(t, store, "b", time.Hour)
+	assert.Equal(t, StatusPending, a.Status)
+
+	next, err := Next(ctx, store)
+	require.NoError(t, err)
+	assert.Nil(t, next, "pending matches are not posted")
+
+	require.NoError(t, Edit(ctx, store, b.ID, "  edited  "))
+	require.NoError(t, Approve(ctx, store, b.ID))
+	require.NoError(t, Reject(ctx, store, a.ID))
+	assert.ErrorIs(t, Approve(ctx, store, a.ID), ErrClosed)
+	assert.Error(t, Edit(ctx, store, b.ID, ""))
+	assert.Error(t, Edit(ctx, store, b.ID, strings.Repeat("x", poster.BlueskyMaxLength+1)))
+	assert.Error(t, Approve(ctx, store, 999))
+
+	open, err := Open(ctx, store)
+	require.NoError(t, err)
+	require.Len(t, open, 1)
+	assert.Equal(t, b.ID, open[0].ID)
+
+	next, err = Next(ctx, store)
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	assert.Equal(t, b.ID, next.ID)
+
+	content, err := Content(ctx, store, next)
+	require.NoError(t, err)
+	assert.Equal(t, "edited", content.Text)
+	assert.Equal(t, "b", content.QuoteText)
+	assert.Equal(t, []string{"en"}, content.Langs)
+
+	record := Record(next)
+	assert.Equal(t, "hash-b", record.TrendHash)
+	assert.Equal(t, 0.7, record.RelevanceScore)
+
+	require.NoError(t, MarkPosted(ctx, store, next, 0))
+	next, err = Next(ctx, store)
+	require.NoError(t, err)
+	assert.Nil(t, next)
+	assert.ErrorIs(t, Reject(ctx, store, b.ID), ErrClosed)
+}
+
+func TestQueue(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	low := enqueue(t, store, "low", time.Hour)
+
+	queue := func(p *db.PendingPost, score float64, hash string) *db.PendingPost {
+		record := Record(p)
+		record.RelevanceScore = score
+		record.TrendHash = hash
+		q, err := Enqueue(ctx, store, record, poster.PostContent{Text: p.Text}, StatusApproved, time.Hour)
+		require.NoError(t, err)
+		return q
+	}
+	require.NoError(t, Approve(ctx, store, low.ID))
+	high := queue(low, 0.9, "other trend")
+	other := enqueue(t, store, "other", time.Hour)
+	mid := queue(other, 0.8, "third trend")
+
+	next, err := Next(ctx, store)
+	require.NoError(t, err)
+	assert.Equal(t, high.ID, next.ID, "the best match is posted first")
+
+	require.NoError(t, MarkPosted(ctx, store, next, 0))
+	got, err := store.GetPendingPost(ctx, low.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusExpired, got.Status, "other matches of a posted quote are dropped")
+
+	next, err = Next(ctx, store)
+	require.NoError(t, err)
+	assert.Equal(t, mid.ID, next.ID)
+}
+
+func TestExpiry(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	p := enqueue(t, store, "a", -time.Minute)
+
+	assert.ErrorIs(t, Approve(ctx, store, p.ID), ErrClosed)
+	got, err := store.GetPendingPost(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusExpired, got.Status)
+
+	open, err := Open(ctx, store)
+	require.NoError(t, err)
+	assert.Empty(t, open)
+}
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	a := enqueue(t, store, "first quote", time.Hour)
+	b := enqueue(t, store, "second quote", time.Hour)
+	srv := httptest.NewServer(Handler(store))
+	defer srv.Close()
+	client := srv.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	resp, err := client.Get(srv.URL + "/")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	post := func(path string, form url.Values) *http.Response {
+		resp, err := client.PostForm(srv.URL+path, form)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+	id := func(p *db.PendingPost) string { return "/posts/" + strconv.FormatInt(p.ID, 10) }
+
+	resp = post(id(a)+"/edit", url.Values{"text": {"changed"}})
+	assert.Equal(t, http.StatusSeeOther, resp.StatusCode)
+	assert.Equal(t, "/", resp.Header.Get("Location"))
+	resp = post(id(a)+"/approve", nil)
+	assert.Equal(t, "/", resp.Header.Get("Location"))
+	post(id(b)+"/reject", nil)
+	resp = post(id(b)+"/approve", nil)
+	assert.Contains(t, resp.Header.Get("Location"), "/?error=")
+	resp = post("/posts/x/approve", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// Another site the reviewer has open cannot post to the page
+	for header, value := range map[string]string{"Sec-Fetch-Site": "cross-site", "Origin": "https://example.com"} {
+		req, err := http.NewRequest(http.MethodPost, srv.URL+id(a)+"/edit", strings.NewReader("text=spam"))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set(header, value)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, header)
+	}
+
+	got, err := store.GetPendingPost(ctx, a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusApproved, got.Status)
+	assert.Equal(t, "changed", got.Text)
+	got, err = store.GetPendingPost(ctx, b.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusRejected, got.Status)
+}
@@ -0,0 +1,97 @@
+package review
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/abdulachik/dostobot/internal/db"
+)
+
+var page = template.Must(template.New("review").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>dostobot review</title>
+<style>
+body { font-family: sans-serif; max-width: 46em; margin: 2em auto; }
+.post { border: 1px solid #ccc; padding: 1em; margin-bottom: 1em; }
+.meta { color: #666; font-size: 0.9em; }
+textarea { width: 100%; height: 9em; }
+</style>
+</head>
+<body>
+<h1>Pending posts</h1>
+{{if .Error}}<p><strong>{{.Error}}</strong></p>{{end}}
+{{range .Posts}}
+<div class="post">
+<p class="meta">#{{.ID}} · {{.Status}} · score {{printf "%.2f" .RelevanceScore}} · {{.TrendSource}}: {{.TrendTitle}} · expires {{.ExpiresAt.Format "2006-01-02 15:04"}}</p>
+{{if .RelevanceReasoning.Valid}}<p class="meta">{{.RelevanceReasoning.String}}</p>{{end}}
+<form method="post" action="/posts/{{.ID}}/edit">
+<textarea name="text">{{.Text}}</textarea>
+<button type="submit">Save</button>
+</form>
+<form method="post" action="/posts/{{.ID}}/approve" style="display:inline"><button type="submit">Approve</button></form>
+<form method="post" action="/posts/{{.ID}}/reject" style="display:inline"><button type="submit">Reject</button></form>
+</div>
+{{else}}
+<p>Nothing to review.</p>
+{{end}}
+</body>
+</html>
+`))
+
+// Handler returns the review page: it lists the open matches and lets a
+// reviewer approve, reject or edit them. It has no authentication, so it
+// should only listen on a local address; cross-origin form posts are
+// refused, so other sites the reviewer opens cannot act on the queue.
+func Handler(store *db.Store) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		posts, err := Open(r.Context(), store)
+		if err != nil {
+			slog.Error("failed to list pending posts", "error", err)
+			http.Error(w, "failed to list pending posts", http.StatusInternalServerError)
+			return
+		}
+		data := struct {
+			Posts []*db.PendingPost
+			Error string
+		}{posts, r.URL.Query().Get("error")}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := page.Execute(w, data); err != nil {
+			slog.Error("failed to render review page", "error", err)
+		}
+	})
+
+	mux.HandleFunc("POST /posts/{id}/{action}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid id", http.StatusBadRequest)
+			return
+		}
+
+		switch r.PathValue("action") {
+		case "approve":
+			err = Approve(r.Context(), store, id)
+		case "reject":
+			err = Reject(r.Context(), store, id)
+		case "edit":
+			err = Edit(r.Context(), store, id, r.FormValue("text"))
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		target := "/"
+		if err != nil {
+			slog.Warn("review action failed", "id", id, "action", r.PathValue("action"), "error", err)
+			target = "/?error=" + template.URLQueryEscaper(err.Error())
+		}
+		http.Redirect(w, r, target, http.StatusSeeOther)
+	})
+
+	return http.NewCrossOriginProtection().Handler(mux)
+}
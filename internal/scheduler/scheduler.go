@@ -3,14 +3,22 @@ package scheduler
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"log/slog"
 	"time"
 
+	"github.com/abdulachik/dostobot/internal/calendar"
+	"github.com/abdulachik/dostobot/internal/characters"
 	"github.com/abdulachik/dostobot/internal/config"
 	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/embedder"
+	"github.com/abdulachik/dostobot/internal/evergreen"
+	"github.com/abdulachik/dostobot/internal/llm"
 	"github.com/abdulachik/dostobot/internal/matcher"
 	"github.com/abdulachik/dostobot/internal/monitor"
 	"github.com/abdulachik/dostobot/internal/poster"
+	"github.com/abdulachik/dostobot/internal/review"
 	"github.com/abdulachik/dostobot/internal/vectorstore"
 )
 
@@ -18,13 +26,21 @@ import (
 type Scheduler struct {
 	cfg        *config.Config
 	store      *db.Store
+	provider   embedder.Provider
 	quoteStore *vectorstore.QuoteStore
 	matcher    *matcher.Matcher
 	poster     poster.Poster
 	agg        *monitor.Aggregator
 	health     *Health
 
+	// reloadCh requests that the VecLite index be reopened from disk.
+	reloadCh chan struct{}
+
 	lastPost time.Time
+
+	// loc is the posting calendar's timezone, which decides the day of
+	// evergreen occasions.
+	loc *time.Location
 }
 
 // Config holds scheduler configuration.
@@ -35,22 +51,46 @@ type Config struct {
 
 // New creates a new scheduler.
 func New(cfg Config) *Scheduler {
-	// Create VecLite quote store (loads veclite.yaml config)
-	quoteStore, err := vectorstore.New(vectorstore.Config{
-		Path: cfg.Cfg.VecLitePath,
-	})
+	// Create the embedding provider shared by VecLite and the in-memory fallback
+	provider, err := loadProvider(cfg.Cfg)
 	if err != nil {
-		slog.Error("failed to create VecLite store, falling back to in-memory index", "error", err)
-		quoteStore = nil
-	} else {
-		slog.Info("VecLite store initialized", "path", cfg.Cfg.VecLitePath, "quotes", quoteStore.Count())
+		slog.Error("failed to create embedding provider", "error", err)
+	}
+
+	// Open the VecLite quote store read-only: the daemon only searches, and
+	// must not write its in-memory copy back over an index swapped in by reindex.
+	var quoteStore *vectorstore.QuoteStore
+	if provider != nil {
+		quoteStore, err = openQuoteStore(cfg.Cfg.VecLitePath, provider)
+		if err != nil {
+			slog.Error("failed to create VecLite store, falling back to in-memory index", "error", err)
+			quoteStore = nil
+		} else {
+			slog.Info("VecLite store initialized", "path", cfg.Cfg.VecLitePath, "quotes", quoteStore.Count())
+		}
+	}
+
+	// Create the selection (and optional trend screening) models
+	backend := llm.Backend{Provider: cfg.Cfg.LLMProvider, BaseURL: cfg.Cfg.LLMBaseURL, APIKey: cfg.Cfg.LLMAPIKey}
+	selectLLM, err := backend.New(cfg.Cfg.SelectModel)
+	if err != nil {
+		slog.Error("failed to create selection model", "error", err)
+	}
+	filterLLM, err := backend.NewOptional(cfg.Cfg.FilterModel)
+	if err != nil {
+		slog.Error("failed to create filter model", "error", err)
 	}
 
 	// Create matcher with VecLite (or nil for legacy in-memory fallback)
 	m := matcher.New(matcher.Config{
-		Store:      cfg.Store,
-		QuoteStore: quoteStore,
-		APIKey:     cfg.Cfg.AnthropicAPIKey,
+		Store:        cfg.Store,
+		Embedder:     provider,
+		QuoteStore:   quoteStore,
+		LLM:          selectLLM,
+		FilterLLM:    filterLLM,
+		Language:     cfg.Cfg.PostLanguage,
+		ApprovedOnly: cfg.Cfg.CurationMode,
+		Themes:       cfg.Cfg.PostThemes,
 	})
 
 	// Create monitors
@@ -83,14 +123,78 @@ func New(cfg Config) *Scheduler {
 	return &Scheduler{
 		cfg:        cfg.Cfg,
 		store:      cfg.Store,
+		provider:   provider,
 		quoteStore: quoteStore,
 		matcher:    m,
 		poster:     bsPoster,
 		agg:        agg,
 		health:     NewHealth(),
+		reloadCh:   make(chan struct{}, 1),
+	}
+}
+
+// loadProvider builds the embedding provider from veclite.yaml and
+// EMBED_PROVIDER.
+func loadProvider(cfg *config.Config) (embedder.Provider, error) {
+	return embedder.LoadProvider(embedder.ProviderConfig{
+		Provider: cfg.EmbedProvider,
+		CacheDir: cfg.EmbedCacheDir,
+	})
+}
+
+// openQuoteStore opens the VecLite index for searching.
+func openQuoteStore(path string, provider embedder.Provider) (*vectorstore.QuoteStore, error) {
+	return vectorstore.NewReadOnly(vectorstore.Config{
+		Path:     path,
+		Provider: provider,
+	})
+}
+
+// ReloadIndex asks the scheduler to reopen the VecLite index, picking up a
+// collection swapped in by `dostobot reindex`. It is safe to call from any
+// goroutine; the reload happens between cycles on the scheduler loop.
+func (s *Scheduler) ReloadIndex() {
+	select {
+	case s.reloadCh <- struct{}{}:
+	default:
+		// A reload is already pending.
 	}
 }
 
+// reloadIndex reopens the VecLite index with a provider rebuilt from
+// veclite.yaml, since a reindex may have switched embedding models, and
+// swaps both into the matcher. On failure the current index and provider
+// keep serving.
+func (s *Scheduler) reloadIndex() {
+	provider, err := loadProvider(s.cfg)
+	if err != nil {
+		s.health.SetUnhealthy("index", err)
+		slog.Error("failed to load embedding provider, keeping current index", "error", err)
+		return
+	}
+
+	quoteStore, err := openQuoteStore(s.cfg.VecLitePath, provider)
+	if err != nil {
+		s.health.SetUnhealthy("index", err)
+		slog.Error("failed to reload VecLite index, keeping current one", "error", err)
+		return
+	}
+
+	old := s.quoteStore
+	s.provider = provider
+	s.quoteStore = quoteStore
+	s.matcher.SetIndex(provider, quoteStore)
+	if old != nil {
+		if err := old.Close(); err != nil {
+			slog.Warn("failed to close previous VecLite index", "error", err)
+		}
+	}
+
+	s.health.SetHealthy("index", "reloaded")
+	slog.Info("VecLite index reloaded", "path", s.cfg.VecLitePath, "quotes", quoteStore.Count(),
+		"provider", provider.Name(), "model", provider.Model())
+}
+
 // Close releases resources held by the scheduler.
 func (s *Scheduler) Close() error {
 	if s.quoteStore != nil {
@@ -104,7 +208,11 @@ func (s *Scheduler) Run(ctx context.Context) error {
 	slog.Info("starting scheduler",
 		"monitor_interval", s.cfg.MonitorInterval,
 		"post_interval", s.cfg.PostInterval,
+		"post_schedule", s.cfg.PostSchedule,
+		"post_windows", s.cfg.PostWindows,
+		"post_timezone", s.cfg.PostTimezone,
 		"max_posts_per_day", s.cfg.MaxPostsPerDay,
+		"post_approval", s.cfg.PostApproval,
 	)
 
 	// Validate credentials on startup
@@ -123,11 +231,25 @@ func (s *Scheduler) Run(ctx context.Context) error {
 		s.health.SetHealthy("index", "loaded")
 	}
 
-	// Create tickers
+	// Build the posting calendar
+	cal, err := calendar.New(calendar.Config{
+		Timezone:   s.cfg.PostTimezone,
+		Windows:    s.cfg.PostWindows,
+		Schedule:   s.cfg.PostSchedule,
+		Interval:   s.cfg.PostInterval,
+		MinSpacing: s.cfg.MinPostSpacing,
+		Jitter:     s.cfg.PostJitter,
+	})
+	if err != nil {
+		return fmt.Errorf("posting calendar: %w", err)
+	}
+	s.loc = cal.Location()
+
+	// Create the monitor ticker and the post timer
 	monitorTicker := time.NewTicker(s.cfg.MonitorInterval)
-	postTicker := time.NewTicker(s.cfg.PostInterval)
 	defer monitorTicker.Stop()
-	defer postTicker.Stop()
+	postTimer := time.NewTimer(s.untilNextPost(ctx, cal, time.Now()))
+	defer postTimer.Stop()
 
 	// Run initial monitoring
 	s.runMonitorCycle(ctx)
@@ -142,12 +264,49 @@ func (s *Scheduler) Run(ctx context.Context) error {
 		case <-monitorTicker.C:
 			s.runMonitorCycle(ctx)
 
-		case <-postTicker.C:
+		case <-postTimer.C:
+			lastPost := s.lastPost
 			s.runPostCycle(ctx)
+
+			// A slot that found nothing to post is tried again once the
+			// next monitor cycle has brought new trends.
+			after := time.Now()
+			if !s.lastPost.After(lastPost) {
+				after = after.Add(s.cfg.MonitorInterval)
+			}
+			postTimer.Reset(s.untilNextPost(ctx, cal, after))
+
+		case <-s.reloadCh:
+			s.reloadIndex()
 		}
 	}
 }
 
+// untilNextPost returns the time until the first post slot at or after
+// after, counting from the last post recorded in the database so that
+// restarts do not shift the schedule.
+func (s *Scheduler) untilNextPost(ctx context.Context, cal *calendar.Calendar, after time.Time) time.Duration {
+	last := s.lastPost
+	posts, err := s.store.ListPostsByPlatform(ctx, db.ListPostsByPlatformParams{Platform: "bluesky", Limit: 1})
+	if err != nil {
+		slog.Warn("failed to get last post", "error", err)
+	} else if len(posts) > 0 && posts[0].PostedAt.Valid && posts[0].PostedAt.Time.After(last) {
+		last = posts[0].PostedAt.Time
+	}
+
+	next := cal.Next(last, after)
+	if next.IsZero() {
+		// New rejects calendars without slots, so this only happens if
+		// the schedule runs out; look again after a monitor cycle.
+		slog.Error("no post slot found", "after", after)
+		return s.cfg.MonitorInterval
+	}
+	next = cal.Jitter(next)
+
+	slog.Info("next post slot", "at", next.Format(time.RFC3339), "last_post", last)
+	return time.Until(next)
+}
+
 // runMonitorCycle fetches and stores new trends.
 func (s *Scheduler) runMonitorCycle(ctx context.Context) {
 	slog.Debug("running monitor cycle")
@@ -156,128 +315,331 @@ func (s *Scheduler) runMonitorCycle(ctx context.Context) {
 	if err != nil {
 		s.health.SetUnhealthy("monitor", err)
 		slog.Error("monitor cycle failed", "error", err)
-		return
+	} else {
+		s.health.SetHealthy("monitor", "fetched trends")
+		slog.Info("monitor cycle complete", "new_trends", len(newTrends))
 	}
 
-	s.health.SetHealthy("monitor", "fetched trends")
-	slog.Info("monitor cycle complete", "new_trends", len(newTrends))
+	// Trends stored by earlier cycles still wait to be matched
+	s.runMatchCycle(ctx)
 }
 
-// runPostCycle attempts to post a quote.
-func (s *Scheduler) runPostCycle(ctx context.Context) {
-	slog.Debug("running post cycle")
+// runMatchCycle matches unmatched trends and queues the matches, so post
+// slots do not wait on the selector. Matching pauses while
+// MatchQueueSize matches are queued. With post approval on, matches wait
+// for a reviewer unless they score at least AutoPostScore.
+func (s *Scheduler) runMatchCycle(ctx context.Context) {
+	slog.Debug("running match cycle")
 
-	// Check daily post limit
-	postsToday, err := s.store.CountPostsToday(ctx, "bluesky")
+	queued, err := review.Open(ctx, s.store)
 	if err != nil {
-		slog.Error("failed to count today's posts", "error", err)
-	} else if postsToday >= int64(s.cfg.MaxPostsPerDay) {
-		slog.Info("daily post limit reached", "posts_today", postsToday, "max", s.cfg.MaxPostsPerDay)
+		s.health.SetUnhealthy("match", err)
+		slog.Error("failed to list queued matches", "error", err)
+		return
+	}
+	room := s.cfg.MatchQueueSize - len(queued)
+	if room <= 0 {
+		slog.Debug("match queue is full", "queued", len(queued))
 		return
 	}
 
 	// Get unmatched trends
 	unmatchedTrends, err := s.agg.GetUnmatchedTrends(ctx, 10)
 	if err != nil {
-		s.health.SetUnhealthy("post", err)
+		s.health.SetUnhealthy("match", err)
 		slog.Error("failed to get unmatched trends", "error", err)
 		return
 	}
 
-	if len(unmatchedTrends) == 0 {
-		slog.Debug("no unmatched trends to post about")
-		return
-	}
-
-	// Try to find a good match
-	var bestMatch *matcher.MatchResult
+	matched := 0
 	for _, trend := range unmatchedTrends {
+		if matched == room {
+			break
+		}
+
 		result, err := s.matcher.Match(ctx, trend)
 		if err != nil {
-			slog.Debug("match failed", "trend", trend.Title, "error", err)
+			switch {
+			case errors.Is(err, llm.ErrAuth):
+				// Every remaining trend would fail the same way.
+				s.health.SetUnhealthy("match", err)
+				slog.Error("selector authentication failed", "error", err)
+				return
+			case llm.IsRetryable(err):
+				// Leave the trends pending for the next cycle.
+				slog.Warn("selector unavailable, ending match cycle", "trend", trend.Title, "error", err)
+				return
+			case errors.Is(err, llm.ErrContextLength), errors.Is(err, llm.ErrInvalidRequest):
+				// Retrying this trend will not help.
+				slog.Warn("selector rejected trend, skipping", "trend", trend.Title, "error", err)
+				if err := s.store.UpdateTrendSkipped(ctx, db.UpdateTrendSkippedParams{
+					ID:         trend.ID,
+					SkipReason: sql.NullString{String: "selector rejected request: " + err.Error(), Valid: true},
+				}); err != nil {
+					slog.Warn("failed to mark trend as skipped", "error", err)
+				}
+			default:
+				slog.Debug("match failed", "trend", trend.Title, "error", err)
+			}
 			continue
 		}
 
-		if result != nil {
-			bestMatch = result
-			break
+		if result == nil {
+			// Mark trends that don't match as skipped
+			if err := s.store.UpdateTrendSkipped(ctx, db.UpdateTrendSkippedParams{
+				ID:         trend.ID,
+				SkipReason: sql.NullString{String: "no suitable quote match", Valid: true},
+			}); err != nil {
+				slog.Warn("failed to mark trend as skipped", "error", err)
+			}
+			continue
+		}
+
+		if err := s.enqueue(ctx, result); err != nil {
+			s.health.SetUnhealthy("match", err)
+			slog.Error("failed to queue match", "trend", trend.Title, "error", err)
+			return
+		}
+		matched++
+
+		// Mark trend as matched
+		if err := s.store.UpdateTrendMatched(ctx, trend.ID); err != nil {
+			slog.Warn("failed to mark trend as matched", "error", err)
+		}
+	}
+
+	s.health.SetHealthy("match", "matched trends")
+	slog.Info("match cycle complete", "trends", len(unmatchedTrends), "queued", matched)
+}
+
+// enqueue formats a match and queues it, approved unless post approval is
+// on and it scores below AutoPostScore.
+func (s *Scheduler) enqueue(ctx context.Context, match *matcher.MatchResult) error {
+	content := FormatPost(ctx, s.store, match.Quote, match.Trend.Title, s.cfg.PostBilingual)
+	record := db.CreatePostParams{
+		QuoteID:     match.Quote.ID,
+		TrendID:     sql.NullInt64{Int64: match.Trend.ID, Valid: true},
+		TrendTitle:  match.Trend.Title,
+		TrendSource: match.Trend.Source,
+		TrendHash: monitor.HashTrend(monitor.Trend{
+			Source:     match.Trend.Source,
+			ExternalID: match.Trend.ExternalID.String,
+			Title:      match.Trend.Title,
+		}),
+		RelevanceScore:     match.RelevanceScore,
+		RelevanceReasoning: sql.NullString{String: match.Reasoning, Valid: match.Reasoning != ""},
+		VectorSimilarity:   float64(match.VectorSimilarity),
+	}
+
+	status, ttl := review.StatusApproved, s.cfg.MatchTTL
+	autoPost := s.cfg.AutoPostScore > 0 && match.RelevanceScore >= s.cfg.AutoPostScore
+	if s.cfg.PostApproval && !autoPost {
+		status, ttl = review.StatusPending, s.cfg.ApprovalTTL
+	}
+
+	pending, err := review.Enqueue(ctx, s.store, record, content, status, ttl)
+	if err != nil {
+		return err
+	}
+	slog.Info("queued match",
+		"id", pending.ID,
+		"status", status,
+		"trend", match.Trend.Title,
+		"score", match.RelevanceScore,
+	)
+	return nil
+}
+
+// FormatPost formats a quote for posting about a trend, crediting its
+// character by the canonical name from the book's registry and, when it
+// fits, tagging the book's author from the catalog. With bilingual set, a
+// quote linked to the original it translates is posted under the original,
+// when both fit in one Bluesky post.
+func FormatPost(ctx context.Context, store *db.Store, quote *db.Quote, trendTitle string, bilingual bool) poster.PostContent {
+	character := characters.Display(ctx, store.Queries, quote)
+	var author string
+	book, err := store.GetBookByTitle(ctx, quote.SourceBook)
+	if err == nil {
+		author = book.Author
+	} else if err != sql.ErrNoRows {
+		slog.Warn("failed to find book of quote", "quote", quote.ID, "book", quote.SourceBook, "error", err)
+	}
+	text := poster.FormatWithTrend(quote.Text, quote.SourceBook, character, author, trendTitle, true)
+	if !poster.FitsInLimit(text, poster.BlueskyMaxLength) {
+		text = poster.FormatQuote(quote.Text, quote.SourceBook, character)
+	}
+
+	content := poster.PostContent{
+		Text:       text,
+		QuoteText:  quote.Text,
+		SourceBook: quote.SourceBook,
+		TrendTitle: trendTitle,
+	}
+	if quote.Language != "" {
+		content.Langs = []string{quote.Language}
+	}
+	if !bilingual {
+		return content
+	}
+
+	original, err := store.GetQuoteOriginal(ctx, quote.ID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			slog.Warn("failed to find original of quote", "quote", quote.ID, "error", err)
 		}
+		return content
+	}
+	text, ok := poster.FormatBilingual(original.Text, quote.Text, quote.SourceBook, character, poster.BlueskyMaxLength)
+	if !ok {
+		slog.Debug("bilingual post too long, posting the translation alone", "quote", quote.ID)
+		return content
+	}
+	content.Text = text
+	content.Langs = []string{original.Language, quote.Language}
+	return content
+}
+
+// runPostCycle posts the best queued match that is ready, or, with
+// EvergreenFallback on, an evergreen quote if there is none.
+func (s *Scheduler) runPostCycle(ctx context.Context) {
+	slog.Debug("running post cycle")
 
-		// Mark trends that don't match as skipped
-		if err := s.store.UpdateTrendSkipped(ctx, db.UpdateTrendSkippedParams{
-			ID:         trend.ID,
-			SkipReason: sql.NullString{String: "no suitable quote match", Valid: true},
-		}); err != nil {
-			slog.Warn("failed to mark trend as skipped", "error", err)
+	// Check daily post limit
+	postsToday, err := s.store.CountPostsToday(ctx, db.CountPostsTodayParams{
+		Platform: "bluesky",
+		Since:    sqliteTime(s.startOfDay(time.Now())),
+	})
+	if err != nil {
+		slog.Error("failed to count today's posts", "error", err)
+	} else if postsToday >= int64(s.cfg.MaxPostsPerDay) {
+		slog.Info("daily post limit reached", "posts_today", postsToday, "max", s.cfg.MaxPostsPerDay)
+		return
+	}
+
+	pending, err := review.Next(ctx, s.store)
+	if err != nil {
+		s.health.SetUnhealthy("post", err)
+		slog.Error("failed to get queued match", "error", err)
+		return
+	}
+	if pending == nil {
+		if s.cfg.EvergreenFallback {
+			s.postEvergreen(ctx)
+		} else {
+			slog.Debug("no queued match to post")
 		}
+		return
 	}
 
-	if bestMatch == nil {
-		slog.Debug("no suitable quote-trend match found")
+	content, err := review.Content(ctx, s.store, pending)
+	if err != nil {
+		s.health.SetUnhealthy("post", err)
+		slog.Error("failed to load queued match", "id", pending.ID, "error", err)
+		return
+	}
+	post, err := s.publish(ctx, content, review.Record(pending))
+	if err != nil {
+		// Leave the match queued for the next slot.
 		return
 	}
 
-	// Format and post
-	character := ""
-	if bestMatch.Quote.Character.Valid {
-		character = bestMatch.Quote.Character.String
+	var postID int64
+	if post != nil {
+		postID = post.ID
+	}
+	if err := review.MarkPosted(ctx, s.store, pending, postID); err != nil {
+		slog.Warn("failed to mark queued match as posted", "id", pending.ID, "error", err)
 	}
-	formatted := poster.FormatQuote(bestMatch.Quote.Text, bestMatch.Quote.SourceBook, character)
+}
 
-	result, err := s.poster.Post(ctx, poster.PostContent{
-		Text:       formatted,
-		QuoteText:  bestMatch.Quote.Text,
-		SourceBook: bestMatch.Quote.SourceBook,
-		TrendTitle: bestMatch.Trend.Title,
+// startOfDay returns midnight of t's day in the posting calendar's
+// timezone, which is when the daily post limits reset.
+func (s *Scheduler) startOfDay(t time.Time) time.Time {
+	if s.loc != nil {
+		t = t.In(s.loc)
+	}
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// sqliteTime formats t as the UTC timestamps SQLite's CURRENT_TIMESTAMP
+// stores, so it compares with them.
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format(time.DateTime)
+}
+
+// postEvergreen posts an evergreen quote for today's occasions or theme,
+// unless EvergreenMaxPerDay evergreen posts were made today.
+func (s *Scheduler) postEvergreen(ctx context.Context) {
+	day := s.startOfDay(time.Now())
+	evergreenToday, err := s.store.CountSourcePostsToday(ctx, db.CountSourcePostsTodayParams{
+		Platform:    "bluesky",
+		TrendSource: evergreen.Source,
+		Since:       sqliteTime(day),
 	})
+	if err != nil {
+		slog.Error("failed to count today's evergreen posts", "error", err)
+		return
+	}
+	if evergreenToday >= int64(s.cfg.EvergreenMaxPerDay) {
+		slog.Debug("daily evergreen limit reached", "posts_today", evergreenToday, "max", s.cfg.EvergreenMaxPerDay)
+		return
+	}
+
+	choice, err := evergreen.Pick(ctx, s.store.Queries, day, s.cfg.EvergreenThemes, s.matcher.Eligible)
 	if err != nil {
 		s.health.SetUnhealthy("post", err)
-		slog.Error("failed to post", "error", err)
+		slog.Error("failed to pick evergreen quote", "error", err)
+		return
+	}
+	if choice == nil {
+		slog.Debug("no evergreen quote to post")
+		return
+	}
+
+	content := FormatPost(ctx, s.store, choice.Quote, "", s.cfg.PostBilingual)
+	if _, err := s.publish(ctx, content, evergreen.Record(choice, time.Now())); err != nil {
 		return
 	}
+	if err := s.store.DiscardQuotePendingPosts(ctx, choice.Quote.ID); err != nil {
+		slog.Warn("failed to drop queued matches of evergreen quote", "quote", choice.Quote.ID, "error", err)
+	}
+}
+
+// publish posts content to Bluesky and records it as the post record. It
+// returns the recorded post, or nil if recording it failed; it only returns
+// an error if posting failed.
+func (s *Scheduler) publish(ctx context.Context, content poster.PostContent, record db.CreatePostParams) (*db.Post, error) {
+	result, err := s.poster.Post(ctx, content)
+	if err != nil {
+		s.health.SetUnhealthy("post", err)
+		slog.Error("failed to post", "error", err)
+		return nil, err
+	}
 
 	s.health.SetHealthy("post", "posted successfully")
 	s.lastPost = time.Now()
 
 	slog.Info("posted quote",
 		"url", result.PostURL,
-		"trend", bestMatch.Trend.Title,
-		"similarity", bestMatch.VectorSimilarity,
+		"trend", record.TrendTitle,
+		"similarity", record.VectorSimilarity,
 	)
 
 	// Record the post
-	trendHash := monitor.HashTrend(monitor.Trend{
-		Source:     bestMatch.Trend.Source,
-		ExternalID: bestMatch.Trend.ExternalID.String,
-		Title:      bestMatch.Trend.Title,
-	})
-
-	_, err = s.store.CreatePost(ctx, db.CreatePostParams{
-		QuoteID:            bestMatch.Quote.ID,
-		Platform:           "bluesky",
-		PlatformPostID:     sql.NullString{String: result.PostID, Valid: true},
-		PostUrl:            sql.NullString{String: result.PostURL, Valid: true},
-		TrendID:            sql.NullInt64{Int64: bestMatch.Trend.ID, Valid: true},
-		TrendTitle:         bestMatch.Trend.Title,
-		TrendSource:        bestMatch.Trend.Source,
-		TrendHash:          trendHash,
-		RelevanceScore:     bestMatch.RelevanceScore,
-		RelevanceReasoning: sql.NullString{String: bestMatch.Reasoning, Valid: bestMatch.Reasoning != ""},
-		VectorSimilarity:   float64(bestMatch.VectorSimilarity),
-	})
+	record.Platform = "bluesky"
+	record.PlatformPostID = sql.NullString{String: result.PostID, Valid: true}
+	record.PostUrl = sql.NullString{String: result.PostURL, Valid: true}
+	post, err := s.store.CreatePost(ctx, record)
 	if err != nil {
 		slog.Warn("failed to record post", "error", err)
 	}
 
-	// Mark trend as matched
-	if err := s.store.UpdateTrendMatched(ctx, bestMatch.Trend.ID); err != nil {
-		slog.Warn("failed to mark trend as matched", "error", err)
-	}
-
 	// Update quote posted count
-	if err := s.store.UpdateQuotePosted(ctx, bestMatch.Quote.ID); err != nil {
+	if err := s.store.UpdateQuotePosted(ctx, record.QuoteID); err != nil {
 		slog.Warn("failed to update quote posted count", "error", err)
 	}
+	return post, nil
 }
 
 // Health returns the health tracker.
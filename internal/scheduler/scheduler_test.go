@@ -1,10 +1,24 @@
 package scheduler
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/abdulachik/dostobot/internal/calendar"
+	"github.com/abdulachik/dostobot/internal/config"
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/evergreen"
+	"github.com/abdulachik/dostobot/internal/matcher"
+	"github.com/abdulachik/dostobot/internal/monitor"
+	"github.com/abdulachik/dostobot/internal/poster"
+	"github.com/abdulachik/dostobot/internal/review"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHealth_SetHealthy(t *testing.T) {
@@ -76,3 +90,236 @@ func TestHealth_IsOverallHealthy(t *testing.T) {
 		assert.True(t, h.IsOverallHealthy())
 	})
 }
+
+func TestFormatPost(t *testing.T) {
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	require.NoError(t, store.Migrate(ctx))
+
+	create := func(text, hash, book, language string) *db.Quote {
+		q, err := store.CreateQuote(ctx, db.CreateQuoteParams{
+			Text:       text,
+			TextHash:   hash,
+			SourceBook: book,
+			Character:  sql.NullString{String: "Myshkin", Valid: true},
+			Themes:     "[]",
+			Language:   language,
+		})
+		require.NoError(t, err)
+		return q
+	}
+	original := create("Красота спасёт мир.", "a", "Идиот", "ru")
+	translation := create("Beauty will save the world.", "b", "The Idiot", "en")
+	require.NoError(t, store.CreateQuoteTranslation(ctx, db.CreateQuoteTranslationParams{
+		OriginalID:    original.ID,
+		TranslationID: translation.ID,
+	}))
+	untranslated := create("Compassion is the chief law of human existence.", "c", "The Idiot", "en")
+
+	t.Run("translation alone", func(t *testing.T) {
+		content := FormatPost(ctx, store, translation, "trend", false)
+		assert.Equal(t, "\"Beauty will save the world.\"\n\n— Prince Myshkin, The Idiot\n\n#Dostoyevsky", content.Text,
+			"credits the canonical name and tags the catalog author")
+		assert.Equal(t, []string{"en"}, content.Langs)
+		assert.Equal(t, "trend", content.TrendTitle)
+	})
+
+	t.Run("bilingual", func(t *testing.T) {
+		content := FormatPost(ctx, store, translation, "trend", true)
+		assert.Equal(t, "\"Красота спасёт мир.\"\n\n\"Beauty will save the world.\"\n\n— Prince Myshkin, The Idiot", content.Text)
+		assert.Equal(t, []string{"ru", "en"}, content.Langs)
+		assert.Equal(t, "Beauty will save the world.", content.QuoteText)
+	})
+
+	t.Run("without a trend or catalog book", func(t *testing.T) {
+		content := FormatPost(ctx, store, translation, "", false)
+		assert.NotContains(t, content.Text, "#")
+		content = FormatPost(ctx, store, original, "trend", false)
+		assert.NotContains(t, content.Text, "#")
+	})
+
+	t.Run("bilingual without an original", func(t *testing.T) {
+		content := FormatPost(ctx, store, untranslated, "trend", true)
+		assert.NotContains(t, content.Text, "Красота")
+		assert.Equal(t, []string{"en"}, content.Langs)
+	})
+}
+
+func TestUntilNextPost(t *testing.T) {
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	require.NoError(t, store.Migrate(ctx))
+
+	s := &Scheduler{cfg: &config.Config{MonitorInterval: 30 * time.Minute}, store: store}
+	cal, err := calendar.New(calendar.Config{Interval: 4 * time.Hour, MinSpacing: time.Hour})
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, s.untilNextPost(ctx, cal, time.Now()), time.Duration(0), "nothing posted yet")
+
+	q, err := store.CreateQuote(ctx, db.CreateQuoteParams{Text: "a", TextHash: "a", SourceBook: "The Idiot", Themes: "[]", Language: "en"})
+	require.NoError(t, err)
+	_, err = store.CreatePost(ctx, db.CreatePostParams{QuoteID: q.ID, Platform: "bluesky", TrendTitle: "trend", TrendSource: "hackernews", TrendHash: "h"})
+	require.NoError(t, err)
+
+	// The slot counts from the stored post, as after a restart
+	assert.InDelta(t, 4*time.Hour, s.untilNextPost(ctx, cal, time.Now()), float64(5*time.Second))
+}
+
+func TestStartOfDay(t *testing.T) {
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	require.NoError(t, store.Migrate(ctx))
+
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	s := &Scheduler{store: store, loc: loc}
+
+	// 02:00 UTC is still the evening before in the posting timezone
+	start := s.startOfDay(time.Date(2026, 10, 18, 2, 0, 0, 0, time.UTC))
+	assert.True(t, start.Equal(time.Date(2026, 10, 17, 0, 0, 0, 0, loc)))
+
+	q, err := store.CreateQuote(ctx, db.CreateQuoteParams{Text: "a", TextHash: "a", SourceBook: "The Idiot", Themes: "[]", Language: "en"})
+	require.NoError(t, err)
+	count := func(postedAt string) int64 {
+		p, err := store.CreatePost(ctx, db.CreatePostParams{QuoteID: q.ID, Platform: "bluesky", TrendTitle: "trend", TrendSource: "hackernews", TrendHash: postedAt})
+		require.NoError(t, err)
+		_, err = store.ExecContext(ctx, "UPDATE posts SET posted_at = ? WHERE id = ?", postedAt, p.ID)
+		require.NoError(t, err)
+		n, err := store.CountPostsToday(ctx, db.CountPostsTodayParams{Platform: "bluesky", Since: sqliteTime(start)})
+		require.NoError(t, err)
+		return n
+	}
+
+	assert.Equal(t, int64(0), count("2026-10-17 04:59:59"), "23:59 the day before, locally")
+	assert.Equal(t, int64(1), count("2026-10-17 05:00:00"), "local midnight")
+	assert.Equal(t, int64(2), count("2026-10-18 01:00:00"), "the next UTC day is still today")
+}
+
+// fakePoster records the posts it is asked to publish.
+type fakePoster struct {
+	posts []poster.PostContent
+}
+
+func (p *fakePoster) Platform() string { return "bluesky" }
+
+func (p *fakePoster) Post(_ context.Context, content poster.PostContent) (*poster.PostResult, error) {
+	p.posts = append(p.posts, content)
+	id := fmt.Sprintf("post-%d", len(p.posts))
+	return &poster.PostResult{PostID: id, PostURL: "https://bsky.app/" + id}, nil
+}
+
+func (p *fakePoster) ValidateCredentials(context.Context) error { return nil }
+
+func TestRunPostCycle(t *testing.T) {
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	require.NoError(t, store.Migrate(ctx))
+
+	create := func(text string) *db.Quote {
+		q, err := store.CreateQuote(ctx, db.CreateQuoteParams{Text: text, TextHash: text, SourceBook: "The Idiot", Themes: "[]", Language: "en"})
+		require.NoError(t, err)
+		return q
+	}
+	queue := func(q *db.Quote, score float64) *db.PendingPost {
+		p, err := review.Enqueue(ctx, store, db.CreatePostParams{
+			QuoteID:        q.ID,
+			TrendTitle:     q.Text + " trend",
+			TrendSource:    "hackernews",
+			TrendHash:      q.Text,
+			RelevanceScore: score,
+		}, poster.PostContent{Text: q.Text}, review.StatusApproved, time.Hour)
+		require.NoError(t, err)
+		return p
+	}
+
+	fake := &fakePoster{}
+	s := &Scheduler{
+		cfg:     &config.Config{MaxPostsPerDay: 10},
+		store:   store,
+		matcher: matcher.New(matcher.Config{Store: store, Language: "en"}),
+		poster:  fake,
+		health:  NewHealth(),
+	}
+
+	a, b := create("a"), create("b")
+	queue(a, 0.7)
+	best := queue(b, 0.9)
+
+	s.runPostCycle(ctx)
+	require.Len(t, fake.posts, 1)
+	assert.Equal(t, "b", fake.posts[0].Text, "the best match is posted first")
+	got, err := store.GetPendingPost(ctx, best.ID)
+	require.NoError(t, err)
+	assert.Equal(t, review.StatusPosted, got.Status)
+	assert.True(t, got.PostID.Valid)
+
+	s.runPostCycle(ctx)
+	s.runPostCycle(ctx)
+	assert.Len(t, fake.posts, 2, "nothing is posted once the queue is empty")
+
+	s.cfg.EvergreenFallback = true
+	evergreenQuote := create("c")
+	s.runPostCycle(ctx)
+	assert.Len(t, fake.posts, 2, "the evergreen limit defaults to none")
+
+	s.cfg.EvergreenMaxPerDay = 1
+	s.runPostCycle(ctx)
+	require.Len(t, fake.posts, 3)
+	assert.Contains(t, fake.posts[2].Text, "c")
+	s.runPostCycle(ctx)
+	assert.Len(t, fake.posts, 3, "one evergreen post a day")
+	posts, err := store.ListPostsByPlatform(ctx, db.ListPostsByPlatformParams{Platform: "bluesky", Limit: 10})
+	require.NoError(t, err)
+	var sources []string
+	for _, p := range posts {
+		if p.QuoteID == evergreenQuote.ID {
+			sources = append(sources, p.TrendSource)
+		}
+	}
+	assert.Equal(t, []string{evergreen.Source}, sources)
+}
+
+// failingMonitor is a trend source that is always down.
+type failingMonitor struct{}
+
+func (failingMonitor) Name() string { return "down" }
+
+func (failingMonitor) FetchTrends(context.Context) ([]monitor.Trend, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestRunMonitorCycle_FetchFails(t *testing.T) {
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	require.NoError(t, store.Migrate(ctx))
+
+	_, err = store.CreateTrend(ctx, db.CreateTrendParams{Source: "hackernews", Title: "A waiting trend"})
+	require.NoError(t, err)
+
+	s := &Scheduler{
+		cfg:     &config.Config{MatchQueueSize: 5},
+		store:   store,
+		agg:     monitor.NewAggregator(monitor.AggregatorConfig{Store: store, Monitors: []monitor.Monitor{failingMonitor{}}}),
+		matcher: matcher.New(matcher.Config{Store: store, Language: "en"}),
+		health:  NewHealth(),
+	}
+
+	s.runMonitorCycle(ctx)
+	status := s.health.GetStatus("monitor")
+	require.NotNil(t, status)
+	assert.False(t, status.Healthy)
+	assert.ErrorContains(t, status.LastError, "connection refused")
+
+	status = s.health.GetStatus("match")
+	require.NotNil(t, status, "waiting trends are matched despite the failed fetch")
+	assert.True(t, status.Healthy)
+}
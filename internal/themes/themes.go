@@ -0,0 +1,274 @@
+// Package themes normalizes the free-form themes the LLM tags quotes with
+// against a taxonomy of canonical themes and their aliases, so that
+// "Suffering", "human-suffering" and "pain" are all stored as "suffering".
+package themes
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/abdulachik/dostobot/internal/db"
+)
+
+// Key returns the form themes and aliases are compared in: lowercase, with
+// hyphens and underscores read as spaces and runs of spaces collapsed.
+func Key(tag string) string {
+	fields := strings.FieldsFunc(strings.ToLower(tag), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-' || r == '_'
+	})
+	return strings.Join(fields, " ")
+}
+
+// Taxonomy maps tags to canonical themes. Tags that are neither a theme nor
+// an alias become new themes when a quote is tagged with them, so they can
+// later be reviewed and folded into others with AddAlias.
+type Taxonomy struct {
+	ids   map[string]int64  // canonical name to theme ID
+	names map[string]string // key of a name or alias to canonical name
+}
+
+// Load reads the taxonomy from the database.
+func Load(ctx context.Context, q *db.Queries) (*Taxonomy, error) {
+	themes, err := q.ListThemes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list themes: %w", err)
+	}
+	aliases, err := q.ListThemeAliases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list theme aliases: %w", err)
+	}
+
+	t := &Taxonomy{
+		ids:   make(map[string]int64, len(themes)),
+		names: make(map[string]string, len(themes)+len(aliases)),
+	}
+	byID := make(map[int64]string, len(themes))
+	for _, theme := range themes {
+		t.ids[theme.Name] = theme.ID
+		t.names[Key(theme.Name)] = theme.Name
+		byID[theme.ID] = theme.Name
+	}
+	for _, alias := range aliases {
+		if name, ok := byID[alias.ThemeID]; ok {
+			t.names[Key(alias.Alias)] = name
+		}
+	}
+	return t, nil
+}
+
+// Canonical returns the canonical theme for tag and whether the taxonomy
+// knows it. Unknown tags are returned in their Key form.
+func (t *Taxonomy) Canonical(tag string) (string, bool) {
+	key := Key(tag)
+	if name, ok := t.names[key]; ok {
+		return name, true
+	}
+	return key, false
+}
+
+// Normalize maps tags to their canonical themes, dropping empty and
+// repeated ones and keeping the order they first appear in.
+func (t *Taxonomy) Normalize(tags []string) []string {
+	out := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		name, _ := t.Canonical(tag)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		out = append(out, name)
+	}
+	return out
+}
+
+// Tag stores the normalized tags as the themes of a quote, both in its
+// JSON themes column and as quote_themes rows, creating themes the
+// taxonomy does not have yet. It returns the canonical themes.
+func (t *Taxonomy) Tag(ctx context.Context, q *db.Queries, quoteID int64, tags []string) ([]string, error) {
+	names := t.Normalize(tags)
+
+	ids := make([]int64, len(names))
+	for i, name := range names {
+		id, err := t.ensure(ctx, q, name)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+
+	themesJSON, err := json.Marshal(names)
+	if err != nil {
+		return nil, fmt.Errorf("marshal themes: %w", err)
+	}
+	if err := q.SetQuoteThemes(ctx, db.SetQuoteThemesParams{Themes: string(themesJSON), ID: quoteID}); err != nil {
+		return nil, fmt.Errorf("set themes of quote %d: %w", quoteID, err)
+	}
+	if err := q.DeleteQuoteThemes(ctx, quoteID); err != nil {
+		return nil, fmt.Errorf("clear themes of quote %d: %w", quoteID, err)
+	}
+	for _, id := range ids {
+		if err := q.AddQuoteTheme(ctx, db.AddQuoteThemeParams{QuoteID: quoteID, ThemeID: id}); err != nil {
+			return nil, fmt.Errorf("tag quote %d: %w", quoteID, err)
+		}
+	}
+	return names, nil
+}
+
+// ensure returns the ID of the theme name, creating it if needed.
+func (t *Taxonomy) ensure(ctx context.Context, q *db.Queries, name string) (int64, error) {
+	if id, ok := t.ids[name]; ok {
+		return id, nil
+	}
+
+	theme, err := q.GetThemeByName(ctx, name)
+	if err == sql.ErrNoRows {
+		theme, err = q.CreateTheme(ctx, name)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("create theme %q: %w", name, err)
+	}
+
+	t.ids[name] = theme.ID
+	t.names[Key(name)] = name
+	return theme.ID, nil
+}
+
+// Parse returns the themes of a quote, or nil if they are not a JSON list.
+func Parse(q *db.Quote) []string {
+	var tags []string
+	if err := json.Unmarshal([]byte(q.Themes), &tags); err != nil {
+		return nil
+	}
+	return tags
+}
+
+// NormalizeAll tags every stored quote with the canonical form of its
+// themes and returns how many quotes' themes changed. Quotes already
+// normalized get their quote_themes rows written if they lack them. With
+// dryRun nothing is saved.
+func NormalizeAll(ctx context.Context, store *db.Store, dryRun bool) (int, error) {
+	tx, err := store.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := store.Queries.WithTx(tx)
+	t, err := Load(ctx, qtx)
+	if err != nil {
+		return 0, err
+	}
+
+	quotes, err := qtx.ListQuotes(ctx, db.ListQuotesParams{Limit: 100000, Offset: 0})
+	if err != nil {
+		return 0, fmt.Errorf("list quotes: %w", err)
+	}
+
+	changed := 0
+	for _, q := range quotes {
+		before := Parse(q)
+		after, err := t.Tag(ctx, qtx, q.ID, before)
+		if err != nil {
+			return 0, err
+		}
+		if !equal(before, after) {
+			changed++
+		}
+	}
+
+	if dryRun {
+		return changed, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return changed, nil
+}
+
+// AddAlias records alias as another name for the theme name. If alias is
+// itself a theme, it is merged into name: its quotes and aliases move to
+// name and it becomes an alias. It returns the number of quotes retagged.
+func AddAlias(ctx context.Context, store *db.Store, alias, name string) (int, error) {
+	key := Key(alias)
+	if key == "" {
+		return 0, fmt.Errorf("alias is empty")
+	}
+
+	tx, err := store.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := store.Queries.WithTx(tx)
+	target, err := qtx.GetThemeByName(ctx, name)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("unknown theme: %s", name)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("find theme: %w", err)
+	}
+
+	retagged := 0
+	merged, err := qtx.GetThemeByName(ctx, key)
+	switch {
+	case err == nil && merged.ID == target.ID:
+		return 0, fmt.Errorf("%q is the theme itself", alias)
+	case err == nil:
+		quotes, err := qtx.ListThemeQuotes(ctx, merged.ID)
+		if err != nil {
+			return 0, fmt.Errorf("list quotes of %s: %w", merged.Name, err)
+		}
+		if err := qtx.ReassignThemeAliases(ctx, db.ReassignThemeAliasesParams{
+			KeeperID:    target.ID,
+			DuplicateID: merged.ID,
+		}); err != nil {
+			return 0, fmt.Errorf("move aliases of %s: %w", merged.Name, err)
+		}
+		if err := qtx.DeleteTheme(ctx, merged.ID); err != nil {
+			return 0, fmt.Errorf("delete theme %s: %w", merged.Name, err)
+		}
+		if err := qtx.SetThemeAlias(ctx, db.SetThemeAliasParams{Alias: key, ThemeID: target.ID}); err != nil {
+			return 0, fmt.Errorf("add alias: %w", err)
+		}
+
+		t, err := Load(ctx, qtx)
+		if err != nil {
+			return 0, err
+		}
+		for _, q := range quotes {
+			if _, err := t.Tag(ctx, qtx, q.ID, Parse(q)); err != nil {
+				return 0, err
+			}
+		}
+		retagged = len(quotes)
+	case err == sql.ErrNoRows:
+		if err := qtx.SetThemeAlias(ctx, db.SetThemeAliasParams{Alias: key, ThemeID: target.ID}); err != nil {
+			return 0, fmt.Errorf("add alias: %w", err)
+		}
+	default:
+		return 0, fmt.Errorf("find theme: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return retagged, nil
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
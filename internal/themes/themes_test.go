@@ -0,0 +1,139 @@
+package themes
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStore(t *testing.T) *db.Store {
+	t.Helper()
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	require.NoError(t, store.Migrate(ctx))
+	return store
+}
+
+func createQuote(t *testing.T, store *db.Store, themes, text string) *db.Quote {
+	t.Helper()
+	q, err := store.CreateQuote(context.Background(), db.CreateQuoteParams{
+		Text:       text,
+		TextHash:   text,
+		SourceBook: "Crime and Punishment",
+		Themes:     themes,
+		CharCount:  int64(len(text)),
+		Language:   "en",
+	})
+	require.NoError(t, err)
+	return q
+}
+
+func themeNames(t *testing.T, store *db.Store, q *db.Quote) []string {
+	t.Helper()
+	got, err := store.GetQuote(context.Background(), q.ID)
+	require.NoError(t, err)
+	return Parse(got)
+}
+
+func TestKey(t *testing.T) {
+	assert.Equal(t, "human suffering", Key("  Human-Suffering "))
+	assert.Equal(t, "human nature", Key("human_nature"))
+	assert.Equal(t, "", Key(" - "))
+}
+
+func TestNormalize(t *testing.T) {
+	store := newStore(t)
+	taxonomy, err := Load(context.Background(), store.Queries)
+	require.NoError(t, err)
+
+	got := taxonomy.Normalize([]string{"Suffering", "human-suffering", "pain", "God", "", "Modern Alienation"})
+	assert.Equal(t, []string{"suffering", "faith", "modern alienation"}, got)
+
+	name, known := taxonomy.Canonical("Pain")
+	assert.True(t, known)
+	assert.Equal(t, "suffering", name)
+}
+
+func TestTag(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	q := createQuote(t, store, `[]`, "a")
+
+	taxonomy, err := Load(ctx, store.Queries)
+	require.NoError(t, err)
+	names, err := taxonomy.Tag(ctx, store.Queries, q.ID, []string{"Pain", "Gambling"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"suffering", "gambling"}, names)
+	assert.Equal(t, names, themeNames(t, store, q))
+
+	gambling, err := store.GetThemeByName(ctx, "gambling")
+	require.NoError(t, err, "unknown themes are added to the taxonomy")
+	tagged, err := store.ListThemeQuotes(ctx, gambling.ID)
+	require.NoError(t, err)
+	require.Len(t, tagged, 1)
+	assert.Equal(t, q.ID, tagged[0].ID)
+
+	untagged, err := store.CountUntaggedQuotes(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, untagged)
+}
+
+func TestNormalizeAll(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	a := createQuote(t, store, `["Suffering","pain"]`, "a")
+	b := createQuote(t, store, `["faith"]`, "b")
+
+	changed, err := NormalizeAll(ctx, store, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, []string{"Suffering", "pain"}, themeNames(t, store, a), "a dry run saves nothing")
+
+	changed, err = NormalizeAll(ctx, store, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, []string{"suffering"}, themeNames(t, store, a))
+	assert.Equal(t, []string{"faith"}, themeNames(t, store, b))
+
+	untagged, err := store.CountUntaggedQuotes(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, untagged, "quotes already normalized are linked to their themes too")
+}
+
+func TestAddAlias(t *testing.T) {
+	ctx := context.Background()
+	store := newStore(t)
+	taxonomy, err := Load(ctx, store.Queries)
+	require.NoError(t, err)
+
+	q := createQuote(t, store, `[]`, "a")
+	_, err = taxonomy.Tag(ctx, store.Queries, q.ID, []string{"gambling", "love"})
+	require.NoError(t, err)
+
+	retagged, err := AddAlias(ctx, store, "Gambling", "madness")
+	require.NoError(t, err)
+	assert.Equal(t, 1, retagged)
+	assert.Equal(t, []string{"madness", "love"}, themeNames(t, store, q))
+
+	_, err = store.GetThemeByName(ctx, "gambling")
+	assert.Error(t, err, "the merged theme is gone")
+
+	retagged, err = AddAlias(ctx, store, "obsession", "madness")
+	require.NoError(t, err)
+	assert.Zero(t, retagged)
+
+	reloaded, err := Load(ctx, store.Queries)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"madness"}, reloaded.Normalize([]string{"gambling", "Obsession"}))
+
+	_, err = AddAlias(ctx, store, "suffering", "suffering")
+	assert.Error(t, err)
+	_, err = AddAlias(ctx, store, "x", "no such theme")
+	assert.Error(t, err)
+}
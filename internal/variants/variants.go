@@ -0,0 +1,205 @@
+// Package variants groups quotes that render the same passage of a work in
+// different editions, e.g. Garnett's and Pevear and Volokhonsky's
+// translations of Crime and Punishment, so one of them can be chosen for a
+// post and the others shown alongside it.
+package variants
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/embedder"
+)
+
+// DefaultSimilarity is the embedding cosine similarity above which quotes of
+// two editions are the same passage. Translations of one passage phrase it
+// differently, so it is lower than the duplicate threshold.
+const DefaultSimilarity = 0.85
+
+// Link is a quote of an original and the quote of a translation that
+// renders it, as recorded by 'dostobot align'.
+type Link struct {
+	OriginalID    int64
+	TranslationID int64
+}
+
+// Find groups the quotes of the editions of one work that render the same
+// passage. Two quotes of different editions in the same language are
+// variants when each is the other's most similar quote in its edition,
+// their embeddings are at least similarity apart, and they are in the same
+// chapter if both have one. Quotes joined by a link are variants whatever
+// their language. embeddings maps quote IDs to their vectors; quotes
+// without one are grouped by links only. Groups are sorted by their first
+// quote's ID and their quotes by ID.
+func Find(quotes []*db.Quote, embeddings map[int64][]float32, links []Link, similarity float32) [][]*db.Quote {
+	if similarity <= 0 {
+		similarity = DefaultSimilarity
+	}
+
+	pos := make(map[int64]int, len(quotes))
+	editions := make(map[string][]int)
+	var books []string
+	for i, q := range quotes {
+		pos[q.ID] = i
+		if _, ok := editions[q.SourceBook]; !ok {
+			books = append(books, q.SourceBook)
+		}
+		editions[q.SourceBook] = append(editions[q.SourceBook], i)
+	}
+	sort.Strings(books)
+
+	// Union-find over positions in quotes.
+	parent := make([]int, len(quotes))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(i, j int) {
+		parent[find(j)] = find(i)
+	}
+
+	for _, l := range links {
+		i, iok := pos[l.OriginalID]
+		j, jok := pos[l.TranslationID]
+		if iok && jok {
+			union(i, j)
+		}
+	}
+
+	for a := range books {
+		for b := a + 1; b < len(books); b++ {
+			from, to := editions[books[a]], editions[books[b]]
+			forward := bestMatches(quotes, from, to, embeddings)
+			backward := bestMatches(quotes, to, from, embeddings)
+			for i, m := range forward {
+				if m.index >= 0 && backward[m.index].index == i && m.similarity >= similarity {
+					union(from[i], to[m.index])
+				}
+			}
+		}
+	}
+
+	members := make(map[int][]*db.Quote)
+	for i, q := range quotes {
+		root := find(i)
+		members[root] = append(members[root], q)
+	}
+
+	var groups [][]*db.Quote
+	for _, qs := range members {
+		if len(qs) < 2 {
+			continue
+		}
+		sort.Slice(qs, func(a, b int) bool { return qs[a].ID < qs[b].ID })
+		groups = append(groups, qs)
+	}
+	sort.Slice(groups, func(a, b int) bool { return groups[a][0].ID < groups[b][0].ID })
+
+	return groups
+}
+
+// match is the most similar quote of another edition, by its index in that
+// edition's positions, or -1 if there is none.
+type match struct {
+	index      int
+	similarity float32
+}
+
+// bestMatches returns, for each quote at the positions from, the most
+// similar comparable quote at the positions to.
+func bestMatches(quotes []*db.Quote, from, to []int, embeddings map[int64][]float32) []match {
+	matches := make([]match, len(from))
+	for i, fi := range from {
+		matches[i] = match{index: -1}
+		a := quotes[fi]
+		va := embeddings[a.ID]
+		if len(va) == 0 {
+			continue
+		}
+		for j, tj := range to {
+			b := quotes[tj]
+			vb := embeddings[b.ID]
+			if !comparable(a, b) || len(vb) != len(va) {
+				continue
+			}
+			if sim := embedder.CosineSimilarity(va, vb); matches[i].index < 0 || sim > matches[i].similarity {
+				matches[i] = match{index: j, similarity: sim}
+			}
+		}
+	}
+	return matches
+}
+
+// comparable reports whether two quotes of different editions may render
+// the same passage: they are in the same language and, if both have a
+// chapter, the same chapter. Sections are labelled alike in every edition.
+func comparable(a, b *db.Quote) bool {
+	if a.Language != b.Language {
+		return false
+	}
+	if a.Chapter.Valid && b.Chapter.Valid && a.Chapter.String != "" && b.Chapter.String != "" {
+		return a.Chapter.String == b.Chapter.String
+	}
+	return true
+}
+
+// Save stores a group in one transaction and returns its ID. Quotes already
+// in other groups bring their groups' quotes along: every group the quotes
+// belong to is folded into the one with the lowest ID, and a new group is
+// created only if none of them has one.
+func Save(ctx context.Context, store *db.Store, group []*db.Quote) (int64, error) {
+	tx, err := store.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := store.Queries.WithTx(tx)
+
+	var id int64
+	for _, q := range group {
+		if q.GroupID.Valid && (id == 0 || q.GroupID.Int64 < id) {
+			id = q.GroupID.Int64
+		}
+	}
+	if id == 0 {
+		created, err := qtx.CreateQuoteGroup(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("create group: %w", err)
+		}
+		id = created.ID
+	}
+	groupID := sql.NullInt64{Int64: id, Valid: true}
+
+	for _, q := range group {
+		if q.GroupID.Valid && q.GroupID.Int64 != id {
+			if err := qtx.MoveQuoteGroup(ctx, db.MoveQuoteGroupParams{
+				KeeperID: groupID,
+				GroupID:  q.GroupID,
+			}); err != nil {
+				return 0, fmt.Errorf("merge group %d: %w", q.GroupID.Int64, err)
+			}
+		}
+		if err := qtx.SetQuoteGroup(ctx, db.SetQuoteGroupParams{GroupID: groupID, ID: q.ID}); err != nil {
+			return 0, fmt.Errorf("group quote %d: %w", q.ID, err)
+		}
+	}
+
+	if err := qtx.DeleteEmptyQuoteGroups(ctx); err != nil {
+		return 0, fmt.Errorf("delete empty groups: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
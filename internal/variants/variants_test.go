@@ -0,0 +1,120 @@
+package variants
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func quote(id int64, book, chapter, text string) *db.Quote {
+	return &db.Quote{
+		ID:         id,
+		Text:       text,
+		SourceBook: book,
+		Chapter:    sql.NullString{String: chapter, Valid: chapter != ""},
+		Language:   "en",
+	}
+}
+
+func TestFind(t *testing.T) {
+	garnett := "Crime and Punishment (Garnett)"
+	pevear := "Crime and Punishment (Pevear and Volokhonsky)"
+
+	g1 := quote(1, garnett, "Part 1, Chapter 1", "Pain and suffering are always inevitable for a large intelligence and a deep heart.")
+	g2 := quote(2, garnett, "Part 3, Chapter 5", "It takes something more than intelligence to act intelligently.")
+	p1 := quote(3, pevear, "Part 1, Chapter 1", "Suffering and pain are always obligatory for a broad consciousness and a deep heart.")
+	p2 := quote(4, pevear, "Part 1, Chapter 1", "A man is fond of counting his troubles.")
+	p3 := quote(5, pevear, "Part 6, Chapter 2", "To act intelligently takes more than intelligence.")
+
+	embeddings := map[int64][]float32{
+		1: {1, 0, 0},
+		2: {0, 1, 0},
+		3: {0.95, 0.1, 0},
+		4: {0.9, 0.2, 0},
+		5: {0, 0.99, 0.05}, // close to 2, but in another chapter
+	}
+
+	groups := Find([]*db.Quote{g1, g2, p1, p2, p3}, embeddings, nil, 0)
+	require.Len(t, groups, 1)
+	assert.Equal(t, []*db.Quote{g1, p1}, groups[0], "only mutual best matches are variants")
+
+	t.Run("links join other languages", func(t *testing.T) {
+		original := quote(6, "Преступление и наказание", "Part 1, Chapter 1", "Страдание и боль всегда обязательны для широкого сознания и глубокого сердца.")
+		original.Language = "ru"
+		links := []Link{{OriginalID: 6, TranslationID: 3}}
+
+		groups := Find([]*db.Quote{g1, g2, p1, p2, p3, original}, embeddings, links, 0)
+		require.Len(t, groups, 1)
+		assert.Equal(t, []*db.Quote{g1, p1, original}, groups[0])
+	})
+
+	t.Run("threshold", func(t *testing.T) {
+		groups := Find([]*db.Quote{g1, p1}, embeddings, nil, 0.999)
+		assert.Empty(t, groups)
+	})
+}
+
+func TestSave(t *testing.T) {
+	ctx := context.Background()
+	store, err := db.NewStore(ctx, filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	require.NoError(t, store.Migrate(ctx))
+
+	create := func(book, hash string) *db.Quote {
+		q, err := store.CreateQuote(ctx, db.CreateQuoteParams{
+			Text:       "text " + hash,
+			TextHash:   hash,
+			SourceBook: book,
+			Themes:     "[]",
+			Language:   "en",
+		})
+		require.NoError(t, err)
+		return q
+	}
+	reload := func(qs ...*db.Quote) []*db.Quote {
+		out := make([]*db.Quote, len(qs))
+		for i, q := range qs {
+			var err error
+			out[i], err = store.GetQuote(ctx, q.ID)
+			require.NoError(t, err)
+		}
+		return out
+	}
+
+	a := create("Garnett", "a")
+	b := create("Pevear", "b")
+	c := create("Garnett", "c")
+	d := create("Pevear", "d")
+
+	first, err := Save(ctx, store, []*db.Quote{a, b})
+	require.NoError(t, err)
+	second, err := Save(ctx, store, []*db.Quote{c, d})
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+
+	// Saving the same group again keeps its ID
+	again, err := Save(ctx, store, reload(a, b))
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+
+	// A group spanning both folds them into the older one
+	qs := reload(a, b, c, d)
+	merged, err := Save(ctx, store, []*db.Quote{qs[1], qs[2]})
+	require.NoError(t, err)
+	assert.Equal(t, first, merged)
+
+	members, err := store.ListGroupQuotes(ctx, sql.NullInt64{Int64: first, Valid: true})
+	require.NoError(t, err)
+	require.Len(t, members, 4)
+	assert.Equal(t, []int64{a.ID, b.ID, c.ID, d.ID}, []int64{members[0].ID, members[1].ID, members[2].ID, members[3].ID})
+
+	count, err := store.CountQuoteGroups(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count, "the emptied group is deleted")
+}
@@ -0,0 +1,100 @@
+package vectorstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/abdulachik/dostobot/internal/db"
+)
+
+// reindexSuffix is appended to the index path for the collection being built.
+const reindexSuffix = ".reindex"
+
+// ErrReindexIncomplete is returned when the rebuilt index does not contain
+// every quote; the existing index is left untouched.
+var ErrReindexIncomplete = errors.New("reindex incomplete")
+
+// ReindexResult reports the outcome of a full rebuild.
+type ReindexResult struct {
+	Expected int
+	Indexed  int
+	Model    string
+	Dim      int
+}
+
+// Reindex builds a fresh index for quotes with the configured provider and
+// atomically replaces the file at cfg.Path with it. The new collection is
+// built alongside the old one, so the existing index keeps serving searches
+// until the swap and is left untouched if anything fails.
+func Reindex(ctx context.Context, cfg Config, quotes []*db.Quote, progress func(done, total int)) (*ReindexResult, error) {
+	tmpPath := cfg.Path + reindexSuffix
+
+	// Clear leftovers from an interrupted run.
+	if err := removeIndexFiles(tmpPath); err != nil {
+		return nil, fmt.Errorf("remove stale reindex file: %w", err)
+	}
+
+	tmpCfg := cfg
+	tmpCfg.Path = tmpPath
+	store, err := New(tmpCfg)
+	if err != nil {
+		return nil, fmt.Errorf("create new index: %w", err)
+	}
+
+	result, err := buildIndex(ctx, store, quotes, progress)
+	if closeErr := store.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("close new index: %w", closeErr)
+	}
+	if err != nil {
+		if rmErr := removeIndexFiles(tmpPath); rmErr != nil {
+			slog.Warn("failed to remove partial index", "path", tmpPath, "error", rmErr)
+		}
+		return result, err
+	}
+
+	if err := os.Rename(tmpPath, cfg.Path); err != nil {
+		return result, fmt.Errorf("swap in new index: %w", err)
+	}
+	if err := removeIndexFiles(tmpPath); err != nil {
+		slog.Warn("failed to clean up reindex files", "path", tmpPath, "error", err)
+	}
+
+	slog.Info("index swapped in", "path", cfg.Path, "quotes", result.Indexed, "model", result.Model)
+	return result, nil
+}
+
+// buildIndex embeds every quote into an empty store and verifies the count.
+func buildIndex(ctx context.Context, store *QuoteStore, quotes []*db.Quote, progress func(done, total int)) (*ReindexResult, error) {
+	result := &ReindexResult{
+		Expected: len(quotes),
+		Model:    store.profile.Model,
+		Dim:      store.profile.Dimension,
+	}
+
+	plan := store.PlanSync(quotes)
+	syncResult, err := store.ApplySync(ctx, plan, progress)
+	if err != nil {
+		return result, fmt.Errorf("build index: %w", err)
+	}
+
+	result.Indexed = store.Count()
+	if syncResult.Failed > 0 || result.Indexed != result.Expected {
+		return result, fmt.Errorf("%w: indexed %d of %d quotes (%d failed)",
+			ErrReindexIncomplete, result.Indexed, result.Expected, syncResult.Failed)
+	}
+
+	return result, nil
+}
+
+// removeIndexFiles deletes a VecLite file and the sidecars VecLite keeps next to it.
+func removeIndexFiles(path string) error {
+	for _, p := range []string{path, path + ".tmp", path + ".lock"} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
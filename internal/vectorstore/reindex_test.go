@@ -0,0 +1,73 @@
+package vectorstore
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider produces deterministic vectors of a fixed size.
+type fakeProvider struct {
+	model string
+	dim   int
+}
+
+func (p *fakeProvider) Name() string   { return "fake" }
+func (p *fakeProvider) Model() string  { return p.model }
+func (p *fakeProvider) Dimension() int { return p.dim }
+
+func (p *fakeProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	v := make([]float32, p.dim)
+	for i, r := range text {
+		v[i%p.dim] += float32(r)
+	}
+	v[0]++ // never all zeros
+	return v, nil
+}
+
+func (p *fakeProvider) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		out[i], _ = p.Embed(ctx, t)
+	}
+	return out, nil
+}
+
+func TestReindex(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "quotes.veclite")
+	quotes := []*db.Quote{testQuote(1, "pain and suffering", "h1"), testQuote(2, "beauty will save the world", "h2")}
+
+	// Build an index with the original model.
+	oldProvider := &fakeProvider{model: "old-model", dim: 4}
+	store, err := New(Config{Path: path, Provider: oldProvider})
+	require.NoError(t, err)
+	_, err = store.ApplySync(ctx, store.PlanSync(quotes), nil)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	// Switching models without reindexing is refused.
+	newProvider := &fakeProvider{model: "new-model", dim: 8}
+	_, err = New(Config{Path: path, Provider: newProvider})
+	assert.ErrorIs(t, err, ErrEmbeddingMismatch)
+
+	result, err := Reindex(ctx, Config{Path: path, Provider: newProvider}, quotes, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Indexed)
+	assert.Equal(t, "new-model", result.Model)
+
+	assert.NoFileExists(t, path+reindexSuffix)
+
+	store, err = NewReadOnly(Config{Path: path, Provider: newProvider})
+	require.NoError(t, err)
+	defer store.Close()
+	assert.Equal(t, 2, store.Count())
+	assert.Equal(t, 8, store.Stats().Dimension)
+
+	_, err = New(Config{Path: path, Provider: oldProvider})
+	assert.ErrorIs(t, err, ErrEmbeddingMismatch)
+}
@@ -3,18 +3,29 @@ package vectorstore
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 
 	"github.com/abdul-hamid-achik/veclite"
 	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/abdulachik/dostobot/internal/embedder"
 )
 
 const (
 	// Collection name for quotes
 	quotesCollection = "quotes"
+
+	// Collection metadata keys recording which model built the index.
+	metaEmbedProvider = "embed_provider"
+	metaEmbedModel    = "embed_model"
+	metaEmbedDim      = "embed_dim"
 )
 
+// ErrEmbeddingMismatch is returned when the index was built with a different
+// embedding model or dimension than the configured provider.
+var ErrEmbeddingMismatch = errors.New("embedding model mismatch")
+
 // Config holds configuration for the QuoteStore.
 type Config struct {
 	// Path to the VecLite database file (e.g., "data/quotes.veclite").
@@ -22,14 +33,25 @@ type Config struct {
 
 	// ConfigPath is the path to veclite.yaml config file (optional).
 	// If empty, searches ./veclite.yaml, ~/.veclite/config.yaml.
+	// Ignored when Provider is set.
 	ConfigPath string
+
+	// Provider generates embeddings (optional). If nil, one is built from veclite.yaml.
+	Provider embedder.Provider
 }
 
 // QuoteStore wraps VecLite for quote vector storage and search.
 type QuoteStore struct {
 	vecdb    *veclite.DB
 	coll     *veclite.Collection
-	embedder veclite.Embedder
+	embedder embedder.Provider
+	profile  embeddingProfile
+}
+
+// embeddingProfile identifies the model that produced a set of vectors.
+type embeddingProfile struct {
+	Model     string
+	Dimension int
 }
 
 // SearchResult represents a search result from the vector store.
@@ -58,24 +80,25 @@ func NewReadOnly(cfg Config) (*QuoteStore, error) {
 func newStore(cfg Config, readOnly bool) (*QuoteStore, error) {
 	slog.Debug("creating QuoteStore", "path", cfg.Path, "config_path", cfg.ConfigPath)
 
-	// Load veclite config (searches ./veclite.yaml, ~/.veclite/config.yaml)
-	vecliteCfg, err := veclite.LoadConfig(cfg.ConfigPath)
-	if err != nil {
-		return nil, fmt.Errorf("load veclite config: %w", err)
+	provider := cfg.Provider
+	if provider == nil {
+		var err error
+		provider, err = embedder.LoadProvider(embedder.ProviderConfig{ConfigPath: cfg.ConfigPath})
+		if err != nil {
+			return nil, fmt.Errorf("create embedder: %w", err)
+		}
 	}
 
-	slog.Info("loaded veclite config",
-		"provider", vecliteCfg.Embedder.Provider,
-	)
-
-	// Create embedder from config
-	embedder, err := veclite.NewEmbedderFromConfig(vecliteCfg.Embedder)
-	if err != nil {
-		return nil, fmt.Errorf("create embedder: %w", err)
+	dimension := provider.Dimension()
+	if dimension == 0 {
+		// Unknown model: ask it once so the collection gets the right size.
+		probe, err := provider.Embed(context.Background(), "dimension probe")
+		if err != nil {
+			return nil, fmt.Errorf("probe embedding dimension: %w", err)
+		}
+		dimension = len(probe)
 	}
-
-	dimension := embedder.Dimension()
-	slog.Debug("embedder created", "dimension", dimension)
+	slog.Debug("embedder created", "provider", provider.Name(), "model", provider.Model(), "dimension", dimension)
 
 	// Open VecLite database
 	opts := []veclite.Option{}
@@ -93,7 +116,7 @@ func newStore(cfg Config, readOnly bool) (*QuoteStore, error) {
 		veclite.WithDistanceType(veclite.DistanceCosine),
 		veclite.WithHNSW(16, 200), // M=16, efConstruction=200
 		veclite.WithTextIndex("themes", "text", "book", "character"),
-		veclite.WithEmbedder(embedder),
+		veclite.WithEmbedder(embedder.AsVecLite(provider)),
 	)
 	if err != nil {
 		// Collection might already exist, try to get it
@@ -104,11 +127,65 @@ func newStore(cfg Config, readOnly bool) (*QuoteStore, error) {
 		}
 	}
 
-	return &QuoteStore{
+	s := &QuoteStore{
 		vecdb:    vecdb,
 		coll:     coll,
-		embedder: embedder,
-	}, nil
+		embedder: provider,
+		profile:  embeddingProfile{Model: provider.Model(), Dimension: dimension},
+	}
+
+	if err := s.checkProfile(provider.Name(), readOnly); err != nil {
+		vecdb.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// checkProfile verifies the index was built with the configured model, so
+// similarities are never computed between vectors from different models.
+// Indexes created before profiles were recorded are adopted on first write.
+func (s *QuoteStore) checkProfile(providerName string, readOnly bool) error {
+	if dim := s.coll.Dimension(); dim != 0 && dim != s.profile.Dimension {
+		return fmt.Errorf("%w: index has %d dimensions but %s/%s produces %d; run `dostobot reindex` to rebuild it",
+			ErrEmbeddingMismatch, dim, providerName, s.profile.Model, s.profile.Dimension)
+	}
+
+	meta := s.coll.Metadata()
+	stored, _ := meta[metaEmbedModel].(string)
+	if stored == "" {
+		// No collection-level profile yet; fall back to any tagged record.
+		s.coll.ForEach(func(r *veclite.Record) bool {
+			stored, _ = r.Payload["embed_model"].(string)
+			return stored == ""
+		})
+	}
+	if stored != "" && stored != s.profile.Model {
+		return fmt.Errorf("%w: index was built with %q but the configured model is %q; run `dostobot reindex` to rebuild it",
+			ErrEmbeddingMismatch, stored, s.profile.Model)
+	}
+
+	if readOnly {
+		return nil
+	}
+	if _, ok := meta[metaEmbedModel]; ok {
+		return nil
+	}
+	for k, v := range map[string]any{
+		metaEmbedProvider: providerName,
+		metaEmbedModel:    s.profile.Model,
+		metaEmbedDim:      s.profile.Dimension,
+	} {
+		if err := s.coll.SetMetadataValue(k, v); err != nil {
+			return fmt.Errorf("record embedding profile: %w", err)
+		}
+	}
+	return nil
+}
+
+// Model returns the embedding model used by the store.
+func (s *QuoteStore) Model() string {
+	return s.profile.Model
 }
 
 // Close closes the VecLite database.
@@ -122,38 +199,16 @@ func (s *QuoteStore) Close() error {
 // InsertQuote adds a quote to the vector store.
 // Returns the VecLite record ID.
 func (s *QuoteStore) InsertQuote(ctx context.Context, q *db.Quote) (uint64, error) {
-	payload := map[string]any{
-		"sqlite_id":  q.ID,
-		"book":       q.SourceBook,
-		"themes":     q.Themes,
-		"char_count": q.CharCount,
-		"text":       q.Text,
-	}
-	if q.Character.Valid {
-		payload["character"] = q.Character.String
-	}
-
-	// Use InsertText which auto-embeds via the configured embedder
-	id, err := s.coll.InsertText(q.Text, payload)
+	embedding, err := s.embedder.Embed(ctx, q.Text)
 	if err != nil {
-		return 0, fmt.Errorf("insert quote: %w", err)
+		return 0, fmt.Errorf("embed quote: %w", err)
 	}
-
-	return id, nil
+	return s.InsertQuoteWithEmbedding(ctx, q, embedding)
 }
 
 // InsertQuoteWithEmbedding adds a quote with a pre-computed embedding.
 func (s *QuoteStore) InsertQuoteWithEmbedding(ctx context.Context, q *db.Quote, embedding []float32) (uint64, error) {
-	payload := map[string]any{
-		"sqlite_id":  q.ID,
-		"book":       q.SourceBook,
-		"themes":     q.Themes,
-		"char_count": q.CharCount,
-		"text":       q.Text,
-	}
-	if q.Character.Valid {
-		payload["character"] = q.Character.String
-	}
+	payload := quotePayload(q, s.profile)
 
 	// Use InsertDocument with pre-computed embedding
 	id, err := s.coll.InsertDocument(embedding, q.Text, payload)
@@ -164,9 +219,34 @@ func (s *QuoteStore) InsertQuoteWithEmbedding(ctx context.Context, q *db.Quote,
 	return id, nil
 }
 
+// quotePayload builds the VecLite payload stored alongside a quote's vector.
+// text_hash lets PlanSync detect edited quotes without re-embedding everything,
+// and embed_model/embed_dim record which model produced the vector.
+func quotePayload(q *db.Quote, profile embeddingProfile) map[string]any {
+	payload := map[string]any{
+		"sqlite_id":   q.ID,
+		"text_hash":   q.TextHash,
+		"embed_model": profile.Model,
+		"embed_dim":   profile.Dimension,
+		"book":        q.SourceBook,
+		"themes":      q.Themes,
+		"char_count":  q.CharCount,
+		"text":        q.Text,
+	}
+	if q.Character.Valid {
+		payload["character"] = q.Character.String
+	}
+	return payload
+}
+
 // Search finds quotes similar to the query text using vector search.
 func (s *QuoteStore) Search(ctx context.Context, query string, k int) ([]SearchResult, error) {
-	results, err := s.coll.SearchText(query, veclite.TopK(k))
+	queryVec, err := s.embedder.Embed(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+
+	results, err := s.coll.Search(queryVec, veclite.TopK(k))
 	if err != nil {
 		return nil, fmt.Errorf("search: %w", err)
 	}
@@ -176,7 +256,12 @@ func (s *QuoteStore) Search(ctx context.Context, query string, k int) ([]SearchR
 
 // SearchWithThreshold finds quotes above a similarity threshold.
 func (s *QuoteStore) SearchWithThreshold(ctx context.Context, query string, threshold float32, maxResults int) ([]SearchResult, error) {
-	results, err := s.coll.SearchText(query,
+	queryVec, err := s.embedder.Embed(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+
+	results, err := s.coll.Search(queryVec,
 		veclite.TopK(maxResults),
 		veclite.Threshold(threshold),
 	)
@@ -213,7 +298,7 @@ func (s *QuoteStore) SearchVectorWithThreshold(ctx context.Context, queryVec []f
 // HybridSearch combines vector and BM25 text search using RRF fusion.
 func (s *QuoteStore) HybridSearch(ctx context.Context, query string, k int, vectorWeight, textWeight float64) ([]SearchResult, error) {
 	// First, embed the query
-	queryVec, err := s.embedder.Embed(query)
+	queryVec, err := s.embedder.Embed(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("embed query: %w", err)
 	}
@@ -242,7 +327,7 @@ func (s *QuoteStore) TextSearch(ctx context.Context, query string, k int) ([]Sea
 
 // SearchByBook filters search results by book.
 func (s *QuoteStore) SearchByBook(ctx context.Context, query string, book string, k int) ([]SearchResult, error) {
-	queryVec, err := s.embedder.Embed(query)
+	queryVec, err := s.embedder.Embed(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("embed query: %w", err)
 	}
@@ -258,16 +343,21 @@ func (s *QuoteStore) SearchByBook(ctx context.Context, query string, book string
 	return s.convertResults(results), nil
 }
 
-// SearchByCharacter filters search results by character.
-func (s *QuoteStore) SearchByCharacter(ctx context.Context, query string, character string, k int) ([]SearchResult, error) {
-	queryVec, err := s.embedder.Embed(query)
+// SearchByCharacter filters search results by book and character. Quotes
+// are indexed under their character's canonical name from the book's
+// registry, so character must be resolved with characters.Registry first.
+func (s *QuoteStore) SearchByCharacter(ctx context.Context, query string, book string, character string, k int) ([]SearchResult, error) {
+	queryVec, err := s.embedder.Embed(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("embed query: %w", err)
 	}
 
 	results, err := s.coll.Search(queryVec,
 		veclite.TopK(k),
-		veclite.WithFilter(veclite.Equal("character", character)),
+		veclite.WithFilter(veclite.And(
+			veclite.Equal("book", book),
+			veclite.Equal("character", character),
+		)),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("search by character: %w", err)
@@ -286,14 +376,26 @@ func (s *QuoteStore) Stats() veclite.CollectionStats {
 	return s.coll.Stats()
 }
 
+// Vectors returns the embedding of every indexed quote, keyed by SQLite ID.
+func (s *QuoteStore) Vectors() map[int64][]float32 {
+	vectors := make(map[int64][]float32, s.coll.Count())
+	s.coll.ForEach(func(r *veclite.Record) bool {
+		if id, ok := payloadInt64(r.Payload, "sqlite_id"); ok {
+			vectors[id] = r.Vector
+		}
+		return true
+	})
+	return vectors
+}
+
 // Sync persists any pending changes to disk.
 func (s *QuoteStore) Sync() error {
 	return s.vecdb.Sync()
 }
 
 // Embed generates an embedding for the given text.
-func (s *QuoteStore) Embed(text string) ([]float32, error) {
-	return s.embedder.Embed(text)
+func (s *QuoteStore) Embed(ctx context.Context, text string) ([]float32, error) {
+	return s.embedder.Embed(ctx, text)
 }
 
 // convertResults converts VecLite results to SearchResults.
@@ -307,10 +409,8 @@ func (s *QuoteStore) convertResults(results []veclite.Result) []SearchResult {
 
 		// Extract payload fields
 		if r.Record.Payload != nil {
-			if id, ok := r.Record.Payload["sqlite_id"].(int64); ok {
+			if id, ok := payloadInt64(r.Record.Payload, "sqlite_id"); ok {
 				sr.SQLiteID = id
-			} else if id, ok := r.Record.Payload["sqlite_id"].(int); ok {
-				sr.SQLiteID = int64(id)
 			}
 			if book, ok := r.Record.Payload["book"].(string); ok {
 				sr.Book = book
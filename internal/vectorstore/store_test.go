@@ -0,0 +1,37 @@
+package vectorstore
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchByCharacter(t *testing.T) {
+	ctx := context.Background()
+	store, err := New(Config{Path: filepath.Join(t.TempDir(), "quotes.veclite"), Provider: &fakeProvider{model: "test", dim: 4}})
+	require.NoError(t, err)
+	defer store.Close()
+
+	raskolnikov := testQuote(1, "pain and suffering", "h1")
+	sonya := testQuote(2, "go to the crossroads", "h2")
+	sonya.Character = sql.NullString{String: "Sonya", Valid: true}
+	elsewhere := testQuote(3, "pain and suffering again", "h3")
+	elsewhere.SourceBook = "The Idiot"
+	_, err = store.ApplySync(ctx, store.PlanSync([]*db.Quote{raskolnikov, sonya, elsewhere}), nil)
+	require.NoError(t, err)
+
+	results, err := store.SearchByCharacter(ctx, "suffering", "Crime and Punishment", "Raskolnikov", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, int64(1), results[0].SQLiteID)
+	assert.Equal(t, "Raskolnikov", results[0].Character)
+
+	results, err = store.SearchByCharacter(ctx, "suffering", "The Idiot", "Sonya", 10)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
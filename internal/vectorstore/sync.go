@@ -0,0 +1,314 @@
+package vectorstore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/abdul-hamid-achik/veclite"
+	"github.com/abdulachik/dostobot/internal/db"
+)
+
+const (
+	// syncEmbedBatchSize is how many quotes are embedded per embedder call during sync.
+	syncEmbedBatchSize = 50
+
+	// syncFlushEvery controls how often pending changes are persisted during sync.
+	syncFlushEvery = 500
+)
+
+// SyncUpdate pairs an existing VecLite record with the SQLite quote it mirrors.
+type SyncUpdate struct {
+	VecLiteID uint64
+	Quote     *db.Quote
+}
+
+// SyncPlan describes the changes needed to bring VecLite in line with SQLite.
+type SyncPlan struct {
+	// Add holds quotes that have no VecLite record yet.
+	Add []*db.Quote
+	// Reembed holds quotes whose text changed since they were embedded,
+	// or that were embedded by a different model.
+	Reembed []SyncUpdate
+	// Refresh holds quotes whose metadata changed but whose text did not,
+	// so the payload can be rewritten without paying for a new embedding.
+	Refresh []SyncUpdate
+	// Delete holds VecLite records for removed quotes, duplicates and orphans.
+	Delete []uint64
+	// Unchanged counts quotes that are already up to date.
+	Unchanged int
+}
+
+// Empty reports whether the plan has nothing to do.
+func (p *SyncPlan) Empty() bool {
+	return len(p.Add) == 0 && len(p.Reembed) == 0 && len(p.Refresh) == 0 && len(p.Delete) == 0
+}
+
+// EmbedCount returns the number of quotes that need a new embedding.
+func (p *SyncPlan) EmbedCount() int {
+	return len(p.Add) + len(p.Reembed)
+}
+
+// SyncResult reports what ApplySync actually did.
+type SyncResult struct {
+	Added      int
+	Reembedded int
+	Refreshed  int
+	Deleted    int
+	Failed     int
+}
+
+// indexedRecord is the subset of a VecLite record needed to plan a sync.
+type indexedRecord struct {
+	ID         uint64
+	SQLiteID   int64
+	HasID      bool
+	TextHash   string
+	Text       string
+	EmbedModel string
+	Payload    map[string]any
+}
+
+// PlanSync compares the quotes in SQLite with the records in VecLite and
+// returns the changes required to make them match. It does not modify anything.
+func (s *QuoteStore) PlanSync(quotes []*db.Quote) *SyncPlan {
+	var records []indexedRecord
+	s.coll.ForEach(func(r *veclite.Record) bool {
+		records = append(records, toIndexedRecord(r))
+		return true
+	})
+	return planSync(records, quotes, s.profile)
+}
+
+// planSync is the pure part of PlanSync, split out for testing.
+func planSync(records []indexedRecord, quotes []*db.Quote, profile embeddingProfile) *SyncPlan {
+	plan := &SyncPlan{}
+
+	// Keep the oldest record per SQLite ID; anything else is a duplicate
+	// left behind by earlier non-idempotent runs.
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	bySQLiteID := make(map[int64]indexedRecord, len(records))
+	for _, r := range records {
+		if !r.HasID {
+			plan.Delete = append(plan.Delete, r.ID)
+			continue
+		}
+		if _, seen := bySQLiteID[r.SQLiteID]; seen {
+			plan.Delete = append(plan.Delete, r.ID)
+			continue
+		}
+		bySQLiteID[r.SQLiteID] = r
+	}
+
+	present := make(map[int64]bool, len(quotes))
+	for _, q := range quotes {
+		present[q.ID] = true
+
+		r, ok := bySQLiteID[q.ID]
+		if !ok {
+			plan.Add = append(plan.Add, q)
+			continue
+		}
+
+		if textChanged(r, q) || (r.EmbedModel != "" && r.EmbedModel != profile.Model) {
+			plan.Reembed = append(plan.Reembed, SyncUpdate{VecLiteID: r.ID, Quote: q})
+			continue
+		}
+
+		if !payloadMatches(r.Payload, quotePayload(q, profile)) {
+			plan.Refresh = append(plan.Refresh, SyncUpdate{VecLiteID: r.ID, Quote: q})
+			continue
+		}
+
+		plan.Unchanged++
+	}
+
+	for id, r := range bySQLiteID {
+		if !present[id] {
+			plan.Delete = append(plan.Delete, r.ID)
+		}
+	}
+	sort.Slice(plan.Delete, func(i, j int) bool { return plan.Delete[i] < plan.Delete[j] })
+
+	return plan
+}
+
+// textChanged reports whether the embedded text differs from the quote's current text.
+// Records written before text hashes were stored fall back to comparing the raw text.
+func textChanged(r indexedRecord, q *db.Quote) bool {
+	if r.TextHash != "" {
+		return r.TextHash != q.TextHash
+	}
+	return r.Text != q.Text
+}
+
+// payloadMatches reports whether every field we write is already present with the same value.
+func payloadMatches(existing, want map[string]any) bool {
+	if len(existing) != len(want) {
+		return false
+	}
+	for k, v := range want {
+		if fmt.Sprint(existing[k]) != fmt.Sprint(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplySync executes a plan produced by PlanSync. Embedding failures are
+// counted and logged rather than aborting, so a rerun picks up where this one
+// left off. The progress callback, if non-nil, is called after each embedded batch.
+func (s *QuoteStore) ApplySync(ctx context.Context, plan *SyncPlan, progress func(done, total int)) (*SyncResult, error) {
+	result := &SyncResult{}
+	pending := 0
+
+	flush := func(force bool) error {
+		if pending == 0 || (!force && pending < syncFlushEvery) {
+			return nil
+		}
+		pending = 0
+		return s.Sync()
+	}
+
+	for _, id := range plan.Delete {
+		if err := s.coll.Delete(id); err != nil {
+			slog.Warn("failed to delete veclite record", "veclite_id", id, "error", err)
+			result.Failed++
+			continue
+		}
+		result.Deleted++
+		pending++
+	}
+
+	for _, u := range plan.Refresh {
+		if err := s.coll.UpdateDocument(u.VecLiteID, u.Quote.Text, quotePayload(u.Quote, s.profile)); err != nil {
+			slog.Warn("failed to refresh quote payload", "id", u.Quote.ID, "error", err)
+			result.Failed++
+			continue
+		}
+		result.Refreshed++
+		pending++
+	}
+	if err := flush(false); err != nil {
+		return result, fmt.Errorf("sync: %w", err)
+	}
+
+	// Re-embeds and additions share the batched embedding path; a zero
+	// VecLiteID marks a quote that needs a fresh record.
+	work := make([]SyncUpdate, 0, plan.EmbedCount())
+	work = append(work, plan.Reembed...)
+	for _, q := range plan.Add {
+		work = append(work, SyncUpdate{Quote: q})
+	}
+
+	done := 0
+	for start := 0; start < len(work); start += syncEmbedBatchSize {
+		select {
+		case <-ctx.Done():
+			if err := flush(true); err != nil {
+				slog.Warn("failed to sync after cancellation", "error", err)
+			}
+			return result, ctx.Err()
+		default:
+		}
+
+		end := min(start+syncEmbedBatchSize, len(work))
+		batch := work[start:end]
+
+		texts := make([]string, len(batch))
+		for i, u := range batch {
+			texts[i] = u.Quote.Text
+		}
+
+		vectors, err := s.embedder.EmbedBatch(ctx, texts)
+		if err != nil {
+			slog.Warn("failed to embed batch", "size", len(batch), "error", err)
+			result.Failed += len(batch)
+			done += len(batch)
+			continue
+		}
+
+		for i, u := range batch {
+			if u.VecLiteID != 0 {
+				if err := s.replaceQuote(u.VecLiteID, u.Quote, vectors[i]); err != nil {
+					slog.Warn("failed to re-embed quote", "id", u.Quote.ID, "error", err)
+					result.Failed++
+					continue
+				}
+				result.Reembedded++
+			} else {
+				if _, err := s.InsertQuoteWithEmbedding(ctx, u.Quote, vectors[i]); err != nil {
+					slog.Warn("failed to insert quote", "id", u.Quote.ID, "error", err)
+					result.Failed++
+					continue
+				}
+				result.Added++
+			}
+			pending++
+		}
+
+		done += len(batch)
+		if progress != nil {
+			progress(done, len(work))
+		}
+		if err := flush(false); err != nil {
+			return result, fmt.Errorf("sync: %w", err)
+		}
+	}
+
+	if err := flush(true); err != nil {
+		return result, fmt.Errorf("sync: %w", err)
+	}
+
+	return result, nil
+}
+
+// replaceQuote swaps the vector and document of an existing record in place.
+func (s *QuoteStore) replaceQuote(id uint64, q *db.Quote, embedding []float32) error {
+	if err := s.coll.UpdateVector(id, embedding); err != nil {
+		return fmt.Errorf("update vector: %w", err)
+	}
+	if err := s.coll.UpdateDocument(id, q.Text, quotePayload(q, s.profile)); err != nil {
+		return fmt.Errorf("update document: %w", err)
+	}
+	return nil
+}
+
+// toIndexedRecord extracts the fields PlanSync cares about from a VecLite record.
+func toIndexedRecord(r *veclite.Record) indexedRecord {
+	ir := indexedRecord{
+		ID:      r.ID,
+		Text:    r.Content,
+		Payload: r.Payload,
+	}
+	if r.Payload == nil {
+		return ir
+	}
+	ir.SQLiteID, ir.HasID = payloadInt64(r.Payload, "sqlite_id")
+	if h, ok := r.Payload["text_hash"].(string); ok {
+		ir.TextHash = h
+	}
+	if text, ok := r.Payload["text"].(string); ok {
+		ir.Text = text
+	}
+	if model, ok := r.Payload["embed_model"].(string); ok {
+		ir.EmbedModel = model
+	}
+	return ir
+}
+
+// payloadInt64 reads an integer payload field regardless of the concrete int type it was stored as.
+func payloadInt64(payload map[string]any, key string) (int64, bool) {
+	switch v := payload[key].(type) {
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case float64:
+		return int64(v), true
+	}
+	return 0, false
+}
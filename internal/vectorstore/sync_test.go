@@ -0,0 +1,117 @@
+package vectorstore
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/abdulachik/dostobot/internal/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func testQuote(id int64, text, hash string) *db.Quote {
+	return &db.Quote{
+		ID:         id,
+		Text:       text,
+		TextHash:   hash,
+		SourceBook: "Crime and Punishment",
+		Character:  sql.NullString{String: "Raskolnikov", Valid: true},
+		Themes:     `["suffering"]`,
+		CharCount:  int64(len(text)),
+	}
+}
+
+var testProfile = embeddingProfile{Model: "test-embed", Dimension: 4}
+
+func testRecord(id uint64, q *db.Quote) indexedRecord {
+	return indexedRecord{
+		ID:         id,
+		SQLiteID:   q.ID,
+		HasID:      true,
+		TextHash:   q.TextHash,
+		Text:       q.Text,
+		EmbedModel: testProfile.Model,
+		Payload:    quotePayload(q, testProfile),
+	}
+}
+
+func TestPlanSync(t *testing.T) {
+	t.Run("empty store adds everything", func(t *testing.T) {
+		quotes := []*db.Quote{testQuote(1, "a", "h1"), testQuote(2, "b", "h2")}
+
+		plan := planSync(nil, quotes, testProfile)
+
+		assert.Len(t, plan.Add, 2)
+		assert.Equal(t, 2, plan.EmbedCount())
+		assert.False(t, plan.Empty())
+	})
+
+	t.Run("in sync store is a no-op", func(t *testing.T) {
+		q1, q2 := testQuote(1, "a", "h1"), testQuote(2, "b", "h2")
+		records := []indexedRecord{testRecord(10, q1), testRecord(11, q2)}
+
+		plan := planSync(records, []*db.Quote{q1, q2}, testProfile)
+
+		assert.True(t, plan.Empty())
+		assert.Equal(t, 2, plan.Unchanged)
+	})
+
+	t.Run("changed text is re-embedded in place", func(t *testing.T) {
+		old := testQuote(1, "old text", "h-old")
+		updated := testQuote(1, "new text", "h-new")
+
+		plan := planSync([]indexedRecord{testRecord(10, old)}, []*db.Quote{updated}, testProfile)
+
+		assert.Len(t, plan.Reembed, 1)
+		assert.Equal(t, uint64(10), plan.Reembed[0].VecLiteID)
+		assert.Empty(t, plan.Add)
+	})
+
+	t.Run("changed metadata is refreshed without embedding", func(t *testing.T) {
+		old := testQuote(1, "a", "h1")
+		updated := testQuote(1, "a", "h1")
+		updated.Themes = `["suffering","redemption"]`
+
+		plan := planSync([]indexedRecord{testRecord(10, old)}, []*db.Quote{updated}, testProfile)
+
+		assert.Len(t, plan.Refresh, 1)
+		assert.Equal(t, 0, plan.EmbedCount())
+	})
+
+	t.Run("legacy records without hash compare text", func(t *testing.T) {
+		q := testQuote(1, "a", "h1")
+		legacy := testRecord(10, q)
+		legacy.TextHash = ""
+		delete(legacy.Payload, "text_hash")
+
+		plan := planSync([]indexedRecord{legacy}, []*db.Quote{q}, testProfile)
+
+		assert.Empty(t, plan.Reembed)
+		assert.Len(t, plan.Refresh, 1, "missing text_hash should be backfilled")
+	})
+
+	t.Run("vectors from another model are re-embedded", func(t *testing.T) {
+		q := testQuote(1, "a", "h1")
+		stale := testRecord(10, q)
+		stale.EmbedModel = "other-model"
+
+		plan := planSync([]indexedRecord{stale}, []*db.Quote{q}, testProfile)
+
+		assert.Len(t, plan.Reembed, 1)
+	})
+
+	t.Run("deletes removed quotes, duplicates and orphans", func(t *testing.T) {
+		q1 := testQuote(1, "a", "h1")
+		gone := testQuote(2, "b", "h2")
+		records := []indexedRecord{
+			testRecord(10, q1),
+			testRecord(12, q1), // duplicate from an earlier re-insert
+			testRecord(11, gone),
+			{ID: 13}, // no sqlite_id
+		}
+
+		plan := planSync(records, []*db.Quote{q1}, testProfile)
+
+		assert.Equal(t, []uint64{11, 12, 13}, plan.Delete)
+		assert.Equal(t, 1, plan.Unchanged)
+	})
+}